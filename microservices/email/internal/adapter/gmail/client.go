@@ -26,7 +26,7 @@ func New(username, password string) *Gmail {
 	}
 }
 
-func (g *Gmail) Send(ctx context.Context, to, subject, plain, html string) error {
+func (g *Gmail) Send(ctx context.Context, to, subject, plain, html string, headers map[string]string) error {
 	logger := loggerPkg.From(ctx)
 
 	auth := smtp.PlainAuth("", g.username, g.password, g.host)
@@ -34,6 +34,9 @@ func (g *Gmail) Send(ctx context.Context, to, subject, plain, html string) error
 	var msg strings.Builder
 	encodedSubject := mime.QEncoding.Encode("utf-8", subject)
 	msg.WriteString(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n", g.username, to, encodedSubject))
+	for name, value := range headers {
+		msg.WriteString(fmt.Sprintf("%s: %s\r\n", name, value))
+	}
 
 	if html != "" {
 		msg.WriteString("MIME-Version: 1.0\r\n")
@@ -51,7 +54,7 @@ func (g *Gmail) Send(ctx context.Context, to, subject, plain, html string) error
 	}
 
 	logger.Debug("Email sent via Gmail",
-		"to", to,
+		"user", loggerPkg.HashEmail(to),
 		"subject", subject,
 		"has_html", html != "")
 	return nil
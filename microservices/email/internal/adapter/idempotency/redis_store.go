@@ -3,6 +3,7 @@ package idempotency
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -76,3 +77,48 @@ func (r *RedisStore) GetStatus(ctx context.Context, messageID string) (Status, e
 	}
 	return Status(val), nil
 }
+
+// PurgeAddress deletes every idempotency entry whose key embeds the email address.
+// Keys from the subscription service have the form "<kind>:<email>:<suffix>".
+func (r *RedisStore) PurgeAddress(ctx context.Context, email string) (int, error) {
+	pattern := r.namespace + "*:" + globEscape(email) + ":*"
+
+	deleted := 0
+	iter := r.client.Scan(ctx, 0, pattern, 500).Iterator()
+	batch := make([]string, 0, 100)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == cap(batch) {
+			n, err := r.client.Del(ctx, batch...).Result()
+			if err != nil {
+				return deleted, err
+			}
+			deleted += int(n)
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+	if len(batch) > 0 {
+		n, err := r.client.Del(ctx, batch...).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int(n)
+	}
+	return deleted, nil
+}
+
+// globEscape quotes the characters Redis MATCH treats as wildcards.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
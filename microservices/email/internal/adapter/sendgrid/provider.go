@@ -22,7 +22,7 @@ func New(apiKey, from string) *SendGrid {
 	}
 }
 
-func (s *SendGrid) Send(ctx context.Context, to, subject, plain, html string) error {
+func (s *SendGrid) Send(ctx context.Context, to, subject, plain, html string, headers map[string]string) error {
 	logger := loggerPkg.From(ctx)
 	logger.Debug("Sending email via SendGrid API", "user", loggerPkg.HashEmail(to))
 
@@ -30,6 +30,9 @@ func (s *SendGrid) Send(ctx context.Context, to, subject, plain, html string) er
 	toUser := mail.NewEmail("User", to)
 
 	message := mail.NewSingleEmail(from, subject, toUser, plain, html)
+	for name, value := range headers {
+		message.SetHeader(name, value)
+	}
 
 	resp, err := s.client.Send(message)
 	if err != nil {
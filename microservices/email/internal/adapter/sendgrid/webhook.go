@@ -0,0 +1,125 @@
+package sendgrid
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"email/internal/domain"
+)
+
+// Headers SendGrid signs Event Webhook requests with.
+const (
+	SignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	TimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidPayload   = errors.New("invalid webhook payload")
+)
+
+// WebhookVerifier checks the ECDSA signature SendGrid puts on Event Webhook requests.
+type WebhookVerifier struct {
+	key    *ecdsa.PublicKey
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewWebhookVerifier takes the verification key from the SendGrid settings page,
+// a base64 encoded DER public key. maxAge rejects replayed requests; zero disables the check.
+func NewWebhookVerifier(publicKey string, maxAge time.Duration) (*WebhookVerifier, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("decode webhook public key: %w", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook public key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("webhook public key is not an ECDSA key")
+	}
+	return &WebhookVerifier{key: key, maxAge: maxAge, now: time.Now}, nil
+}
+
+// Verify checks the signature over the timestamp followed by the raw request body.
+func (v *WebhookVerifier) Verify(body []byte, signature, timestamp string) error {
+	if signature == "" || timestamp == "" {
+		return fmt.Errorf("%w: missing signature headers", ErrInvalidSignature)
+	}
+
+	if v.maxAge > 0 {
+		sec, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+		}
+		if age := v.now().Sub(time.Unix(sec, 0)); age > v.maxAge || age < -v.maxAge {
+			return fmt.Errorf("%w: timestamp outside the allowed window", ErrInvalidSignature)
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: bad encoding", ErrInvalidSignature)
+	}
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	if !ecdsa.VerifyASN1(v.key, digest[:], sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type event struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ParseEvents extracts the events that should suppress an address: hard bounces and spam
+// reports. Deliveries, opens, soft bounces ("blocked") and the like are skipped.
+func ParseEvents(body []byte) ([]domain.SuppressionEvent, error) {
+	var events []event
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	var out []domain.SuppressionEvent
+	for _, ev := range events {
+		var reason domain.SuppressionReason
+		switch {
+		case ev.Event == "bounce" && ev.Type != "blocked":
+			reason = domain.SuppressionBounce
+		case ev.Event == "spamreport":
+			reason = domain.SuppressionComplaint
+		default:
+			continue
+		}
+		if ev.Email == "" {
+			continue
+		}
+
+		occurredAt := time.Now().UTC()
+		if ev.Timestamp > 0 {
+			occurredAt = time.Unix(ev.Timestamp, 0).UTC()
+		}
+		out = append(out, domain.SuppressionEvent{
+			Email:      strings.ToLower(strings.TrimSpace(ev.Email)),
+			Reason:     reason,
+			Detail:     ev.Reason,
+			Provider:   "sendgrid",
+			OccurredAt: occurredAt,
+		})
+	}
+	return out, nil
+}
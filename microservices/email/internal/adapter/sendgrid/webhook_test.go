@@ -0,0 +1,98 @@
+package sendgrid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"email/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifier(t *testing.T, now time.Time) (*WebhookVerifier, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	v, err := NewWebhookVerifier(base64.StdEncoding.EncodeToString(der), 10*time.Minute)
+	require.NoError(t, err)
+	v.now = func() time.Time { return now }
+	return v, key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, timestamp string, body []byte) string {
+	t.Helper()
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestWebhookVerifier(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v, key := newTestVerifier(t, now)
+	body := []byte(`[{"email":"a@example.com","event":"bounce"}]`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, v.Verify(body, sign(t, key, ts, body), ts))
+	})
+
+	t.Run("TamperedBody", func(t *testing.T) {
+		err := v.Verify([]byte(`[{"email":"b@example.com","event":"bounce"}]`), sign(t, key, ts, body), ts)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("OtherKey", func(t *testing.T) {
+		_, otherKey := newTestVerifier(t, now)
+		assert.ErrorIs(t, v.Verify(body, sign(t, otherKey, ts, body), ts), ErrInvalidSignature)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		old := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+		assert.ErrorIs(t, v.Verify(body, sign(t, key, old, body), old), ErrInvalidSignature)
+	})
+
+	t.Run("MissingHeaders", func(t *testing.T) {
+		assert.ErrorIs(t, v.Verify(body, "", ts), ErrInvalidSignature)
+	})
+}
+
+func TestNewWebhookVerifier_RejectsBadKey(t *testing.T) {
+	_, err := NewWebhookVerifier("not base64!", 0)
+	assert.Error(t, err)
+}
+
+func TestParseEvents(t *testing.T) {
+	body := []byte(`[
+		{"email":"Hard@Example.com","event":"bounce","type":"bounce","reason":"550 no such user","timestamp":1700000000},
+		{"email":"soft@example.com","event":"bounce","type":"blocked","reason":"mailbox full"},
+		{"email":"spam@example.com","event":"spamreport","timestamp":1700000100},
+		{"email":"ok@example.com","event":"delivered"}
+	]`)
+
+	events, err := ParseEvents(body)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "hard@example.com", events[0].Email)
+	assert.Equal(t, domain.SuppressionBounce, events[0].Reason)
+	assert.Equal(t, "550 no such user", events[0].Detail)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), events[0].OccurredAt)
+
+	assert.Equal(t, "spam@example.com", events[1].Email)
+	assert.Equal(t, domain.SuppressionComplaint, events[1].Reason)
+
+	_, err = ParseEvents([]byte(`{"not":"an array"}`))
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+}
@@ -0,0 +1,45 @@
+package suppression
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"email/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the suppression list in Redis. Keys hold a hash of the address,
+// so the list can outlive a data erasure request without keeping the address itself.
+type RedisStore struct {
+	client    *redis.Client
+	namespace string
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, namespace: "suppression:"}
+}
+
+func (s *RedisStore) key(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return s.namespace + hex.EncodeToString(sum[:])
+}
+
+func (s *RedisStore) Add(ctx context.Context, ev domain.SuppressionEvent) error {
+	return s.client.HSet(ctx, s.key(ev.Email),
+		"reason", string(ev.Reason),
+		"provider", ev.Provider,
+		"occurred_at", ev.OccurredAt.UTC().Format(time.RFC3339),
+	).Err()
+}
+
+func (s *RedisStore) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	n, err := s.client.Exists(ctx, s.key(email)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -20,13 +20,15 @@ type templateParts struct {
 	HTML    *template.Template
 }
 
+// Store holds the email templates. <name>.tmpl is the default, Ukrainian version of a template
+// and <name>.<locale>.tmpl its translation, for example weather_report.en.tmpl.
 type Store struct {
-	templates map[domain.TemplateName]templateParts
+	templates map[domain.TemplateName]map[string]templateParts
 }
 
 func Load(path string) (*Store, error) {
 	store := &Store{
-		templates: make(map[domain.TemplateName]templateParts),
+		templates: make(map[domain.TemplateName]map[string]templateParts),
 	}
 
 	entries, err := os.ReadDir(path)
@@ -39,12 +41,13 @@ func Load(path string) (*Store, error) {
 			continue
 		}
 
-		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		name, locale, _ := strings.Cut(strings.TrimSuffix(entry.Name(), ".tmpl"), ".")
+		locale = strings.ToLower(locale)
 		fullPath := filepath.Join(path, entry.Name())
 
 		tmpl, err := template.ParseFiles(fullPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+			return nil, fmt.Errorf("failed to parse template %s: %w", entry.Name(), err)
 		}
 
 		subject := tmpl.Lookup("subject")
@@ -52,31 +55,63 @@ func Load(path string) (*Store, error) {
 		html := tmpl.Lookup("html")
 
 		if subject == nil || plain == nil || html == nil {
-			return nil, fmt.Errorf("template %s must define subject, plain, and html blocks", name)
+			return nil, fmt.Errorf("template %s must define subject, plain, and html blocks", entry.Name())
 		}
 
-		store.templates[domain.TemplateName(name)] = templateParts{
+		variants, ok := store.templates[domain.TemplateName(name)]
+		if !ok {
+			variants = make(map[string]templateParts)
+			store.templates[domain.TemplateName(name)] = variants
+		}
+		variants[locale] = templateParts{
 			Subject: subject,
 			Plain:   plain,
 			HTML:    html,
 		}
 	}
 
+	for name, variants := range store.templates {
+		if _, ok := variants[""]; !ok {
+			return nil, fmt.Errorf("template %s has translations but no default %s.tmpl", name, name)
+		}
+	}
+
 	return store, nil
 }
 
-func (s *Store) Render(ctx context.Context, templateName domain.TemplateName, data map[string]string) (subject, plain, html string, err error) {
+// variant picks the template for locale: an exact match such as "en-gb", then the language
+// ("en"), then the default version.
+func variant(variants map[string]templateParts, locale string) (templateParts, string) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if tmpl, ok := variants[locale]; ok {
+		return tmpl, locale
+	}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		if tmpl, ok := variants[lang]; ok {
+			return tmpl, lang
+		}
+	}
+	return variants[""], ""
+}
+
+func (s *Store) Render(ctx context.Context, templateName domain.TemplateName, locale string, data map[string]any) (subject, plain, html string, err error) {
 	logger := loggerPkg.From(ctx)
 
-	tmpl, ok := s.templates[templateName]
+	variants, ok := s.templates[templateName]
 	if !ok {
 		logger.Error("Template not found",
 			"template", templateName,
 			"available", len(s.templates))
 		return "", "", "", fmt.Errorf("template %s not found", templateName)
 	}
+	tmpl, used := variant(variants, locale)
+	if locale != "" && used == "" {
+		logger.Debug("Template translation not found, using default",
+			"template", templateName,
+			"locale", locale)
+	}
 
-	logger.Debug("Rendering template", "template", templateName)
+	logger.Debug("Rendering template", "template", templateName, "locale", used)
 
 	var subjectBuf, plainBuf, htmlBuf bytes.Buffer
 
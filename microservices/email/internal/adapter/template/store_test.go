@@ -0,0 +1,86 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"email/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_PicksLocaleWithFallback(t *testing.T) {
+	store, err := Load("../../template")
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"en":    "Confirm your subscription",
+		"EN-gb": "Confirm your subscription",
+		"uk":    "Підтвердіть вашу підписку",
+		"de":    "Підтвердіть вашу підписку",
+		"":      "Підтвердіть вашу підписку",
+	}
+	for locale, want := range tests {
+		t.Run(locale, func(t *testing.T) {
+			subject, _, _, err := store.Render(context.Background(), domain.TemplateConfirmation, locale, map[string]any{"confirm_url": "https://example.com"})
+			require.NoError(t, err)
+			assert.Equal(t, want, subject)
+		})
+	}
+}
+
+func TestLoad_EveryTemplateHasEnglishTranslation(t *testing.T) {
+	store, err := Load("../../template")
+	require.NoError(t, err)
+
+	for _, name := range []domain.TemplateName{
+		domain.TemplateConfirmation,
+		domain.TemplateConfirmationReminder,
+		domain.TemplateWeatherReport,
+		domain.TemplateManageLink,
+		domain.TemplateWeatherDigest,
+	} {
+		assert.Contains(t, store.templates[name], "en", name)
+	}
+}
+
+func TestRender_DigestListsEveryCity(t *testing.T) {
+	store, err := Load("../../template")
+	require.NoError(t, err)
+
+	// Data arrives as JSON, so the digest is decoded the way the consumer decodes it.
+	var req domain.SendEmailRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"To": "a@example.com",
+		"Template": "weather_digest",
+		"Data": {
+			"cities": [
+				{"city": "Kyiv", "temperature": 21.5, "humidity": 40, "description": "Sunny", "unsubscribe_url": "https://example.com/u/1"},
+				{"city": "Lviv", "temperature": 17, "humidity": 65, "description": "Rain", "unsubscribe_url": "https://example.com/u/2"}
+			]
+		}
+	}`), &req))
+
+	subject, plain, html, err := store.Render(context.Background(), req.Template, "en", req.Data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Weather in your cities", subject)
+	assert.Contains(t, plain, "Kyiv:\nTemperature: 21.5°C")
+	assert.Contains(t, plain, "Lviv:\nTemperature: 17°C")
+	assert.NotContains(t, plain, "Manage your subscriptions")
+	assert.Contains(t, html, `<a href="https://example.com/u/2">Unsubscribe from Lviv</a>`)
+}
+
+func TestLoad_RejectsTranslationWithoutDefault(t *testing.T) {
+	dir := t.TempDir()
+	content := `{{define "subject"}}s{{end}}{{define "plain"}}p{{end}}{{define "html"}}h{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "digest.en.tmpl"), []byte(content), 0o600))
+
+	_, err := Load(dir)
+
+	assert.ErrorContains(t, err, "no default digest.tmpl")
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"email/internal/adapter/gmail"
+	"email/internal/adapter/sendgrid"
+	suppressionAdapter "email/internal/adapter/suppression"
 
 	"email/internal/app/di"
 	"email/internal/delivery/consumer"
@@ -22,6 +24,7 @@ import (
 	"email/internal/config"
 	"email/internal/delivery"
 	"email/internal/email"
+	"email/internal/suppression"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 	metricsPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
@@ -30,6 +33,7 @@ import (
 type App struct {
 	Server        *http.Server
 	QueueConsumer *consumer.Consumer
+	Erasure       *consumer.ErasureConsumer
 	ShutdownFunc  func() error
 }
 
@@ -79,15 +83,16 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, err
 	}
 
-	emailProvider := gmail.New(cfg.GmailAddr, cfg.GmailPass)
-	emailService := email.NewService(emailProvider, templateStore)
-
 	redisClient, err := infra.NewRedisClient(ctx, cfg)
 	if err != nil {
 		loggerPkg.From(ctx).Error("Failed to connect to Redis", "redis_url", cfg.RedisURL, "err", err)
 		return nil, fmt.Errorf("redis error: %w", err)
 	}
 
+	suppressionStore := suppressionAdapter.NewRedisStore(redisClient)
+	emailProvider := gmail.New(cfg.GmailAddr, cfg.GmailPass)
+	emailService := email.NewService(emailProvider, templateStore, suppressionStore)
+
 	queueModule, err := di.NewQueueModule(ctx, cfg, emailService, redisClient)
 	if err != nil {
 		loggerPkg.From(ctx).Error("Failed to init queue module", "err", err)
@@ -101,8 +106,20 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		Subsystem: "http",
 	})
 
+	var webhook *delivery.SendGridWebhookHandler
+	if cfg.SendGridWebhookKey != "" {
+		verifier, err := sendgrid.NewWebhookVerifier(cfg.SendGridWebhookKey, cfg.SendGridWebhookMaxAge)
+		if err != nil {
+			loggerPkg.From(ctx).Error("Invalid SendGrid webhook key", "err", err)
+			return nil, fmt.Errorf("sendgrid webhook: %w", err)
+		}
+		webhook = delivery.NewSendGridWebhookHandler(verifier, suppression.NewService(suppressionStore, queueModule.Suppressions))
+	} else {
+		loggerPkg.From(ctx).Warn("SENDGRID_WEBHOOK_PUBLIC_KEY is not set, provider webhook is disabled")
+	}
+
 	mux := http.NewServeMux()
-	delivery.RegisterRoutes(mux, handler, metrics)
+	delivery.RegisterRoutes(mux, handler, webhook, metrics)
 
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -114,6 +131,7 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	return &App{
 		Server:        server,
 		QueueConsumer: queueModule.Consumer,
+		Erasure:       queueModule.Erasure,
 		ShutdownFunc:  queueModule.ShutdownFunc,
 	}, nil
 }
@@ -134,6 +152,12 @@ func (a *App) Start(ctx context.Context) error {
 		}
 	}()
 
+	go func() {
+		if err := a.Erasure.Start(ctx); err != nil {
+			logger.Error("Erasure consumer error", "err", err)
+		}
+	}()
+
 	time.Sleep(100 * time.Millisecond)
 	return nil
 }
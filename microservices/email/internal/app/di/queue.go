@@ -17,6 +17,8 @@ import (
 
 type QueueModule struct {
 	Consumer     *consumer.Consumer
+	Erasure      *consumer.ErasureConsumer
+	Suppressions *rabbitmq.SuppressionPublisher
 	RabbitConn   *rabbitmq.Connection
 	ShutdownFunc func() error
 }
@@ -34,7 +36,11 @@ func NewQueueModule(ctx context.Context, cfg *config.Config, svc email.Service,
 		[]rabbitmq.QueueConfig{
 			{
 				QueueName:   "email.queue",
-				RoutingKeys: []string{"email.confirmation", "email.weather_report"},
+				RoutingKeys: []string{"email.confirmation", "email.weather_report", "email.manage_link"},
+			},
+			{
+				QueueName:   rabbitmq.QueueErasure,
+				RoutingKeys: []string{rabbitmq.RoutingKeyPurgeAddress},
 			},
 		},
 	)
@@ -47,14 +53,35 @@ func NewQueueModule(ctx context.Context, cfg *config.Config, svc email.Service,
 		return nil, err
 	}
 
+	statusPublisher, err := rabbitmq.NewStatusPublisher(rmqConn, "email.exchange")
+	if err != nil {
+		loggerPkg.From(ctx).Error("Failed to create status publisher", "err", err)
+		if closeErr := rmqConn.Close(); closeErr != nil {
+			loggerPkg.From(ctx).Error("Error during RabbitMQ connection close", "err", closeErr)
+		}
+		return nil, err
+	}
+
+	suppressionPublisher, err := rabbitmq.NewSuppressionPublisher(rmqConn, "email.exchange")
+	if err != nil {
+		loggerPkg.From(ctx).Error("Failed to create suppression publisher", "err", err)
+		if closeErr := rmqConn.Close(); closeErr != nil {
+			loggerPkg.From(ctx).Error("Error during RabbitMQ connection close", "err", closeErr)
+		}
+		return nil, err
+	}
+
 	source := rabbitmq.NewSource(rmqConn, "email.queue")
 	store := idempotency.NewRedisStore(redisClient, 24*time.Hour)
 	breaker := consumer.NewDefaultCB()
-	cons := consumer.NewConsumer(source, svc, store, breaker)
+	cons := consumer.NewConsumer(source, svc, store, breaker, statusPublisher)
+	erasure := consumer.NewErasureConsumer(rabbitmq.NewSource(rmqConn, rabbitmq.QueueErasure), store)
 
 	return &QueueModule{
-		Consumer:   cons,
-		RabbitConn: rmqConn,
+		Consumer:     cons,
+		Erasure:      erasure,
+		Suppressions: suppressionPublisher,
+		RabbitConn:   rmqConn,
 		ShutdownFunc: func() error {
 			if err := rmqConn.Close(); err != nil {
 				loggerPkg.From(ctx).Error("Error during RabbitMQ connection close", "err", err)
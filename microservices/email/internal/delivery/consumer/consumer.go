@@ -3,6 +3,7 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"time"
@@ -35,19 +36,27 @@ type CircuitBreaker interface {
 type EmailUseCase interface {
 	Send(ctx context.Context, req domain.SendEmailRequest) error
 }
+
+// StatusPublisher reports send outcomes back to the requesting service.
+type StatusPublisher interface {
+	PublishStatus(ctx context.Context, event domain.DeliveryStatusEvent) error
+}
+
 type Consumer struct {
 	source      MessageSource
 	useCase     EmailUseCase
 	idempotency IdempotencyStore
 	breaker     CircuitBreaker
+	status      StatusPublisher
 }
 
-func NewConsumer(source MessageSource, useCase EmailUseCase, idempotency IdempotencyStore, breaker CircuitBreaker) *Consumer {
+func NewConsumer(source MessageSource, useCase EmailUseCase, idempotency IdempotencyStore, breaker CircuitBreaker, status StatusPublisher) *Consumer {
 	return &Consumer{
 		source:      source,
 		useCase:     useCase,
 		idempotency: idempotency,
 		breaker:     breaker,
+		status:      status,
 	}
 }
 
@@ -180,8 +189,16 @@ func (c *Consumer) processIdempotently(ctx context.Context, messageID string, ms
 		return fmt.Errorf("invalid message format: %w", err)
 	}
 
-	if err := c.useCase.Send(ctx, req); err != nil {
+	err = c.useCase.Send(ctx, req)
+	switch {
+	case errors.Is(err, domain.ErrAddressSuppressed):
+		// Retrying cannot help; the message is settled as bounced.
+		c.publishStatus(ctx, messageID, req.Template, domain.DeliveryBounced, err)
+	case err != nil:
+		c.publishStatus(ctx, messageID, req.Template, domain.DeliveryFailed, err)
 		return fmt.Errorf("email sending failed: %w", err)
+	default:
+		c.publishStatus(ctx, messageID, req.Template, domain.DeliverySent, nil)
 	}
 
 	if err := c.idempotency.MarkAsProcessed(ctx, messageID); err != nil {
@@ -190,3 +207,22 @@ func (c *Consumer) processIdempotently(ctx context.Context, messageID string, ms
 
 	return nil
 }
+
+// publishStatus is best effort: a lost status event leaves the delivery record behind,
+// but must not cause the email to be sent again.
+func (c *Consumer) publishStatus(ctx context.Context, messageID string, template domain.TemplateName, status domain.DeliveryStatus, sendErr error) {
+	event := domain.DeliveryStatusEvent{
+		MessageID:     messageID,
+		Status:        status,
+		Template:      template,
+		CorrelationID: loggerPkg.GetCorrelationID(ctx),
+		OccurredAt:    time.Now().UTC(),
+	}
+	if sendErr != nil {
+		event.Error = sendErr.Error()
+	}
+
+	if err := c.status.PublishStatus(ctx, event); err != nil {
+		loggerPkg.From(ctx).Error("Failed to publish delivery status", "status", status, "err", err)
+	}
+}
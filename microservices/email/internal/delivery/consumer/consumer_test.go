@@ -87,6 +87,27 @@ func (m *MockAcknowledger) Reject(tag uint64, requeue bool) error {
 	return args.Error(0)
 }
 
+type mockStatus struct {
+	mock.Mock
+}
+
+func (m *mockStatus) PublishStatus(ctx context.Context, event domain.DeliveryStatusEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func newMockStatus() *mockStatus {
+	status := new(mockStatus)
+	status.On("PublishStatus", mock.Anything, mock.Anything).Return(nil)
+	return status
+}
+
+func hasStatus(id string, status domain.DeliveryStatus) any {
+	return mock.MatchedBy(func(e domain.DeliveryStatusEvent) bool {
+		return e.MessageID == id && e.Status == status
+	})
+}
+
 type mockBreaker struct {
 	mock.Mock
 }
@@ -148,7 +169,8 @@ func TestConsumer_Handle_Success(t *testing.T) {
 	breaker.On("RecordSuccess", mock.Anything).Return()
 	breaker.On("RecordFailure", mock.Anything).Return()
 
-	c := consumer.NewConsumer(source, useCase, idem, breaker)
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
 
 	go func() {
 		_ = c.Start(ctx)
@@ -160,6 +182,7 @@ func TestConsumer_Handle_Success(t *testing.T) {
 	idem.AssertExpectations(t)
 	useCase.AssertExpectations(t)
 	logger.AssertExpectations(t)
+	status.AssertCalled(t, "PublishStatus", mock.Anything, hasStatus("msg-1", domain.DeliverySent))
 }
 
 func TestConsumer_AlreadyProcessed(t *testing.T) {
@@ -193,7 +216,8 @@ func TestConsumer_AlreadyProcessed(t *testing.T) {
 	breaker.On("RecordSuccess", mock.Anything).Return()
 	breaker.On("RecordFailure", mock.Anything).Return()
 
-	c := consumer.NewConsumer(source, useCase, idem, breaker)
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
 	go func() { _ = c.Start(ctx) }()
 	time.Sleep(50 * time.Millisecond)
 
@@ -235,7 +259,8 @@ func TestConsumer_InvalidJSON(t *testing.T) {
 	breaker.On("RecordSuccess", mock.Anything).Return()
 	breaker.On("RecordFailure", mock.Anything).Return()
 
-	c := consumer.NewConsumer(source, useCase, idem, breaker)
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
 	go func() { _ = c.Start(ctx) }()
 	time.Sleep(50 * time.Millisecond)
 
@@ -281,11 +306,13 @@ func TestConsumer_SendFails(t *testing.T) {
 	breaker.On("RecordSuccess", mock.Anything).Return()
 	breaker.On("RecordFailure", mock.Anything).Return()
 
-	c := consumer.NewConsumer(source, useCase, idem, breaker)
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
 	go func() { _ = c.Start(ctx) }()
 	time.Sleep(50 * time.Millisecond)
 
 	idem.AssertNotCalled(t, "MarkAsProcessed", mock.Anything, "msg-3")
+	status.AssertCalled(t, "PublishStatus", mock.Anything, hasStatus("msg-3", domain.DeliveryFailed))
 }
 
 func TestConsumer_ConcurrentProcessing(t *testing.T) {
@@ -342,7 +369,8 @@ func TestConsumer_ConcurrentProcessing(t *testing.T) {
 	breaker.On("RecordSuccess", mock.Anything).Return()
 	breaker.On("RecordFailure", mock.Anything).Return()
 
-	c := consumer.NewConsumer(source, useCase, idem, breaker)
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
 
 	go func() {
 		_ = c.Start(ctx)
@@ -355,3 +383,48 @@ func TestConsumer_ConcurrentProcessing(t *testing.T) {
 	useCase.AssertExpectations(t)
 	logger.AssertExpectations(t)
 }
+
+func TestConsumer_SuppressedAddressSettledAsBounced(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := domain.SendEmailRequest{To: "gone@example.com", Template: "weather_report"}
+	body, _ := json.Marshal(req)
+
+	ack := new(MockAcknowledger)
+	ack.On("Ack", uint64(1), false).Return(nil)
+
+	msgCh := make(chan amqp.Delivery, 1)
+	msgCh <- amqp.Delivery{
+		MessageId:    "msg-1",
+		Body:         body,
+		Acknowledger: ack,
+		DeliveryTag:  1,
+	}
+
+	source := new(mockSource)
+	source.On("Consume", mock.Anything).Return((<-chan amqp.Delivery)(msgCh), nil)
+
+	idem := new(mockIdempotency)
+	idem.On("IsProcessed", mock.Anything, "msg-1").Return(false, nil)
+	idem.On("MarkAsProcessing", mock.Anything, "msg-1").Return(true, nil)
+	idem.On("MarkAsProcessed", mock.Anything, "msg-1").Return(nil)
+	idem.On("ClearProcessing", mock.Anything, "msg-1").Return(nil)
+
+	useCase := new(mockUseCase)
+	useCase.On("Send", mock.Anything, req).Return(fmt.Errorf("send: %w", domain.ErrAddressSuppressed))
+
+	breaker := new(mockBreaker)
+	breaker.On("CanExecute", mock.Anything).Return(true)
+	breaker.On("RecordSuccess", mock.Anything).Return()
+
+	status := newMockStatus()
+	c := consumer.NewConsumer(source, useCase, idem, breaker, status)
+	go func() { _ = c.Start(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+
+	ack.AssertCalled(t, "Ack", uint64(1), false)
+	idem.AssertCalled(t, "MarkAsProcessed", mock.Anything, "msg-1")
+	breaker.AssertNotCalled(t, "RecordFailure", mock.Anything)
+	status.AssertCalled(t, "PublishStatus", mock.Anything, hasStatus("msg-1", domain.DeliveryBounced))
+}
@@ -0,0 +1,85 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AddressPurger forgets everything stored for an email address.
+type AddressPurger interface {
+	PurgeAddress(ctx context.Context, email string) (int, error)
+}
+
+type purgeCommand struct {
+	Email         string `json:"email"`
+	CorrelationID string `json:"correlation_id"` //nolint:tagliatelle
+}
+
+// ErasureConsumer handles erasure requests from the subscription service.
+// Purging is naturally idempotent, so redelivered commands need no dedup.
+type ErasureConsumer struct {
+	source MessageSource
+	purger AddressPurger
+}
+
+func NewErasureConsumer(source MessageSource, purger AddressPurger) *ErasureConsumer {
+	return &ErasureConsumer{source: source, purger: purger}
+}
+
+func (c *ErasureConsumer) Start(ctx context.Context) error {
+	msgs, err := c.source.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("consume: %w", err)
+	}
+
+	logger := loggerPkg.From(ctx)
+	logger.Info("Erasure consumer started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Erasure consumer stopped")
+			return ctx.Err()
+
+		case msg, ok := <-msgs:
+			if !ok {
+				logger.Info("Erasure message channel closed")
+				return nil
+			}
+
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+func (c *ErasureConsumer) handle(ctx context.Context, msg amqp.Delivery) {
+	logger := loggerPkg.From(ctx)
+
+	var cmd purgeCommand
+	if err := json.Unmarshal(msg.Body, &cmd); err != nil || cmd.Email == "" {
+		logger.Warn("Rejecting malformed purge command", "message_id", msg.MessageId)
+		_ = msg.Reject(false)
+		return
+	}
+
+	logger = logger.With("correlation_id", cmd.CorrelationID, "user", loggerPkg.HashEmail(cmd.Email))
+	ctx = loggerPkg.WithCorrelationID(ctx, cmd.CorrelationID)
+	ctx = loggerPkg.With(ctx, logger)
+
+	n, err := c.purger.PurgeAddress(ctx, cmd.Email)
+	if err != nil {
+		logger.Error("Failed to purge address", "err", err)
+		_ = msg.Nack(false, true)
+		return
+	}
+
+	logger.Info("Address purged", "idempotency_keys", n)
+	if err := msg.Ack(false); err != nil {
+		logger.Error("Failed to ack purge command", "err", err)
+	}
+}
@@ -0,0 +1,75 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"email/internal/delivery/consumer"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockPurger struct {
+	mock.Mock
+}
+
+func (m *mockPurger) PurgeAddress(ctx context.Context, email string) (int, error) {
+	args := m.Called(ctx, email)
+	return args.Int(0), args.Error(1)
+}
+
+func runErasure(t *testing.T, body string, purger *mockPurger) *MockAcknowledger {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ack := new(MockAcknowledger)
+	ack.On("Ack", uint64(1), false).Return(nil).Maybe()
+	ack.On("Nack", uint64(1), false, true).Return(nil).Maybe()
+	ack.On("Reject", uint64(1), false).Return(nil).Maybe()
+
+	msgCh := make(chan amqp.Delivery, 1)
+	msgCh <- amqp.Delivery{Body: []byte(body), MessageId: "purge:1", Acknowledger: ack, DeliveryTag: 1}
+
+	source := new(mockSource)
+	source.On("Consume", mock.Anything).Return((<-chan amqp.Delivery)(msgCh), nil)
+
+	go func() {
+		_ = consumer.NewErasureConsumer(source, purger).Start(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond) // wait for processing
+
+	return ack
+}
+
+func TestErasureConsumer_PurgesAddress(t *testing.T) {
+	purger := new(mockPurger)
+	purger.On("PurgeAddress", mock.Anything, "gone@example.com").Return(3, nil)
+
+	ack := runErasure(t, `{"email":"gone@example.com","correlation_id":"corr-1"}`, purger)
+
+	purger.AssertExpectations(t)
+	ack.AssertCalled(t, "Ack", uint64(1), false)
+}
+
+func TestErasureConsumer_RequeuesOnPurgeFailure(t *testing.T) {
+	purger := new(mockPurger)
+	purger.On("PurgeAddress", mock.Anything, "gone@example.com").Return(0, errors.New("redis down"))
+
+	ack := runErasure(t, `{"email":"gone@example.com"}`, purger)
+
+	ack.AssertCalled(t, "Nack", uint64(1), false, true)
+	ack.AssertNotCalled(t, "Ack", mock.Anything, mock.Anything)
+}
+
+func TestErasureConsumer_RejectsMalformedCommand(t *testing.T) {
+	purger := new(mockPurger)
+
+	ack := runErasure(t, `{"correlation_id":"corr-1"}`, purger)
+
+	ack.AssertCalled(t, "Reject", uint64(1), false)
+	purger.AssertNotCalled(t, "PurgeAddress", mock.Anything, mock.Anything)
+}
@@ -42,6 +42,10 @@ func (h *EmailHandler) Send(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.sender.Send(ctx, req); err != nil {
+		if isSuppressed(err) {
+			http.Error(w, "recipient is suppressed", http.StatusUnprocessableEntity)
+			return
+		}
 		logger.Error("Email request failed", "error_chain", err.Error())
 		http.Error(w, "failed to send email", http.StatusInternalServerError)
 		return
@@ -6,11 +6,16 @@ import (
 	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
 )
 
-func RegisterRoutes(mux *http.ServeMux, handler *EmailHandler, metrics *metrics.Metrics) {
+// RegisterRoutes mounts the API; the provider webhook is only exposed when webhook is non-nil.
+func RegisterRoutes(mux *http.ServeMux, handler *EmailHandler, webhook *SendGridWebhookHandler, metrics *metrics.Metrics) {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
 	emailHandler := http.HandlerFunc(handler.Send)
 	mux.Handle("/api/email/send", RequestMiddleware(metrics)(emailHandler))
+
+	if webhook != nil {
+		mux.Handle("/api/webhooks/sendgrid", RequestMiddleware(metrics)(http.HandlerFunc(webhook.Handle)))
+	}
 }
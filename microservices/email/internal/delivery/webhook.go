@@ -0,0 +1,79 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"email/internal/adapter/sendgrid"
+	"email/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// maxWebhookBody caps a single event batch; SendGrid posts at most a few hundred KB.
+const maxWebhookBody = 5 << 20
+
+type webhookVerifier interface {
+	Verify(body []byte, signature, timestamp string) error
+}
+
+type suppressionRecorder interface {
+	Record(ctx context.Context, events []domain.SuppressionEvent) error
+}
+
+// SendGridWebhookHandler receives the SendGrid Event Webhook and suppresses addresses
+// that hard bounced or reported our mail as spam.
+type SendGridWebhookHandler struct {
+	verifier webhookVerifier
+	recorder suppressionRecorder
+}
+
+func NewSendGridWebhookHandler(verifier webhookVerifier, recorder suppressionRecorder) *SendGridWebhookHandler {
+	return &SendGridWebhookHandler{verifier: verifier, recorder: recorder}
+}
+
+func (h *SendGridWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := loggerPkg.From(ctx)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		logger.Warn("Failed to read webhook body", "err", err)
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verifier.Verify(body, r.Header.Get(sendgrid.SignatureHeader), r.Header.Get(sendgrid.TimestampHeader)); err != nil {
+		logger.Warn("Rejected webhook request", "err", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	events, err := sendgrid.ParseEvents(body)
+	if err != nil {
+		logger.Warn("Invalid webhook payload", "err", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.recorder.Record(ctx, events); err != nil {
+		// A 5xx makes SendGrid retry the batch.
+		logger.Error("Failed to record suppressions", "err", err)
+		http.Error(w, "failed to process events", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debug("Webhook processed", "suppressions", len(events))
+	w.WriteHeader(http.StatusOK)
+}
+
+func isSuppressed(err error) bool {
+	return errors.Is(err, domain.ErrAddressSuppressed)
+}
@@ -1,14 +1,68 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrAddressSuppressed is returned for sends to an address on the suppression list.
+var ErrAddressSuppressed = errors.New("address is suppressed")
+
 type TemplateName string
 
 const (
-	TemplateConfirmation  TemplateName = "confirmation"
-	TemplateWeatherReport TemplateName = "weather_report"
+	TemplateConfirmation         TemplateName = "confirmation"
+	TemplateConfirmationReminder TemplateName = "confirmation_reminder"
+	TemplateWeatherReport        TemplateName = "weather_report"
+	TemplateManageLink           TemplateName = "manage_link"
+	TemplateWeatherDigest        TemplateName = "weather_digest"
 )
 
 type SendEmailRequest struct {
 	To       string
 	Template TemplateName
-	Data     map[string]string
+	// Data holds the template fields. Values are usually strings, but may be any JSON value,
+	// for example the list of cities of a digest.
+	Data map[string]any
+	// Locale is the recipient's language, e.g. "en"; templates fall back to the default one.
+	Locale string
+	// Headers are extra message headers, e.g. List-Unsubscribe; only allowed names are passed on.
+	Headers map[string]string
+}
+
+type DeliveryStatus string
+
+const (
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+	DeliveryBounced DeliveryStatus = "bounced"
+)
+
+// DeliveryStatusEvent reports the outcome of a send attempt back to the service that requested it.
+// MessageID is the idempotency key the email command was published with.
+type DeliveryStatusEvent struct {
+	MessageID     string         `json:"message_id"` //nolint:tagliatelle
+	Status        DeliveryStatus `json:"status"`
+	Template      TemplateName   `json:"template"`
+	Error         string         `json:"error,omitempty"`
+	CorrelationID string         `json:"correlation_id"` //nolint:tagliatelle
+	OccurredAt    time.Time      `json:"occurred_at"`    //nolint:tagliatelle
+}
+
+type SuppressionReason string
+
+const (
+	SuppressionBounce    SuppressionReason = "bounce"
+	SuppressionComplaint SuppressionReason = "complaint"
+)
+
+// SuppressionEvent records that the provider reported an address as undeliverable or as
+// having marked our mail as spam. It is also published to let other services react.
+type SuppressionEvent struct {
+	Email         string            `json:"email"`
+	Reason        SuppressionReason `json:"reason"`
+	Detail        string            `json:"detail,omitempty"`
+	Provider      string            `json:"provider"`
+	CorrelationID string            `json:"correlation_id"` //nolint:tagliatelle
+	OccurredAt    time.Time         `json:"occurred_at"`    //nolint:tagliatelle
 }
@@ -3,6 +3,8 @@ package email
 import (
 	"context"
 	"fmt"
+	"net/textproto"
+	"strings"
 
 	"email/internal/domain"
 
@@ -10,22 +12,35 @@ import (
 )
 
 type Provider interface {
-	Send(ctx context.Context, to, subject, plain, html string) error
+	Send(ctx context.Context, to, subject, plain, html string, headers map[string]string) error
+}
+
+// allowedHeaders are the extra headers callers may set; everything else is dropped so a
+// request cannot override From, Reply-To and the like.
+var allowedHeaders = map[string]struct{}{
+	"List-Unsubscribe":      {},
+	"List-Unsubscribe-Post": {},
 }
 
 type TemplateRenderer interface {
-	Render(ctx context.Context, template domain.TemplateName, data map[string]string) (subject, plain, html string, err error)
+	Render(ctx context.Context, template domain.TemplateName, locale string, data map[string]any) (subject, plain, html string, err error)
+}
+
+type SuppressionChecker interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
 }
 
 type Service struct {
 	provider      Provider
 	templateStore TemplateRenderer
+	suppressions  SuppressionChecker
 }
 
-func NewService(provider Provider, templateStore TemplateRenderer) Service {
+func NewService(provider Provider, templateStore TemplateRenderer, suppressions SuppressionChecker) Service {
 	return Service{
 		provider:      provider,
 		templateStore: templateStore,
+		suppressions:  suppressions,
 	}
 }
 
@@ -34,9 +49,20 @@ func (s Service) Send(ctx context.Context, req domain.SendEmailRequest) error {
 	logger.Debug("Starting email send operation",
 		"user", loggerPkg.HashEmail(req.To),
 		"template", string(req.Template),
+		"locale", req.Locale,
 		"data_fields", len(req.Data))
 
-	subject, plain, html, err := s.templateStore.Render(ctx, req.Template, req.Data)
+	suppressed, err := s.suppressions.IsSuppressed(ctx, req.To)
+	if err != nil {
+		// Better one more mail to a bad address than none to a good one.
+		logger.Error("Suppression check failed, sending anyway", "user", loggerPkg.HashEmail(req.To), "err", err)
+	}
+	if suppressed {
+		logger.Info("Skipping send to suppressed address", "user", loggerPkg.HashEmail(req.To))
+		return domain.ErrAddressSuppressed
+	}
+
+	subject, plain, html, err := s.templateStore.Render(ctx, req.Template, req.Locale, req.Data)
 	if err != nil {
 		logger.Error("Template processing failed",
 			"user", loggerPkg.HashEmail(req.To),
@@ -44,7 +70,7 @@ func (s Service) Send(ctx context.Context, req domain.SendEmailRequest) error {
 		return fmt.Errorf("template processing failed: %w", err)
 	}
 
-	if err := s.provider.Send(ctx, req.To, subject, plain, html); err != nil {
+	if err := s.provider.Send(ctx, req.To, subject, plain, html, filterHeaders(ctx, req.Headers)); err != nil {
 		logger.Error("Email delivery failed",
 			"user", loggerPkg.HashEmail(req.To),
 			"template", string(req.Template))
@@ -56,3 +82,19 @@ func (s Service) Send(ctx context.Context, req domain.SendEmailRequest) error {
 		"template", string(req.Template))
 	return nil
 }
+
+func filterHeaders(ctx context.Context, headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		if _, ok := allowedHeaders[name]; !ok || strings.ContainsAny(value, "\r\n") {
+			loggerPkg.From(ctx).Warn("Dropping email header", "header", name)
+			continue
+		}
+		out[name] = value
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"email/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	to      string
+	headers map[string]string
+	calls   int
+}
+
+func (p *fakeProvider) Send(_ context.Context, to, _, _, _ string, headers map[string]string) error {
+	p.calls++
+	p.to, p.headers = to, headers
+	return nil
+}
+
+type fakeRenderer struct{}
+
+func (fakeRenderer) Render(context.Context, domain.TemplateName, string, map[string]any) (string, string, string, error) {
+	return "subject", "plain", "<p>html</p>", nil
+}
+
+type fakeSuppressions struct {
+	suppressed bool
+	err        error
+}
+
+func (s fakeSuppressions) IsSuppressed(context.Context, string) (bool, error) {
+	return s.suppressed, s.err
+}
+
+func TestSend_PassesOnlyAllowedHeaders(t *testing.T) {
+	provider := &fakeProvider{}
+	svc := NewService(provider, fakeRenderer{}, fakeSuppressions{})
+
+	err := svc.Send(context.Background(), domain.SendEmailRequest{
+		To:       "a@example.com",
+		Template: domain.TemplateWeatherReport,
+		Headers: map[string]string{
+			"list-unsubscribe":      "<https://example.com/api/unsubscribe/tok>",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			"Reply-To":              "attacker@example.com",
+			"X-Injected":            "a\r\nBcc: victim@example.com",
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"List-Unsubscribe":      "<https://example.com/api/unsubscribe/tok>",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}, provider.headers)
+}
+
+func TestSend_SuppressedAddressIsSkipped(t *testing.T) {
+	provider := &fakeProvider{}
+	svc := NewService(provider, fakeRenderer{}, fakeSuppressions{suppressed: true})
+
+	err := svc.Send(context.Background(), domain.SendEmailRequest{To: "gone@example.com", Template: domain.TemplateWeatherReport})
+
+	assert.ErrorIs(t, err, domain.ErrAddressSuppressed)
+	assert.Zero(t, provider.calls)
+}
+
+func TestSend_SuppressionCheckFailureStillSends(t *testing.T) {
+	provider := &fakeProvider{}
+	svc := NewService(provider, fakeRenderer{}, fakeSuppressions{err: errors.New("redis down")})
+
+	err := svc.Send(context.Background(), domain.SendEmailRequest{To: "a@example.com", Template: domain.TemplateConfirmation})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.calls)
+}
@@ -33,6 +33,16 @@ func (c *Connection) Channel() *amqp.Channel {
 	return c.channel
 }
 
+// OpenChannel opens an additional channel on the connection, so publishing does not share
+// a channel with the consumer. It is closed together with the connection.
+func (c *Connection) OpenChannel() (*amqp.Channel, error) {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch, nil
+}
+
 func (c *Connection) Close() error {
 	if err := c.channel.Close(); err != nil {
 		_ = c.conn.Close()
@@ -3,8 +3,13 @@ package rabbitmq
 const (
 	ExchangeEmail = "email.exchange"
 
-	QueueEmail = "email.queue"
+	QueueEmail   = "email.queue"
+	QueueErasure = "email.erasure"
 
 	RoutingKeyConfirmation = "cmd.email.send_confirmation"
 	RoutingKeyWeather      = "cmd.email.send_weather_report"
+
+	RoutingKeyDeliveryStatus    = "email.delivery_status"
+	RoutingKeyPurgeAddress      = "email.purge_address"
+	RoutingKeyAddressSuppressed = "email.address_suppressed"
 )
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"email/internal/domain"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// StatusPublisher publishes delivery status events to the email exchange.
+type StatusPublisher struct {
+	mu       sync.Mutex
+	channel  *amqp.Channel
+	exchange string
+}
+
+func NewStatusPublisher(conn *Connection, exchange string) (*StatusPublisher, error) {
+	ch, err := conn.OpenChannel()
+	if err != nil {
+		return nil, err
+	}
+	return &StatusPublisher{channel: ch, exchange: exchange}, nil
+}
+
+func (p *StatusPublisher) PublishStatus(ctx context.Context, event domain.DeliveryStatusEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal status event: %w", err)
+	}
+
+	pub := amqp.Publishing{
+		ContentType:   "application/json",
+		Body:          body,
+		Timestamp:     time.Now(),
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     fmt.Sprintf("%s:%s:%d", event.MessageID, event.Status, event.OccurredAt.UnixNano()),
+		CorrelationId: event.CorrelationID,
+	}
+
+	// amqp channels are not safe for concurrent publishing.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.channel.PublishWithContext(ctx, p.exchange, RoutingKeyDeliveryStatus, false, false, pub); err != nil {
+		return fmt.Errorf("publish status event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"email/internal/domain"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// SuppressionPublisher announces suppressed addresses on the email exchange.
+type SuppressionPublisher struct {
+	mu       sync.Mutex
+	channel  *amqp.Channel
+	exchange string
+}
+
+func NewSuppressionPublisher(conn *Connection, exchange string) (*SuppressionPublisher, error) {
+	ch, err := conn.OpenChannel()
+	if err != nil {
+		return nil, err
+	}
+	return &SuppressionPublisher{channel: ch, exchange: exchange}, nil
+}
+
+func (p *SuppressionPublisher) PublishSuppression(ctx context.Context, event domain.SuppressionEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal suppression event: %w", err)
+	}
+
+	pub := amqp.Publishing{
+		ContentType:   "application/json",
+		Body:          body,
+		Timestamp:     time.Now(),
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     uuid.NewString(), // keeps the address out of the broker's message metadata
+		CorrelationId: event.CorrelationID,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.channel.PublishWithContext(ctx, p.exchange, RoutingKeyAddressSuppressed, false, false, pub); err != nil {
+		return fmt.Errorf("publish suppression event: %w", err)
+	}
+	return nil
+}
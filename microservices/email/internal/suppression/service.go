@@ -0,0 +1,48 @@
+package suppression
+
+import (
+	"context"
+	"fmt"
+
+	"email/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+type store interface {
+	Add(ctx context.Context, ev domain.SuppressionEvent) error
+}
+
+type publisher interface {
+	PublishSuppression(ctx context.Context, ev domain.SuppressionEvent) error
+}
+
+// Service puts addresses reported by the provider on the suppression list and tells
+// the subscription service so it can deactivate them.
+type Service struct {
+	store     store
+	publisher publisher
+}
+
+func NewService(store store, publisher publisher) Service {
+	return Service{store: store, publisher: publisher}
+}
+
+// Record stops at the first failure; providers retry the whole batch and both steps are idempotent.
+func (s Service) Record(ctx context.Context, events []domain.SuppressionEvent) error {
+	logger := loggerPkg.From(ctx)
+	for _, ev := range events {
+		ev.CorrelationID = loggerPkg.GetCorrelationID(ctx)
+		if err := s.store.Add(ctx, ev); err != nil {
+			return fmt.Errorf("add to suppression list: %w", err)
+		}
+		if err := s.publisher.PublishSuppression(ctx, ev); err != nil {
+			return fmt.Errorf("publish suppression: %w", err)
+		}
+		logger.Info("Address suppressed",
+			"user", loggerPkg.HashEmail(ev.Email),
+			"reason", ev.Reason,
+			"provider", ev.Provider)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"gateway/internal/middleware"
@@ -31,11 +32,20 @@ type SubscribeRequest struct {
 	Email     string `json:"email"`
 	City      string `json:"city"`
 	Frequency string `json:"frequency"`
+	// Locale picks the language of the emails; empty leaves it to the Accept-Language header.
+	Locale string `json:"locale,omitempty"`
+	// Nickname is the form honeypot, passed through so the subscription service can spot bots.
+	Nickname string `json:"nickname,omitempty"`
+	// Channel is "email" (the default) or "webhook", which posts reports to WebhookURL.
+	Channel    string `json:"channel,omitempty"`
+	WebhookURL string `json:"webhook_url,omitempty"` //nolint:tagliatelle
 }
 
 type SubscribeResponse struct {
 	Message string `json:"message"`
 	Status  string `json:"status"`
+	// WebhookSecret signs the webhook requests; it is only ever returned here.
+	WebhookSecret string `json:"webhook_secret,omitempty"` //nolint:tagliatelle
 }
 
 type ConfirmResponse struct {
@@ -48,6 +58,52 @@ type UnsubscribeResponse struct {
 	Status  string `json:"status"`
 }
 
+type ManageLinkRequest struct {
+	Email string `json:"email"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type SubscriptionView struct {
+	ID           string      `json:"id"`
+	City         string      `json:"city"`
+	Frequency    string      `json:"frequency"`
+	DeliveryHour int         `json:"delivery_hour"` //nolint:tagliatelle
+	Locale       string      `json:"locale"`
+	IsConfirmed  bool        `json:"is_confirmed"` //nolint:tagliatelle
+	CreatedAt    time.Time   `json:"created_at"`   //nolint:tagliatelle
+	PausedUntil  *time.Time  `json:"paused_until"` //nolint:tagliatelle
+	QuietHours   *QuietHours `json:"quiet_hours"`  //nolint:tagliatelle
+	Digest       bool        `json:"digest"`
+}
+
+// QuietHours is a daily window, in hours of an IANA timezone, without hourly reports.
+type QuietHours struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type ListSubscriptionsResponse struct {
+	Subscriptions []SubscriptionView `json:"subscriptions"`
+}
+
+type UpdateSubscriptionRequest struct {
+	City         *string `json:"city,omitempty"`
+	Frequency    *string `json:"frequency,omitempty"`
+	DeliveryHour *int    `json:"delivery_hour,omitempty"` //nolint:tagliatelle
+	// QuietHours with equal start and end removes the quiet hours.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"` //nolint:tagliatelle
+	// Digest combines the subscription's reports with the address's other digest subscriptions.
+	Digest *bool `json:"digest,omitempty"`
+}
+
+type PauseSubscriptionRequest struct {
+	Until time.Time `json:"until"`
+}
+
 type WeatherResponse struct {
 	Temperature float64 `json:"temperature"`
 	Description string  `json:"description"`
@@ -83,6 +139,37 @@ func (c *Client) Unsubscribe(ctx context.Context, token string) (*UnsubscribeRes
 	return &resp, nil
 }
 
+// OneClickUnsubscribe forwards an RFC 8058 one-click unsubscribe POST.
+func (c *Client) OneClickUnsubscribe(ctx context.Context, token string) (*UnsubscribeResponse, error) {
+	endpoint := fmt.Sprintf("/api/unsubscribe/%s", url.PathEscape(token))
+	form := url.Values{"List-Unsubscribe": {"One-Click"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "api-gateway/1.0.0")
+	setForwardingHeaders(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer c.closeBody(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+
+	var out UnsubscribeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out, nil
+}
+
 func (c *Client) GetWeather(ctx context.Context, city string) (*WeatherResponse, error) {
 	endpoint := "/api/weather"
 	if city != "" {
@@ -96,6 +183,83 @@ func (c *Client) GetWeather(ctx context.Context, city string) (*WeatherResponse,
 	return &resp, nil
 }
 
+func (c *Client) RequestManageLink(ctx context.Context, req ManageLinkRequest) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.postJSON(ctx, "/api/subscriptions/manage-link", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ListSubscriptions(ctx context.Context, token string) (*ListSubscriptionsResponse, error) {
+	var resp ListSubscriptionsResponse
+	err := c.doWithToken(ctx, http.MethodGet, "/api/subscriptions", token, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) UpdateSubscription(ctx context.Context, token, id string, req UpdateSubscriptionRequest) (*SubscriptionView, error) {
+	endpoint := fmt.Sprintf("/api/subscriptions/%s", url.PathEscape(id))
+	var resp SubscriptionView
+	err := c.doWithToken(ctx, http.MethodPatch, endpoint, token, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) DeleteSubscription(ctx context.Context, token, id string) (*MessageResponse, error) {
+	endpoint := fmt.Sprintf("/api/subscriptions/%s", url.PathEscape(id))
+	var resp MessageResponse
+	err := c.doWithToken(ctx, http.MethodDelete, endpoint, token, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) PauseSubscription(ctx context.Context, token, id string, req PauseSubscriptionRequest) (*SubscriptionView, error) {
+	endpoint := fmt.Sprintf("/api/subscriptions/%s/pause", url.PathEscape(id))
+	var resp SubscriptionView
+	err := c.doWithToken(ctx, http.MethodPost, endpoint, token, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ResumeSubscription(ctx context.Context, token, id string) (*SubscriptionView, error) {
+	endpoint := fmt.Sprintf("/api/subscriptions/%s/resume", url.PathEscape(id))
+	var resp SubscriptionView
+	err := c.doWithToken(ctx, http.MethodPost, endpoint, token, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExportData returns the personal data bundle as the subscription service rendered it.
+func (c *Client) ExportData(ctx context.Context, token string) (json.RawMessage, error) {
+	var resp json.RawMessage
+	err := c.doWithToken(ctx, http.MethodGet, "/api/privacy/data", token, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) EraseData(ctx context.Context, token string) (*MessageResponse, error) {
+	var resp MessageResponse
+	err := c.doWithToken(ctx, http.MethodDelete, "/api/privacy/data", token, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *Client) postJSON(ctx context.Context, endpoint string, reqBody interface{}, respBody interface{}) error {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -111,9 +275,7 @@ func (c *Client) postJSON(ctx context.Context, endpoint string, reqBody interfac
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "api-gateway/1.0.0")
 
-	if requestID := middleware.GetRequestID(ctx); requestID != "" {
-		req.Header.Set("X-Request-ID", requestID)
-	}
+	setForwardingHeaders(ctx, req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -122,8 +284,7 @@ func (c *Client) postJSON(ctx context.Context, endpoint string, reqBody interfac
 	defer c.closeBody(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+		return newAPIError(resp)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
@@ -143,9 +304,50 @@ func (c *Client) get(ctx context.Context, endpoint string, respBody interface{})
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "api-gateway/1.0.0")
 
-	if requestID := middleware.GetRequestID(ctx); requestID != "" {
-		req.Header.Set("X-Request-ID", requestID)
+	setForwardingHeaders(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
 	}
+	defer c.closeBody(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// doWithToken sends a request authorised by a magic link token to the subscription service.
+func (c *Client) doWithToken(ctx context.Context, method, endpoint, token string, reqBody interface{}, respBody interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		jsonData, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	fullURL := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "api-gateway/1.0.0")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	setForwardingHeaders(ctx, req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -154,8 +356,7 @@ func (c *Client) get(ctx context.Context, endpoint string, respBody interface{})
 	defer c.closeBody(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+		return newAPIError(resp)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
@@ -170,3 +371,17 @@ func (c *Client) closeBody(body io.Closer) {
 		fmt.Printf("Failed to close response body: %v\n", err)
 	}
 }
+
+// setForwardingHeaders passes the request id, the original client address and its preferred
+// languages downstream.
+func setForwardingHeaders(ctx context.Context, req *http.Request) {
+	if requestID := middleware.GetRequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	if clientIP := middleware.GetClientIP(ctx); clientIP != "" {
+		req.Header.Set("X-Forwarded-For", clientIP)
+	}
+	if acceptLanguage := middleware.GetAcceptLanguage(ctx); acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+}
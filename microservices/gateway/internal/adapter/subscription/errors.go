@@ -0,0 +1,57 @@
+package subscription
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error codes the subscription service attaches to rejected requests, shared by both transports.
+const (
+	CodeInvalidEmail       = "invalid_email"
+	CodeDisposableEmail    = "disposable_email"
+	CodeUndeliverableEmail = "undeliverable_email"
+)
+
+// APIError is a request the subscription service answered with a failure.
+type APIError struct {
+	// StatusCode is the HTTP status the failure maps to.
+	StatusCode int
+	// Code is the machine-readable reason, e.g. "disposable_email"; empty when the service sent none.
+	Code    string
+	Message string
+	// RetryAfter is how long a rate limited caller should wait; zero when unknown.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("unexpected status: %d, code: %s, message: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("unexpected status: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// newAPIError reads the {"error", "code"} body the subscription service sends with failures.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(resp.Body)
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Error
+	} else {
+		apiErr.Message = string(body)
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
@@ -0,0 +1,162 @@
+package subscriptiongrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gateway/internal/adapter/subscription"
+	"gateway/internal/adapter/subscriptiongrpc/pb"
+	"gateway/internal/middleware"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Client calls Subscribe, Confirm, Unsubscribe and GetWeather over gRPC. The calls the proto
+// does not cover yet fall through to the embedded HTTP client.
+type Client struct {
+	*subscription.Client
+	conn    *grpc.ClientConn
+	api     pb.SubscriptionServiceClient
+	timeout time.Duration
+}
+
+func NewClient(addr string, timeout time.Duration, httpClient *subscription.Client) (*Client, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("grpc dial: %w", err)
+	}
+	return &Client{
+		Client:  httpClient,
+		conn:    conn,
+		api:     pb.NewSubscriptionServiceClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+func (c *Client) Subscribe(ctx context.Context, req subscription.SubscribeRequest) (*subscription.SubscribeResponse, error) {
+	ctx, cancel := c.outgoing(ctx)
+	defer cancel()
+
+	resp, err := c.api.Subscribe(ctx, &pb.SubscribeRequest{
+		Email:      req.Email,
+		City:       req.City,
+		Frequency:  req.Frequency,
+		Locale:     req.Locale,
+		Nickname:   req.Nickname,
+		Channel:    req.Channel,
+		WebhookUrl: req.WebhookURL,
+	})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return &subscription.SubscribeResponse{Message: resp.GetMessage(), WebhookSecret: resp.GetWebhookSecret()}, nil
+}
+
+func (c *Client) Confirm(ctx context.Context, token string) (*subscription.ConfirmResponse, error) {
+	ctx, cancel := c.outgoing(ctx)
+	defer cancel()
+
+	resp, err := c.api.Confirm(ctx, &pb.ConfirmRequest{Token: token})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return &subscription.ConfirmResponse{Message: resp.GetMessage()}, nil
+}
+
+func (c *Client) Unsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error) {
+	ctx, cancel := c.outgoing(ctx)
+	defer cancel()
+
+	resp, err := c.api.Unsubscribe(ctx, &pb.UnsubscribeRequest{Token: token})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return &subscription.UnsubscribeResponse{Message: resp.GetMessage()}, nil
+}
+
+func (c *Client) GetWeather(ctx context.Context, city string) (*subscription.WeatherResponse, error) {
+	ctx, cancel := c.outgoing(ctx)
+	defer cancel()
+
+	resp, err := c.api.GetWeather(ctx, &pb.GetWeatherRequest{City: city})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return &subscription.WeatherResponse{
+		Temperature: resp.GetTemperature(),
+		Description: resp.GetDescription(),
+		Humidity:    int(resp.GetHumidity()),
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// outgoing bounds the call like the HTTP client's timeout and forwards what setForwardingHeaders
+// sends over HTTP as metadata.
+func (c *Client) outgoing(ctx context.Context) (context.Context, context.CancelFunc) {
+	md := metadata.MD{}
+	if requestID := middleware.GetRequestID(ctx); requestID != "" {
+		md.Set("x-correlation-id", requestID)
+	}
+	if clientIP := middleware.GetClientIP(ctx); clientIP != "" {
+		md.Set("x-forwarded-for", clientIP)
+	}
+	if acceptLanguage := middleware.GetAcceptLanguage(ctx); acceptLanguage != "" {
+		md.Set("accept-language", acceptLanguage)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// toAPIError turns a gRPC status into the error the HTTP client would have returned, using the
+// ErrorInfo reason as the code, so callers handle both transports the same way.
+func toAPIError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	apiErr := &subscription.APIError{StatusCode: httpStatus(st.Code()), Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			apiErr.Code = strings.ToLower(d.GetReason())
+		case *errdetails.RetryInfo:
+			apiErr.RetryAfter = d.GetRetryDelay().AsDuration()
+		}
+	}
+	return apiErr
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
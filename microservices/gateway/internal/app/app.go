@@ -3,6 +3,8 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"gateway/internal/adapter/subscription"
+	"gateway/internal/adapter/subscriptiongrpc"
 	"gateway/internal/config"
 	"gateway/internal/delivery"
 	"gateway/internal/service"
@@ -19,13 +22,15 @@ import (
 
 type App struct {
 	server *http.Server
+	// closer releases the gRPC connection to the subscription service; nil over HTTP.
+	closer io.Closer
 }
 
-func NewApp(cfg *config.Config, ctx context.Context) *App {
-	subscriptionClient := subscription.NewClient(
-		cfg.SubscriptionServiceAddr,
-		cfg.RequestTimeout,
-	)
+func NewApp(cfg *config.Config, ctx context.Context) (*App, error) {
+	subscriptionClient, closer, err := newSubscriptionClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 	validator := service.NewSecurityValidator()
 	gatewayService := service.NewService(subscriptionClient, validator)
 	responseWriter := delivery.NewResponseWriter()
@@ -42,6 +47,26 @@ func NewApp(cfg *config.Config, ctx context.Context) *App {
 
 	return &App{
 		server: server,
+		closer: closer,
+	}, nil
+}
+
+// newSubscriptionClient picks the transport to the subscription service from SUBSCRIPTION_TRANSPORT.
+// Over gRPC only the calls in subscription.proto leave HTTP.
+func newSubscriptionClient(cfg *config.Config) (service.SubscriptionClient, io.Closer, error) {
+	httpClient := subscription.NewClient(cfg.SubscriptionServiceAddr, cfg.RequestTimeout)
+
+	switch cfg.SubscriptionTransport {
+	case "http":
+		return httpClient, nil, nil
+	case "grpc":
+		grpcClient, err := subscriptiongrpc.NewClient(cfg.SubscriptionGRPCAddr, cfg.RequestTimeout, httpClient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("subscription gRPC client: %w", err)
+		}
+		return grpcClient, grpcClient, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown SUBSCRIPTION_TRANSPORT %q", cfg.SubscriptionTransport)
 	}
 }
 
@@ -69,6 +94,12 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	if a.closer != nil {
+		if err := a.closer.Close(); err != nil {
+			logger.Error("Subscription client close failed", "err", err)
+		}
+	}
+
 	logger.Info("API Gateway shutdown completed")
 	return nil
 }
@@ -82,6 +113,10 @@ func Run(logger *loggerPkg.Logger) error {
 
 	ctx := loggerPkg.With(context.Background(), logger)
 
-	app := NewApp(cfg, ctx)
+	app, err := NewApp(cfg, ctx)
+	if err != nil {
+		logger.Error("Failed to initialize API Gateway", "err", err)
+		return err
+	}
 	return app.Start(ctx)
 }
@@ -11,6 +11,8 @@ import (
 type Config struct {
 	Port                    string        `env:"PORT"`
 	SubscriptionServiceAddr string        `env:"SUBSCRIPTION_SERVICE_ADDR"`
+	SubscriptionTransport   string        `env:"SUBSCRIPTION_TRANSPORT"`
+	SubscriptionGRPCAddr    string        `env:"SUBSCRIPTION_GRPC_ADDR"`
 	RequestTimeout          time.Duration `env:"REQUEST_TIMEOUT"`
 	ReadTimeout             time.Duration `env:"READ_TIMEOUT"`
 	WriteTimeout            time.Duration `env:"WRITE_TIMEOUT"`
@@ -33,6 +35,8 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		Port:                    getEnv("PORT", "8080"),
 		SubscriptionServiceAddr: getEnv("SUBSCRIPTION_SERVICE_ADDR", "http://localhost:8083"),
+		SubscriptionTransport:   getEnv("SUBSCRIPTION_TRANSPORT", "http"),
+		SubscriptionGRPCAddr:    getEnv("SUBSCRIPTION_GRPC_ADDR", "localhost:50052"),
 		RequestTimeout:          parseDuration(getEnv("REQUEST_TIMEOUT", "30s")),
 		ReadTimeout:             parseDuration(getEnv("READ_TIMEOUT", "15s")),
 		WriteTimeout:            parseDuration(getEnv("WRITE_TIMEOUT", "15s")),
@@ -3,7 +3,10 @@ package delivery
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"gateway/internal/adapter/subscription"
@@ -15,7 +18,16 @@ type SubscriptionService interface {
 	Subscribe(ctx context.Context, req subscription.SubscribeRequest) (*subscription.SubscribeResponse, error)
 	Confirm(ctx context.Context, token string) (*subscription.ConfirmResponse, error)
 	Unsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error)
+	OneClickUnsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error)
 	GetWeather(ctx context.Context, city string) (*subscription.WeatherResponse, error)
+	RequestManageLink(ctx context.Context, req subscription.ManageLinkRequest) (*subscription.MessageResponse, error)
+	ListSubscriptions(ctx context.Context, token string) (*subscription.ListSubscriptionsResponse, error)
+	UpdateSubscription(ctx context.Context, token, id string, req subscription.UpdateSubscriptionRequest) (*subscription.SubscriptionView, error)
+	DeleteSubscription(ctx context.Context, token, id string) (*subscription.MessageResponse, error)
+	PauseSubscription(ctx context.Context, token, id string, req subscription.PauseSubscriptionRequest) (*subscription.SubscriptionView, error)
+	ResumeSubscription(ctx context.Context, token, id string) (*subscription.SubscriptionView, error)
+	ExportData(ctx context.Context, token string) (json.RawMessage, error)
+	EraseData(ctx context.Context, token string) (*subscription.MessageResponse, error)
 }
 
 type responseWriter interface {
@@ -109,6 +121,33 @@ func (h *SubscriptionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request
 	h.responseWriter.WriteSuccess(w, resp)
 }
 
+// OneClickUnsubscribe handles RFC 8058 POSTs from mailbox providers; no confirmation step follows.
+func (h *SubscriptionHandler) OneClickUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, "/api/unsubscribe/")
+	if token == "" || token == r.URL.Path {
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Validation failed", "Token is required", r)
+		return
+	}
+
+	if r.PostFormValue("List-Unsubscribe") != "One-Click" {
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Validation failed", "List-Unsubscribe=One-Click body is required", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.OneClickUnsubscribe(r.Context(), token)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
 func (h *SubscriptionHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
@@ -132,20 +171,234 @@ func (h *SubscriptionHandler) GetWeather(w http.ResponseWriter, r *http.Request)
 	h.responseWriter.WriteSuccess(w, resp)
 }
 
+func (h *SubscriptionHandler) RequestManageLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	var req subscription.ManageLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger := loggerPkg.From(r.Context())
+		logger.Warn("Invalid JSON in request body", "err", err)
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Invalid JSON", "Request body must be valid JSON", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.RequestManageLink(r.Context(), req)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Manage link request completed successfully")
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+func (h *SubscriptionHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	token := manageToken(r)
+	if token == "" {
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Token is required", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.ListSubscriptions(r.Context(), token)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("List subscriptions request completed successfully")
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+func (h *SubscriptionHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	id, token, ok := h.manageTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var req subscription.UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger := loggerPkg.From(r.Context())
+		logger.Warn("Invalid JSON in request body", "err", err)
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Invalid JSON", "Request body must be valid JSON", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.UpdateSubscription(r.Context(), token, id, req)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Update subscription request completed successfully")
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	id, token, ok := h.manageTarget(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.subscriptionService.DeleteSubscription(r.Context(), token, id)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Delete subscription request completed successfully")
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+// SubscriptionAction handles POST /api/subscriptions/{id}/pause and /api/subscriptions/{id}/resume.
+func (h *SubscriptionHandler) SubscriptionAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	id, action, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/subscriptions/"), "/")
+	if !found || id == "" || (action != "pause" && action != "resume") {
+		h.responseWriter.WriteError(w, http.StatusNotFound, "Not found", "Resource not found", r)
+		return
+	}
+
+	token := manageToken(r)
+	if token == "" {
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Token is required", r)
+		return
+	}
+
+	var (
+		resp *subscription.SubscriptionView
+		err  error
+	)
+	if action == "pause" {
+		var req subscription.PauseSubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger := loggerPkg.From(r.Context())
+			logger.Warn("Invalid JSON in request body", "err", err)
+			h.responseWriter.WriteError(w, http.StatusBadRequest, "Invalid JSON", "Request body must be valid JSON with an RFC 3339 until time", r)
+			return
+		}
+		resp, err = h.subscriptionService.PauseSubscription(r.Context(), token, id, req)
+	} else {
+		resp, err = h.subscriptionService.ResumeSubscription(r.Context(), token, id)
+	}
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Subscription action completed successfully", "action", action)
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+func (h *SubscriptionHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	token := manageToken(r)
+	if token == "" {
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Token is required", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.ExportData(r.Context(), token)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Data export request completed successfully")
+	w.Header().Set("Content-Disposition", `attachment; filename="weather-subscription-data.json"`)
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+func (h *SubscriptionHandler) EraseData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+		return
+	}
+
+	token := manageToken(r)
+	if token == "" {
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Token is required", r)
+		return
+	}
+
+	resp, err := h.subscriptionService.EraseData(r.Context(), token)
+	if err != nil {
+		h.handleServiceError(w, err, r)
+		return
+	}
+
+	logger := loggerPkg.From(r.Context())
+	logger.Debug("Data erasure request completed successfully")
+	h.responseWriter.WriteSuccess(w, resp)
+}
+
+// manageTarget extracts the subscription ID from the path and the magic link token from the request.
+func (h *SubscriptionHandler) manageTarget(w http.ResponseWriter, r *http.Request) (id, token string, ok bool) {
+	id = strings.TrimPrefix(r.URL.Path, "/api/subscriptions/")
+	if id == "" || id == r.URL.Path || strings.Contains(id, "/") {
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Validation failed", "Subscription ID is required", r)
+		return "", "", false
+	}
+
+	token = manageToken(r)
+	if token == "" {
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Token is required", r)
+		return "", "", false
+	}
+
+	return id, token, true
+}
+
+func manageToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return r.URL.Query().Get("token")
+}
+
 func (h *SubscriptionHandler) handleServiceError(w http.ResponseWriter, err error, r *http.Request) {
+	var apiErr *subscription.APIError
+	if errors.As(err, &apiErr) {
+		h.writeAPIError(w, apiErr, r)
+		return
+	}
+
 	errStr := err.Error()
 
 	switch {
 	case strings.Contains(errStr, "validation failed"):
 		h.responseWriter.WriteError(w, http.StatusBadRequest, "Validation failed", "Invalid input data", r)
-	case strings.Contains(errStr, "status: 400"):
-		h.responseWriter.WriteError(w, http.StatusBadRequest, "Bad request", "Invalid request data", r)
-	case strings.Contains(errStr, "status: 404"):
-		h.responseWriter.WriteError(w, http.StatusNotFound, "Not found", "Resource not found", r)
-	case strings.Contains(errStr, "status: 409"):
-		h.responseWriter.WriteError(w, http.StatusConflict, "Conflict", "Resource already exists", r)
-	case strings.Contains(errStr, "status: 500"):
-		h.responseWriter.WriteError(w, http.StatusInternalServerError, "Internal server error", "Service temporarily unavailable", r)
 	case strings.Contains(errStr, "context deadline exceeded"):
 		h.responseWriter.WriteError(w, http.StatusGatewayTimeout, "Gateway timeout", "Service request timeout", r)
 	case strings.Contains(errStr, "connection refused"):
@@ -154,3 +407,42 @@ func (h *SubscriptionHandler) handleServiceError(w http.ResponseWriter, err erro
 		h.responseWriter.WriteError(w, http.StatusInternalServerError, "Internal server error", "An unexpected error occurred", r)
 	}
 }
+
+// writeAPIError maps a failure reported by the subscription service, over either transport.
+func (h *SubscriptionHandler) writeAPIError(w http.ResponseWriter, apiErr *subscription.APIError, r *http.Request) {
+	switch apiErr.Code {
+	case subscription.CodeDisposableEmail:
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "disposable_email", "Disposable email addresses are not allowed", r)
+		return
+	case subscription.CodeUndeliverableEmail:
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "undeliverable_email", "Email domain cannot receive mail", r)
+		return
+	case subscription.CodeInvalidEmail:
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "invalid_email", "Invalid email address", r)
+		return
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest:
+		h.responseWriter.WriteError(w, http.StatusBadRequest, "Bad request", "Invalid request data", r)
+	case http.StatusUnauthorized:
+		h.responseWriter.WriteError(w, http.StatusUnauthorized, "Unauthorized", "Invalid or expired token", r)
+	case http.StatusNotFound:
+		h.responseWriter.WriteError(w, http.StatusNotFound, "Not found", "Resource not found", r)
+	case http.StatusConflict:
+		h.responseWriter.WriteError(w, http.StatusConflict, "Conflict", "Resource already exists", r)
+	case http.StatusTooManyRequests:
+		if apiErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(apiErr.RetryAfter.Seconds()))))
+		}
+		h.responseWriter.WriteError(w, http.StatusTooManyRequests, "Too many requests", "Too many attempts, please try again later", r)
+	case http.StatusServiceUnavailable:
+		h.responseWriter.WriteError(w, http.StatusServiceUnavailable, "Service unavailable", "Subscription service is unavailable", r)
+	case http.StatusGatewayTimeout:
+		h.responseWriter.WriteError(w, http.StatusGatewayTimeout, "Gateway timeout", "Service request timeout", r)
+	case http.StatusInternalServerError:
+		h.responseWriter.WriteError(w, http.StatusInternalServerError, "Internal server error", "Service temporarily unavailable", r)
+	default:
+		h.responseWriter.WriteError(w, http.StatusInternalServerError, "Internal server error", "An unexpected error occurred", r)
+	}
+}
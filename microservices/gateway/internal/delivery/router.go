@@ -22,14 +22,36 @@ func SetupRoutes(handler *SubscriptionHandler, cfg *config.Config, ctx context.C
 		"Confirm":     handler.Confirm,
 		"Unsubscribe": handler.Unsubscribe,
 		"GetWeather":  handler.GetWeather,
+
+		"OneClickUnsubscribe": handler.OneClickUnsubscribe,
+
+		"RequestManageLink":  handler.RequestManageLink,
+		"ListSubscriptions":  handler.ListSubscriptions,
+		"UpdateSubscription": handler.UpdateSubscription,
+		"DeleteSubscription": handler.DeleteSubscription,
+		"SubscriptionAction": handler.SubscriptionAction,
+
+		"ExportData": handler.ExportData,
+		"EraseData":  handler.EraseData,
 	}
 
+	// Several routes may share a path with different methods, so group them before registering.
+	paths := make([]string, 0, len(cfg.Routes))
+	byPath := make(map[string]map[string]http.HandlerFunc)
 	for _, rt := range cfg.Routes {
 		hf, ok := handlers[rt.Handler]
 		if !ok {
 			continue
 		}
-		mux.HandleFunc(rt.Path, hf)
+		if _, seen := byPath[rt.Path]; !seen {
+			paths = append(paths, rt.Path)
+			byPath[rt.Path] = make(map[string]http.HandlerFunc)
+		}
+		byPath[rt.Path][strings.ToUpper(rt.Method)] = hf
+	}
+
+	for _, path := range paths {
+		mux.HandleFunc(path, methodHandler(handler, byPath[path]))
 	}
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -53,10 +75,29 @@ func SetupRoutes(handler *SubscriptionHandler, cfg *config.Config, ctx context.C
 	finalHandler = middleware.Logging()(finalHandler)
 	finalHandler = middleware.WithLogger(logger)(finalHandler)
 	finalHandler = middleware.RequestID()(finalHandler)
+	finalHandler = middleware.ClientIP()(finalHandler)
+	finalHandler = middleware.AcceptLanguage()(finalHandler)
 
 	return finalHandler
 }
 
+func methodHandler(handler *SubscriptionHandler, byMethod map[string]http.HandlerFunc) http.HandlerFunc {
+	if len(byMethod) == 1 {
+		for _, hf := range byMethod {
+			return hf
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		hf, ok := byMethod[r.Method]
+		if !ok {
+			handler.responseWriter.WriteError(w, http.StatusMethodNotAllowed, "Method not allowed", "", r)
+			return
+		}
+		hf(w, r)
+	}
+}
+
 func generateAPIDocs(cfg *config.Config) any {
 	type ep struct {
 		Method string `json:"method"`
@@ -66,6 +107,9 @@ func generateAPIDocs(cfg *config.Config) any {
 	endpoints := make(map[string]ep, len(cfg.Routes))
 	for _, rt := range cfg.Routes {
 		key := strings.TrimPrefix(rt.Path, "/api/")
+		if _, exists := endpoints[key]; exists {
+			key += " " + rt.Method
+		}
 		endpoints[key] = ep{
 			Method: rt.Method,
 			Path:   rt.Path,
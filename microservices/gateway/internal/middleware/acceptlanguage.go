@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+const AcceptLanguageKey = "acceptLanguage"
+
+// AcceptLanguage stores the caller's Accept-Language header so the subscription service can
+// pick the language of emails and weather descriptions.
+func AcceptLanguage() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), AcceptLanguageKey, r.Header.Get("Accept-Language"))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func GetAcceptLanguage(ctx context.Context) string {
+	if v := ctx.Value(AcceptLanguageKey); v != nil {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
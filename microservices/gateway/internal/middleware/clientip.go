@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+const ClientIPKey = "clientIP"
+
+// ClientIP stores the caller's address so downstream services can rate limit per client
+// instead of seeing every request come from the gateway.
+func ClientIP() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			ctx := context.WithValue(r.Context(), ClientIPKey, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func GetClientIP(ctx context.Context) string {
+	if v := ctx.Value(ClientIPKey); v != nil {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"gateway/internal/adapter/subscription"
@@ -19,7 +20,16 @@ type SubscriptionClient interface {
 	Subscribe(ctx context.Context, req subscription.SubscribeRequest) (*subscription.SubscribeResponse, error)
 	Confirm(ctx context.Context, token string) (*subscription.ConfirmResponse, error)
 	Unsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error)
+	OneClickUnsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error)
 	GetWeather(ctx context.Context, city string) (*subscription.WeatherResponse, error)
+	RequestManageLink(ctx context.Context, req subscription.ManageLinkRequest) (*subscription.MessageResponse, error)
+	ListSubscriptions(ctx context.Context, token string) (*subscription.ListSubscriptionsResponse, error)
+	UpdateSubscription(ctx context.Context, token, id string, req subscription.UpdateSubscriptionRequest) (*subscription.SubscriptionView, error)
+	DeleteSubscription(ctx context.Context, token, id string) (*subscription.MessageResponse, error)
+	PauseSubscription(ctx context.Context, token, id string, req subscription.PauseSubscriptionRequest) (*subscription.SubscriptionView, error)
+	ResumeSubscription(ctx context.Context, token, id string) (*subscription.SubscriptionView, error)
+	ExportData(ctx context.Context, token string) (json.RawMessage, error)
+	EraseData(ctx context.Context, token string) (*subscription.MessageResponse, error)
 }
 
 type Service struct {
@@ -43,6 +53,7 @@ func (s *Service) Subscribe(ctx context.Context, req subscription.SubscribeReque
 	req.Email = s.securityValidator.SanitizeInput(req.Email)
 	req.City = s.securityValidator.SanitizeInput(req.City)
 	req.Frequency = s.securityValidator.SanitizeInput(req.Frequency)
+	req.Locale = s.securityValidator.SanitizeInput(req.Locale)
 
 	if err := s.securityValidator.ValidateCity(req.City); err != nil {
 		logger.Warn("Security validation failed", "validation_error", err, "city", req.City)
@@ -95,6 +106,24 @@ func (s *Service) Unsubscribe(ctx context.Context, token string) (*subscription.
 	return resp, nil
 }
 
+func (s *Service) OneClickUnsubscribe(ctx context.Context, token string) (*subscription.UnsubscribeResponse, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.OneClickUnsubscribe(ctx, token)
+	if err != nil {
+		logger.Error("One-click unsubscribe service call failed", "err", err)
+		return nil, fmt.Errorf("unsubscribe service failed: %w", err)
+	}
+
+	logger.Debug("One-click unsubscribe successful")
+	return resp, nil
+}
+
 func (s *Service) GetWeather(ctx context.Context, city string) (*subscription.WeatherResponse, error) {
 	logger := loggerPkg.From(ctx)
 
@@ -113,3 +142,162 @@ func (s *Service) GetWeather(ctx context.Context, city string) (*subscription.We
 	logger.Debug("Weather fetch successful", "city", city)
 	return resp, nil
 }
+
+func (s *Service) RequestManageLink(ctx context.Context, req subscription.ManageLinkRequest) (*subscription.MessageResponse, error) {
+	logger := loggerPkg.From(ctx)
+
+	req.Email = s.securityValidator.SanitizeInput(req.Email)
+
+	resp, err := s.subscriptionClient.RequestManageLink(ctx, req)
+	if err != nil {
+		logger.Error("Manage link service call failed", "err", err)
+		return nil, fmt.Errorf("manage link service failed: %w", err)
+	}
+
+	logger.Debug("Manage link requested")
+	return resp, nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context, token string) (*subscription.ListSubscriptionsResponse, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.ListSubscriptions(ctx, token)
+	if err != nil {
+		logger.Error("List subscriptions service call failed", "err", err)
+		return nil, fmt.Errorf("list subscriptions service failed: %w", err)
+	}
+
+	logger.Debug("Subscriptions listed", "count", len(resp.Subscriptions))
+	return resp, nil
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, token, id string, req subscription.UpdateSubscriptionRequest) (*subscription.SubscriptionView, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	if req.City != nil {
+		city := s.securityValidator.SanitizeInput(*req.City)
+		if err := s.securityValidator.ValidateCity(city); err != nil {
+			logger.Warn("City validation failed", "validation_error", err, "city", city)
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
+		req.City = &city
+	}
+	if req.Frequency != nil {
+		frequency := s.securityValidator.SanitizeInput(*req.Frequency)
+		req.Frequency = &frequency
+	}
+	if req.QuietHours != nil {
+		quiet := *req.QuietHours
+		quiet.Timezone = s.securityValidator.SanitizeInput(quiet.Timezone)
+		req.QuietHours = &quiet
+	}
+
+	resp, err := s.subscriptionClient.UpdateSubscription(ctx, token, s.securityValidator.SanitizeInput(id), req)
+	if err != nil {
+		logger.Error("Update subscription service call failed", "err", err)
+		return nil, fmt.Errorf("update subscription service failed: %w", err)
+	}
+
+	logger.Debug("Subscription updated")
+	return resp, nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, token, id string) (*subscription.MessageResponse, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.DeleteSubscription(ctx, token, s.securityValidator.SanitizeInput(id))
+	if err != nil {
+		logger.Error("Delete subscription service call failed", "err", err)
+		return nil, fmt.Errorf("delete subscription service failed: %w", err)
+	}
+
+	logger.Debug("Subscription deleted")
+	return resp, nil
+}
+
+func (s *Service) PauseSubscription(ctx context.Context, token, id string, req subscription.PauseSubscriptionRequest) (*subscription.SubscriptionView, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.PauseSubscription(ctx, token, s.securityValidator.SanitizeInput(id), req)
+	if err != nil {
+		logger.Error("Pause subscription service call failed", "err", err)
+		return nil, fmt.Errorf("pause subscription service failed: %w", err)
+	}
+
+	logger.Debug("Subscription paused", "until", req.Until)
+	return resp, nil
+}
+
+func (s *Service) ResumeSubscription(ctx context.Context, token, id string) (*subscription.SubscriptionView, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.ResumeSubscription(ctx, token, s.securityValidator.SanitizeInput(id))
+	if err != nil {
+		logger.Error("Resume subscription service call failed", "err", err)
+		return nil, fmt.Errorf("resume subscription service failed: %w", err)
+	}
+
+	logger.Debug("Subscription resumed")
+	return resp, nil
+}
+
+func (s *Service) ExportData(ctx context.Context, token string) (json.RawMessage, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.ExportData(ctx, token)
+	if err != nil {
+		logger.Error("Data export service call failed", "err", err)
+		return nil, fmt.Errorf("data export service failed: %w", err)
+	}
+
+	logger.Debug("Personal data exported")
+	return resp, nil
+}
+
+func (s *Service) EraseData(ctx context.Context, token string) (*subscription.MessageResponse, error) {
+	logger := loggerPkg.From(ctx)
+
+	if err := s.securityValidator.ValidateToken(token); err != nil {
+		logger.Warn("Token validation failed", "validation_error", err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	resp, err := s.subscriptionClient.EraseData(ctx, token)
+	if err != nil {
+		logger.Error("Data erasure service call failed", "err", err)
+		return nil, fmt.Errorf("data erasure service failed: %w", err)
+	}
+
+	logger.Debug("Personal data erased")
+	return resp, nil
+}
@@ -12,7 +12,8 @@ func NewSecurityValidator() SecurityValidator {
 }
 
 func (v *securityValidator) ValidateToken(token string) error {
-	if len(token) < 8 || len(token) > 128 {
+	// Signed JWTs easily exceed 128 characters, so leave enough headroom for them.
+	if len(token) < 8 || len(token) > 512 {
 		return fmt.Errorf("invalid token length")
 	}
 
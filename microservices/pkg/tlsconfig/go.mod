@@ -0,0 +1,3 @@
+module github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig
+
+go 1.24.3
@@ -0,0 +1,278 @@
+// Package tlsconfig builds server and client TLS configs from PEM files and keeps them in step
+// with the files on disk, so rotated certificates are picked up without a restart.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrSANNotAllowed   = errors.New("peer certificate SAN not allowed")
+	ErrNoPeerCert      = errors.New("no peer certificate")
+	ErrInvalidCAFile   = errors.New("no certificates found in CA file")
+	ErrIncompleteFiles = errors.New("certificate and key files must be set together")
+)
+
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// CAFile verifies the peer: client certificates on a server, which turns on mutual TLS,
+	// and the server certificate on a client, where the system roots are used when empty.
+	CAFile string
+	// ServerName overrides the name a client expects in the server certificate.
+	ServerName string
+	// AllowedSANs restricts which client certificates a server accepts. Entries match DNS
+	// names, IP addresses, URIs or email addresses exactly; "*.example.com" matches any
+	// subdomain. Empty allows every certificate signed by the CA.
+	AllowedSANs []string
+	// ReloadInterval is how often Watch checks the files for changes.
+	ReloadInterval time.Duration
+}
+
+// Source holds the current certificate and CA pool and hands them out to TLS handshakes.
+// Connections established before a reload keep the credentials they were opened with.
+type Source struct {
+	opts Options
+
+	cert atomic.Pointer[tls.Certificate]
+	pool atomic.Pointer[x509.CertPool]
+
+	mu       sync.Mutex
+	versions map[string]fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// Load reads the files once and fails when any of them is missing or invalid.
+func Load(opts Options) (*Source, error) {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, ErrIncompleteFiles
+	}
+	if len(opts.AllowedSANs) > 0 && opts.CAFile == "" {
+		return nil, errors.New("SAN allowlist requires a CA file to verify client certificates")
+	}
+
+	s := &Source{opts: opts}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the files again. On error the previous credentials stay in use.
+func (s *Source) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.stat()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if s.opts.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(s.opts.CertFile, s.opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if s.opts.CAFile != "" {
+		pem, err := os.ReadFile(s.opts.CAFile)
+		if err != nil {
+			return fmt.Errorf("read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w: %s", ErrInvalidCAFile, s.opts.CAFile)
+		}
+	}
+
+	s.cert.Store(cert)
+	s.pool.Store(pool)
+	s.versions = versions
+	return nil
+}
+
+// Watch polls the files every ReloadInterval and reloads them when one changes, until ctx
+// is done. report is called with the outcome of every reload attempt.
+func (s *Source) Watch(ctx context.Context, report func(error)) {
+	if s.opts.ReloadInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.opts.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.changed() {
+				continue
+			}
+			report(s.Reload())
+		}
+	}
+}
+
+// ServerConfig returns a config for a TLS server. With a CA file, clients must present a
+// certificate signed by it and, with an allowlist, carrying an allowed SAN.
+func (s *Source) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: s.getCertificate,
+			}
+			if pool := s.pool.Load(); pool != nil {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = pool
+				cfg.VerifyConnection = s.verifyClientSAN
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig returns a config for a TLS client, presenting the certificate when one is
+// configured.
+func (s *Source) ClientConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: s.opts.ServerName,
+	}
+	if s.opts.CertFile != "" {
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return s.cert.Load(), nil
+		}
+	}
+	if s.opts.CAFile != "" {
+		// The standard verification reads RootCAs once per config; doing it here lets a
+		// reloaded CA take effect on the next connection.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = s.verifyServer
+	}
+	return cfg
+}
+
+func (s *Source) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := s.cert.Load()
+	if cert == nil {
+		return nil, errors.New("no server certificate configured")
+	}
+	return cert, nil
+}
+
+func (s *Source) verifyServer(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return ErrNoPeerCert
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         s.pool.Load(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func (s *Source) verifyClientSAN(cs tls.ConnectionState) error {
+	if len(s.opts.AllowedSANs) == 0 {
+		return nil
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return ErrNoPeerCert
+	}
+	leaf := cs.PeerCertificates[0]
+	for _, san := range sans(leaf) {
+		if s.sanAllowed(san) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrSANNotAllowed, sans(leaf))
+}
+
+func (s *Source) sanAllowed(san string) bool {
+	for _, allowed := range s.opts.AllowedSANs {
+		if suffix, ok := strings.CutPrefix(strings.ToLower(allowed), "*"); ok && strings.HasPrefix(suffix, ".") {
+			label, found := strings.CutSuffix(strings.ToLower(san), suffix)
+			if found && label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(san, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func sans(cert *x509.Certificate) []string {
+	names := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return append(names, cert.EmailAddresses...)
+}
+
+func (s *Source) files() []string {
+	var files []string
+	for _, f := range []string{s.opts.CertFile, s.opts.KeyFile, s.opts.CAFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (s *Source) stat() (map[string]fileVersion, error) {
+	versions := make(map[string]fileVersion)
+	for _, f := range s.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, err
+		}
+		versions[f] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+	}
+	return versions, nil
+}
+
+func (s *Source) changed() bool {
+	versions, err := s.stat()
+	if err != nil {
+		// A file being replaced may be missing for a moment; try again on the next tick.
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for f, v := range versions {
+		if prev := s.versions[f]; !prev.modTime.Equal(v.modTime) || prev.size != v.size {
+			return true
+		}
+	}
+	return false
+}
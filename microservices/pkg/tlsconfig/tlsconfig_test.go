@@ -0,0 +1,239 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig/certtest"
+)
+
+// handshake connects a client to a server and returns the errors each side saw.
+func handshake(t *testing.T, server, client *Source) (serverErr, clientErr error) {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client.ClientConfig())
+	if err == nil {
+		conn.Close()
+	}
+	return <-done, err
+}
+
+func mustLoad(t *testing.T, opts Options) *Source {
+	t.Helper()
+	s, err := Load(opts)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return s
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	serverFiles := ca.Issue(t, "weather", "localhost", "127.0.0.1")
+	allowedFiles := ca.Issue(t, "subscription", "subscription.svc.local")
+	otherFiles := ca.Issue(t, "gateway", "gateway.svc.local")
+
+	server := mustLoad(t, Options{
+		CertFile:    serverFiles.CertFile,
+		KeyFile:     serverFiles.KeyFile,
+		CAFile:      ca.CAFile,
+		AllowedSANs: []string{"*.svc.other", "subscription.svc.local"},
+	})
+
+	t.Run("allowed client", func(t *testing.T) {
+		client := mustLoad(t, Options{
+			CertFile:   allowedFiles.CertFile,
+			KeyFile:    allowedFiles.KeyFile,
+			CAFile:     ca.CAFile,
+			ServerName: "localhost",
+		})
+		serverErr, clientErr := handshake(t, server, client)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("handshake failed: server %v, client %v", serverErr, clientErr)
+		}
+	})
+
+	t.Run("SAN not in allowlist", func(t *testing.T) {
+		client := mustLoad(t, Options{
+			CertFile:   otherFiles.CertFile,
+			KeyFile:    otherFiles.KeyFile,
+			CAFile:     ca.CAFile,
+			ServerName: "localhost",
+		})
+		serverErr, _ := handshake(t, server, client)
+		if !errors.Is(serverErr, ErrSANNotAllowed) {
+			t.Fatalf("expected ErrSANNotAllowed, got %v", serverErr)
+		}
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		client := mustLoad(t, Options{CAFile: ca.CAFile, ServerName: "localhost"})
+		serverErr, _ := handshake(t, server, client)
+		if serverErr == nil {
+			t.Fatal("expected the server to reject a client without a certificate")
+		}
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		otherCA := certtest.NewCA(t, "other")
+		client := mustLoad(t, Options{
+			CertFile:   allowedFiles.CertFile,
+			KeyFile:    allowedFiles.KeyFile,
+			CAFile:     otherCA.CAFile,
+			ServerName: "localhost",
+		})
+		_, clientErr := handshake(t, server, client)
+		if clientErr == nil {
+			t.Fatal("expected the client to reject a server signed by an unknown CA")
+		}
+	})
+
+	t.Run("wrong server name", func(t *testing.T) {
+		client := mustLoad(t, Options{
+			CertFile:   allowedFiles.CertFile,
+			KeyFile:    allowedFiles.KeyFile,
+			CAFile:     ca.CAFile,
+			ServerName: "weather.example.com",
+		})
+		_, clientErr := handshake(t, server, client)
+		if clientErr == nil {
+			t.Fatal("expected the client to reject a certificate for another host")
+		}
+	})
+}
+
+func TestServerTLSWithoutClientAuth(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	serverFiles := ca.Issue(t, "weather", "localhost")
+
+	server := mustLoad(t, Options{CertFile: serverFiles.CertFile, KeyFile: serverFiles.KeyFile})
+	client := mustLoad(t, Options{CAFile: ca.CAFile, ServerName: "localhost"})
+
+	serverErr, clientErr := handshake(t, server, client)
+	if serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake failed: server %v, client %v", serverErr, clientErr)
+	}
+}
+
+func TestWatchReloadsRotatedCertificate(t *testing.T) {
+	oldCA := certtest.NewCA(t, "old")
+	newCA := certtest.NewCA(t, "new")
+	serverFiles := oldCA.Issue(t, "weather", "localhost")
+
+	server := mustLoad(t, Options{
+		CertFile:       serverFiles.CertFile,
+		KeyFile:        serverFiles.KeyFile,
+		ReloadInterval: 10 * time.Millisecond,
+	})
+	client := mustLoad(t, Options{CAFile: newCA.CAFile, ServerName: "localhost"})
+
+	if _, clientErr := handshake(t, server, client); clientErr == nil {
+		t.Fatal("expected the client to reject the certificate before rotation")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan error, 1)
+	go server.Watch(ctx, func(err error) { reloaded <- err })
+
+	newCA.IssueTo(t, serverFiles, "weather", "localhost")
+	// Make the change visible even on file systems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{serverFiles.CertFile, serverFiles.KeyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("certificate was not reloaded")
+	}
+
+	if serverErr, clientErr := handshake(t, server, client); serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake after rotation failed: server %v, client %v", serverErr, clientErr)
+	}
+}
+
+func TestReloadKeepsPreviousCertificateOnError(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	serverFiles := ca.Issue(t, "weather", "localhost")
+	server := mustLoad(t, Options{CertFile: serverFiles.CertFile, KeyFile: serverFiles.KeyFile})
+
+	if err := os.WriteFile(serverFiles.KeyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := server.Reload(); err == nil {
+		t.Fatal("expected reload to fail on a broken key")
+	}
+
+	client := mustLoad(t, Options{CAFile: ca.CAFile, ServerName: "localhost"})
+	if serverErr, clientErr := handshake(t, server, client); serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake failed: server %v, client %v", serverErr, clientErr)
+	}
+}
+
+func TestLoadRejectsInvalidOptions(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	files := ca.Issue(t, "weather", "localhost")
+
+	tests := map[string]Options{
+		"cert without key":        {CertFile: files.CertFile},
+		"allowlist without CA":    {CertFile: files.CertFile, KeyFile: files.KeyFile, AllowedSANs: []string{"a"}},
+		"missing CA file":         {CAFile: files.CertFile + ".missing"},
+		"CA file without a cert":  {CAFile: files.KeyFile},
+		"key does not match cert": {CertFile: files.CertFile, KeyFile: ca.Issue(t, "other").KeyFile},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(opts); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestSANAllowed(t *testing.T) {
+	s := &Source{opts: Options{AllowedSANs: []string{"*.svc.local", "spiffe://cluster/subscription", "10.0.0.5"}}}
+
+	tests := map[string]bool{
+		"subscription.svc.local":        true,
+		"Subscription.SVC.local":        true,
+		"a.b.svc.local":                 false,
+		"svc.local":                     false,
+		"spiffe://cluster/subscription": true,
+		"spiffe://cluster/gateway":      false,
+		"10.0.0.5":                      true,
+		"10.0.0.6":                      false,
+	}
+	for san, want := range tests {
+		if got := s.sanAllowed(san); got != want {
+			t.Errorf("sanAllowed(%q) = %v, want %v", san, got, want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+	// The runtime image has no zoneinfo; quiet hours need the subscriber's timezone.
+	_ "time/tzdata"
 
 	"subscription/internal/app"
 
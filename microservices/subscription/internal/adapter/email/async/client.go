@@ -3,12 +3,19 @@ package async
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
 
 	"subscription/internal/domain"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/google/uuid"
 )
 
+// RoutingKeyPurgeAddress routes erasure requests to the email service.
+const RoutingKeyPurgeAddress = "email.purge_address"
+
 type Publisher interface {
 	Publish(ctx context.Context, routingKey string, msg IdKeyGetter) error
 }
@@ -27,12 +34,14 @@ type EmailMessage struct {
 	CorrelationID string            `json:"correlation_id"` //nolint:tagliatelle
 	To            string            `json:"to"`
 	Template      string            `json:"template"`
-	Data          map[string]string `json:"data"`
+	Locale        string            `json:"locale,omitempty"`
+	Data          map[string]any    `json:"data"`
+	Headers       map[string]string `json:"headers,omitempty"`
 }
 
 func (m EmailMessage) GetIdKey() string { return m.IdKey }
 
-func (c *Client) SendConfirmationEmail(ctx context.Context, email, token, idKey string) error {
+func (c *Client) SendConfirmationEmail(ctx context.Context, email, locale, token, idKey string) error {
 	confirmURL := fmt.Sprintf("%s/api/confirm/%s", c.baseURL, token)
 
 	msg := EmailMessage{
@@ -40,12 +49,13 @@ func (c *Client) SendConfirmationEmail(ctx context.Context, email, token, idKey
 		CorrelationID: loggerPkg.GetCorrelationID(ctx),
 		To:            email,
 		Template:      "confirmation",
-		Data:          map[string]string{"confirm_url": confirmURL},
+		Locale:        locale,
+		Data:          map[string]any{"confirm_url": confirmURL},
 	}
 	return c.publisher.Publish(ctx, "email.confirmation", msg)
 }
 
-func (c *Client) SendWeatherReport(ctx context.Context, email string, weather domain.Report, city, token, idKey string) error {
+func (c *Client) SendWeatherReport(ctx context.Context, email, locale string, weather domain.Report, city, token, idKey string) error {
 	unsubscribeURL := fmt.Sprintf("%s/api/unsubscribe/%s", c.baseURL, token)
 
 	msg := EmailMessage{
@@ -53,13 +63,100 @@ func (c *Client) SendWeatherReport(ctx context.Context, email string, weather do
 		CorrelationID: loggerPkg.GetCorrelationID(ctx),
 		To:            email,
 		Template:      "weather_report",
-		Data: map[string]string{
+		Locale:        locale,
+		Data: map[string]any{
 			"temperature":     fmt.Sprintf("%.2f", weather.Temperature),
 			"humidity":        fmt.Sprintf("%d", weather.Humidity),
 			"description":     weather.Description,
 			"city":            city,
 			"unsubscribe_url": unsubscribeURL,
 		},
+		// RFC 8058: mailbox providers POST "List-Unsubscribe=One-Click" to the same URL.
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+	return c.publisher.Publish(ctx, "email.weather_report", msg)
+}
+
+// SendWeatherDigest sends the reports of several cities in one email. Each city links to the
+// unsubscribe of its own subscription; the email has no List-Unsubscribe header, since a
+// one-click unsubscribe could only cancel one of them.
+func (c *Client) SendWeatherDigest(ctx context.Context, email, locale string, entries []domain.DigestEntry, idKey string) error {
+	cities := make([]map[string]string, 0, len(entries))
+	for _, e := range entries {
+		cities = append(cities, map[string]string{
+			"city":            e.City,
+			"temperature":     fmt.Sprintf("%.2f", e.Report.Temperature),
+			"humidity":        fmt.Sprintf("%d", e.Report.Humidity),
+			"description":     e.Report.Description,
+			"unsubscribe_url": fmt.Sprintf("%s/api/unsubscribe/%s", c.baseURL, e.Token),
+		})
+	}
+
+	msg := EmailMessage{
+		IdKey:         idKey,
+		CorrelationID: loggerPkg.GetCorrelationID(ctx),
+		To:            email,
+		Template:      "weather_digest",
+		Locale:        locale,
+		Data:          map[string]any{"cities": cities},
 	}
 	return c.publisher.Publish(ctx, "email.weather_report", msg)
 }
+
+func (c *Client) SendManageLink(ctx context.Context, email, locale, token, idKey string) error {
+	manageURL := fmt.Sprintf("%s/manage?token=%s", c.baseURL, url.QueryEscape(token))
+
+	msg := EmailMessage{
+		IdKey:         idKey,
+		CorrelationID: loggerPkg.GetCorrelationID(ctx),
+		To:            email,
+		Template:      "manage_link",
+		Locale:        locale,
+		Data:          map[string]any{"manage_url": manageURL},
+	}
+	return c.publisher.Publish(ctx, "email.manage_link", msg)
+}
+
+// SendConfirmationReminder re-sends the confirmation link to a pending subscription.
+// A non-zero expiresAt tells the recipient when the unconfirmed subscription will be removed.
+func (c *Client) SendConfirmationReminder(ctx context.Context, email, locale, token string, expiresAt time.Time, idKey string) error {
+	confirmURL := fmt.Sprintf("%s/api/confirm/%s", c.baseURL, token)
+
+	data := map[string]any{"confirm_url": confirmURL}
+	if !expiresAt.IsZero() {
+		data["expires_at"] = expiresAt.UTC().Format("02.01.2006 15:04 UTC")
+	}
+
+	msg := EmailMessage{
+		IdKey:         idKey,
+		CorrelationID: loggerPkg.GetCorrelationID(ctx),
+		To:            email,
+		Template:      "confirmation_reminder",
+		Locale:        locale,
+		Data:          data,
+	}
+	return c.publisher.Publish(ctx, "email.confirmation", msg)
+}
+
+// PurgeMessage asks the email service to drop everything it keeps for an address.
+type PurgeMessage struct {
+	IdKey         string `json:"-"`
+	CorrelationID string `json:"correlation_id"` //nolint:tagliatelle
+	Email         string `json:"email"`
+}
+
+func (m PurgeMessage) GetIdKey() string { return m.IdKey }
+
+// PurgeAddress is keyed by a random id so the message id never carries the address
+// that is being erased.
+func (c *Client) PurgeAddress(ctx context.Context, email string) error {
+	msg := PurgeMessage{
+		IdKey:         "purge:" + uuid.NewString(),
+		CorrelationID: loggerPkg.GetCorrelationID(ctx),
+		Email:         email,
+	}
+	return c.publisher.Publish(ctx, RoutingKeyPurgeAddress, msg)
+}
@@ -0,0 +1,52 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturingPublisher struct {
+	routingKey string
+	msg        IdKeyGetter
+}
+
+func (p *capturingPublisher) Publish(_ context.Context, routingKey string, msg IdKeyGetter) error {
+	p.routingKey, p.msg = routingKey, msg
+	return nil
+}
+
+func TestClient_SendWeatherDigest(t *testing.T) {
+	publisher := &capturingPublisher{}
+	client := NewAsyncClient(publisher, "https://weather.example.com")
+
+	err := client.SendWeatherDigest(context.Background(), "a@example.com", "en", []domain.DigestEntry{
+		{City: "Kyiv", Report: domain.Report{Temperature: 21.5, Humidity: 40, Description: "Sunny"}, Token: "t1"},
+		{City: "Lviv", Report: domain.Report{Temperature: 17, Humidity: 65, Description: "Rain"}, Token: "t2"},
+	}, "digest:a@example.com:2025-01-01T12")
+
+	require.NoError(t, err)
+	assert.Equal(t, "email.weather_report", publisher.routingKey)
+	assert.Equal(t, "digest:a@example.com:2025-01-01T12", publisher.msg.GetIdKey())
+
+	// The email service renders the weather_digest template from this payload.
+	body, err := json.Marshal(publisher.msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"correlation_id": "",
+		"to": "a@example.com",
+		"template": "weather_digest",
+		"locale": "en",
+		"data": {"cities": [
+			{"city": "Kyiv", "temperature": "21.50", "humidity": "40", "description": "Sunny",
+			 "unsubscribe_url": "https://weather.example.com/api/unsubscribe/t1"},
+			{"city": "Lviv", "temperature": "17.00", "humidity": "65", "description": "Rain",
+			 "unsubscribe_url": "https://weather.example.com/api/unsubscribe/t2"}
+		]}
+	}`, string(body))
+}
@@ -0,0 +1,52 @@
+package async
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PublisherMetrics struct {
+	published *prometheus.CounterVec
+	retries   prometheus.Counter
+	channels  prometheus.Counter
+	once      sync.Once
+}
+
+func NewPublisherMetrics() *PublisherMetrics {
+	return &PublisherMetrics{
+		published: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_rabbitmq_publish_total",
+				Help: "RabbitMQ publish attempts by outcome: acked, nacked, returned, timeout or error",
+			},
+			[]string{"result"},
+		),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "subscription_rabbitmq_publish_retries_total",
+			Help: "RabbitMQ publishes retried after a failed attempt",
+		}),
+		channels: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "subscription_rabbitmq_publisher_channels_opened_total",
+			Help: "Confirm mode channels opened by the publisher, including replacements for broken ones",
+		}),
+	}
+}
+
+func (m *PublisherMetrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.published, m.retries, m.channels)
+	})
+}
+
+func (m *PublisherMetrics) RecordPublish(result string) {
+	m.published.WithLabelValues(result).Inc()
+}
+
+func (m *PublisherMetrics) RecordRetry() {
+	m.retries.Inc()
+}
+
+func (m *PublisherMetrics) RecordChannelOpened() {
+	m.channels.Inc()
+}
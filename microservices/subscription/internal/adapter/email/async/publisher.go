@@ -3,7 +3,10 @@ package async
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
@@ -11,17 +14,100 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-type RabbitPublisher struct {
-	channel  *amqp.Channel
-	exchange string
-}
+var (
+	ErrPublishNacked  = errors.New("rabbitmq nacked the message")
+	ErrUnroutable     = errors.New("message is unroutable")
+	ErrConfirmTimeout = errors.New("timed out waiting for publisher confirm")
+	errChannelClosed  = errors.New("channel closed while waiting for confirm")
+)
+
+// Publish outcomes, used as metric labels.
+const (
+	PublishAcked    = "acked"
+	PublishNacked   = "nacked"
+	PublishReturned = "returned"
+	PublishTimeout  = "timeout"
+	PublishError    = "error"
+)
 
 type IdKeyGetter interface {
 	GetIdKey() string
 }
 
-func NewRabbitPublisher(ch *amqp.Channel, exchange string) *RabbitPublisher {
-	return &RabbitPublisher{channel: ch, exchange: exchange}
+// ExchangeGetter is implemented by messages bound for an exchange other than the publisher's.
+type ExchangeGetter interface {
+	GetExchange() string
+}
+
+// HeadersGetter is implemented by messages that carry AMQP headers.
+type HeadersGetter interface {
+	GetHeaders() map[string]string
+}
+
+// ConfirmChannel is the part of *amqp.Channel the publisher needs.
+type ConfirmChannel interface {
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	NotifyReturn(c chan amqp.Return) chan amqp.Return
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
+// channelOpener hands out channels on a connection that is redialed when lost.
+type channelOpener interface {
+	OpenChannel(ctx context.Context) (*amqp.Channel, error)
+}
+
+type publisherMetrics interface {
+	RecordPublish(result string)
+	RecordRetry()
+	RecordChannelOpened()
+}
+
+type PublisherConfig struct {
+	// ConfirmTimeout bounds the wait for the broker to ack a publish.
+	ConfirmTimeout time.Duration
+	// MaxAttempts is how many times a publish is tried before the error is returned.
+	MaxAttempts int
+	// RetryDelay is the pause before the second attempt; it doubles for every further one.
+	RetryDelay time.Duration
+	// OptionalRouting lists the exchanges whose messages may have no queue bound, like the
+	// lifecycle events exchange before anyone subscribes. They are published without mandatory
+	// routing, so the broker drops them instead of returning them as unroutable.
+	OptionalRouting []string
+}
+
+func DefaultPublisherConfig() PublisherConfig {
+	return PublisherConfig{ConfirmTimeout: 5 * time.Second, MaxAttempts: 3, RetryDelay: 200 * time.Millisecond}
+}
+
+// RabbitPublisher publishes on a channel in confirm mode and waits for each message to be acked.
+// Messages are published with mandatory routing unless their exchange is in OptionalRouting.
+// A broken channel is replaced on the next attempt.
+//
+// Publishes are serialised, so a confirm or a returned message always belongs to the message
+// in flight.
+type RabbitPublisher struct {
+	open     func(ctx context.Context) (ConfirmChannel, error)
+	exchange string
+	metrics  publisherMetrics
+	cfg      PublisherConfig
+
+	mu       sync.Mutex
+	channel  ConfirmChannel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+}
+
+func NewRabbitPublisher(conn channelOpener, exchange string, metrics publisherMetrics, cfg PublisherConfig) *RabbitPublisher {
+	open := func(ctx context.Context) (ConfirmChannel, error) {
+		ch, err := conn.OpenChannel(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ch, nil
+	}
+	return &RabbitPublisher{open: open, exchange: exchange, metrics: metrics, cfg: cfg}
 }
 
 func (p *RabbitPublisher) Publish(ctx context.Context, routingKey string, msg IdKeyGetter) error {
@@ -37,12 +123,151 @@ func (p *RabbitPublisher) Publish(ctx context.Context, routingKey string, msg Id
 		DeliveryMode: amqp.Persistent,
 		MessageId:    msg.GetIdKey(),
 	}
+	if h, ok := msg.(HeadersGetter); ok && len(h.GetHeaders()) > 0 {
+		pub.Headers = amqp.Table{}
+		for k, v := range h.GetHeaders() {
+			pub.Headers[k] = v
+		}
+	}
 
-	if err := p.channel.PublishWithContext(ctx, p.exchange, routingKey, false, false, pub); err != nil {
-		return fmt.Errorf("rabbitmq publish error: %w", err)
+	exchange := p.exchange
+	if e, ok := msg.(ExchangeGetter); ok && e.GetExchange() != "" {
+		exchange = e.GetExchange()
 	}
 
 	logger := loggerPkg.From(ctx)
-	logger.Info("Published message", "routingKey", routingKey, "msgId", msg.GetIdKey())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delay := p.cfg.RetryDelay
+	for attempt := 1; ; attempt++ {
+		err = p.publishOnce(ctx, exchange, routingKey, p.mandatory(exchange), pub)
+		p.metrics.RecordPublish(publishResult(err))
+		if err == nil {
+			logger.Info("Published message", "exchange", exchange, "routingKey", routingKey, "msgId", msg.GetIdKey())
+			return nil
+		}
+
+		// Retrying cannot route a message no queue is bound for.
+		if errors.Is(err, ErrUnroutable) || attempt >= p.cfg.MaxAttempts {
+			return fmt.Errorf("rabbitmq publish error: %w", err)
+		}
+
+		logger.Warn("RabbitMQ publish failed, retrying", "exchange", exchange, "routingKey", routingKey, "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("rabbitmq publish error: %w", err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+		p.metrics.RecordRetry()
+	}
+}
+
+// publishOnce sends one message and waits for its confirm; callers hold p.mu.
+func (p *RabbitPublisher) publishOnce(ctx context.Context, exchange, routingKey string, mandatory bool, pub amqp.Publishing) error {
+	ch, err := p.ensureChannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Drop a return left behind by a message that was not waited for to the end.
+	select {
+	case <-p.returns:
+	default:
+	}
+
+	if err := ch.PublishWithContext(ctx, exchange, routingKey, mandatory, false, pub); err != nil {
+		p.resetChannel()
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	timer := time.NewTimer(p.cfg.ConfirmTimeout)
+	defer timer.Stop()
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			p.resetChannel()
+			return errChannelClosed
+		}
+		if !confirm.Ack {
+			return ErrPublishNacked
+		}
+		// The broker sends basic.return before the ack of an unroutable message.
+		select {
+		case ret, ok := <-p.returns:
+			if ok {
+				return fmt.Errorf("%w: %d %s", ErrUnroutable, ret.ReplyCode, ret.ReplyText)
+			}
+		default:
+		}
+		return nil
+	case <-timer.C:
+		// A late confirm would be taken for the next message's, so start over on a new channel.
+		p.resetChannel()
+		return ErrConfirmTimeout
+	case <-ctx.Done():
+		p.resetChannel()
+		return ctx.Err()
+	}
+}
+
+// mandatory reports whether messages for the exchange must reach a queue.
+func (p *RabbitPublisher) mandatory(exchange string) bool {
+	return !slices.Contains(p.cfg.OptionalRouting, exchange)
+}
+
+func (p *RabbitPublisher) ensureChannel(ctx context.Context) (ConfirmChannel, error) {
+	if p.channel != nil {
+		return p.channel, nil
+	}
+
+	ch, err := p.open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("open channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("enable publisher confirms: %w", err)
+	}
+	// One message is in flight at a time, so a single slot is enough and never blocks the
+	// connection's reader.
+	p.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	p.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	p.channel = ch
+	p.metrics.RecordChannelOpened()
+	return ch, nil
+}
+
+func (p *RabbitPublisher) resetChannel() {
+	if p.channel == nil {
+		return
+	}
+	_ = p.channel.Close()
+	p.channel = nil
+}
+
+// Close closes the publishing channel; the next Publish opens a new one.
+func (p *RabbitPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetChannel()
 	return nil
 }
+
+func publishResult(err error) string {
+	switch {
+	case err == nil:
+		return PublishAcked
+	case errors.Is(err, ErrPublishNacked):
+		return PublishNacked
+	case errors.Is(err, ErrUnroutable):
+		return PublishReturned
+	case errors.Is(err, ErrConfirmTimeout):
+		return PublishTimeout
+	default:
+		return PublishError
+	}
+}
@@ -0,0 +1,194 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeChannel answers each publish the way the next entry of outcomes says.
+type fakeChannel struct {
+	outcomes  *[]string
+	confirms  chan amqp.Confirmation
+	returns   chan amqp.Return
+	published []amqp.Publishing
+	mandatory bool
+	closed    bool
+}
+
+func (c *fakeChannel) Confirm(bool) error { return nil }
+
+func (c *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	c.confirms = confirm
+	return confirm
+}
+
+func (c *fakeChannel) NotifyReturn(ret chan amqp.Return) chan amqp.Return {
+	c.returns = ret
+	return ret
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, _, key string, mandatory, _ bool, msg amqp.Publishing) error {
+	if c.closed {
+		return amqp.ErrClosed
+	}
+	c.published = append(c.published, msg)
+	c.mandatory = mandatory
+
+	outcome := (*c.outcomes)[0]
+	*c.outcomes = (*c.outcomes)[1:]
+	switch outcome {
+	case "ack":
+		c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published)), Ack: true}
+	case "nack":
+		c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published))}
+	case "return":
+		c.returns <- amqp.Return{ReplyCode: 312, ReplyText: "NO_ROUTE", RoutingKey: key}
+		c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published)), Ack: true}
+	case "unbound":
+		// No queue is bound: like the broker, return the message only when it is mandatory.
+		if mandatory {
+			c.returns <- amqp.Return{ReplyCode: 312, ReplyText: "NO_ROUTE", RoutingKey: key}
+		}
+		c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published)), Ack: true}
+	case "closed":
+		c.closed = true
+		close(c.confirms)
+	case "silent":
+	}
+	return nil
+}
+
+func (c *fakeChannel) Close() error {
+	c.closed = true
+	return nil
+}
+
+type countingPublisherMetrics struct {
+	results  []string
+	retries  int
+	channels int
+}
+
+func (m *countingPublisherMetrics) RecordPublish(result string) {
+	m.results = append(m.results, result)
+}
+
+func (m *countingPublisherMetrics) RecordRetry() { m.retries++ }
+
+func (m *countingPublisherMetrics) RecordChannelOpened() { m.channels++ }
+
+type testMessage struct {
+	ID string `json:"id"`
+}
+
+func (m testMessage) GetIdKey() string { return m.ID }
+
+func newTestPublisher(outcomes ...string) (*RabbitPublisher, *countingPublisherMetrics, *[]*fakeChannel) {
+	metrics := &countingPublisherMetrics{}
+	var opened []*fakeChannel
+	p := &RabbitPublisher{
+		exchange: "email.exchange",
+		metrics:  metrics,
+		cfg:      PublisherConfig{ConfirmTimeout: 20 * time.Millisecond, MaxAttempts: 3, RetryDelay: time.Millisecond},
+	}
+	p.open = func(context.Context) (ConfirmChannel, error) {
+		ch := &fakeChannel{outcomes: &outcomes}
+		opened = append(opened, ch)
+		return ch, nil
+	}
+	return p, metrics, &opened
+}
+
+func TestRabbitPublisher_AckedOnFirstAttempt(t *testing.T) {
+	p, metrics, opened := newTestPublisher("ack", "ack")
+
+	require.NoError(t, p.Publish(context.Background(), "email.confirmation", testMessage{ID: "a"}))
+	require.NoError(t, p.Publish(context.Background(), "email.confirmation", testMessage{ID: "b"}))
+
+	require.Len(t, *opened, 1, "the channel is reused")
+	ch := (*opened)[0]
+	assert.True(t, ch.mandatory)
+	assert.Equal(t, "b", ch.published[1].MessageId)
+	assert.Equal(t, []string{PublishAcked, PublishAcked}, metrics.results)
+	assert.Zero(t, metrics.retries)
+}
+
+func TestRabbitPublisher_RetriesNackOnSameChannel(t *testing.T) {
+	p, metrics, opened := newTestPublisher("nack", "ack")
+
+	require.NoError(t, p.Publish(context.Background(), "email.confirmation", testMessage{ID: "a"}))
+
+	assert.Len(t, *opened, 1)
+	assert.Equal(t, []string{PublishNacked, PublishAcked}, metrics.results)
+	assert.Equal(t, 1, metrics.retries)
+}
+
+func TestRabbitPublisher_UnroutableIsNotRetried(t *testing.T) {
+	p, metrics, _ := newTestPublisher("return")
+
+	err := p.Publish(context.Background(), "email.unknown", testMessage{ID: "a"})
+
+	require.ErrorIs(t, err, ErrUnroutable)
+	assert.Contains(t, err.Error(), "NO_ROUTE")
+	assert.Equal(t, []string{PublishReturned}, metrics.results)
+	assert.Zero(t, metrics.retries)
+}
+
+type eventMessage struct {
+	ID string `json:"id"`
+}
+
+func (m eventMessage) GetIdKey() string    { return m.ID }
+func (m eventMessage) GetExchange() string { return "subscription.events" }
+
+func TestRabbitPublisher_EventWithoutSubscriberIsDelivered(t *testing.T) {
+	p, metrics, opened := newTestPublisher("unbound", "unbound")
+	p.cfg.OptionalRouting = []string{"subscription.events"}
+
+	require.NoError(t, p.Publish(context.Background(), "subscription.created", eventMessage{ID: "a"}))
+	assert.False(t, (*opened)[0].mandatory)
+
+	// Email commands still need a queue.
+	err := p.Publish(context.Background(), "email.confirmation", testMessage{ID: "b"})
+	require.ErrorIs(t, err, ErrUnroutable)
+	assert.True(t, (*opened)[0].mandatory)
+	assert.Equal(t, []string{PublishAcked, PublishReturned}, metrics.results)
+}
+
+func TestRabbitPublisher_ReopensChannelAfterTimeoutAndClose(t *testing.T) {
+	p, metrics, opened := newTestPublisher("silent", "closed", "ack")
+
+	require.NoError(t, p.Publish(context.Background(), "email.confirmation", testMessage{ID: "a"}))
+
+	require.Len(t, *opened, 3)
+	assert.True(t, (*opened)[0].closed, "timed out channel is closed")
+	assert.Equal(t, []string{PublishTimeout, PublishError, PublishAcked}, metrics.results)
+	assert.Equal(t, 3, metrics.channels)
+}
+
+func TestRabbitPublisher_GivesUpAfterMaxAttempts(t *testing.T) {
+	p, metrics, _ := newTestPublisher("nack", "nack", "nack")
+
+	err := p.Publish(context.Background(), "email.confirmation", testMessage{ID: "a"})
+
+	require.ErrorIs(t, err, ErrPublishNacked)
+	assert.Len(t, metrics.results, 3)
+	assert.Equal(t, 2, metrics.retries)
+}
+
+func TestRabbitPublisher_OpenFailure(t *testing.T) {
+	p, metrics, _ := newTestPublisher()
+	p.open = func(context.Context) (ConfirmChannel, error) { return nil, errors.New("connection refused") }
+
+	err := p.Publish(context.Background(), "email.confirmation", testMessage{ID: "a"})
+
+	require.Error(t, err)
+	assert.Equal(t, []string{PublishError, PublishError, PublishError}, metrics.results)
+}
@@ -0,0 +1,114 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RoutingKeyDeliveryStatus is the key the email service publishes send outcomes with.
+const RoutingKeyDeliveryStatus = "email.delivery_status"
+
+var errInvalidStatusEvent = errors.New("invalid delivery status event")
+
+// StatusEvent is published by the email service after each send attempt.
+// MessageID is the idempotency key the email command was published with.
+type StatusEvent struct {
+	MessageID     string    `json:"message_id"` //nolint:tagliatelle
+	Status        string    `json:"status"`
+	Template      string    `json:"template"`
+	Error         string    `json:"error"`
+	CorrelationID string    `json:"correlation_id"` //nolint:tagliatelle
+	OccurredAt    time.Time `json:"occurred_at"`    //nolint:tagliatelle
+}
+
+type statusUpdater interface {
+	UpdateStatus(ctx context.Context, idKey string, status domain.DeliveryStatus, at time.Time, errMsg string) (bool, error)
+}
+
+type StatusConsumer struct {
+	channel    *amqp.Channel
+	queue      string
+	deliveries statusUpdater
+}
+
+func NewStatusConsumer(ch *amqp.Channel, queue string, deliveries statusUpdater) *StatusConsumer {
+	return &StatusConsumer{channel: ch, queue: queue, deliveries: deliveries}
+}
+
+// Start consumes status events until ctx is cancelled or the channel closes.
+func (c *StatusConsumer) Start(ctx context.Context) error {
+	msgs, err := c.channel.Consume(c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", c.queue, err)
+	}
+
+	logger := loggerPkg.From(ctx)
+	logger.Info("Delivery status consumer started", "queue", c.queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Delivery status consumer stopped")
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				logger.Info("Delivery status channel closed")
+				return nil
+			}
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+func (c *StatusConsumer) handle(ctx context.Context, msg amqp.Delivery) {
+	logger := loggerPkg.From(ctx)
+
+	err := c.apply(ctx, msg.Body)
+	switch {
+	case err == nil:
+		_ = msg.Ack(false)
+	case errors.Is(err, errInvalidStatusEvent):
+		logger.Warn("Dropping delivery status event", "err", err)
+		_ = msg.Reject(false)
+	default:
+		logger.Error("Failed to apply delivery status event", "err", err)
+		_ = msg.Nack(false, true)
+	}
+}
+
+// apply records the event on its delivery. Events for emails without a delivery record,
+// such as confirmation emails, are acknowledged and ignored.
+func (c *StatusConsumer) apply(ctx context.Context, body []byte) error {
+	var ev StatusEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidStatusEvent, err)
+	}
+
+	status := domain.DeliveryStatus(ev.Status)
+	if ev.MessageID == "" || !status.Valid() || status == domain.DeliveryQueued {
+		return fmt.Errorf("%w: message %q with status %q", errInvalidStatusEvent, ev.MessageID, ev.Status)
+	}
+
+	at := ev.OccurredAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	updated, err := c.deliveries.UpdateStatus(ctx, ev.MessageID, status, at, ev.Error)
+	if err != nil {
+		return fmt.Errorf("update delivery %s: %w", ev.MessageID, err)
+	}
+
+	loggerPkg.From(ctx).Debug("Delivery status event applied",
+		"message_id", ev.MessageID, "status", status, "updated", updated, "correlation_id", ev.CorrelationID)
+	return nil
+}
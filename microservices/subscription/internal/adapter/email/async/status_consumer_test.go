@@ -0,0 +1,78 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStatusUpdater struct {
+	idKey  string
+	status domain.DeliveryStatus
+	at     time.Time
+	errMsg string
+	err    error
+	calls  int
+}
+
+func (f *fakeStatusUpdater) UpdateStatus(_ context.Context, idKey string, status domain.DeliveryStatus, at time.Time, errMsg string) (bool, error) {
+	f.calls++
+	f.idKey, f.status, f.at, f.errMsg = idKey, status, at, errMsg
+	return f.err == nil, f.err
+}
+
+func TestStatusConsumer_Apply(t *testing.T) {
+	t.Run("Sent", func(t *testing.T) {
+		updater := &fakeStatusUpdater{}
+		c := NewStatusConsumer(nil, "q", updater)
+
+		err := c.apply(context.Background(), []byte(`{"message_id":"report:a@example.com:2025-01-01T12","status":"sent","occurred_at":"2025-01-01T12:00:05Z"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "report:a@example.com:2025-01-01T12", updater.idKey)
+		assert.Equal(t, domain.DeliverySent, updater.status)
+		assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 5, 0, time.UTC), updater.at)
+	})
+
+	t.Run("FailedCarriesError", func(t *testing.T) {
+		updater := &fakeStatusUpdater{}
+		c := NewStatusConsumer(nil, "q", updater)
+
+		err := c.apply(context.Background(), []byte(`{"message_id":"k","status":"failed","error":"smtp timeout"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DeliveryFailed, updater.status)
+		assert.Equal(t, "smtp timeout", updater.errMsg)
+		assert.False(t, updater.at.IsZero())
+	})
+
+	t.Run("InvalidEvents", func(t *testing.T) {
+		for _, body := range []string{
+			`not json`,
+			`{"status":"sent"}`,
+			`{"message_id":"k","status":"delivered"}`,
+			`{"message_id":"k","status":"queued"}`,
+		} {
+			updater := &fakeStatusUpdater{}
+			err := NewStatusConsumer(nil, "q", updater).apply(context.Background(), []byte(body))
+
+			assert.ErrorIs(t, err, errInvalidStatusEvent, body)
+			assert.Zero(t, updater.calls, body)
+		}
+	})
+
+	t.Run("StoreError", func(t *testing.T) {
+		updater := &fakeStatusUpdater{err: errors.New("db down")}
+		c := NewStatusConsumer(nil, "q", updater)
+
+		err := c.apply(context.Background(), []byte(`{"message_id":"k","status":"sent"}`))
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errInvalidStatusEvent)
+	})
+}
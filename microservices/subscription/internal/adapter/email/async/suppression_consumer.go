@@ -0,0 +1,104 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RoutingKeyAddressSuppressed is the key the email service announces bounced or complaining addresses with.
+const RoutingKeyAddressSuppressed = "email.address_suppressed"
+
+var errInvalidSuppressionEvent = errors.New("invalid suppression event")
+
+// SuppressionEvent is published by the email service when its provider reports a hard bounce
+// or a spam complaint for an address.
+type SuppressionEvent struct {
+	Email         string    `json:"email"`
+	Reason        string    `json:"reason"`
+	Provider      string    `json:"provider"`
+	CorrelationID string    `json:"correlation_id"` //nolint:tagliatelle
+	OccurredAt    time.Time `json:"occurred_at"`    //nolint:tagliatelle
+}
+
+type addressDeactivator interface {
+	DeactivateAddress(ctx context.Context, email, reason string) (bool, error)
+}
+
+type SuppressionConsumer struct {
+	channel       *amqp.Channel
+	queue         string
+	subscriptions addressDeactivator
+}
+
+func NewSuppressionConsumer(ch *amqp.Channel, queue string, subscriptions addressDeactivator) *SuppressionConsumer {
+	return &SuppressionConsumer{channel: ch, queue: queue, subscriptions: subscriptions}
+}
+
+// Start consumes suppression events until ctx is cancelled or the channel closes.
+func (c *SuppressionConsumer) Start(ctx context.Context) error {
+	msgs, err := c.channel.Consume(c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", c.queue, err)
+	}
+
+	logger := loggerPkg.From(ctx)
+	logger.Info("Suppression consumer started", "queue", c.queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Suppression consumer stopped")
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				logger.Info("Suppression channel closed")
+				return nil
+			}
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+func (c *SuppressionConsumer) handle(ctx context.Context, msg amqp.Delivery) {
+	logger := loggerPkg.From(ctx)
+
+	err := c.apply(ctx, msg.Body)
+	switch {
+	case err == nil:
+		_ = msg.Ack(false)
+	case errors.Is(err, errInvalidSuppressionEvent):
+		logger.Warn("Dropping suppression event", "err", err)
+		_ = msg.Reject(false)
+	default:
+		logger.Error("Failed to apply suppression event", "err", err)
+		_ = msg.Nack(false, true)
+	}
+}
+
+// apply deactivates the subscription of the reported address; deactivating is idempotent,
+// so redelivered events need no dedup.
+func (c *SuppressionConsumer) apply(ctx context.Context, body []byte) error {
+	var ev SuppressionEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidSuppressionEvent, err)
+	}
+	if ev.Email == "" {
+		return fmt.Errorf("%w: missing email", errInvalidSuppressionEvent)
+	}
+
+	deactivated, err := c.subscriptions.DeactivateAddress(ctx, ev.Email, ev.Reason)
+	if err != nil {
+		return fmt.Errorf("deactivate address: %w", err)
+	}
+
+	loggerPkg.From(ctx).Debug("Suppression event applied",
+		"user", loggerPkg.HashEmail(ev.Email), "reason", ev.Reason, "deactivated", deactivated, "correlation_id", ev.CorrelationID)
+	return nil
+}
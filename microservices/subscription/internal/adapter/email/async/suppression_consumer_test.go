@@ -0,0 +1,53 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDeactivator struct {
+	email, reason string
+	err           error
+	calls         int
+}
+
+func (f *fakeDeactivator) DeactivateAddress(_ context.Context, email, reason string) (bool, error) {
+	f.calls++
+	f.email, f.reason = email, reason
+	return f.err == nil, f.err
+}
+
+func TestSuppressionConsumer_Apply(t *testing.T) {
+	t.Run("Deactivates", func(t *testing.T) {
+		subs := &fakeDeactivator{}
+		c := NewSuppressionConsumer(nil, "q", subs)
+
+		err := c.apply(context.Background(), []byte(`{"email":"a@example.com","reason":"bounce","provider":"sendgrid"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "a@example.com", subs.email)
+		assert.Equal(t, "bounce", subs.reason)
+	})
+
+	t.Run("InvalidEvents", func(t *testing.T) {
+		for _, body := range []string{`not json`, `{"reason":"bounce"}`} {
+			subs := &fakeDeactivator{}
+			err := NewSuppressionConsumer(nil, "q", subs).apply(context.Background(), []byte(body))
+
+			assert.ErrorIs(t, err, errInvalidSuppressionEvent, body)
+			assert.Zero(t, subs.calls, body)
+		}
+	})
+
+	t.Run("StoreError", func(t *testing.T) {
+		subs := &fakeDeactivator{err: errors.New("db down")}
+
+		err := NewSuppressionConsumer(nil, "q", subs).apply(context.Background(), []byte(`{"email":"a@example.com"}`))
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errInvalidSuppressionEvent)
+	})
+}
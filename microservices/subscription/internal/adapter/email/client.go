@@ -28,20 +28,22 @@ func NewClient(baseURL string, client *http.Client) *Client {
 	}
 }
 
-func (e *Client) SendConfirmationEmail(ctx context.Context, email, token string, _ string) error {
+func (e *Client) SendConfirmationEmail(ctx context.Context, email, locale, token string, _ string) error {
 	return e.send(ctx, Request{
 		To:       email,
 		Template: "confirmation",
+		Locale:   locale,
 		Data: map[string]string{
 			"token": token,
 		},
 	})
 }
 
-func (e *Client) SendWeatherReport(ctx context.Context, email string, weather domain.Report, city, token string, _ string) error {
+func (e *Client) SendWeatherReport(ctx context.Context, email, locale string, weather domain.Report, city, token string, _ string) error {
 	return e.send(ctx, Request{
 		To:       email,
 		Template: "weather_report",
+		Locale:   locale,
 		Data: map[string]string{
 			"temperature": fmt.Sprintf("%.2f", weather.Temperature),
 			"humidity":    fmt.Sprintf("%d", weather.Humidity),
@@ -52,6 +54,17 @@ func (e *Client) SendWeatherReport(ctx context.Context, email string, weather do
 	})
 }
 
+func (e *Client) SendManageLink(ctx context.Context, email, locale, token string, _ string) error {
+	return e.send(ctx, Request{
+		To:       email,
+		Template: "manage_link",
+		Locale:   locale,
+		Data: map[string]string{
+			"token": token,
+		},
+	})
+}
+
 func (e *Client) send(ctx context.Context, req Request) error {
 	logger := loggerPkg.From(ctx)
 	body, err := json.Marshal(req)
@@ -89,5 +102,6 @@ func (e *Client) send(ctx context.Context, req Request) error {
 type Request struct {
 	To       string            `json:"to"`
 	Template string            `json:"template"`
+	Locale   string            `json:"locale,omitempty"`
 	Data     map[string]string `json:"data"`
 }
@@ -0,0 +1,96 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock. It pins one connection from the pool
+// for as long as the lock is held; if that session dies, Postgres drops the lock with it.
+type AdvisoryLock struct {
+	db   *gorm.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(db *gorm.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		sqlDB, err := l.db.DB()
+		if err != nil {
+			return false, fmt.Errorf("get sql.DB: %w", err)
+		}
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return false, fmt.Errorf("open lock connection: %w", err)
+		}
+		l.conn = conn
+	}
+
+	var acquired bool
+	if err := l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		l.closeConn()
+		return false, fmt.Errorf("pg_try_advisory_lock: %w", err)
+	}
+	return acquired, nil
+}
+
+func (l *AdvisoryLock) Held(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return false, nil
+	}
+
+	var held bool
+	err := l.conn.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND granted AND pid = pg_backend_pid()
+			  AND objsubid = 1 AND ((classid::bigint << 32) | objid::bigint) = $1
+		)`, l.key).Scan(&held)
+	if err != nil {
+		l.closeConn()
+		return false, fmt.Errorf("check advisory lock: %w", err)
+	}
+	return held, nil
+}
+
+// Release unlocks and returns the pinned connection to the pool.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		l.closeConn()
+		return fmt.Errorf("pg_advisory_unlock: %w", err)
+	}
+	_ = l.conn.Close()
+	l.conn = nil
+	return nil
+}
+
+// closeConn discards the pinned connection instead of returning it to the pool,
+// so a session that may still hold the lock is never reused by other queries.
+func (l *AdvisoryLock) closeConn() {
+	_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = l.conn.Close()
+	l.conn = nil
+}
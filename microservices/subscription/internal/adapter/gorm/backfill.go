@@ -0,0 +1,105 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"subscription/internal/backfill"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BackfillRecord struct {
+	Name       string `gorm:"primaryKey"`
+	FinishedAt time.Time
+}
+
+func (BackfillRecord) TableName() string {
+	return "backfills"
+}
+
+type GormBackfillRepository struct {
+	db *gorm.DB
+}
+
+func NewBackfillRepo(db *gorm.DB) *GormBackfillRepository {
+	return &GormBackfillRepository{db: db}
+}
+
+// Claim inserts the backfill's row. Run inside the backfill's transaction, a concurrent claim
+// waits for it and then finds the row, and a rolled back backfill can be claimed again.
+func (r *GormBackfillRepository) Claim(ctx context.Context, name string) (bool, error) {
+	res := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&BackfillRecord{Name: name, FinishedAt: time.Now()})
+	return res.RowsAffected == 1, res.Error
+}
+
+// normalizedEmail is the SQL form of emailcheck's normalisation: trimmed, lowercased, without a
+// trailing dot and, when plus tags are stripped, without the "+tag" of the local part.
+func normalizedEmail(stripPlusTags bool) string {
+	expr := `rtrim(lower(trim(email)), '.')`
+	if stripPlusTags {
+		expr = `regexp_replace(` + expr + `, '^([^+@]+)\+[^@]*@', '\1@')`
+	}
+	return expr
+}
+
+// MergeAddresses keeps one subscription per normalised address and city, moves the delivery history
+// of the others to it and deletes them, then rewrites the kept addresses.
+func (r *GormBackfillRepository) MergeAddresses(ctx context.Context, stripPlusTags bool) (backfill.MergeResult, error) {
+	db := conn(ctx, r.db)
+	address := normalizedEmail(stripPlusTags)
+
+	var dups []struct {
+		ID     string
+		KeptID string
+	}
+	err := db.Raw(fmt.Sprintf(`
+		SELECT id, kept_id FROM (
+			SELECT id,
+				first_value(id) OVER w AS kept_id,
+				row_number() OVER w AS rank
+			FROM subscriptions
+			WINDOW w AS (
+				PARTITION BY %s, lower(city)
+				ORDER BY (is_confirmed AND NOT is_unsubscribed) DESC, is_confirmed DESC, created_at DESC, id
+			)
+		) ranked
+		WHERE rank > 1`, address)).
+		Scan(&dups).Error
+	if err != nil {
+		return backfill.MergeResult{}, fmt.Errorf("find duplicates: %w", err)
+	}
+
+	var res backfill.MergeResult
+	for _, dup := range dups {
+		// A delivery already recorded under the kept id for the same email stays with the removed one.
+		err := db.Exec(`
+			UPDATE deliveries d SET subscription_id = ?
+			WHERE d.subscription_id = ? AND NOT EXISTS (
+				SELECT 1 FROM deliveries k WHERE k.subscription_id = ? AND k.idempotency_key = d.idempotency_key
+			)`, dup.KeptID, dup.ID, dup.KeptID).Error
+		if err != nil {
+			return backfill.MergeResult{}, fmt.Errorf("move deliveries of %s: %w", dup.ID, err)
+		}
+
+		var recs []SubscriptionRecord
+		err = db.Clauses(clause.Returning{}).Where("id = ?", dup.ID).Delete(&recs).Error
+		if err != nil {
+			return backfill.MergeResult{}, fmt.Errorf("delete %s: %w", dup.ID, err)
+		}
+		for _, rec := range recs {
+			res.Removed = append(res.Removed, backfill.Merged{Sub: fromRecord(rec), KeptID: dup.KeptID})
+		}
+	}
+
+	update := db.Exec(fmt.Sprintf(`UPDATE subscriptions SET email = %[1]s WHERE email <> %[1]s`, address))
+	if update.Error != nil {
+		return backfill.MergeResult{}, fmt.Errorf("rewrite addresses: %w", update.Error)
+	}
+	res.Rewritten = update.RowsAffected
+	return res, nil
+}
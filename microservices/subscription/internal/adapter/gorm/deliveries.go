@@ -0,0 +1,243 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeliveryRecord struct {
+	ID             int64  `gorm:"primaryKey"`
+	SubscriptionID string `gorm:"not null;uniqueIndex:deliveries_idempotency_key_subscription_key"`
+	Email          string `gorm:"not null"`
+	City           string `gorm:"not null"`
+	Locale         string `gorm:"not null;default:''"`
+	RunID          *int64
+	JobID          *string
+	IdempotencyKey string `gorm:"not null;uniqueIndex:deliveries_idempotency_key_subscription_key"`
+	Weather        []byte `gorm:"type:jsonb"`
+	Status         string `gorm:"not null"`
+	Error          *string
+	QueuedAt       time.Time `gorm:"not null"`
+	SentAt         *time.Time
+	FailedAt       *time.Time
+	BouncedAt      *time.Time
+	UpdatedAt      time.Time
+}
+
+func (DeliveryRecord) TableName() string {
+	return "deliveries"
+}
+
+type weatherSnapshot struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	Description string  `json:"description"`
+}
+
+func toDeliveryRecord(d domain.Delivery) (DeliveryRecord, error) {
+	rec := DeliveryRecord{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		Email:          d.Email,
+		City:           d.City,
+		Locale:         d.Locale,
+		RunID:          nullableID(d.RunID),
+		IdempotencyKey: d.IdempotencyKey,
+		Status:         string(d.Status),
+		QueuedAt:       d.QueuedAt,
+		SentAt:         d.SentAt,
+		FailedAt:       d.FailedAt,
+		BouncedAt:      d.BouncedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if d.JobID != "" {
+		rec.JobID = &d.JobID
+	}
+	if d.Error != "" {
+		rec.Error = &d.Error
+	}
+	if d.Weather != nil {
+		raw, err := json.Marshal(weatherSnapshot(*d.Weather))
+		if err != nil {
+			return DeliveryRecord{}, fmt.Errorf("marshal weather snapshot: %w", err)
+		}
+		rec.Weather = raw
+	}
+	return rec, nil
+}
+
+func fromDeliveryRecord(rec DeliveryRecord) (domain.Delivery, error) {
+	d := domain.Delivery{
+		ID:             rec.ID,
+		SubscriptionID: rec.SubscriptionID,
+		Email:          rec.Email,
+		City:           rec.City,
+		Locale:         rec.Locale,
+		IdempotencyKey: rec.IdempotencyKey,
+		Status:         domain.DeliveryStatus(rec.Status),
+		QueuedAt:       rec.QueuedAt,
+		SentAt:         rec.SentAt,
+		FailedAt:       rec.FailedAt,
+		BouncedAt:      rec.BouncedAt,
+		UpdatedAt:      rec.UpdatedAt,
+	}
+	if rec.RunID != nil {
+		d.RunID = *rec.RunID
+	}
+	if rec.JobID != nil {
+		d.JobID = *rec.JobID
+	}
+	if rec.Error != nil {
+		d.Error = *rec.Error
+	}
+	if len(rec.Weather) > 0 {
+		var snap weatherSnapshot
+		if err := json.Unmarshal(rec.Weather, &snap); err != nil {
+			return domain.Delivery{}, fmt.Errorf("unmarshal weather snapshot of delivery %d: %w", rec.ID, err)
+		}
+		report := domain.Report(snap)
+		d.Weather = &report
+	}
+	return d, nil
+}
+
+type GormDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveryRepo(db *gorm.DB) *GormDeliveryRepository {
+	return &GormDeliveryRepository{db: db}
+}
+
+// Record inserts a delivery or refreshes it when a retried task reuses the idempotency key.
+// The deliveries of a digest share the key of its email and differ by subscription.
+// Deliveries the email service already reported as sent or bounced are left untouched.
+func (r *GormDeliveryRepository) Record(ctx context.Context, d domain.Delivery) error {
+	rec, err := toDeliveryRecord(d)
+	if err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "idempotency_key"}, {Name: "subscription_id"}},
+		DoUpdates: append(
+			clause.AssignmentColumns([]string{"run_id", "job_id", "weather", "status", "error", "updated_at"}),
+			clause.Assignment{
+				Column: clause.Column{Name: "failed_at"},
+				Value:  gorm.Expr("COALESCE(EXCLUDED.failed_at, deliveries.failed_at)"),
+			},
+		),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.IN{
+				Column: clause.Column{Table: "deliveries", Name: "status"},
+				Values: []any{string(domain.DeliveryQueued), string(domain.DeliveryFailed)},
+			},
+		}},
+	}).Create(&rec).Error
+}
+
+// UpdateStatus applies a status reported by the email service to every delivery of the email,
+// which is several for a digest. Events may arrive out of order, so a late "failed" never
+// overwrites "sent", and nothing overwrites "bounced".
+// It returns false when no delivery with the key exists or the transition was not allowed.
+func (r *GormDeliveryRepository) UpdateStatus(ctx context.Context, idKey string, status domain.DeliveryStatus, at time.Time, errMsg string) (bool, error) {
+	fields := map[string]any{
+		"status":     string(status),
+		"updated_at": time.Now(),
+	}
+	var from []string
+	switch status {
+	case domain.DeliverySent:
+		fields["sent_at"] = at
+		fields["error"] = nil
+		from = []string{string(domain.DeliveryQueued), string(domain.DeliveryFailed)}
+	case domain.DeliveryFailed:
+		fields["failed_at"] = at
+		fields["error"] = errMsg
+		from = []string{string(domain.DeliveryQueued), string(domain.DeliveryFailed)}
+	case domain.DeliveryBounced:
+		fields["bounced_at"] = at
+		fields["error"] = errMsg
+		from = []string{string(domain.DeliveryQueued), string(domain.DeliveryFailed), string(domain.DeliverySent)}
+	default:
+		return false, fmt.Errorf("unsupported delivery status %q", status)
+	}
+
+	res := conn(ctx, r.db).Model(&DeliveryRecord{}).
+		Where("idempotency_key = ? AND status IN ?", idKey, from).
+		Updates(fields)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error) {
+	return listDeliveries(conn(ctx, r.db).Where("subscription_id = ?", subscriptionID), limit)
+}
+
+func (r *GormDeliveryRepository) ListByRun(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error) {
+	return listDeliveries(conn(ctx, r.db).Where("run_id = ?", runID), limit)
+}
+
+func listDeliveries(q *gorm.DB, limit int) ([]domain.Delivery, error) {
+	var recs []DeliveryRecord
+	if err := q.Order("queued_at DESC, id DESC").Limit(limit).Find(&recs).Error; err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]domain.Delivery, 0, len(recs))
+	for _, rec := range recs {
+		d, err := fromDeliveryRecord(rec)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// LatestReports returns the report of each of the latest limit dispatch runs of the given
+// frequency that covered the city in the locale, newest first. Every delivery of a run in one
+// locale carries the same weather, so one of them stands for the run.
+func (r *GormDeliveryRepository) LatestReports(ctx context.Context, city, locale string, frequency domain.Frequency, limit int) ([]domain.ReportSnapshot, error) {
+	var rows []struct {
+		RunID       int64
+		City        string
+		Weather     []byte
+		ScheduledAt time.Time
+	}
+	err := conn(ctx, r.db).Raw(`
+		SELECT DISTINCT ON (r.scheduled_at, r.id) r.id AS run_id, d.city, d.weather, r.scheduled_at
+		FROM deliveries d
+		JOIN dispatch_runs r ON r.id = d.run_id
+		WHERE lower(d.city) = lower(?) AND d.locale = ? AND d.weather IS NOT NULL AND r.frequency = ?
+		ORDER BY r.scheduled_at DESC, r.id DESC, d.id
+		LIMIT ?`, city, locale, string(frequency), limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]domain.ReportSnapshot, 0, len(rows))
+	for _, row := range rows {
+		var snap weatherSnapshot
+		if err := json.Unmarshal(row.Weather, &snap); err != nil {
+			return nil, fmt.Errorf("unmarshal weather snapshot of run %d: %w", row.RunID, err)
+		}
+		snapshots = append(snapshots, domain.ReportSnapshot{
+			RunID:  row.RunID,
+			City:   row.City,
+			Report: domain.Report(snap),
+			At:     row.ScheduledAt,
+		})
+	}
+	return snapshots, nil
+}
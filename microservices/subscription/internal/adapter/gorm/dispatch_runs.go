@@ -0,0 +1,122 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"subscription/internal/job"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DispatchRunRecord struct {
+	ID            int64     `gorm:"primaryKey"`
+	Frequency     string    `gorm:"not null"`
+	ScheduledAt   time.Time `gorm:"not null"`
+	StartedAt     time.Time `gorm:"not null"`
+	FinishedAt    *time.Time
+	Status        string `gorm:"not null"`
+	CatchUp       bool   `gorm:"not null;default:false"`
+	TasksTotal    int    `gorm:"not null;default:0"`
+	TasksEnqueued int    `gorm:"not null;default:0"`
+	TasksFailed   int    `gorm:"not null;default:0"`
+	Recipients    int    `gorm:"not null;default:0"`
+	Error         *string
+}
+
+func (DispatchRunRecord) TableName() string {
+	return "dispatch_runs"
+}
+
+func toRunRecord(r job.Run) DispatchRunRecord {
+	rec := DispatchRunRecord{
+		ID:            r.ID,
+		Frequency:     r.Frequency,
+		ScheduledAt:   r.ScheduledAt,
+		StartedAt:     r.StartedAt,
+		FinishedAt:    r.FinishedAt,
+		Status:        string(r.Status),
+		CatchUp:       r.CatchUp,
+		TasksTotal:    r.TasksTotal,
+		TasksEnqueued: r.TasksEnqueued,
+		TasksFailed:   r.TasksFailed,
+		Recipients:    r.Recipients,
+	}
+	if r.Error != "" {
+		rec.Error = &r.Error
+	}
+	return rec
+}
+
+func fromRunRecord(rec DispatchRunRecord) job.Run {
+	run := job.Run{
+		ID:            rec.ID,
+		Frequency:     rec.Frequency,
+		ScheduledAt:   rec.ScheduledAt,
+		StartedAt:     rec.StartedAt,
+		FinishedAt:    rec.FinishedAt,
+		Status:        job.RunStatus(rec.Status),
+		CatchUp:       rec.CatchUp,
+		TasksTotal:    rec.TasksTotal,
+		TasksEnqueued: rec.TasksEnqueued,
+		TasksFailed:   rec.TasksFailed,
+		Recipients:    rec.Recipients,
+	}
+	if rec.Error != nil {
+		run.Error = *rec.Error
+	}
+	return run
+}
+
+type GormDispatchRunRepository struct {
+	db *gorm.DB
+}
+
+func NewDispatchRunRepo(db *gorm.DB) *GormDispatchRunRepository {
+	return &GormDispatchRunRepository{db: db}
+}
+
+// Start inserts the run unless its (frequency, scheduled_at) slot is taken.
+func (r *GormDispatchRunRepository) Start(ctx context.Context, run job.Run) (job.Run, error) {
+	rec := toRunRecord(run)
+	res := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&rec)
+	if res.Error != nil {
+		return job.Run{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return job.Run{}, job.ErrRunExists
+	}
+	return fromRunRecord(rec), nil
+}
+
+func (r *GormDispatchRunRepository) Finish(ctx context.Context, run job.Run) error {
+	rec := toRunRecord(run)
+	return conn(ctx, r.db).Save(&rec).Error
+}
+
+func (r *GormDispatchRunRepository) LatestScheduled(ctx context.Context, frequency string) (time.Time, bool, error) {
+	var rec DispatchRunRecord
+	err := conn(ctx, r.db).Where("frequency = ?", frequency).Order("scheduled_at DESC").First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return rec.ScheduledAt, true, nil
+}
+
+func (r *GormDispatchRunRepository) ListRecent(ctx context.Context, limit int) ([]job.Run, error) {
+	var recs []DispatchRunRecord
+	if err := conn(ctx, r.db).Order("scheduled_at DESC, id DESC").Limit(limit).Find(&recs).Error; err != nil {
+		return nil, err
+	}
+
+	runs := make([]job.Run, 0, len(recs))
+	for _, rec := range recs {
+		runs = append(runs, fromRunRecord(rec))
+	}
+	return runs, nil
+}
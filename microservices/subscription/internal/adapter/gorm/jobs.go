@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"subscription/internal/job"
+
+	"gorm.io/gorm"
+)
+
+const (
+	jobStatusQueued = "queued"
+	jobStatusDone   = "done"
+	jobStatusDead   = "dead"
+)
+
+type JobRecord struct {
+	ID         int64 `gorm:"primaryKey"`
+	RunID      *int64
+	City       string `gorm:"not null"`
+	Recipients []byte `gorm:"type:jsonb;not null"`
+	Status     string `gorm:"not null;default:queued"`
+	Attempts   int    `gorm:"not null;default:0"`
+	LastError  *string
+	VisibleAt  time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Digest     bool `gorm:"not null;default:false"`
+	// ScheduledAt is the slot of the task's run; nil for tasks enqueued outside the ledger.
+	ScheduledAt *time.Time
+}
+
+func (JobRecord) TableName() string {
+	return "jobs"
+}
+
+type GormJobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepo(db *gorm.DB) *GormJobRepository {
+	return &GormJobRepository{db: db}
+}
+
+func (r *GormJobRepository) Insert(ctx context.Context, task job.Task) error {
+	recipients, err := json.Marshal(task.Recipients)
+	if err != nil {
+		return fmt.Errorf("marshal recipients: %w", err)
+	}
+
+	now := time.Now()
+	rec := JobRecord{
+		RunID:       nullableID(task.RunID),
+		City:        task.City,
+		Recipients:  recipients,
+		Digest:      task.Digest,
+		ScheduledAt: nullableTime(task.ScheduledAt),
+		Status:      jobStatusQueued,
+		VisibleAt:   now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	return conn(ctx, r.db).Create(&rec).Error
+}
+
+// Claim buries jobs whose last lease expired after their final attempt, then leases the next due job.
+func (r *GormJobRepository) Claim(ctx context.Context, now, visibleUntil time.Time, maxAttempts int) (job.Task, bool, error) {
+	db := conn(ctx, r.db)
+
+	err := db.Model(&JobRecord{}).
+		Where("status = ? AND visible_at <= ? AND attempts >= ?", jobStatusQueued, now, maxAttempts).
+		Updates(map[string]any{
+			"status":     jobStatusDead,
+			"last_error": "visibility timeout expired on final attempt",
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return job.Task{}, false, err
+	}
+
+	var recs []JobRecord
+	err = db.Raw(`
+		UPDATE jobs SET attempts = attempts + 1, visible_at = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = ? AND visible_at <= ?
+			ORDER BY visible_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, visibleUntil, now, jobStatusQueued, now).
+		Scan(&recs).Error
+	if err != nil {
+		return job.Task{}, false, err
+	}
+	if len(recs) == 0 {
+		return job.Task{}, false, nil
+	}
+
+	rec := recs[0]
+	var recipients []job.Recipient
+	if err := json.Unmarshal(rec.Recipients, &recipients); err != nil {
+		return job.Task{}, false, fmt.Errorf("unmarshal recipients of job %d: %w", rec.ID, err)
+	}
+
+	task := job.Task{
+		ID:         strconv.FormatInt(rec.ID, 10),
+		City:       rec.City,
+		Recipients: recipients,
+		Digest:     rec.Digest,
+		Attempts:   rec.Attempts,
+	}
+	if rec.RunID != nil {
+		task.RunID = *rec.RunID
+	}
+	if rec.ScheduledAt != nil {
+		task.ScheduledAt = rec.ScheduledAt.UTC()
+	}
+	return task, true, nil
+}
+
+func nullableID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (r *GormJobRepository) CountQueued(ctx context.Context) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&JobRecord{}).Where("status = ?", jobStatusQueued).Count(&count).Error
+	return count, err
+}
+
+func (r *GormJobRepository) Complete(ctx context.Context, id string) error {
+	return r.updateQueued(ctx, id, map[string]any{"status": jobStatusDone})
+}
+
+func (r *GormJobRepository) Retry(ctx context.Context, id string, visibleAt time.Time, lastErr string) error {
+	return r.updateQueued(ctx, id, map[string]any{"visible_at": visibleAt, "last_error": lastErr})
+}
+
+func (r *GormJobRepository) Bury(ctx context.Context, id string, lastErr string) error {
+	return r.updateQueued(ctx, id, map[string]any{"status": jobStatusDead, "last_error": lastErr})
+}
+
+func (r *GormJobRepository) updateQueued(ctx context.Context, id string, fields map[string]any) error {
+	fields["updated_at"] = time.Now()
+	return conn(ctx, r.db).Model(&JobRecord{}).
+		Where("id = ? AND status = ?", id, jobStatusQueued).
+		Updates(fields).Error
+}
@@ -0,0 +1,158 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"subscription/internal/outbox"
+
+	"gorm.io/gorm"
+)
+
+const (
+	outboxStatusPending = "pending"
+	outboxStatusSent    = "sent"
+	outboxStatusDead    = "dead"
+)
+
+type OutboxRecord struct {
+	ID            int64  `gorm:"primaryKey"`
+	MessageID     string `gorm:"not null"`
+	Exchange      string `gorm:"not null;default:''"`
+	RoutingKey    string `gorm:"not null"`
+	Headers       []byte `gorm:"type:jsonb"`
+	Payload       []byte `gorm:"type:jsonb;not null"`
+	Status        string `gorm:"not null;default:pending"`
+	Attempts      int    `gorm:"not null;default:0"`
+	LastError     *string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+func (OutboxRecord) TableName() string {
+	return "outbox"
+}
+
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+// Add inserts a message; inside WithinTx it commits or rolls back together with the caller's changes.
+func (r *GormOutboxRepository) Add(ctx context.Context, msg outbox.Message) error {
+	var headers []byte
+	if len(msg.Headers) > 0 {
+		var err error
+		if headers, err = json.Marshal(msg.Headers); err != nil {
+			return fmt.Errorf("marshal outbox headers: %w", err)
+		}
+	}
+
+	now := time.Now()
+	rec := OutboxRecord{
+		MessageID:     msg.MessageID,
+		Exchange:      msg.Exchange,
+		RoutingKey:    msg.RoutingKey,
+		Headers:       headers,
+		Payload:       msg.Payload,
+		Status:        outboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	return conn(ctx, r.db).Create(&rec).Error
+}
+
+// Claim pushes next_attempt_at of due rows forward to leaseUntil, so concurrent relays skip them
+// and a crashed relay's rows become due again once the lease passes.
+func (r *GormOutboxRepository) Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]outbox.Message, error) {
+	var recs []OutboxRecord
+	err := conn(ctx, r.db).Raw(`
+		UPDATE outbox SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, outboxStatusPending, now, limit).
+		Scan(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]outbox.Message, 0, len(recs))
+	for _, rec := range recs {
+		var headers map[string]string
+		if len(rec.Headers) > 0 {
+			if err := json.Unmarshal(rec.Headers, &headers); err != nil {
+				return nil, fmt.Errorf("unmarshal headers of outbox message %d: %w", rec.ID, err)
+			}
+		}
+		msgs = append(msgs, outbox.Message{
+			ID:         rec.ID,
+			MessageID:  rec.MessageID,
+			Exchange:   rec.Exchange,
+			RoutingKey: rec.RoutingKey,
+			Headers:    headers,
+			Payload:    rec.Payload,
+			Attempts:   rec.Attempts,
+			CreatedAt:  rec.CreatedAt,
+		})
+	}
+	return msgs, nil
+}
+
+func (r *GormOutboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	return conn(ctx, r.db).Model(&OutboxRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"status":  outboxStatusSent,
+		"sent_at": sentAt,
+	}).Error
+}
+
+func (r *GormOutboxRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	return conn(ctx, r.db).Model(&OutboxRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastErr,
+	}).Error
+}
+
+func (r *GormOutboxRepository) MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error {
+	return conn(ctx, r.db).Model(&OutboxRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     outboxStatusDead,
+		"attempts":   attempts,
+		"last_error": lastErr,
+	}).Error
+}
+
+func (r *GormOutboxRepository) Stats(ctx context.Context) (outbox.Stats, error) {
+	var row struct {
+		Pending int64
+		Oldest  *time.Time
+	}
+	err := conn(ctx, r.db).Model(&OutboxRecord{}).
+		Select("COUNT(*) AS pending, MIN(created_at) AS oldest").
+		Where("status = ?", outboxStatusPending).
+		Scan(&row).Error
+	if err != nil {
+		return outbox.Stats{}, err
+	}
+
+	stats := outbox.Stats{Pending: row.Pending}
+	if row.Oldest != nil {
+		stats.OldestCreatedAt = *row.Oldest
+	}
+	return stats, nil
+}
+
+func (r *GormOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	res := conn(ctx, r.db).Where("status = ? AND sent_at < ?", outboxStatusSent, before).Delete(&OutboxRecord{})
+	return res.RowsAffected, res.Error
+}
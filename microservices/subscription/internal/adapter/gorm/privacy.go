@@ -0,0 +1,162 @@
+package gorm
+
+import (
+	"context"
+
+	"subscription/internal/subscription"
+
+	"gorm.io/gorm"
+)
+
+// GormPrivacyRepository finds and erases an email address across all tables that hold it,
+// including the webhook endpoints and queued webhook reports of its subscriptions.
+type GormPrivacyRepository struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepo(db *gorm.DB) *GormPrivacyRepository {
+	return &GormPrivacyRepository{db: db}
+}
+
+func (r *GormPrivacyRepository) Collect(ctx context.Context, email string) (subscription.DataExport, error) {
+	db := conn(ctx, r.db)
+	var data subscription.DataExport
+
+	var subRecs []SubscriptionRecord
+	if err := db.Where("email = ?", email).Order("created_at").Find(&subRecs).Error; err != nil {
+		return data, err
+	}
+	ids := make([]string, 0, len(subRecs))
+	for _, rec := range subRecs {
+		data.Subscriptions = append(data.Subscriptions, fromRecord(rec))
+		ids = append(ids, rec.ID)
+	}
+
+	var deliveryRecs []DeliveryRecord
+	err := deliveriesOf(db, email, ids).
+		Order("queued_at, id").
+		Find(&deliveryRecs).Error
+	if err != nil {
+		return data, err
+	}
+	for _, rec := range deliveryRecs {
+		d, err := fromDeliveryRecord(rec)
+		if err != nil {
+			return data, err
+		}
+		data.Deliveries = append(data.Deliveries, d)
+	}
+
+	var outboxRecs []OutboxRecord
+	if err := db.Where("payload->>'to' = ?", email).Order("id").Find(&outboxRecs).Error; err != nil {
+		return data, err
+	}
+	for _, rec := range outboxRecs {
+		data.Messages = append(data.Messages, subscription.StoredMessage{
+			RoutingKey: rec.RoutingKey,
+			Status:     rec.Status,
+			CreatedAt:  rec.CreatedAt,
+			SentAt:     rec.SentAt,
+		})
+	}
+
+	if len(ids) == 0 {
+		return data, nil
+	}
+
+	var endpointRecs []WebhookEndpointRecord
+	if err := db.Where("subscription_id IN ?", ids).Order("created_at").Find(&endpointRecs).Error; err != nil {
+		return data, err
+	}
+	for _, rec := range endpointRecs {
+		data.Webhooks = append(data.Webhooks, fromWebhookEndpointRecord(rec))
+	}
+
+	var webhookRecs []WebhookDeliveryRecord
+	if err := db.Where("subscription_id IN ?", ids).Order("id").Find(&webhookRecs).Error; err != nil {
+		return data, err
+	}
+	for _, rec := range webhookRecs {
+		data.WebhookDeliveries = append(data.WebhookDeliveries, subscription.StoredWebhookDelivery{
+			SubscriptionID: rec.SubscriptionID,
+			Status:         rec.Status,
+			Attempts:       rec.Attempts,
+			CreatedAt:      rec.CreatedAt,
+			SentAt:         rec.SentAt,
+		})
+	}
+
+	return data, nil
+}
+
+// Erase must run inside a transaction so a failure leaves the address fully intact.
+func (r *GormPrivacyRepository) Erase(ctx context.Context, email string) (subscription.ErasureResult, error) {
+	db := conn(ctx, r.db)
+	var res subscription.ErasureResult
+
+	var recs []SubscriptionRecord
+	if err := db.Where("email = ?", email).Find(&recs).Error; err != nil {
+		return res, err
+	}
+	ids := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		ids = append(ids, rec.ID)
+		res.Removed = append(res.Removed, fromRecord(rec))
+	}
+
+	del := deliveriesOf(db, email, ids).Delete(&DeliveryRecord{})
+	if del.Error != nil {
+		return res, del.Error
+	}
+	res.Deliveries = del.RowsAffected
+
+	del = db.Where("payload->>'to' = ?", email).Delete(&OutboxRecord{})
+	if del.Error != nil {
+		return res, del.Error
+	}
+	res.Messages = del.RowsAffected
+
+	scrub := db.Exec(`
+		UPDATE jobs SET
+			recipients = COALESCE((
+				SELECT jsonb_agg(r) FROM jsonb_array_elements(recipients) AS r
+				WHERE r->>'email' <> ?
+			), '[]'::jsonb),
+			updated_at = NOW()
+		WHERE recipients @> jsonb_build_array(jsonb_build_object('email', ?::text))`, email, email)
+	if scrub.Error != nil {
+		return res, scrub.Error
+	}
+	res.Jobs = scrub.RowsAffected
+
+	// The webhook tables cascade with the subscriptions, but are deleted first to be counted.
+	if len(ids) > 0 {
+		del = db.Where("subscription_id IN ?", ids).Delete(&WebhookDeliveryRecord{})
+		if del.Error != nil {
+			return res, del.Error
+		}
+		res.WebhookDeliveries = del.RowsAffected
+
+		del = db.Where("subscription_id IN ?", ids).Delete(&WebhookEndpointRecord{})
+		if del.Error != nil {
+			return res, del.Error
+		}
+		res.Webhooks = del.RowsAffected
+	}
+
+	del = db.Where("email = ?", email).Delete(&SubscriptionRecord{})
+	if del.Error != nil {
+		return res, del.Error
+	}
+	res.Subscriptions = del.RowsAffected
+
+	return res, nil
+}
+
+// deliveriesOf matches deliveries sent to the address or belonging to its subscriptions.
+func deliveriesOf(db *gorm.DB, email string, subscriptionIDs []string) *gorm.DB {
+	if len(subscriptionIDs) == 0 {
+		return db.Where("email = ?", email)
+	}
+	return db.Where("email = ? OR subscription_id IN ?", email, subscriptionIDs)
+}
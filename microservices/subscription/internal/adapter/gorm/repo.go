@@ -3,49 +3,90 @@ package gorm
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"subscription/internal/domain"
 	"subscription/internal/subscription"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SubscriptionRecord struct {
 	ID             string `gorm:"primaryKey"`
-	Email          string `gorm:"not null;uniqueIndex"`
-	City           string `gorm:"not null"`
+	Email          string `gorm:"not null;uniqueIndex:idx_subscriptions_email_city"`
+	City           string `gorm:"not null;uniqueIndex:idx_subscriptions_email_city"`
 	Frequency      string `gorm:"type:text;not null"`
+	DeliveryHour   int    `gorm:"not null;default:12"`
+	Locale         string `gorm:"not null;default:uk"`
+	Channel        string `gorm:"not null;default:email"`
 	IsConfirmed    bool   `gorm:"default:false"`
 	IsUnsubscribed bool   `gorm:"default:false"`
 	Token          string `gorm:"not null"`
+	FeedToken      string `gorm:"not null;default:''"`
 	CreatedAt      time.Time
+	ReminderSentAt *time.Time
+	PausedUntil    *time.Time
+	// Quiet hours are stored as three nullable columns; all are set or all are NULL.
+	QuietHoursStart    *int
+	QuietHoursEnd      *int
+	QuietHoursTimezone *string
+	Digest             bool `gorm:"not null;default:false"`
 }
 
 func toRecord(s domain.Subscription) SubscriptionRecord {
-	return SubscriptionRecord{
+	rec := SubscriptionRecord{
 		ID:             s.ID,
 		Email:          s.Email,
 		City:           s.City,
 		Frequency:      string(s.Frequency),
+		DeliveryHour:   s.DeliveryHour,
+		Locale:         s.Locale,
+		Channel:        string(s.Channel),
 		IsConfirmed:    s.IsConfirmed,
 		IsUnsubscribed: s.IsUnsubscribed,
 		Token:          s.Token,
+		FeedToken:      s.FeedToken,
 		CreatedAt:      s.CreatedAt,
+		ReminderSentAt: s.ReminderSentAt,
+		PausedUntil:    s.PausedUntil,
+		Digest:         s.Digest,
 	}
+	if q := s.QuietHours; q != nil {
+		rec.QuietHoursStart = &q.Start
+		rec.QuietHoursEnd = &q.End
+		rec.QuietHoursTimezone = &q.Timezone
+	}
+	return rec
 }
 
 func fromRecord(r SubscriptionRecord) domain.Subscription {
-	return domain.Subscription{
+	sub := domain.Subscription{
 		ID:             r.ID,
 		Email:          r.Email,
 		City:           r.City,
 		Frequency:      domain.Frequency(r.Frequency),
+		DeliveryHour:   r.DeliveryHour,
+		Locale:         r.Locale,
+		Channel:        domain.Channel(r.Channel),
 		IsConfirmed:    r.IsConfirmed,
 		IsUnsubscribed: r.IsUnsubscribed,
 		Token:          r.Token,
+		FeedToken:      r.FeedToken,
 		CreatedAt:      r.CreatedAt,
+		ReminderSentAt: r.ReminderSentAt,
+		PausedUntil:    r.PausedUntil,
+		Digest:         r.Digest,
+	}
+	if r.QuietHoursStart != nil && r.QuietHoursEnd != nil && r.QuietHoursTimezone != nil {
+		sub.QuietHours = &domain.QuietHours{
+			Start:    *r.QuietHoursStart,
+			End:      *r.QuietHoursEnd,
+			Timezone: *r.QuietHoursTimezone,
+		}
 	}
+	return sub
 }
 
 type GormSubscriptionRepository struct {
@@ -56,9 +97,38 @@ func NewRepo(db *gorm.DB) *GormSubscriptionRepository {
 	return &GormSubscriptionRepository{db: db}
 }
 
-func (r *GormSubscriptionRepository) GetByEmail(ctx context.Context, email string) (*domain.Subscription, error) {
+// ListByEmail returns the subscriptions of an address, oldest first.
+func (r *GormSubscriptionRepository) ListByEmail(ctx context.Context, email string) ([]domain.Subscription, error) {
+	var recs []SubscriptionRecord
+	err := conn(ctx, r.db).Where("email = ?", email).Order("created_at, id").Find(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(recs))
+	for _, rec := range recs {
+		subs = append(subs, fromRecord(rec))
+	}
+	return subs, nil
+}
+
+// GetByEmailAndCity returns the subscription of an address to a city; cities match regardless of case.
+func (r *GormSubscriptionRepository) GetByEmailAndCity(ctx context.Context, email, city string) (*domain.Subscription, error) {
+	var rec SubscriptionRecord
+	err := conn(ctx, r.db).Where("email = ? AND lower(city) = lower(?)", email, city).First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, subscription.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub := fromRecord(rec)
+	return &sub, nil
+}
+
+func (r *GormSubscriptionRepository) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
 	var rec SubscriptionRecord
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&rec).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&rec).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, subscription.ErrSubscriptionNotFound
 	}
@@ -71,17 +141,28 @@ func (r *GormSubscriptionRepository) GetByEmail(ctx context.Context, email strin
 
 func (r *GormSubscriptionRepository) Create(ctx context.Context, sub *domain.Subscription) error {
 	rec := toRecord(*sub)
-	return r.db.WithContext(ctx).Create(&rec).Error
+	return conn(ctx, r.db).Create(&rec).Error
 }
 
 func (r *GormSubscriptionRepository) Update(ctx context.Context, sub *domain.Subscription) error {
 	rec := toRecord(*sub)
-	return r.db.WithContext(ctx).Save(&rec).Error
+	return conn(ctx, r.db).Save(&rec).Error
+}
+
+func (r *GormSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	res := conn(ctx, r.db).Where("id = ?", id).Delete(&SubscriptionRecord{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return subscription.ErrSubscriptionNotFound
+	}
+	return nil
 }
 
 func (r *GormSubscriptionRepository) GetConfirmedByFrequency(ctx context.Context, freq string) ([]domain.Subscription, error) {
 	var recs []SubscriptionRecord
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("is_confirmed = ? AND is_unsubscribed = ? AND frequency = ?", true, false, freq).
 		Find(&recs).Error
 	if err != nil {
@@ -98,3 +179,141 @@ func (r *GormSubscriptionRepository) GetConfirmedByFrequency(ctx context.Context
 func (SubscriptionRecord) TableName() string {
 	return "subscriptions"
 }
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search returns subscriptions matching the filter, newest first, starting after the cursor.
+func (r *GormSubscriptionRepository) Search(ctx context.Context, f subscription.SearchFilter, after *subscription.Cursor, limit int) ([]domain.Subscription, error) {
+	q := conn(ctx, r.db).Model(&SubscriptionRecord{})
+	if f.Email != "" {
+		q = q.Where("email ILIKE ?", "%"+likeEscaper.Replace(f.Email)+"%")
+	}
+	if f.City != "" {
+		q = q.Where("LOWER(city) = LOWER(?)", f.City)
+	}
+	if f.Frequency != "" {
+		q = q.Where("frequency = ?", string(f.Frequency))
+	}
+	switch f.Status {
+	case domain.StatusPending:
+		q = q.Where("is_confirmed = ? AND is_unsubscribed = ?", false, false)
+	case domain.StatusActive:
+		q = q.Where("is_confirmed = ? AND is_unsubscribed = ?", true, false)
+	case domain.StatusUnsubscribed:
+		q = q.Where("is_unsubscribed = ?", true)
+	}
+	if after != nil {
+		q = q.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var recs []SubscriptionRecord
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&recs).Error; err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(recs))
+	for _, rec := range recs {
+		subs = append(subs, fromRecord(rec))
+	}
+	return subs, nil
+}
+
+// Stats counts subscriptions by status; the city and frequency breakdown covers active ones only.
+func (r *GormSubscriptionRepository) Stats(ctx context.Context) (subscription.Stats, error) {
+	db := conn(ctx, r.db)
+
+	var totals struct {
+		Total        int64
+		Pending      int64
+		Active       int64
+		Unsubscribed int64
+		Confirmed    int64
+	}
+	err := db.Raw(`
+		SELECT COUNT(*) AS total,
+		       COUNT(*) FILTER (WHERE NOT is_confirmed AND NOT is_unsubscribed) AS pending,
+		       COUNT(*) FILTER (WHERE is_confirmed AND NOT is_unsubscribed) AS active,
+		       COUNT(*) FILTER (WHERE is_unsubscribed) AS unsubscribed,
+		       COUNT(*) FILTER (WHERE is_confirmed) AS confirmed
+		FROM subscriptions`).
+		Scan(&totals).Error
+	if err != nil {
+		return subscription.Stats{}, err
+	}
+
+	var rows []struct {
+		City      string
+		Frequency string
+		Count     int64
+	}
+	err = db.Model(&SubscriptionRecord{}).
+		Select("city, frequency, COUNT(*) AS count").
+		Where("is_confirmed = ? AND is_unsubscribed = ?", true, false).
+		Group("city, frequency").
+		Order("count DESC, city, frequency").
+		Scan(&rows).Error
+	if err != nil {
+		return subscription.Stats{}, err
+	}
+
+	stats := subscription.Stats{
+		Total:        totals.Total,
+		Pending:      totals.Pending,
+		Active:       totals.Active,
+		Unsubscribed: totals.Unsubscribed,
+		Confirmed:    totals.Confirmed,
+		ByFrequency:  map[domain.Frequency]int64{},
+		ByCity:       make([]subscription.CityCount, 0, len(rows)),
+	}
+	for _, row := range rows {
+		freq := domain.Frequency(row.Frequency)
+		stats.ByFrequency[freq] += row.Count
+		stats.ByCity = append(stats.ByCity, subscription.CityCount{City: row.City, Frequency: freq, Count: row.Count})
+	}
+	return stats, nil
+}
+
+// DueForReminder returns pending subscriptions created before the cutoff that were not reminded yet, oldest first.
+func (r *GormSubscriptionRepository) DueForReminder(ctx context.Context, createdBefore time.Time, limit int) ([]domain.Subscription, error) {
+	var recs []SubscriptionRecord
+	err := conn(ctx, r.db).
+		Where("is_confirmed = ? AND is_unsubscribed = ? AND reminder_sent_at IS NULL AND created_at < ?", false, false, createdBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(recs))
+	for _, rec := range recs {
+		subs = append(subs, fromRecord(rec))
+	}
+	return subs, nil
+}
+
+// MarkReminded sets reminder_sent_at unless the subscription was reminded, confirmed or removed meanwhile.
+func (r *GormSubscriptionRepository) MarkReminded(ctx context.Context, id string, at time.Time) (bool, error) {
+	res := conn(ctx, r.db).Model(&SubscriptionRecord{}).
+		Where("id = ? AND is_confirmed = ? AND is_unsubscribed = ? AND reminder_sent_at IS NULL", id, false, false).
+		Update("reminder_sent_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteUnconfirmedBefore removes pending subscriptions created before the cutoff and returns them.
+func (r *GormSubscriptionRepository) DeleteUnconfirmedBefore(ctx context.Context, createdBefore time.Time) ([]domain.Subscription, error) {
+	var recs []SubscriptionRecord
+	err := conn(ctx, r.db).
+		Clauses(clause.Returning{}).
+		Where("is_confirmed = ? AND is_unsubscribed = ? AND created_at < ?", false, false, createdBefore).
+		Delete(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.Subscription, 0, len(recs))
+	for _, rec := range recs {
+		subs = append(subs, fromRecord(rec))
+	}
+	return subs, nil
+}
@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"subscription/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRecord struct {
+	JTI            string `gorm:"column:jti;primaryKey"`
+	SubscriptionID string `gorm:"not null"`
+	Purpose        string `gorm:"not null"`
+	ExpiresAt      *time.Time
+	RevokedAt      time.Time
+}
+
+func (RevokedTokenRecord) TableName() string {
+	return "revoked_tokens"
+}
+
+type GormRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepo(db *gorm.DB) *GormRevocationRepository {
+	return &GormRevocationRepository{db: db}
+}
+
+func (r *GormRevocationRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&RevokedTokenRecord{}).Where("jti = ?", tokenID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GormRevocationRepository) Revoke(ctx context.Context, claims domain.TokenClaims) error {
+	rec := RevokedTokenRecord{
+		JTI:            claims.ID,
+		SubscriptionID: claims.SubscriptionID,
+		Purpose:        string(claims.Purpose),
+		RevokedAt:      time.Now(),
+	}
+	if !claims.ExpiresAt.IsZero() {
+		rec.ExpiresAt = &claims.ExpiresAt
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&rec).Error
+}
@@ -0,0 +1,265 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/webhook"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	webhookStatusPending = "pending"
+	webhookStatusSent    = "sent"
+	webhookStatusDead    = "dead"
+)
+
+type WebhookEndpointRecord struct {
+	SubscriptionID string `gorm:"primaryKey"`
+	URL            string `gorm:"not null"`
+	Secret         string `gorm:"not null"`
+	VerifiedAt     *time.Time
+	VerifyAttempts int `gorm:"not null;default:0"`
+	NextVerifyAt   *time.Time
+	Failures       int `gorm:"not null;default:0"`
+	DisabledAt     *time.Time
+	LastError      *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (WebhookEndpointRecord) TableName() string {
+	return "webhook_endpoints"
+}
+
+type WebhookDeliveryRecord struct {
+	ID             int64  `gorm:"primaryKey"`
+	SubscriptionID string `gorm:"not null"`
+	IdempotencyKey string `gorm:"not null;uniqueIndex"`
+	Payload        []byte `gorm:"type:jsonb;not null"`
+	Status         string `gorm:"not null;default:pending"`
+	Attempts       int    `gorm:"not null;default:0"`
+	LastError      *string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	SentAt         *time.Time
+}
+
+func (WebhookDeliveryRecord) TableName() string {
+	return "webhook_deliveries"
+}
+
+func toWebhookEndpointRecord(e domain.WebhookEndpoint) WebhookEndpointRecord {
+	rec := WebhookEndpointRecord{
+		SubscriptionID: e.SubscriptionID,
+		URL:            e.URL,
+		Secret:         e.Secret,
+		VerifiedAt:     e.VerifiedAt,
+		VerifyAttempts: e.VerifyAttempts,
+		NextVerifyAt:   e.NextVerifyAt,
+		Failures:       e.Failures,
+		DisabledAt:     e.DisabledAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      time.Now(),
+	}
+	if e.LastError != "" {
+		rec.LastError = &e.LastError
+	}
+	return rec
+}
+
+func fromWebhookEndpointRecord(rec WebhookEndpointRecord) domain.WebhookEndpoint {
+	e := domain.WebhookEndpoint{
+		SubscriptionID: rec.SubscriptionID,
+		URL:            rec.URL,
+		Secret:         rec.Secret,
+		VerifiedAt:     rec.VerifiedAt,
+		VerifyAttempts: rec.VerifyAttempts,
+		NextVerifyAt:   rec.NextVerifyAt,
+		Failures:       rec.Failures,
+		DisabledAt:     rec.DisabledAt,
+		CreatedAt:      rec.CreatedAt,
+	}
+	if rec.LastError != nil {
+		e.LastError = *rec.LastError
+	}
+	return e
+}
+
+// GormWebhookRepository stores webhook endpoints and the queue of reports posted to them.
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepo(db *gorm.DB) *GormWebhookRepository {
+	return &GormWebhookRepository{db: db}
+}
+
+func (r *GormWebhookRepository) GetEndpoint(ctx context.Context, subscriptionID string) (*domain.WebhookEndpoint, error) {
+	var rec WebhookEndpointRecord
+	err := conn(ctx, r.db).Where("subscription_id = ?", subscriptionID).First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, webhook.ErrEndpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	e := fromWebhookEndpointRecord(rec)
+	return &e, nil
+}
+
+func (r *GormWebhookRepository) SaveEndpoint(ctx context.Context, e domain.WebhookEndpoint) error {
+	rec := toWebhookEndpointRecord(e)
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}},
+		UpdateAll: true,
+	}).Create(&rec).Error
+}
+
+// ClaimVerifications pushes next_verify_at of due handshakes forward to leaseUntil, the same
+// lease the outbox uses.
+func (r *GormWebhookRepository) ClaimVerifications(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookEndpoint, error) {
+	var recs []WebhookEndpointRecord
+	err := conn(ctx, r.db).Raw(`
+		UPDATE webhook_endpoints SET next_verify_at = ?
+		WHERE subscription_id IN (
+			SELECT subscription_id FROM webhook_endpoints
+			WHERE verified_at IS NULL AND next_verify_at <= ?
+			ORDER BY next_verify_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, now, limit).
+		Scan(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]domain.WebhookEndpoint, 0, len(recs))
+	for _, rec := range recs {
+		endpoints = append(endpoints, fromWebhookEndpointRecord(rec))
+	}
+	return endpoints, nil
+}
+
+// SaveVerification matches on the secret, so the outcome of a handshake with a replaced
+// endpoint is discarded.
+func (r *GormWebhookRepository) SaveVerification(ctx context.Context, e domain.WebhookEndpoint) error {
+	rec := toWebhookEndpointRecord(e)
+	return conn(ctx, r.db).Model(&WebhookEndpointRecord{}).
+		Where("subscription_id = ? AND secret = ?", e.SubscriptionID, e.Secret).
+		Updates(map[string]any{
+			"verified_at":     rec.VerifiedAt,
+			"verify_attempts": rec.VerifyAttempts,
+			"next_verify_at":  rec.NextVerifyAt,
+			"last_error":      rec.LastError,
+			"updated_at":      rec.UpdatedAt,
+		}).Error
+}
+
+func (r *GormWebhookRepository) ResetFailures(ctx context.Context, subscriptionID string) error {
+	return conn(ctx, r.db).Model(&WebhookEndpointRecord{}).
+		Where("subscription_id = ?", subscriptionID).
+		Updates(map[string]any{
+			"failures":   0,
+			"last_error": nil,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// RecordFailure increments the counter in one statement, so concurrent dispatchers cannot lose
+// a failure, and sets disabled_at when the counter reaches disableAfter.
+func (r *GormWebhookRepository) RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, at time.Time, lastErr string) (bool, error) {
+	var rows []struct {
+		Disabled bool
+	}
+	err := conn(ctx, r.db).Raw(`
+		UPDATE webhook_endpoints SET
+			failures = failures + 1,
+			last_error = ?,
+			disabled_at = CASE WHEN disabled_at IS NULL AND failures + 1 >= ? THEN ?::timestamptz ELSE disabled_at END,
+			updated_at = NOW()
+		WHERE subscription_id = ?
+		RETURNING disabled_at = ?::timestamptz AS disabled`, lastErr, disableAfter, at, subscriptionID, at).
+		Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0 && rows[0].Disabled, nil
+}
+
+// Enqueue ignores a delivery whose idempotency key is already queued, as happens when a report
+// task is retried.
+func (r *GormWebhookRepository) Enqueue(ctx context.Context, d webhook.Delivery) error {
+	now := time.Now()
+	rec := WebhookDeliveryRecord{
+		SubscriptionID: d.SubscriptionID,
+		IdempotencyKey: d.IdempotencyKey,
+		Payload:        d.Payload,
+		Status:         webhookStatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(&rec).Error
+}
+
+func (r *GormWebhookRepository) Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]webhook.Delivery, error) {
+	var recs []WebhookDeliveryRecord
+	err := conn(ctx, r.db).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, webhookStatusPending, now, limit).
+		Scan(&recs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(recs))
+	for _, rec := range recs {
+		deliveries = append(deliveries, webhook.Delivery{
+			ID:             rec.ID,
+			SubscriptionID: rec.SubscriptionID,
+			IdempotencyKey: rec.IdempotencyKey,
+			Payload:        rec.Payload,
+			Attempts:       rec.Attempts,
+			CreatedAt:      rec.CreatedAt,
+		})
+	}
+	return deliveries, nil
+}
+
+func (r *GormWebhookRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	return conn(ctx, r.db).Model(&WebhookDeliveryRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"status":  webhookStatusSent,
+		"sent_at": sentAt,
+	}).Error
+}
+
+func (r *GormWebhookRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	return conn(ctx, r.db).Model(&WebhookDeliveryRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastErr,
+	}).Error
+}
+
+func (r *GormWebhookRepository) MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error {
+	return conn(ctx, r.db).Model(&WebhookDeliveryRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     webhookStatusDead,
+		"attempts":   attempts,
+		"last_error": lastErr,
+	}).Error
+}
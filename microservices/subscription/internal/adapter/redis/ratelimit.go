@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitStore keeps fixed-window counters and cooldown markers in Redis.
+type RateLimitStore struct {
+	client *redis.Client
+}
+
+func NewRateLimitStore(client *redis.Client) *RateLimitStore {
+	return &RateLimitStore{client: client}
+}
+
+func (s *RateLimitStore) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	// NX keeps the window anchored at the first hit instead of sliding with every request.
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	return incr.Val(), ttl.Val(), nil
+}
+
+func (s *RateLimitStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// Negative values mean the key is missing or has no expiry.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RateLimitStore) Set(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, 1, ttl).Err()
+}
@@ -2,11 +2,13 @@ package weathergrpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 
 	weatherpb2 "subscription/internal/adapter/weathergrpc/pb"
 	"subscription/internal/domain"
 
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 
@@ -19,8 +21,14 @@ type Client struct {
 	client weatherpb2.WeatherServiceClient
 }
 
-func NewClient(ctx context.Context, addr string) (*Client, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewClient connects to the weather service, over TLS when tlsConfig is set and in
+// plaintext otherwise.
+func NewClient(ctx context.Context, addr string, tlsConfig *tls.Config) (*Client, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, fmt.Errorf("grpc dial: %w", err)
 	}
@@ -30,10 +38,10 @@ func NewClient(ctx context.Context, addr string) (*Client, error) {
 	}, nil
 }
 
-func (c *Client) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (c *Client) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	ctx = c.addCorrelationIDToContext(ctx)
 
-	resp, err := c.client.GetWeather(ctx, &weatherpb2.WeatherRequest{City: city})
+	resp, err := c.client.GetWeather(ctx, &weatherpb2.WeatherRequest{City: city, Lang: lang})
 	if err != nil {
 		return domain.Report{}, err
 	}
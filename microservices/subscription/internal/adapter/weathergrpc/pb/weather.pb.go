@@ -23,8 +23,10 @@ const (
 )
 
 type WeatherRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	City          string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	City  string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// ISO 639-1 code of the language for the description, e.g. "uk"; empty means English.
+	Lang          string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +68,13 @@ func (x *WeatherRequest) GetCity() string {
 	return ""
 }
 
+func (x *WeatherRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
 type WeatherResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Temperature   float64                `protobuf:"fixed64,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
@@ -218,9 +227,10 @@ var File_weather_proto protoreflect.FileDescriptor
 
 const file_weather_proto_rawDesc = "" +
 	"\n" +
-	"\rweather.proto\x12\aweather\"$\n" +
+	"\rweather.proto\x12\aweather\"8\n" +
 	"\x0eWeatherRequest\x12\x12\n" +
-	"\x04city\x18\x01 \x01(\tR\x04city\"q\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\"q\n" +
 	"\x0fWeatherResponse\x12 \n" +
 	"\vtemperature\x18\x01 \x01(\x01R\vtemperature\x12\x1a\n" +
 	"\bhumidity\x18\x02 \x01(\x05R\bhumidity\x12 \n" +
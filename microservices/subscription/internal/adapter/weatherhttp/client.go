@@ -16,6 +16,17 @@ import (
 
 var ErrCityNotFound = domain.ErrCityNotFound
 
+// StatusError reports an unexpected response status so callers can tell server failures
+// from client errors.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Code, e.Status)
+}
+
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
@@ -37,8 +48,8 @@ type weatherResponse struct {
 	Description string  `json:"description"`
 }
 
-func (c *Client) GetWeather(ctx context.Context, city string) (domain.Report, error) {
-	endpoint := fmt.Sprintf("%s/weather?city=%s", c.baseURL, url.QueryEscape(city))
+func (c *Client) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	endpoint := fmt.Sprintf("%s/weather?city=%s&lang=%s", c.baseURL, url.QueryEscape(city), url.QueryEscape(lang))
 
 	resp, err := c.doRequest(ctx, http.MethodGet, endpoint)
 	if err != nil {
@@ -51,7 +62,7 @@ func (c *Client) GetWeather(ctx context.Context, city string) (domain.Report, er
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return domain.Report{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, resp.Status)
+		return domain.Report{}, &StatusError{Code: resp.StatusCode, Status: resp.Status}
 	}
 
 	var res weatherResponse
@@ -76,7 +87,7 @@ func (c *Client) CityIsValid(ctx context.Context, city string) (bool, error) {
 	defer c.closeBody(ctx, resp.Body, "CityIsValid")
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, resp.Status)
+		return false, &StatusError{Code: resp.StatusCode, Status: resp.Status}
 	}
 
 	var data struct {
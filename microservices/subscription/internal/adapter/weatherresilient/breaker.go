@@ -0,0 +1,109 @@
+package weatherresilient
+
+import (
+	"sync"
+	"time"
+)
+
+// State of a circuit breaker; the values are exported as the breaker state gauge.
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker opens after threshold consecutive transient failures and rejects calls until the
+// cooldown has passed. Then a single probe is let through: success closes the breaker,
+// failure opens it for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration, now func() time.Time) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: now}
+}
+
+// allow reports whether a call may go through. The returned state is the one after the check
+// and changed is set when the check moved the breaker to half-open.
+func (b *breaker) allow() (ok bool, state State, changed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false, b.state, false
+		}
+		b.probing = true
+		return true, StateHalfOpen, b.set(StateHalfOpen)
+	case StateHalfOpen:
+		if b.probing {
+			return false, b.state, false
+		}
+		b.probing = true
+		return true, b.state, false
+	default:
+		return true, b.state, false
+	}
+}
+
+// success records a call that reached the transport.
+func (b *breaker) success() (State, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	return StateClosed, b.set(StateClosed)
+}
+
+// failure records a transient failure.
+func (b *breaker) failure() (State, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if b.threshold <= 0 {
+		return b.state, false
+	}
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		return StateOpen, b.set(StateOpen)
+	}
+	return b.state, false
+}
+
+// release gives back a probe slot when the call was abandoned without a verdict.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) set(state State) bool {
+	changed := b.state != state
+	b.state = state
+	return changed
+}
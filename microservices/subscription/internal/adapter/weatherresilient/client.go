@@ -0,0 +1,302 @@
+package weatherresilient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"subscription/internal/adapter/weatherhttp"
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrCircuitOpen = errors.New("weather circuit breaker open")
+
+// Call results; also used as metric labels.
+const (
+	ResultSuccess   = "success"
+	ResultError     = "error"
+	ResultTransient = "transient_error"
+	ResultRejected  = "rejected"
+	ResultCanceled  = "canceled"
+)
+
+const (
+	opGetWeather  = "get_weather"
+	opCityIsValid = "city_is_valid"
+)
+
+type weatherClient interface {
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
+	CityIsValid(ctx context.Context, city string) (bool, error)
+}
+
+type clientMetrics interface {
+	RecordCall(transport, operation, result string)
+	RecordRetry(transport, operation string)
+	RecordFallback(from, to string)
+	SetBreakerState(transport string, state State)
+}
+
+// Transport is one way of reaching the weather service. Transports are tried in the order
+// they are passed to NewClient.
+type Transport struct {
+	Name   string
+	Client weatherClient
+}
+
+type Config struct {
+	// MaxAttempts is the number of calls made on one transport before moving on to the next.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// CallTimeout bounds a single attempt; 0 leaves it to the caller's context.
+	CallTimeout time.Duration
+	// BreakerThreshold is the number of consecutive transient failures that opens a
+	// transport's breaker; 0 disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:      3,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         2 * time.Second,
+		CallTimeout:      5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+type guardedTransport struct {
+	Transport
+	breaker *breaker
+}
+
+// Client retries transient errors with jittered exponential backoff, keeps a circuit breaker
+// per transport and falls back to the next transport when one cannot serve the call.
+// Answers from the weather service itself, such as an unknown city, are returned as is.
+type Client struct {
+	transports []*guardedTransport
+	metrics    clientMetrics
+	cfg        Config
+
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(limit time.Duration) time.Duration
+}
+
+func NewClient(metrics clientMetrics, cfg Config, transports ...Transport) *Client {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	c := &Client{
+		metrics: metrics,
+		cfg:     cfg,
+		sleep:   sleep,
+		jitter:  jitter,
+	}
+	for _, t := range transports {
+		c.transports = append(c.transports, &guardedTransport{
+			Transport: t,
+			breaker:   newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, time.Now),
+		})
+		metrics.SetBreakerState(t.Name, StateClosed)
+	}
+	return c
+}
+
+func (c *Client) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	var report domain.Report
+	err := c.do(ctx, opGetWeather, func(ctx context.Context, client weatherClient) error {
+		var err error
+		report, err = client.GetWeather(ctx, city, lang)
+		return err
+	})
+	return report, err
+}
+
+func (c *Client) CityIsValid(ctx context.Context, city string) (bool, error) {
+	var valid bool
+	err := c.do(ctx, opCityIsValid, func(ctx context.Context, client weatherClient) error {
+		var err error
+		valid, err = client.CityIsValid(ctx, city)
+		return err
+	})
+	return valid, err
+}
+
+// Close closes the transports that hold connections.
+func (c *Client) Close() error {
+	var errs []error
+	for _, t := range c.transports {
+		if closer, ok := t.Client.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", t.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Client) do(ctx context.Context, op string, call func(context.Context, weatherClient) error) error {
+	var err error
+	for i, t := range c.transports {
+		if i > 0 {
+			from := c.transports[i-1].Name
+			c.metrics.RecordFallback(from, t.Name)
+			loggerPkg.From(ctx).Warn("Weather transport unavailable, falling back",
+				"from", from, "to", t.Name, "operation", op, "err", err)
+		}
+		err = c.try(ctx, t, op, call)
+		if err == nil || ctx.Err() != nil || !(errors.Is(err, ErrCircuitOpen) || IsTransient(err)) {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) try(ctx context.Context, t *guardedTransport, op string, call func(context.Context, weatherClient) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		ok, state, changed := t.breaker.allow()
+		c.observe(ctx, t, state, changed)
+		if !ok {
+			c.metrics.RecordCall(t.Name, op, ResultRejected)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%s: %w", t.Name, ErrCircuitOpen)
+		}
+
+		err = c.attempt(ctx, t, call)
+		switch {
+		case err == nil:
+			c.metrics.RecordCall(t.Name, op, ResultSuccess)
+			state, changed = t.breaker.success()
+			c.observe(ctx, t, state, changed)
+			return nil
+		case ctx.Err() != nil:
+			c.metrics.RecordCall(t.Name, op, ResultCanceled)
+			t.breaker.release()
+			return err
+		case !IsTransient(err):
+			// The transport delivered the weather service's answer, so it counts as healthy.
+			c.metrics.RecordCall(t.Name, op, ResultError)
+			state, changed = t.breaker.success()
+			c.observe(ctx, t, state, changed)
+			return err
+		}
+
+		c.metrics.RecordCall(t.Name, op, ResultTransient)
+		state, changed = t.breaker.failure()
+		c.observe(ctx, t, state, changed)
+		if attempt >= c.cfg.MaxAttempts {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		loggerPkg.From(ctx).Warn("Weather call failed, retrying",
+			"transport", t.Name, "operation", op, "attempt", attempt, "delay", delay, "err", err)
+		if c.sleep(ctx, delay) != nil {
+			return err
+		}
+		c.metrics.RecordRetry(t.Name, op)
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, t *guardedTransport, call func(context.Context, weatherClient) error) error {
+	if c.cfg.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.CallTimeout)
+		defer cancel()
+	}
+	return call(ctx, t.Client)
+}
+
+func (c *Client) observe(ctx context.Context, t *guardedTransport, state State, changed bool) {
+	c.metrics.SetBreakerState(t.Name, state)
+	if !changed {
+		return
+	}
+	logger := loggerPkg.From(ctx)
+	if state == StateOpen {
+		logger.Error("Weather circuit breaker opened", "transport", t.Name, "cooldown", c.cfg.BreakerCooldown)
+		return
+	}
+	logger.Info("Weather circuit breaker state changed", "transport", t.Name, "state", state.String())
+}
+
+// backoff doubles the delay with every attempt up to MaxDelay and randomises the upper half
+// so that workers hitting the same outage do not retry in lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.cfg.BaseDelay <= 0 {
+		return 0
+	}
+	delay := c.cfg.BaseDelay
+	for i := 1; i < attempt && delay < c.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if c.cfg.MaxDelay > 0 && delay > c.cfg.MaxDelay {
+		delay = c.cfg.MaxDelay
+	}
+	half := delay / 2
+	return half + c.jitter(delay-half)
+}
+
+// IsTransient reports whether err means the transport could not serve the call, as opposed
+// to the weather service answering with an error such as an unknown city.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, domain.ErrCityNotFound) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var statusErr *weatherhttp.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= http.StatusInternalServerError || statusErr.Code == http.StatusTooManyRequests
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+			return true
+		default:
+			return false
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit + 1)
+}
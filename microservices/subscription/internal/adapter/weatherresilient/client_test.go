@@ -0,0 +1,266 @@
+package weatherresilient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"subscription/internal/adapter/weatherhttp"
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeTransport struct {
+	errs  []error
+	calls int
+}
+
+// GetWeather fails with the queued errors in order and succeeds once they run out.
+func (f *fakeTransport) GetWeather(_ context.Context, city, _ string) (domain.Report, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return domain.Report{}, err
+	}
+	return domain.Report{Temperature: 21, Humidity: 40, Description: "Sunny in " + city}, nil
+}
+
+func (f *fakeTransport) CityIsValid(ctx context.Context, city string) (bool, error) {
+	if _, err := f.GetWeather(ctx, city, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type recordingMetrics struct {
+	calls     map[string]int
+	retries   int
+	fallbacks int
+	states    map[string]State
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{calls: map[string]int{}, states: map[string]State{}}
+}
+
+func (m *recordingMetrics) RecordCall(transport, _, result string) { m.calls[transport+":"+result]++ }
+func (m *recordingMetrics) RecordRetry(string, string)             { m.retries++ }
+func (m *recordingMetrics) RecordFallback(string, string)          { m.fallbacks++ }
+func (m *recordingMetrics) SetBreakerState(transport string, s State) {
+	m.states[transport] = s
+}
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+func newTestClient(cfg Config, transports ...Transport) (*Client, *recordingMetrics, *[]time.Duration) {
+	metrics := newRecordingMetrics()
+	c := NewClient(metrics, cfg, transports...)
+	var sleeps []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	c.jitter = func(time.Duration) time.Duration { return 0 }
+	return c, metrics, &sleeps
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	primary := &fakeTransport{errs: []error{errUnavailable, errUnavailable}}
+	c, metrics, sleeps := newTestClient(DefaultConfig(), Transport{Name: "grpc", Client: primary})
+
+	report, err := c.GetWeather(context.Background(), "Kyiv", "uk")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in Kyiv", report.Description)
+	assert.Equal(t, 3, primary.calls)
+	assert.Equal(t, 2, metrics.retries)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *sleeps)
+	assert.Equal(t, 1, metrics.calls["grpc:"+ResultSuccess])
+	assert.Equal(t, 2, metrics.calls["grpc:"+ResultTransient])
+}
+
+func TestClient_DoesNotRetryOrFallBackOnServiceAnswer(t *testing.T) {
+	notFound := status.Error(codes.Unknown, "city not found")
+	primary := &fakeTransport{errs: []error{notFound}}
+	fallback := &fakeTransport{}
+	c, metrics, _ := newTestClient(DefaultConfig(),
+		Transport{Name: "grpc", Client: primary},
+		Transport{Name: "http", Client: fallback},
+	)
+
+	_, err := c.CityIsValid(context.Background(), "Atlantis")
+
+	assert.Equal(t, notFound, err)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 0, fallback.calls)
+	assert.Zero(t, metrics.fallbacks)
+	assert.Equal(t, 1, metrics.calls["grpc:"+ResultError])
+}
+
+func TestClient_FallsBackWhenPrimaryIsUnavailable(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 2
+	primary := &fakeTransport{errs: []error{errUnavailable, errUnavailable}}
+	fallback := &fakeTransport{}
+	c, metrics, _ := newTestClient(cfg,
+		Transport{Name: "grpc", Client: primary},
+		Transport{Name: "http", Client: fallback},
+	)
+
+	report, err := c.GetWeather(context.Background(), "Lviv", "uk")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in Lviv", report.Description)
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 1, fallback.calls)
+	assert.Equal(t, 1, metrics.fallbacks)
+}
+
+func TestClient_ReturnsLastErrorWhenAllTransportsFail(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 1
+	httpErr := &weatherhttp.StatusError{Code: http.StatusBadGateway, Status: "502 Bad Gateway"}
+	c, _, _ := newTestClient(cfg,
+		Transport{Name: "grpc", Client: &fakeTransport{errs: []error{errUnavailable}}},
+		Transport{Name: "http", Client: &fakeTransport{errs: []error{httpErr}}},
+	)
+
+	_, err := c.GetWeather(context.Background(), "Odesa", "uk")
+
+	assert.ErrorIs(t, err, httpErr)
+}
+
+func TestClient_BreakerOpensAndRecovers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 1
+	cfg.BreakerThreshold = 2
+	cfg.BreakerCooldown = time.Minute
+	primary := &fakeTransport{errs: []error{errUnavailable, errUnavailable}}
+	c, metrics, _ := newTestClient(cfg, Transport{Name: "grpc", Client: primary})
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.transports[0].breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := c.GetWeather(ctx, "Kyiv", "uk")
+		require.Error(t, err)
+	}
+	assert.Equal(t, StateOpen, metrics.states["grpc"])
+
+	_, err := c.GetWeather(ctx, "Kyiv", "uk")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, primary.calls, "open breaker must not reach the transport")
+	assert.Equal(t, 1, metrics.calls["grpc:"+ResultRejected])
+
+	now = now.Add(time.Minute)
+	_, err = c.GetWeather(ctx, "Kyiv", "uk")
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, metrics.states["grpc"])
+}
+
+func TestClient_OpenBreakerSkipsStraightToFallback(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 1
+	cfg.BreakerThreshold = 1
+	primary := &fakeTransport{errs: []error{errUnavailable}}
+	fallback := &fakeTransport{}
+	c, _, _ := newTestClient(cfg,
+		Transport{Name: "grpc", Client: primary},
+		Transport{Name: "http", Client: fallback},
+	)
+
+	for range 3 {
+		_, err := c.GetWeather(context.Background(), "Dnipro", "uk")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 3, fallback.calls)
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b := newBreaker(3, time.Minute, nil)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	for range 3 {
+		b.failure()
+	}
+	ok, _, _ := b.allow()
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	ok, state, changed := b.allow()
+	assert.True(t, ok)
+	assert.Equal(t, StateHalfOpen, state)
+	assert.True(t, changed)
+
+	ok, _, _ = b.allow()
+	assert.False(t, ok, "only one probe at a time")
+
+	state, _ = b.failure()
+	assert.Equal(t, StateOpen, state)
+	ok, _, _ = b.allow()
+	assert.False(t, ok)
+}
+
+func TestClient_CanceledContextStopsWithoutFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := &fakeTransport{errs: []error{context.Canceled}}
+	fallback := &fakeTransport{}
+	c, _, _ := newTestClient(DefaultConfig(),
+		Transport{Name: "grpc", Client: primary},
+		Transport{Name: "http", Client: fallback},
+	)
+	cancel()
+
+	_, err := c.GetWeather(ctx, "Kyiv", "uk")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, fallback.calls)
+}
+
+func TestClient_BackoffIsCapped(t *testing.T) {
+	c := NewClient(newRecordingMetrics(), Config{BaseDelay: 200 * time.Millisecond, MaxDelay: time.Second})
+	c.jitter = func(limit time.Duration) time.Duration { return limit }
+
+	assert.Equal(t, 200*time.Millisecond, c.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, c.backoff(2))
+	assert.Equal(t, 800*time.Millisecond, c.backoff(3))
+	assert.Equal(t, time.Second, c.backoff(4))
+	assert.Equal(t, time.Second, c.backoff(30))
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"grpc unavailable", errUnavailable, true},
+		{"grpc deadline", status.Error(codes.DeadlineExceeded, "slow"), true},
+		{"grpc unknown", status.Error(codes.Unknown, "city not found"), false},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "empty city"), false},
+		{"city not found", fmt.Errorf("lookup: %w", domain.ErrCityNotFound), false},
+		{"http 503", &weatherhttp.StatusError{Code: http.StatusServiceUnavailable}, true},
+		{"http 429", &weatherhttp.StatusError{Code: http.StatusTooManyRequests}, true},
+		{"http 400", &weatherhttp.StatusError{Code: http.StatusBadRequest}, false},
+		{"network", fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: errors.New("refused")}), true},
+		{"call timeout", fmt.Errorf("request failed: %w", context.DeadlineExceeded), true},
+		{"decode", errors.New("decode weather response: unexpected EOF"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
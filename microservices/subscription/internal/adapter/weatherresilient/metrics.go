@@ -0,0 +1,70 @@
+package weatherresilient
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	calls     *prometheus.CounterVec
+	retries   *prometheus.CounterVec
+	fallbacks *prometheus.CounterVec
+	breaker   *prometheus.GaugeVec
+	once      sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		calls: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_weather_calls_total",
+				Help: "Weather service calls by transport, operation and result",
+			},
+			[]string{"transport", "operation", "result"},
+		),
+		retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_weather_retries_total",
+				Help: "Weather service calls retried after a transient error",
+			},
+			[]string{"transport", "operation"},
+		),
+		fallbacks: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_weather_fallbacks_total",
+				Help: "Weather service calls handed over to the fallback transport",
+			},
+			[]string{"from", "to"},
+		),
+		breaker: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "subscription_weather_circuit_state",
+				Help: "Weather transport circuit breaker state: 0 closed, 1 open, 2 half-open",
+			},
+			[]string{"transport"},
+		),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.calls, m.retries, m.fallbacks, m.breaker)
+	})
+}
+
+func (m *Metrics) RecordCall(transport, operation, result string) {
+	m.calls.WithLabelValues(transport, operation, result).Inc()
+}
+
+func (m *Metrics) RecordRetry(transport, operation string) {
+	m.retries.WithLabelValues(transport, operation).Inc()
+}
+
+func (m *Metrics) RecordFallback(from, to string) {
+	m.fallbacks.WithLabelValues(from, to).Inc()
+}
+
+func (m *Metrics) SetBreakerState(transport string, state State) {
+	m.breaker.WithLabelValues(transport).Set(float64(state))
+}
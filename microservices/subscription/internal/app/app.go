@@ -2,9 +2,11 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,28 +15,58 @@ import (
 
 	"subscription/internal/adapter/email/async"
 	"subscription/internal/adapter/gorm"
+	redisAdapter "subscription/internal/adapter/redis"
 	"subscription/internal/adapter/weathergrpc"
 	"subscription/internal/adapter/weatherhttp"
+	"subscription/internal/adapter/weatherresilient"
 	"subscription/internal/app/di"
+	"subscription/internal/backfill"
 	"subscription/internal/config"
 	"subscription/internal/delivery"
+	"subscription/internal/delivery/grpcapi"
+	"subscription/internal/domain"
+	"subscription/internal/emailcheck"
+	"subscription/internal/events"
+	"subscription/internal/expiry"
 	"subscription/internal/infra"
 	"subscription/internal/infra/rabbitmq"
+	"subscription/internal/job"
+	"subscription/internal/leader"
+	"subscription/internal/locale"
+	"subscription/internal/outbox"
+	subscriptionpb "subscription/internal/proto"
+	"subscription/internal/ratelimit"
 	"subscription/internal/subscription"
+	"subscription/internal/token"
 	"subscription/internal/token/jwt"
+	"subscription/internal/webhook"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 	metricsPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig"
 )
 
 type App struct {
 	Server        *http.Server
+	GrpcServer    *grpc.Server
+	GrpcLis       net.Listener
 	DB            *infra.Gorm
 	Scheduler     *di.WeatherScheduler
 	WeatherClient io.Closer
+	WeatherTLS    *tlsconfig.Source
 	RabbitMQConn  *rabbitmq.Connection
+	OutboxRelay   *outbox.Relay
+	Webhooks      *webhook.Dispatcher
+	Elector       *leader.Elector
+	StatusEvents  *async.StatusConsumer
+	Suppressions  *async.SuppressionConsumer
+	Expiry        *expiry.Sweeper
+	AddressMerge  *backfill.AddressMerge
+	Redis         *redis.Client
 }
 
 func Run(logger *loggerPkg.Logger) error {
@@ -86,7 +118,7 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	logger.Info("Connected to database")
 
 	// Async email client
-	rmqConn, err := rabbitmq.NewConnection(cfg.RabbitMQUrl)
+	rmqConn, err := rabbitmq.NewConnection(cfg.RabbitMQUrl, rabbitmq.Backoff{Base: cfg.ReconnectBaseDelay, Max: cfg.ReconnectMaxDelay})
 	if err != nil {
 		logger.Error("Failed to connect to RabbitMQ", "err", err)
 		return nil, fmt.Errorf("connection to RabbitMQ: %w", err)
@@ -97,26 +129,61 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("exchange verification: %w", err)
 	}
 	logger.Info("Verified RabbitMQ exchange")
-	rabbitPublisher := async.NewRabbitPublisher(rmqConn.Channel(), cfg.RabbitMQExchange)
-	emailClient := async.NewAsyncClient(rabbitPublisher, cfg.BaseURL)
+	publisherMetrics := async.NewPublisherMetrics()
+	publisherMetrics.Register()
+	publisherCfg := async.DefaultPublisherConfig()
+	publisherCfg.ConfirmTimeout = cfg.PublishConfirmWait
+	publisherCfg.MaxAttempts = cfg.PublishAttempts
+	publisherCfg.RetryDelay = cfg.PublishRetryDelay
+	// Lifecycle events are broadcast; nobody has to be listening for them.
+	publisherCfg.OptionalRouting = []string{cfg.EventsExchange}
+	rabbitPublisher := async.NewRabbitPublisher(rmqConn, cfg.RabbitMQExchange, publisherMetrics, publisherCfg)
+
+	// Outbox: email commands are stored with the subscription change and relayed to RabbitMQ
+	outboxRepo := gorm.NewOutboxRepo(db.Gorm)
+	outboxMetrics := outbox.NewMetrics()
+	outboxMetrics.Register()
+	relayCfg := outbox.DefaultRelayConfig()
+	relayCfg.PollInterval = cfg.OutboxPollInterval
+	relayCfg.BatchSize = cfg.OutboxBatchSize
+	relayCfg.MaxAttempts = cfg.OutboxMaxAttempts
+	relayCfg.Retention = cfg.OutboxRetention
+	outboxRelay := outbox.NewRelay(outboxRepo, rabbitPublisher, outboxMetrics, relayCfg)
+	outboxWriter := outbox.NewWriter(outboxRepo)
+	emailClient := async.NewAsyncClient(outboxWriter, cfg.BaseURL)
+
+	// Subscription lifecycle events for other services, relayed through the outbox as well
+	if err := rabbitmq.DeclareExchange(rmqConn.Channel(), cfg.EventsExchange, "topic"); err != nil {
+		logger.Error("Failed to declare events exchange", "err", err)
+		return nil, fmt.Errorf("events exchange: %w", err)
+	}
+	eventPublisher := events.NewPublisher(outboxWriter, cfg.EventsExchange, cfg.EventsEmailHashKey)
 
-	// Weather client
-	var weatherClient subscription.WeatherClient
-	var weatherCloser io.Closer
+	// Delivery history, updated with send outcomes reported by the email service
+	deliveryRepo := gorm.NewDeliveryRepo(db.Gorm)
+	statusCh, err := rmqConn.OpenChannel(ctx)
+	if err != nil {
+		logger.Error("Failed to open RabbitMQ channel", "err", err)
+		return nil, fmt.Errorf("delivery status channel: %w", err)
+	}
+	if err := rabbitmq.DeclareQueue(statusCh, cfg.DeliveryStatusQueue, cfg.RabbitMQExchange, async.RoutingKeyDeliveryStatus); err != nil {
+		logger.Error("Failed to declare delivery status queue", "err", err)
+		return nil, fmt.Errorf("delivery status queue: %w", err)
+	}
+	statusConsumer := async.NewStatusConsumer(statusCh, cfg.DeliveryStatusQueue, deliveryRepo)
 
-	if cfg.UseGRPC {
-		client, err := weathergrpc.NewClient(ctx, cfg.WeatherGRPCAddr)
-		if err != nil {
-			logger.Error("Failed to connect to weather via gRPC", "err", err)
-			return nil, fmt.Errorf("failed to connect to weather via gRPC: %w", err)
-		}
-		weatherClient = client
-		weatherCloser = client
-		logger.Info("Using gRPC weather client")
-	} else {
-		httpClient := weatherhttp.NewClient(cfg.WeatherHTTPAddr)
-		weatherClient = httpClient
-		logger.Info("Using HTTP weather client")
+	// Weather client
+	weatherMetrics := weatherresilient.NewMetrics()
+	weatherMetrics.Register()
+	weatherTLS, err := newWeatherTLS(cfg)
+	if err != nil {
+		logger.Error("Failed to load weather gRPC TLS certificates", "err", err)
+		return nil, fmt.Errorf("weather gRPC TLS: %w", err)
+	}
+	weatherClient, err := newWeatherClient(ctx, cfg, weatherTLS, weatherMetrics)
+	if err != nil {
+		logger.Error("Failed to connect to weather via gRPC", "err", err)
+		return nil, fmt.Errorf("failed to connect to weather via gRPC: %w", err)
 	}
 
 	// Metrics
@@ -126,46 +193,374 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	})
 
 	// Adapters
-	tokenProvider := jwt.NewJWT(cfg.JWTSecret)
+	tokenProvider, err := newTokenProvider(cfg)
+	if err != nil {
+		logger.Error("Failed to configure token signing keys", "err", err)
+		return nil, fmt.Errorf("token provider: %w", err)
+	}
+	tokenService := token.NewService(tokenProvider, gorm.NewRevocationRepo(db.Gorm))
 	subscriptionRepo := gorm.NewRepo(db.Gorm)
 
+	// Webhook channel: reports are queued with the delivery record and posted by the dispatcher
+	webhookRepo := gorm.NewWebhookRepo(db.Gorm)
+
 	// Service
 	subService := subscription.NewService(
 		subscriptionRepo,
 		emailClient,
 		weatherClient,
-		tokenProvider,
+		tokenService,
+		gorm.NewTransactor(db.Gorm),
+		deliveryRepo,
+		eventPublisher,
+		webhook.NewEndpoints(webhookRepo, cfg.WebhookAllowHTTP),
+		webhook.NewChannel(webhookRepo),
 	)
 
+	webhookMetrics := webhook.NewMetrics()
+	webhookMetrics.Register()
+	webhookCfg := webhook.DefaultConfig()
+	webhookCfg.PollInterval = cfg.WebhookPollInterval
+	webhookCfg.MaxAttempts = cfg.WebhookMaxAttempts
+	webhookCfg.BaseBackoff = cfg.WebhookRetryBase
+	webhookCfg.MaxBackoff = cfg.WebhookRetryMax
+	webhookCfg.DisableAfter = cfg.WebhookDisableAfter
+	webhookCfg.VerifyAttempts = cfg.WebhookVerifyTries
+	webhookSender := webhook.NewSender(cfg.WebhookTimeout, cfg.WebhookAllowPrivate)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, webhookSender, deliveryRepo, webhookMetrics, webhookCfg)
+
+	// Bounced and complaining addresses reported by the email service
+	suppressionCh, err := rmqConn.OpenChannel(ctx)
+	if err != nil {
+		logger.Error("Failed to open RabbitMQ channel", "err", err)
+		return nil, fmt.Errorf("suppression channel: %w", err)
+	}
+	if err := rabbitmq.DeclareQueue(suppressionCh, cfg.SuppressionQueue, cfg.RabbitMQExchange, async.RoutingKeyAddressSuppressed); err != nil {
+		logger.Error("Failed to declare suppression queue", "err", err)
+		return nil, fmt.Errorf("suppression queue: %w", err)
+	}
+	suppressionConsumer := async.NewSuppressionConsumer(suppressionCh, cfg.SuppressionQueue, subService)
+
 	// Scheduler
-	scheduler := di.NewScheduler(subService)
+	queue, err := newJobQueue(cfg, db)
+	if err != nil {
+		logger.Error("Failed to configure job queue", "err", err)
+		return nil, fmt.Errorf("job queue: %w", err)
+	}
+	logger.Info("Using job queue", "type", cfg.JobQueue)
+	jobMetrics := job.NewMetrics()
+	jobMetrics.Register()
+	workerCfg := job.DefaultWorkerConfig()
+	workerCfg.Concurrency = cfg.WorkerConcurrency
+	workerCfg.TaskTimeout = cfg.WorkerTaskTimeout
+	var elector *leader.Elector
+	var leaderGate di.Leader = leader.AlwaysLeader{}
+	if cfg.LeaderElection {
+		leaderMetrics := leader.NewMetrics()
+		leaderMetrics.Register()
+		elector = leader.NewElector(gorm.NewAdvisoryLock(db.Gorm, cfg.LeaderLockKey), leaderMetrics, cfg.LeaderCheckInterval)
+		leaderGate = elector
+	}
+	dispatchRuns := gorm.NewDispatchRunRepo(db.Gorm)
+	scheduler := di.NewScheduler(subService, queue, workerCfg, jobMetrics, leaderGate, dispatchRuns, cfg.CatchUpWindow)
+
+	// Reminders for and expiry of unconfirmed subscriptions
+	expiryMetrics := expiry.NewMetrics()
+	expiryMetrics.Register()
+	expiryCfg := expiry.DefaultConfig()
+	expiryCfg.Interval = cfg.ExpirySweepInterval
+	expiryCfg.RemindAfter = cfg.ReminderAfter
+	expiryCfg.ExpireAfter = cfg.UnconfirmedExpiry
+	sweeper := expiry.NewSweeper(subscriptionRepo, emailClient, tokenService, gorm.NewTransactor(db.Gorm), eventPublisher, leaderGate, expiryMetrics, expiryCfg)
+
+	// Stored addresses are brought in line with subscribe's normalisation once, by the leader
+	addressMerge := backfill.NewAddressMerge(gorm.NewBackfillRepo(db.Gorm), gorm.NewTransactor(db.Gorm), eventPublisher, leaderGate, cfg.EmailStripPlusTags, cfg.LeaderCheckInterval)
+
+	privacyService := subscription.NewPrivacyService(
+		subscriptionRepo,
+		tokenService,
+		gorm.NewPrivacyRepo(db.Gorm),
+		emailClient,
+		gorm.NewTransactor(db.Gorm),
+		eventPublisher,
+	)
+	feedService := subscription.NewFeedService(subscriptionRepo, tokenService, deliveryRepo, weatherClient, cfg.FeedEntries)
+
+	addressValidator, err := newAddressValidator(cfg)
+	if err != nil {
+		logger.Error("Failed to configure email validation", "err", err)
+		return nil, fmt.Errorf("email validation: %w", err)
+	}
+
+	locales, err := locale.NewNegotiator(cfg.DefaultLocale, cfg.SupportedLocales)
+	if err != nil {
+		logger.Error("Failed to configure locales", "err", err)
+		return nil, fmt.Errorf("locales: %w", err)
+	}
+
+	// HTTP dependencies
+	deps := delivery.Deps{
+		SubService:    subService,
+		AdminService:  subscription.NewAdminService(subscriptionRepo, emailClient, tokenService, gorm.NewTransactor(db.Gorm), eventPublisher),
+		Privacy:       privacyService,
+		WeatherClient: weatherClient,
+		Leader:        leaderGate,
+		DispatchRuns:  dispatchRuns,
+		Deliveries:    deliveryRepo,
+		AdminToken:    cfg.AdminToken,
+		Addresses:     addressValidator,
+		Locales:       locales,
+		Feeds:         feedService,
+		BaseURL:       cfg.BaseURL,
+	}
+
+	// Abuse protection on subscribe; the honeypot check runs even without Redis
+	var redisClient *redis.Client
+	if cfg.RedisURL != "" {
+		redisClient, err = infra.NewRedisClient(ctx, cfg.RedisURL)
+		if err != nil {
+			logger.Error("Failed to connect to Redis", "err", err)
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		logger.Info("Connected to Redis")
+		limitMetrics := ratelimit.NewMetrics()
+		limitMetrics.Register()
+		deps.SubscribeGuard = ratelimit.NewGuard(redisAdapter.NewRateLimitStore(redisClient), limitMetrics, ratelimit.Config{
+			IPLimit:     int64(cfg.SubscribeIPLimit),
+			IPWindow:    cfg.SubscribeIPWindow,
+			EmailLimit:  int64(cfg.SubscribeEmailLimit),
+			EmailWindow: cfg.SubscribeEmailWindow,
+			Cooldown:    cfg.ConfirmationCooldown,
+		})
+	} else {
+		logger.Warn("REDIS_URL is not set, subscribe rate limits are disabled")
+	}
 
 	// HTTP server
-	router := delivery.SetupRoutes(subService, weatherClient, logger, metrics)
+	router := delivery.SetupRoutes(deps, logger, metrics)
+	// Gin trusts X-Forwarded-For from every peer by default; only the configured proxies are trusted,
+	// so clients cannot pick the IP the subscribe limits see.
+	if len(cfg.TrustedProxies) == 0 {
+		logger.Warn("TRUSTED_PROXIES is none, X-Forwarded-For is ignored; behind the gateway all clients share its IP limit")
+	} else {
+		logger.Info("Trusting X-Forwarded-For from proxies", "proxies", cfg.TrustedProxies)
+	}
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
 	server := &http.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
 
+	// gRPC server for the same public API
+	grpcHandler := grpcapi.NewHandler(subService, weatherClient, addressValidator, deps.SubscribeGuard, locales)
+	if err := grpcHandler.WithTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	grpcLis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return nil, fmt.Errorf("listen gRPC: %w", err)
+	}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpcapi.LoggingUnaryServerInterceptor(logger, metrics, "subscription")))
+	subscriptionpb.RegisterSubscriptionServiceServer(grpcServer, grpcHandler)
+
 	logger.Info("App is ready to serve requests")
 
 	return &App{
 		Server:        server,
+		GrpcServer:    grpcServer,
+		GrpcLis:       grpcLis,
 		DB:            db,
 		Scheduler:     scheduler,
-		WeatherClient: weatherCloser,
+		WeatherClient: weatherClient,
+		WeatherTLS:    weatherTLS,
 		RabbitMQConn:  rmqConn,
+		OutboxRelay:   outboxRelay,
+		Webhooks:      webhookDispatcher,
+		Elector:       elector,
+		StatusEvents:  statusConsumer,
+		Suppressions:  suppressionConsumer,
+		Expiry:        sweeper,
+		AddressMerge:  addressMerge,
+		Redis:         redisClient,
 	}, nil
 }
 
+// newTokenProvider builds the signing keyring. JWT_SECRET is registered under the "default" kid,
+// JWT_KEYS adds further kid:secret pairs so old links keep validating after a rotation.
+// JWT_SECRET also verifies the kid-less tokens issued before the keyring existed.
+func newTokenProvider(cfg *config.Config) (*jwt.JWT, error) {
+	keys, err := jwt.ParseKeys(cfg.JWTKeys)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := keys[jwt.DefaultKID]; !ok {
+		keys[jwt.DefaultKID] = cfg.JWTSecret
+	}
+
+	return jwt.New(jwt.Config{
+		Keys:      keys,
+		ActiveKID: cfg.JWTActiveKID,
+		LegacyKID: jwt.DefaultKID,
+		TTLs: map[domain.TokenPurpose]time.Duration{
+			domain.TokenPurposeConfirm:     cfg.ConfirmTokenTTL,
+			domain.TokenPurposeManage:      cfg.ManageTokenTTL,
+			domain.TokenPurposeUnsubscribe: 0,
+			domain.TokenPurposeFeed:        cfg.FeedTokenTTL,
+		},
+	})
+}
+
+// newAddressValidator extends the bundled disposable-domain list with EMAIL_BLOCKLIST_FILE, if set.
+func newAddressValidator(cfg *config.Config) (*emailcheck.Validator, error) {
+	checkCfg := emailcheck.DefaultConfig()
+	checkCfg.StripPlusTags = cfg.EmailStripPlusTags
+	checkCfg.CheckMX = cfg.EmailCheckMX
+	checkCfg.LookupTimeout = cfg.EmailMXTimeout
+	if cfg.EmailBlocklistFile != "" {
+		extra, err := emailcheck.LoadBlocklist(cfg.EmailBlocklistFile)
+		if err != nil {
+			return nil, err
+		}
+		checkCfg.BlockedDomains = append(checkCfg.BlockedDomains, extra...)
+	}
+	return emailcheck.NewValidator(net.DefaultResolver, checkCfg), nil
+}
+
+// newWeatherTLS loads the certificates for the weather gRPC connection, or returns nil when
+// the connection is plaintext.
+func newWeatherTLS(cfg *config.Config) (*tlsconfig.Source, error) {
+	if !cfg.UseGRPC || !cfg.WeatherGRPCTLS {
+		return nil, nil
+	}
+	return tlsconfig.Load(tlsconfig.Options{
+		CertFile:       cfg.WeatherGRPCCertFile,
+		KeyFile:        cfg.WeatherGRPCKeyFile,
+		CAFile:         cfg.WeatherGRPCCAFile,
+		ServerName:     cfg.WeatherGRPCHost,
+		ReloadInterval: cfg.WeatherTLSReload,
+	})
+}
+
+// newWeatherClient wraps the configured weather transports with retries and circuit breakers.
+// With gRPC enabled, HTTP is kept as a fallback unless WEATHER_FALLBACK_HTTP is off.
+func newWeatherClient(ctx context.Context, cfg *config.Config, tlsSource *tlsconfig.Source, metrics *weatherresilient.Metrics) (*weatherresilient.Client, error) {
+	logger := loggerPkg.From(ctx)
+	var transports []weatherresilient.Transport
+
+	if cfg.UseGRPC {
+		var tlsCfg *tls.Config
+		if tlsSource != nil {
+			tlsCfg = tlsSource.ClientConfig()
+		}
+		client, err := weathergrpc.NewClient(ctx, cfg.WeatherGRPCAddr, tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+		transports = append(transports, weatherresilient.Transport{Name: "grpc", Client: client})
+	}
+	if !cfg.UseGRPC || cfg.WeatherFallbackHTTP {
+		transports = append(transports, weatherresilient.Transport{Name: "http", Client: weatherhttp.NewClient(cfg.WeatherHTTPAddr)})
+	}
+
+	names := make([]string, 0, len(transports))
+	for _, t := range transports {
+		names = append(names, t.Name)
+	}
+	logger.Info("Using weather transports", "transports", names)
+
+	return weatherresilient.NewClient(metrics, weatherresilient.Config{
+		MaxAttempts:      cfg.WeatherRetryAttempts,
+		BaseDelay:        cfg.WeatherRetryBase,
+		MaxDelay:         cfg.WeatherRetryMax,
+		CallTimeout:      cfg.WeatherCallTimeout,
+		BreakerThreshold: cfg.WeatherBreakerLimit,
+		BreakerCooldown:  cfg.WeatherBreakerWait,
+	}, transports...), nil
+}
+
+// newJobQueue selects the weather report queue: "postgres" survives restarts and retries failed tasks,
+// "local" is an in-memory channel.
+func newJobQueue(cfg *config.Config, db *infra.Gorm) (di.Queue, error) {
+	switch cfg.JobQueue {
+	case "postgres":
+		queueCfg := job.DefaultDurableQueueConfig()
+		queueCfg.VisibilityTimeout = cfg.JobVisibilityTimeout
+		queueCfg.MaxAttempts = cfg.JobMaxAttempts
+		return job.NewDurableQueue(gorm.NewJobRepo(db.Gorm), queueCfg), nil
+	case "local":
+		return job.NewLocalQueue(100), nil
+	default:
+		return nil, fmt.Errorf("unknown JOB_QUEUE %q", cfg.JobQueue)
+	}
+}
+
 func (a *App) StartServer(ctx context.Context) error {
 	logger := loggerPkg.From(ctx)
+	if a.Elector != nil {
+		go func() {
+			logger.Info("Starting leader election")
+			eLogger := logger.With("module", "leader")
+			a.Elector.Run(loggerPkg.With(ctx, eLogger))
+		}()
+	}
+
+	if a.WeatherTLS != nil {
+		go a.WeatherTLS.Watch(ctx, func(err error) {
+			if err != nil {
+				logger.Error("Failed to reload weather gRPC TLS certificates, keeping the previous ones", "err", err)
+				return
+			}
+			logger.Info("Weather gRPC TLS certificates reloaded")
+		})
+	}
+
 	go func() {
 		logger.Info("Starting scheduler")
 		a.Scheduler.Start(ctx)
 		logger.Info("Scheduler stopped")
 	}()
 
+	go func() {
+		logger.Info("Starting outbox relay")
+		rLogger := logger.With("module", "outbox")
+		a.OutboxRelay.Start(loggerPkg.With(ctx, rLogger))
+	}()
+
+	go func() {
+		logger.Info("Starting webhook dispatcher")
+		wLogger := logger.With("module", "webhook")
+		a.Webhooks.Start(loggerPkg.With(ctx, wLogger))
+	}()
+
+	go func() {
+		logger.Info("Starting expiry sweeper")
+		sLogger := logger.With("module", "expiry")
+		a.Expiry.Start(loggerPkg.With(ctx, sLogger))
+	}()
+
+	go func() {
+		bLogger := logger.With("module", "backfill")
+		a.AddressMerge.Start(loggerPkg.With(ctx, bLogger))
+	}()
+
+	go func() {
+		logger.Info("Starting delivery status consumer")
+		cLogger := logger.With("module", "delivery_status")
+		if err := a.StatusEvents.Start(loggerPkg.With(ctx, cLogger)); err != nil {
+			logger.Error("Delivery status consumer error", "error", err)
+		}
+	}()
+
+	go func() {
+		logger.Info("Starting suppression consumer")
+		cLogger := logger.With("module", "suppression")
+		if err := a.Suppressions.Start(loggerPkg.With(ctx, cLogger)); err != nil {
+			logger.Error("Suppression consumer error", "error", err)
+		}
+	}()
+
 	go func() {
 		logger.Info("Server listening", "addr", a.Server.Addr)
 		if err := a.Server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -173,6 +568,14 @@ func (a *App) StartServer(ctx context.Context) error {
 		}
 		logger.Info("HTTP server stopped")
 	}()
+
+	go func() {
+		logger.Info("gRPC server listening", "addr", a.GrpcLis.Addr().String())
+		if err := a.GrpcServer.Serve(a.GrpcLis); err != nil {
+			logger.Error("gRPC server error", "error", err)
+		}
+		logger.Info("gRPC server stopped")
+	}()
 	return nil
 }
 
@@ -184,6 +587,17 @@ func (a *App) Shutdown(ctx context.Context) error {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
 
+	done := make(chan struct{})
+	go func() {
+		a.GrpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		a.GrpcServer.Stop()
+	}
+
 	a.Scheduler.Stop(ctx)
 	logger.Info("Scheduler stopped")
 
@@ -201,6 +615,14 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if a.Redis != nil {
+		if err := a.Redis.Close(); err != nil {
+			logger.Error("Redis client close error", "err", err)
+		} else {
+			logger.Info("Redis client closed")
+		}
+	}
+
 	a.DB.Close(ctx)
 	logger.Info("Database connection closed")
 
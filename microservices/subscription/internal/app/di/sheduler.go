@@ -3,6 +3,7 @@ package di
 import (
 	"context"
 	"sync"
+	"time"
 
 	"subscription/internal/job"
 	"subscription/internal/subscription"
@@ -10,28 +11,55 @@ import (
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 )
 
+// Queue is the task queue shared by the dispatcher and the worker.
+type Queue interface {
+	Enqueue(ctx context.Context, task job.Task) error
+	Dequeue(ctx context.Context) (job.Task, error)
+	Close(ctx context.Context)
+}
+
+// Leader reports whether this replica owns the cron.
+type Leader interface {
+	IsLeader() bool
+}
+
+// Runs is the dispatch ledger used for once-only runs and missed-run catch-up.
+type Runs interface {
+	Start(ctx context.Context, run job.Run) (job.Run, error)
+	Finish(ctx context.Context, run job.Run) error
+	LatestScheduled(ctx context.Context, frequency string) (time.Time, bool, error)
+}
+
 type WeatherScheduler struct {
-	queue      *job.LocalQueue
+	queue      Queue
 	dispatcher *job.EmailDispatcher
 	worker     *job.Worker
 	cron       *job.CronEventSource
+	catchUp    *job.CatchUp
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 }
 
 func NewScheduler(
 	subService subscription.Service,
+	queue Queue,
+	workerCfg job.WorkerConfig,
+	metrics *job.Metrics,
+	leader Leader,
+	runs Runs,
+	catchUpWindow time.Duration,
 ) *WeatherScheduler {
-	queue := job.NewLocalQueue(100)
-	cron := job.NewCronEventSource()
-	dispatcher := job.NewEmailDispatcher(subService, queue, cron)
-	worker := job.NewWorker(queue, subService)
+	cron := job.NewCronEventSource(leader)
+	dispatcher := job.NewEmailDispatcher(subService, queue, cron, runs, metrics)
+	worker := job.NewWorker(queue, subService, metrics, workerCfg)
+	catchUp := job.NewCatchUp(runs, cron, leader, catchUpWindow)
 
 	return &WeatherScheduler{
 		queue:      queue,
 		dispatcher: dispatcher,
 		worker:     worker,
 		cron:       cron,
+		catchUp:    catchUp,
 	}
 }
 
@@ -51,14 +79,27 @@ func (s *WeatherScheduler) Start(ctx context.Context) {
 
 	cLogger := loggerPkg.From(ctx).With("module", "scheduler")
 	s.cron.Start(loggerPkg.With(ctx, cLogger))
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		uLogger := loggerPkg.From(ctx).With("module", "scheduler")
+		s.catchUp.Start(loggerPkg.With(ctx, uLogger))
+	}()
 }
 
+// Stop lets in-flight tasks finish until ctx expires, then closes the queue.
 func (s *WeatherScheduler) Stop(ctx context.Context) {
 	if s.cancel != nil {
 		s.cancel()
 	}
 
-	s.wg.Wait()
+	wLogger := loggerPkg.From(ctx).With("module", "scheduler")
+	if err := s.worker.Stop(ctx); err != nil {
+		wLogger.Warn("Worker drain interrupted", "err", err)
+	} else {
+		s.wg.Wait()
+	}
 
 	cLogger := loggerPkg.From(ctx).With("module", "scheduler")
 	s.cron.Stop(loggerPkg.With(ctx, cLogger))
@@ -0,0 +1,144 @@
+// Package backfill runs one-off fixes of stored data. Each runs once per deployment, on the
+// scheduler leader, and records that it ran in the same transaction as its changes.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// ReasonMerged is reported with the removed events of subscriptions folded into another one.
+const ReasonMerged = "merged"
+
+// Merged is a subscription deleted in favour of the kept one of its address and city.
+type Merged struct {
+	Sub    domain.Subscription
+	KeptID string
+}
+
+// MergeResult tells what an address merge changed.
+type MergeResult struct {
+	// Rewritten counts the kept subscriptions whose address was rewritten.
+	Rewritten int64
+	Removed   []Merged
+}
+
+type store interface {
+	// Claim records that the named backfill ran and reports false when it already had.
+	Claim(ctx context.Context, name string) (bool, error)
+	// MergeAddresses rewrites stored addresses to the form subscribe input is normalised to.
+	// Where several subscriptions of one address follow the same city, the active one (then the
+	// confirmed, then the newest) is kept with its tokens and gets the delivery history of the rest,
+	// which are deleted.
+	MergeAddresses(ctx context.Context, stripPlusTags bool) (MergeResult, error)
+}
+
+// removalEvents announces deleted subscriptions through the outbox, like the subscription flows.
+type removalEvents interface {
+	Removed(ctx context.Context, sub domain.Subscription, reason string) error
+}
+
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type leader interface {
+	IsLeader() bool
+}
+
+// AddressMerge brings stored addresses in line with how subscribe normalises them, so lookups keep
+// finding them: lowercased and trimmed, and without plus tags once EMAIL_STRIP_PLUS_TAGS is on.
+// Turning plus-tag stripping on later runs the merge again.
+type AddressMerge struct {
+	store         store
+	tx            transactor
+	events        removalEvents
+	leader        leader
+	stripPlusTags bool
+	interval      time.Duration
+}
+
+func NewAddressMerge(store store, tx transactor, events removalEvents, leader leader, stripPlusTags bool, interval time.Duration) *AddressMerge {
+	return &AddressMerge{
+		store:         store,
+		tx:            tx,
+		events:        events,
+		leader:        leader,
+		stripPlusTags: stripPlusTags,
+		interval:      interval,
+	}
+}
+
+// Start waits until this replica leads, runs the merge and returns; a failed merge is retried
+// every interval.
+func (m *AddressMerge) Start(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if m.leader.IsLeader() {
+			err := m.Run(ctx)
+			if err == nil {
+				return
+			}
+			logger.Error("Address merge failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run merges the stored addresses unless that was already done, announcing every removed
+// subscription in the same transaction.
+func (m *AddressMerge) Run(ctx context.Context) error {
+	logger := loggerPkg.From(ctx)
+	name := "merge_addresses"
+	if m.stripPlusTags {
+		name = "merge_addresses_strip_plus_tags"
+	}
+
+	var res MergeResult
+	ran := false
+	err := m.tx.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := m.store.Claim(ctx, name)
+		if err != nil {
+			return fmt.Errorf("claim %s: %w", name, err)
+		}
+		if !claimed {
+			return nil
+		}
+		ran = true
+
+		if res, err = m.store.MergeAddresses(ctx, m.stripPlusTags); err != nil {
+			return fmt.Errorf("merge addresses: %w", err)
+		}
+		for _, merged := range res.Removed {
+			if err := m.events.Removed(ctx, merged.Sub, ReasonMerged); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || !ran {
+		return err
+	}
+
+	for _, merged := range res.Removed {
+		logger.Info("Merged duplicate subscription",
+			"subscription_id", merged.Sub.ID,
+			"kept_id", merged.KeptID,
+			"user", loggerPkg.HashEmail(merged.Sub.Email))
+	}
+	logger.Info("Stored addresses merged", "backfill", name, "rewritten", res.Rewritten, "removed", len(res.Removed))
+	return nil
+}
@@ -0,0 +1,137 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	claimed map[string]bool
+	result  MergeResult
+	strip   []bool
+}
+
+func newFakeStore(result MergeResult) *fakeStore {
+	return &fakeStore{claimed: map[string]bool{}, result: result}
+}
+
+func (s *fakeStore) Claim(_ context.Context, name string) (bool, error) {
+	if s.claimed[name] {
+		return false, nil
+	}
+	s.claimed[name] = true
+	return true, nil
+}
+
+func (s *fakeStore) MergeAddresses(_ context.Context, stripPlusTags bool) (MergeResult, error) {
+	s.strip = append(s.strip, stripPlusTags)
+	return s.result, nil
+}
+
+type removal struct {
+	id, reason string
+}
+
+type fakeEvents struct {
+	err     error
+	removed []removal
+}
+
+func (e *fakeEvents) Removed(_ context.Context, sub domain.Subscription, reason string) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.removed = append(e.removed, removal{id: sub.ID, reason: reason})
+	return nil
+}
+
+// fakeTx forgets the claims made inside fn when it fails, like a rolled back transaction.
+type fakeTx struct{ store *fakeStore }
+
+func (t fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make(map[string]bool, len(t.store.claimed))
+	for k, v := range t.store.claimed {
+		snapshot[k] = v
+	}
+	if err := fn(ctx); err != nil {
+		t.store.claimed = snapshot
+		return err
+	}
+	return nil
+}
+
+type fakeLeader bool
+
+func (l fakeLeader) IsLeader() bool { return bool(l) }
+
+var merged = MergeResult{
+	Rewritten: 2,
+	Removed: []Merged{
+		{Sub: domain.Subscription{ID: "sub-2", Email: "User@example.com"}, KeptID: "sub-1"},
+		{Sub: domain.Subscription{ID: "sub-3", Email: "user+news@example.com"}, KeptID: "sub-1"},
+	},
+}
+
+func TestAddressMerge_AnnouncesRemovedSubscriptions(t *testing.T) {
+	store := newFakeStore(merged)
+	events := &fakeEvents{}
+	m := NewAddressMerge(store, fakeTx{store}, events, fakeLeader(true), true, time.Minute)
+
+	require.NoError(t, m.Run(context.Background()))
+
+	assert.Equal(t, []bool{true}, store.strip)
+	assert.Equal(t, []removal{{"sub-2", ReasonMerged}, {"sub-3", ReasonMerged}}, events.removed)
+}
+
+func TestAddressMerge_RunsOnce(t *testing.T) {
+	store := newFakeStore(merged)
+	events := &fakeEvents{}
+	m := NewAddressMerge(store, fakeTx{store}, events, fakeLeader(true), false, time.Minute)
+
+	require.NoError(t, m.Run(context.Background()))
+	require.NoError(t, m.Run(context.Background()))
+
+	assert.Len(t, store.strip, 1)
+	assert.Len(t, events.removed, 2)
+}
+
+func TestAddressMerge_RunsAgainWhenPlusTagStrippingIsTurnedOn(t *testing.T) {
+	store := newFakeStore(MergeResult{})
+	events := &fakeEvents{}
+
+	require.NoError(t, NewAddressMerge(store, fakeTx{store}, events, fakeLeader(true), false, time.Minute).Run(context.Background()))
+	require.NoError(t, NewAddressMerge(store, fakeTx{store}, events, fakeLeader(true), true, time.Minute).Run(context.Background()))
+
+	assert.Equal(t, []bool{false, true}, store.strip)
+}
+
+func TestAddressMerge_EventFailure_LeavesMergeUnclaimed(t *testing.T) {
+	store := newFakeStore(merged)
+	events := &fakeEvents{err: errors.New("outbox down")}
+	m := NewAddressMerge(store, fakeTx{store}, events, fakeLeader(true), false, time.Minute)
+
+	require.Error(t, m.Run(context.Background()))
+
+	events.err = nil
+	require.NoError(t, m.Run(context.Background()))
+	assert.Len(t, store.strip, 2, "the merge is retried")
+	assert.Len(t, events.removed, 2)
+}
+
+func TestAddressMerge_WaitsForLeadership(t *testing.T) {
+	store := newFakeStore(merged)
+	m := NewAddressMerge(store, fakeTx{store}, &fakeEvents{}, fakeLeader(false), false, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	m.Start(ctx)
+
+	assert.Empty(t, store.strip, "followers leave the merge to the leader")
+}
@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"subscription/internal/emailcheck"
+	pb "subscription/internal/proto"
+	"subscription/internal/ratelimit"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain is set on every ErrorInfo detail this API returns.
+const ErrorDomain = "subscription"
+
+// newStatus builds a status error carrying an ErrorInfo with the given reason plus any extra details.
+func newStatus(code codes.Code, reason pb.ErrorReason, msg string, metadata map[string]string, details ...protoadapt.MessageV1) error {
+	info := &errdetails.ErrorInfo{Reason: reason.String(), Domain: ErrorDomain, Metadata: metadata}
+	st, err := status.New(code, msg).WithDetails(append([]protoadapt.MessageV1{info}, details...)...)
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+func invalidField(reason pb.ErrorReason, field, msg string) error {
+	violation := &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: msg}},
+	}
+	return newStatus(codes.InvalidArgument, reason, msg, nil, violation)
+}
+
+func internalError(msg string) error {
+	return status.Error(codes.Internal, msg)
+}
+
+func addressError(err error) error {
+	switch {
+	case errors.Is(err, emailcheck.ErrDisposableDomain):
+		return invalidField(pb.ErrorReason_DISPOSABLE_EMAIL, "email", "Disposable email addresses are not allowed")
+	case errors.Is(err, emailcheck.ErrUndeliverableDomain):
+		return invalidField(pb.ErrorReason_UNDELIVERABLE_EMAIL, "email", "Email domain cannot receive mail")
+	default:
+		return invalidField(pb.ErrorReason_INVALID_EMAIL, "email", "Invalid email address")
+	}
+}
+
+func rateLimited(err error) error {
+	var limitErr *ratelimit.LimitError
+	if !errors.As(err, &limitErr) {
+		return newStatus(codes.ResourceExhausted, pb.ErrorReason_RATE_LIMITED, "Too many requests, please try again later", nil)
+	}
+
+	msg := "Too many subscription attempts, please try again later"
+	switch limitErr.Reason {
+	case ratelimit.ReasonCooldown:
+		msg = "A confirmation email was sent recently, please check your inbox"
+	case ratelimit.ReasonEmail:
+		msg = "Too many subscription attempts for this email, please try again later"
+	}
+
+	var details []protoadapt.MessageV1
+	if limitErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)})
+	}
+	return newStatus(codes.ResourceExhausted, pb.ErrorReason_RATE_LIMITED, msg, map[string]string{"limit": limitErr.Reason}, details...)
+}
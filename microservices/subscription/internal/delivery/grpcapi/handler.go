@@ -0,0 +1,257 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+
+	"subscription/internal/domain"
+	pb "subscription/internal/proto"
+	"subscription/internal/subscription"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Metadata keys mirroring the headers the HTTP API reads.
+const (
+	ForwardedForKey   = "x-forwarded-for"
+	AcceptLanguageKey = "accept-language"
+)
+
+type subscriptionService interface {
+	Subscribe(ctx context.Context, email, city string, frequency domain.Frequency, locale string) error
+	SubscribeWebhook(ctx context.Context, email, city string, frequency domain.Frequency, locale, webhookURL string) (string, error)
+	Confirm(ctx context.Context, token string) error
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type weatherService interface {
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
+}
+
+type addressValidator interface {
+	Validate(ctx context.Context, email string) (string, error)
+}
+
+// subscribeGuard throttles subscribe attempts; a nil guard disables the limits.
+type subscribeGuard interface {
+	Check(ctx context.Context, ip, email, city string) error
+	ConfirmationSent(ctx context.Context, email, city string)
+}
+
+type localeNegotiator interface {
+	Negotiate(requested, acceptLanguage string) string
+}
+
+// Handler serves the public subscription API over gRPC with the same rules as the gin handlers.
+type Handler struct {
+	pb.UnimplementedSubscriptionServiceServer
+	service   subscriptionService
+	weather   weatherService
+	addresses addressValidator
+	guard     subscribeGuard
+	locales   localeNegotiator
+	proxies   []netip.Prefix
+}
+
+func NewHandler(service subscriptionService, weather weatherService, addresses addressValidator, guard subscribeGuard, locales localeNegotiator) *Handler {
+	return &Handler{service: service, weather: weather, addresses: addresses, guard: guard, locales: locales}
+}
+
+// WithTrustedProxies limits whose x-forwarded-for metadata is believed, like gin's SetTrustedProxies.
+// Entries are IPs or CIDRs; without any, no peer is trusted and the peer address is used.
+func (h *Handler) WithTrustedProxies(proxies []string) error {
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return err
+			}
+			h.proxies = append(h.proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return err
+		}
+		h.proxies = append(h.proxies, prefix)
+	}
+	return nil
+}
+
+func (h *Handler) Subscribe(ctx context.Context, req *pb.SubscribeRequest) (*pb.SubscribeResponse, error) {
+	logger := loggerPkg.From(ctx)
+	switch {
+	case req.GetEmail() == "":
+		return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "email", "Invalid input")
+	case req.GetCity() == "":
+		return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "city", "Invalid input")
+	}
+
+	ip := h.clientIP(ctx)
+	if req.GetNickname() != "" {
+		// Bots get the usual answer so they have nothing to adapt to.
+		logger.Warn("subscribe honeypot triggered", "ip", ip)
+		return &pb.SubscribeResponse{Message: "Subscription successful. Confirmation email sent."}, nil
+	}
+
+	freq := domain.Frequency(req.GetFrequency())
+	if !freq.Valid() {
+		logger.Warn("invalid frequency value", "value", req.GetFrequency())
+		return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "frequency", "Invalid frequency value")
+	}
+
+	channel := domain.ChannelEmail
+	if req.GetChannel() != "" {
+		channel = domain.Channel(req.GetChannel())
+	}
+	switch {
+	case !channel.Valid():
+		return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "channel", "Invalid channel value")
+	case channel == domain.ChannelWebhook && req.GetWebhookUrl() == "":
+		return nil, invalidField(pb.ErrorReason_INVALID_WEBHOOK_URL, "webhook_url", "Webhook URL is required")
+	}
+
+	email, err := h.addresses.Validate(ctx, req.GetEmail())
+	if err != nil {
+		logger.Warn("subscribe address rejected", "user", loggerPkg.HashEmail(req.GetEmail()), "err", err)
+		return nil, addressError(err)
+	}
+
+	if h.guard != nil {
+		if err := h.guard.Check(ctx, ip, email, req.GetCity()); err != nil {
+			logger.Warn("subscribe rate limited", "user", loggerPkg.HashEmail(email), "ip", ip, "err", err)
+			return nil, rateLimited(err)
+		}
+	}
+
+	locale := h.locales.Negotiate(req.GetLocale(), incoming(ctx, AcceptLanguageKey))
+	var secret string
+	if channel == domain.ChannelWebhook {
+		secret, err = h.service.SubscribeWebhook(ctx, email, req.GetCity(), freq, locale, req.GetWebhookUrl())
+	} else {
+		err = h.service.Subscribe(ctx, email, req.GetCity(), freq, locale)
+	}
+	if err != nil {
+		logger.Warn("subscribe failed", "user", loggerPkg.HashEmail(email), "city", req.GetCity(), "channel", channel, "err", err)
+		switch {
+		case errors.Is(err, subscription.ErrCityNotFound):
+			return nil, invalidField(pb.ErrorReason_CITY_NOT_FOUND, "city", "City not found")
+		case errors.Is(err, subscription.ErrEmailAlreadyExists):
+			return nil, newStatus(codes.AlreadyExists, pb.ErrorReason_EMAIL_ALREADY_SUBSCRIBED, "Email already subscribed", nil)
+		case errors.Is(err, subscription.ErrInvalidWebhookURL):
+			return nil, invalidField(pb.ErrorReason_INVALID_WEBHOOK_URL, "webhook_url", "Invalid webhook URL")
+		case errors.Is(err, subscription.ErrWebhooksDisabled):
+			return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "channel", "Webhook channel is not available")
+		default:
+			return nil, internalError("Something went wrong")
+		}
+	}
+
+	if h.guard != nil {
+		h.guard.ConfirmationSent(ctx, email, req.GetCity())
+	}
+
+	if channel == domain.ChannelWebhook {
+		return &pb.SubscribeResponse{
+			Message:       "Subscription successful. Confirmation email sent, the webhook URL will be verified.",
+			WebhookSecret: secret,
+		}, nil
+	}
+	return &pb.SubscribeResponse{Message: "Subscription successful. Confirmation email sent."}, nil
+}
+
+func (h *Handler) Confirm(ctx context.Context, req *pb.ConfirmRequest) (*pb.ConfirmResponse, error) {
+	if err := h.service.Confirm(ctx, req.GetToken()); err != nil {
+		loggerPkg.From(ctx).Warn("confirm failed", "err", err)
+		return nil, tokenError(err)
+	}
+	return &pb.ConfirmResponse{Message: "Subscription confirmed successfully"}, nil
+}
+
+func (h *Handler) Unsubscribe(ctx context.Context, req *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error) {
+	if err := h.service.Unsubscribe(ctx, req.GetToken()); err != nil {
+		loggerPkg.From(ctx).Warn("unsubscribe failed", "err", err)
+		return nil, tokenError(err)
+	}
+	return &pb.UnsubscribeResponse{Message: "Unsubscribed successfully"}, nil
+}
+
+func (h *Handler) GetWeather(ctx context.Context, req *pb.GetWeatherRequest) (*pb.GetWeatherResponse, error) {
+	if req.GetCity() == "" {
+		return nil, invalidField(pb.ErrorReason_INVALID_INPUT, "city", "City is required")
+	}
+
+	lang := h.locales.Negotiate(req.GetLang(), incoming(ctx, AcceptLanguageKey))
+	report, err := h.weather.GetWeather(ctx, req.GetCity(), lang)
+	if err != nil {
+		loggerPkg.From(ctx).Warn("failed to fetch weather data", "city", req.GetCity(), "err", err)
+		if errors.Is(err, domain.ErrCityNotFound) {
+			return nil, invalidField(pb.ErrorReason_CITY_NOT_FOUND, "city", "City not found")
+		}
+		return nil, internalError("Failed to fetch weather data")
+	}
+
+	return &pb.GetWeatherResponse{
+		Temperature: report.Temperature,
+		Humidity:    int32(report.Humidity),
+		Description: report.Description,
+	}, nil
+}
+
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, subscription.ErrInvalidToken):
+		return invalidField(pb.ErrorReason_INVALID_TOKEN, "token", "Invalid token")
+	case errors.Is(err, subscription.ErrSubscriptionNotFound):
+		return newStatus(codes.NotFound, pb.ErrorReason_SUBSCRIPTION_NOT_FOUND, "Subscription not found", nil)
+	default:
+		return internalError("Something went wrong")
+	}
+}
+
+// clientIP prefers the first x-forwarded-for entry when the peer is a trusted proxy.
+func (h *Handler) clientIP(ctx context.Context) string {
+	var peerIP string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(peerIP); err == nil {
+			peerIP = host
+		}
+	}
+
+	forwarded := strings.TrimSpace(strings.Split(incoming(ctx, ForwardedForKey), ",")[0])
+	if forwarded == "" || !h.trusted(peerIP) {
+		return peerIP
+	}
+	return forwarded
+}
+
+func (h *Handler) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, p := range h.proxies {
+		if p.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func incoming(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/emailcheck"
+	pb "subscription/internal/proto"
+	"subscription/internal/ratelimit"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type fakeService struct {
+	err                               error
+	email, city, locale, confirmToken string
+	webhookURL                        string
+	frequency                         domain.Frequency
+}
+
+func (f *fakeService) Subscribe(_ context.Context, email, city string, frequency domain.Frequency, locale string) error {
+	f.email, f.city, f.frequency, f.locale = email, city, frequency, locale
+	return f.err
+}
+
+func (f *fakeService) SubscribeWebhook(_ context.Context, email, city string, frequency domain.Frequency, locale, webhookURL string) (string, error) {
+	f.email, f.city, f.frequency, f.locale, f.webhookURL = email, city, frequency, locale, webhookURL
+	if f.err != nil {
+		return "", f.err
+	}
+	return "whsec_test", nil
+}
+
+func (f *fakeService) Confirm(_ context.Context, token string) error {
+	f.confirmToken = token
+	return f.err
+}
+
+func (f *fakeService) Unsubscribe(_ context.Context, _ string) error {
+	return f.err
+}
+
+type fakeWeather struct {
+	report domain.Report
+	err    error
+	lang   string
+}
+
+func (f *fakeWeather) GetWeather(_ context.Context, _, lang string) (domain.Report, error) {
+	f.lang = lang
+	return f.report, f.err
+}
+
+type fakeAddresses struct{ err error }
+
+func (f fakeAddresses) Validate(_ context.Context, email string) (string, error) {
+	return email, f.err
+}
+
+type fakeGuard struct {
+	err error
+	ip  string
+}
+
+func (f *fakeGuard) Check(_ context.Context, ip, _, _ string) error {
+	f.ip = ip
+	return f.err
+}
+
+func (f *fakeGuard) ConfirmationSent(context.Context, string, string) {}
+
+// fakeLocales prefers the requested locale, then the raw Accept-Language value, then "uk".
+type fakeLocales struct{}
+
+func (fakeLocales) Negotiate(requested, acceptLanguage string) string {
+	switch {
+	case requested != "":
+		return requested
+	case acceptLanguage != "":
+		return acceptLanguage
+	default:
+		return "uk"
+	}
+}
+
+func errorInfo(t *testing.T, err error) (*status.Status, *errdetails.ErrorInfo) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return st, info
+		}
+	}
+	t.Fatalf("no ErrorInfo in %v", st.Details())
+	return nil, nil
+}
+
+func validSubscribe() *pb.SubscribeRequest {
+	return &pb.SubscribeRequest{Email: "a@example.com", City: "Kyiv", Frequency: "daily"}
+}
+
+func TestSubscribe_Success(t *testing.T) {
+	service := &fakeService{}
+	h := NewHandler(service, &fakeWeather{}, fakeAddresses{}, nil, fakeLocales{})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AcceptLanguageKey, "en"))
+
+	resp, err := h.Subscribe(ctx, validSubscribe())
+
+	require.NoError(t, err)
+	assert.Equal(t, "Subscription successful. Confirmation email sent.", resp.GetMessage())
+	assert.Equal(t, "a@example.com", service.email)
+	assert.Equal(t, domain.FreqDaily, service.frequency)
+	assert.Equal(t, "en", service.locale)
+}
+
+func TestSubscribe_WebhookReturnsSecret(t *testing.T) {
+	service := &fakeService{}
+	h := NewHandler(service, &fakeWeather{}, fakeAddresses{}, nil, fakeLocales{})
+	req := validSubscribe()
+	req.Channel = "webhook"
+	req.WebhookUrl = "https://hooks.example.com/weather"
+
+	resp, err := h.Subscribe(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "whsec_test", resp.GetWebhookSecret())
+	assert.Equal(t, "https://hooks.example.com/weather", service.webhookURL)
+}
+
+func TestSubscribe_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *pb.SubscribeRequest
+		addressErr error
+		serviceErr error
+		wantCode   codes.Code
+		wantReason pb.ErrorReason
+		wantField  string
+	}{
+		{name: "MissingCity", req: &pb.SubscribeRequest{Email: "a@example.com", Frequency: "daily"}, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_INPUT, wantField: "city"},
+		{name: "BadFrequency", req: &pb.SubscribeRequest{Email: "a@example.com", City: "Kyiv", Frequency: "weekly"}, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_INPUT, wantField: "frequency"},
+		{name: "DisposableEmail", req: validSubscribe(), addressErr: emailcheck.ErrDisposableDomain, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_DISPOSABLE_EMAIL, wantField: "email"},
+		{name: "CityNotFound", req: validSubscribe(), serviceErr: subscription.ErrCityNotFound, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_CITY_NOT_FOUND, wantField: "city"},
+		{name: "AlreadySubscribed", req: validSubscribe(), serviceErr: subscription.ErrEmailAlreadyExists, wantCode: codes.AlreadyExists, wantReason: pb.ErrorReason_EMAIL_ALREADY_SUBSCRIBED},
+		{name: "UnknownChannel", req: &pb.SubscribeRequest{Email: "a@example.com", City: "Kyiv", Frequency: "daily", Channel: "sms"}, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_INPUT, wantField: "channel"},
+		{name: "WebhookWithoutURL", req: &pb.SubscribeRequest{Email: "a@example.com", City: "Kyiv", Frequency: "daily", Channel: "webhook"}, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_WEBHOOK_URL, wantField: "webhook_url"},
+		{name: "InvalidWebhookURL", req: &pb.SubscribeRequest{Email: "a@example.com", City: "Kyiv", Frequency: "daily", Channel: "webhook", WebhookUrl: "ftp://x"}, serviceErr: subscription.ErrInvalidWebhookURL, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_WEBHOOK_URL, wantField: "webhook_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(&fakeService{err: tt.serviceErr}, &fakeWeather{}, fakeAddresses{err: tt.addressErr}, nil, fakeLocales{})
+
+			_, err := h.Subscribe(context.Background(), tt.req)
+
+			st, info := errorInfo(t, err)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantReason.String(), info.GetReason())
+			assert.Equal(t, ErrorDomain, info.GetDomain())
+
+			var field string
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok {
+					field = br.GetFieldViolations()[0].GetField()
+				}
+			}
+			assert.Equal(t, tt.wantField, field)
+		})
+	}
+}
+
+func TestSubscribe_RateLimitedCarriesRetryInfo(t *testing.T) {
+	guard := &fakeGuard{err: &ratelimit.LimitError{Reason: ratelimit.ReasonCooldown, RetryAfter: 90 * time.Second}}
+	h := NewHandler(&fakeService{}, &fakeWeather{}, fakeAddresses{}, guard, fakeLocales{})
+
+	_, err := h.Subscribe(context.Background(), validSubscribe())
+
+	st, info := errorInfo(t, err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, "RATE_LIMITED", info.GetReason())
+	assert.Equal(t, ratelimit.ReasonCooldown, info.GetMetadata()["limit"])
+
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	require.NotNil(t, retry)
+	assert.Equal(t, 90*time.Second, retry.GetRetryDelay().AsDuration())
+}
+
+func TestSubscribe_ClientIP(t *testing.T) {
+	proxyPeer := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 4000}}
+	md := metadata.Pairs(ForwardedForKey, "203.0.113.7, 10.0.0.5")
+
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{name: "TrustNoneByDefault", want: "10.0.0.5"},
+		{name: "TrustedProxy", proxies: []string{"10.0.0.0/8"}, want: "203.0.113.7"},
+		{name: "UntrustedPeer", proxies: []string{"192.168.1.1"}, want: "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := &fakeGuard{}
+			h := NewHandler(&fakeService{}, &fakeWeather{}, fakeAddresses{}, guard, fakeLocales{})
+			require.NoError(t, h.WithTrustedProxies(tt.proxies))
+			ctx := peer.NewContext(metadata.NewIncomingContext(context.Background(), md), proxyPeer)
+
+			_, err := h.Subscribe(ctx, validSubscribe())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, guard.ip)
+		})
+	}
+}
+
+func TestConfirm_TokenErrors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantCode   codes.Code
+		wantReason pb.ErrorReason
+	}{
+		{err: subscription.ErrInvalidToken, wantCode: codes.InvalidArgument, wantReason: pb.ErrorReason_INVALID_TOKEN},
+		{err: subscription.ErrSubscriptionNotFound, wantCode: codes.NotFound, wantReason: pb.ErrorReason_SUBSCRIPTION_NOT_FOUND},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantReason.String(), func(t *testing.T) {
+			h := NewHandler(&fakeService{err: tt.err}, &fakeWeather{}, fakeAddresses{}, nil, fakeLocales{})
+
+			_, err := h.Confirm(context.Background(), &pb.ConfirmRequest{Token: "tok"})
+
+			st, info := errorInfo(t, err)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantReason.String(), info.GetReason())
+		})
+	}
+
+	t.Run("Unexpected", func(t *testing.T) {
+		h := NewHandler(&fakeService{err: errors.New("db down")}, &fakeWeather{}, fakeAddresses{}, nil, fakeLocales{})
+
+		_, err := h.Confirm(context.Background(), &pb.ConfirmRequest{Token: "tok"})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestGetWeather(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		weather := &fakeWeather{report: domain.Report{Temperature: 21.5, Humidity: 40, Description: "Sunny"}}
+		h := NewHandler(&fakeService{}, weather, fakeAddresses{}, nil, fakeLocales{})
+
+		resp, err := h.GetWeather(context.Background(), &pb.GetWeatherRequest{City: "Kyiv", Lang: "en"})
+
+		require.NoError(t, err)
+		assert.InDelta(t, 21.5, resp.GetTemperature(), 0.001)
+		assert.Equal(t, int32(40), resp.GetHumidity())
+		assert.Equal(t, "en", weather.lang)
+	})
+
+	t.Run("CityNotFound", func(t *testing.T) {
+		h := NewHandler(&fakeService{}, &fakeWeather{err: domain.ErrCityNotFound}, fakeAddresses{}, nil, fakeLocales{})
+
+		_, err := h.GetWeather(context.Background(), &pb.GetWeatherRequest{City: "Nowhere"})
+
+		st, info := errorInfo(t, err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "CITY_NOT_FOUND", info.GetReason())
+	})
+}
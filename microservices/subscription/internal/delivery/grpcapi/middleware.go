@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+	metricsPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const CorrelationIDKey = "x-correlation-id"
+
+// LoggingUnaryServerInterceptor does for gRPC calls what RequestLoggingMiddleware does for HTTP:
+// it scopes a logger to the call and records request metrics under the full method name.
+func LoggingUnaryServerInterceptor(baseLogger *loggerPkg.Logger, metrics *metricsPkg.Metrics, serviceName string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		reqID := uuid.New().String()
+
+		corrID := incoming(ctx, CorrelationIDKey)
+		if corrID == "" {
+			corrID = "grpc-" + uuid.New().String()[:8]
+		}
+
+		logger := baseLogger.With("request_id", reqID, "correlation_id", corrID)
+
+		ctx = loggerPkg.WithRequestID(ctx, reqID)
+		ctx = loggerPkg.WithCorrelationID(ctx, corrID)
+		ctx = loggerPkg.With(ctx, logger)
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDKey, corrID)); err != nil {
+			logger.Warn("failed to set correlation header", "error", err)
+		}
+
+		metrics.IncActiveConnections(serviceName, "GRPC", info.FullMethod)
+		start := time.Now()
+		resp, err = handler(ctx, req)
+		duration := time.Since(start)
+		metrics.DecActiveConnections(serviceName, "GRPC", info.FullMethod)
+
+		code := status.Code(err)
+		metrics.RecordRequest(serviceName, "GRPC", info.FullMethod, code.String(), duration)
+
+		logFields := []interface{}{
+			"method", info.FullMethod,
+			"status", code.String(),
+			"duration_ms", duration.Milliseconds(),
+		}
+
+		switch {
+		case code == codes.Internal || code == codes.Unavailable || code == codes.DataLoss:
+			metrics.RecordError(serviceName, "GRPC", info.FullMethod, code.String(), "server_error")
+			logger.Error("grpc request failed", logFields...)
+		case code != codes.OK:
+			metrics.RecordError(serviceName, "GRPC", info.FullMethod, code.String(), "client_error")
+			logger.Warn("grpc request client error", logFields...)
+		case duration > 1000*time.Millisecond:
+			logger.Warn("slow grpc request", logFields...)
+		default:
+			logger.Info("grpc request", logFields...)
+		}
+
+		return resp, err
+	}
+}
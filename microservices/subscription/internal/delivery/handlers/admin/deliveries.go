@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDeliveriesLimit = 100
+	maxDeliveriesLimit     = 1000
+)
+
+type WeatherView struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	Description string  `json:"description"`
+}
+
+type DeliveryView struct {
+	ID             int64        `json:"id"`
+	SubscriptionID string       `json:"subscription_id"` //nolint:tagliatelle
+	Email          string       `json:"email"`
+	City           string       `json:"city"`
+	RunID          *int64       `json:"run_id"`           //nolint:tagliatelle
+	JobID          string       `json:"job_id,omitempty"` //nolint:tagliatelle
+	IdempotencyKey string       `json:"idempotency_key"`  //nolint:tagliatelle
+	Weather        *WeatherView `json:"weather"`
+	Status         string       `json:"status"`
+	Error          string       `json:"error,omitempty"`
+	QueuedAt       time.Time    `json:"queued_at"`  //nolint:tagliatelle
+	SentAt         *time.Time   `json:"sent_at"`    //nolint:tagliatelle
+	FailedAt       *time.Time   `json:"failed_at"`  //nolint:tagliatelle
+	BouncedAt      *time.Time   `json:"bounced_at"` //nolint:tagliatelle
+	UpdatedAt      time.Time    `json:"updated_at"` //nolint:tagliatelle
+}
+
+func toDeliveryView(d domain.Delivery) DeliveryView {
+	v := DeliveryView{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		Email:          d.Email,
+		City:           d.City,
+		JobID:          d.JobID,
+		IdempotencyKey: d.IdempotencyKey,
+		Status:         string(d.Status),
+		Error:          d.Error,
+		QueuedAt:       d.QueuedAt,
+		SentAt:         d.SentAt,
+		FailedAt:       d.FailedAt,
+		BouncedAt:      d.BouncedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if d.RunID != 0 {
+		runID := d.RunID
+		v.RunID = &runID
+	}
+	if d.Weather != nil {
+		v.Weather = &WeatherView{
+			Temperature: d.Weather.Temperature,
+			Humidity:    d.Weather.Humidity,
+			Description: d.Weather.Description,
+		}
+	}
+	return v
+}
+
+type subscriptionDeliveryLister interface {
+	ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error)
+}
+
+type runDeliveryLister interface {
+	ListByRun(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error)
+}
+
+// SubscriptionDeliveries lists the delivery history of one subscription, newest first.
+type SubscriptionDeliveries struct {
+	deliveries subscriptionDeliveryLister
+}
+
+func NewSubscriptionDeliveries(deliveries subscriptionDeliveryLister) SubscriptionDeliveries {
+	return SubscriptionDeliveries{deliveries: deliveries}
+}
+
+func (h SubscriptionDeliveries) Handle(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultDeliveriesLimit, maxDeliveriesLimit)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.deliveries.ListBySubscription(c.Request.Context(), c.Param("id"), limit)
+	sendDeliveries(c, deliveries, err)
+}
+
+// RunDeliveries lists the deliveries produced by one dispatch run.
+type RunDeliveries struct {
+	deliveries runDeliveryLister
+}
+
+func NewRunDeliveries(deliveries runDeliveryLister) RunDeliveries {
+	return RunDeliveries{deliveries: deliveries}
+}
+
+func (h RunDeliveries) Handle(c *gin.Context) {
+	runID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || runID < 1 {
+		response.SendError(c, http.StatusBadRequest, "Invalid run id")
+		return
+	}
+
+	limit, ok := queryLimit(c, defaultDeliveriesLimit, maxDeliveriesLimit)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.deliveries.ListByRun(c.Request.Context(), runID, limit)
+	sendDeliveries(c, deliveries, err)
+}
+
+func sendDeliveries(c *gin.Context, deliveries []domain.Delivery, err error) {
+	if err != nil {
+		loggerPkg.From(c.Request.Context()).Error("Failed to list deliveries", "err", err)
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	views := make([]DeliveryView, 0, len(deliveries))
+	for _, d := range deliveries {
+		views = append(views, toDeliveryView(d))
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": views})
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"subscription/internal/delivery/middleware"
+	"subscription/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeliveryLister struct {
+	bySubscription func(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error)
+	byRun          func(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error)
+}
+
+func (m *mockDeliveryLister) ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error) {
+	return m.bySubscription(ctx, subscriptionID, limit)
+}
+
+func (m *mockDeliveryLister) ListByRun(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error) {
+	return m.byRun(ctx, runID, limit)
+}
+
+func setupDeliveriesRouter(mock *mockDeliveryLister) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	adminAPI := r.Group("/api/admin", middleware.AdminAuth("secret"))
+	adminAPI.GET("/subscriptions/:id/deliveries", NewSubscriptionDeliveries(mock).Handle)
+	adminAPI.GET("/dispatch-runs/:id/deliveries", NewRunDeliveries(mock).Handle)
+	return r
+}
+
+func doAdminGet(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	return doAdminRequest(r, http.MethodGet, path)
+}
+
+func doAdminRequest(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubscriptionDeliveriesHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		sentAt := time.Date(2025, 1, 1, 12, 0, 3, 0, time.UTC)
+		mock := &mockDeliveryLister{
+			bySubscription: func(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error) {
+				assert.Equal(t, "sub-1", subscriptionID)
+				assert.Equal(t, defaultDeliveriesLimit, limit)
+				return []domain.Delivery{{
+					ID:             1,
+					SubscriptionID: "sub-1",
+					City:           "Kyiv",
+					RunID:          4,
+					IdempotencyKey: "report:a@example.com:2025-01-01T12",
+					Weather:        &domain.Report{Temperature: 21.5, Humidity: 40, Description: "Sunny"},
+					Status:         domain.DeliverySent,
+					SentAt:         &sentAt,
+				}}, nil
+			},
+		}
+
+		w := doAdminGet(setupDeliveriesRouter(mock), "/api/admin/subscriptions/sub-1/deliveries")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"sent"`)
+		assert.Contains(t, w.Body.String(), `"run_id":4`)
+		assert.Contains(t, w.Body.String(), `"sent_at":"2025-01-01T12:00:03Z"`)
+		assert.Contains(t, w.Body.String(), `"description":"Sunny"`)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		w := doAdminGet(setupDeliveriesRouter(&mockDeliveryLister{}), "/api/admin/subscriptions/sub-1/deliveries?limit=5000")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("StoreError", func(t *testing.T) {
+		mock := &mockDeliveryLister{
+			bySubscription: func(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error) {
+				return nil, errors.New("db down")
+			},
+		}
+
+		w := doAdminGet(setupDeliveriesRouter(mock), "/api/admin/subscriptions/sub-1/deliveries")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestRunDeliveriesHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockDeliveryLister{
+			byRun: func(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error) {
+				assert.Equal(t, int64(7), runID)
+				assert.Equal(t, 20, limit)
+				return []domain.Delivery{{ID: 1, Status: domain.DeliveryQueued}}, nil
+			},
+		}
+
+		w := doAdminGet(setupDeliveriesRouter(mock), "/api/admin/dispatch-runs/7/deliveries?limit=20")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"queued"`)
+		assert.Contains(t, w.Body.String(), `"run_id":null`)
+	})
+
+	t.Run("InvalidRunID", func(t *testing.T) {
+		w := doAdminGet(setupDeliveriesRouter(&mockDeliveryLister{}), "/api/admin/dispatch-runs/abc/deliveries")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
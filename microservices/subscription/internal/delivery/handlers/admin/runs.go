@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/job"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultRunsLimit = 50
+	maxRunsLimit     = 500
+)
+
+type RunView struct {
+	ID            int64      `json:"id"`
+	Frequency     string     `json:"frequency"`
+	ScheduledAt   time.Time  `json:"scheduled_at"` //nolint:tagliatelle
+	StartedAt     time.Time  `json:"started_at"`   //nolint:tagliatelle
+	FinishedAt    *time.Time `json:"finished_at"`  //nolint:tagliatelle
+	Status        string     `json:"status"`
+	CatchUp       bool       `json:"catch_up"`       //nolint:tagliatelle
+	TasksTotal    int        `json:"tasks_total"`    //nolint:tagliatelle
+	TasksEnqueued int        `json:"tasks_enqueued"` //nolint:tagliatelle
+	TasksFailed   int        `json:"tasks_failed"`   //nolint:tagliatelle
+	Recipients    int        `json:"recipients"`
+	Error         string     `json:"error,omitempty"`
+}
+
+func toRunView(r job.Run) RunView {
+	return RunView{
+		ID:            r.ID,
+		Frequency:     r.Frequency,
+		ScheduledAt:   r.ScheduledAt,
+		StartedAt:     r.StartedAt,
+		FinishedAt:    r.FinishedAt,
+		Status:        string(r.Status),
+		CatchUp:       r.CatchUp,
+		TasksTotal:    r.TasksTotal,
+		TasksEnqueued: r.TasksEnqueued,
+		TasksFailed:   r.TasksFailed,
+		Recipients:    r.Recipients,
+		Error:         r.Error,
+	}
+}
+
+type runLister interface {
+	ListRecent(ctx context.Context, limit int) ([]job.Run, error)
+}
+
+type ListRuns struct {
+	runs runLister
+}
+
+func NewListRuns(runs runLister) ListRuns {
+	return ListRuns{runs: runs}
+}
+
+func (h ListRuns) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+
+	limit, ok := queryLimit(c, defaultRunsLimit, maxRunsLimit)
+	if !ok {
+		return
+	}
+
+	runs, err := h.runs.ListRecent(c.Request.Context(), limit)
+	if err != nil {
+		logger.Error("Failed to list dispatch runs", "err", err)
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	views := make([]RunView, 0, len(runs))
+	for _, r := range runs {
+		views = append(views, toRunView(r))
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": views})
+}
+
+// queryLimit reads the optional "limit" query parameter and answers 400 when it is out of range.
+func queryLimit(c *gin.Context, def, maxLimit int) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > maxLimit {
+		response.SendError(c, http.StatusBadRequest, "Invalid limit")
+		return 0, false
+	}
+	return n, true
+}
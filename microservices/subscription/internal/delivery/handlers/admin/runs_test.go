@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"subscription/internal/delivery/middleware"
+	"subscription/internal/job"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRunLister struct {
+	listFunc func(ctx context.Context, limit int) ([]job.Run, error)
+}
+
+func (m *mockRunLister) ListRecent(ctx context.Context, limit int) ([]job.Run, error) {
+	return m.listFunc(ctx, limit)
+}
+
+func setupRunsRouter(mock *mockRunLister, token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.GET("/api/admin/dispatch-runs", middleware.AdminAuth(token), NewListRuns(mock).Handle)
+	return r
+}
+
+func TestListRunsHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockRunLister{
+			listFunc: func(ctx context.Context, limit int) ([]job.Run, error) {
+				assert.Equal(t, 10, limit)
+				return []job.Run{{
+					ID:          1,
+					Frequency:   "daily",
+					ScheduledAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:      job.RunSucceeded,
+					CatchUp:     true,
+					TasksTotal:  3,
+				}}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dispatch-runs?limit=10", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		setupRunsRouter(mock, "secret").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"succeeded"`)
+		assert.Contains(t, w.Body.String(), `"catch_up":true`)
+		assert.Contains(t, w.Body.String(), `"scheduled_at":"2025-01-01T12:00:00Z"`)
+	})
+
+	t.Run("WrongToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dispatch-runs", nil)
+		req.Header.Set("Authorization", "Bearer nope")
+		w := httptest.NewRecorder()
+		setupRunsRouter(&mockRunLister{}, "secret").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("DisabledWithoutToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dispatch-runs", nil)
+		w := httptest.NewRecorder()
+		setupRunsRouter(&mockRunLister{}, "").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dispatch-runs?limit=0", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		setupRunsRouter(&mockRunLister{}, "secret").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		mock := &mockRunLister{
+			listFunc: func(ctx context.Context, limit int) ([]job.Run, error) {
+				return nil, errors.New("db down")
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dispatch-runs", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		setupRunsRouter(mock, "secret").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
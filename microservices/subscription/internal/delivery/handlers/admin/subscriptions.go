@@ -0,0 +1,321 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSubscriptionsLimit = 50
+	maxSubscriptionsLimit     = 500
+)
+
+type SubscriptionView struct {
+	ID             string     `json:"id"`
+	Email          string     `json:"email"`
+	City           string     `json:"city"`
+	Frequency      string     `json:"frequency"`
+	DeliveryHour   int        `json:"delivery_hour"` //nolint:tagliatelle
+	Locale         string     `json:"locale"`
+	Channel        string     `json:"channel"`
+	Status         string     `json:"status"`
+	IsConfirmed    bool       `json:"is_confirmed"`    //nolint:tagliatelle
+	IsUnsubscribed bool       `json:"is_unsubscribed"` //nolint:tagliatelle
+	CreatedAt      time.Time  `json:"created_at"`      //nolint:tagliatelle
+	PausedUntil    *time.Time `json:"paused_until"`    //nolint:tagliatelle
+}
+
+func toSubscriptionView(sub domain.Subscription) SubscriptionView {
+	return SubscriptionView{
+		ID:             sub.ID,
+		Email:          sub.Email,
+		City:           sub.City,
+		Frequency:      string(sub.Frequency),
+		DeliveryHour:   sub.DeliveryHour,
+		Locale:         sub.Locale,
+		Channel:        string(sub.Channel),
+		Status:         string(sub.Status()),
+		IsConfirmed:    sub.IsConfirmed,
+		IsUnsubscribed: sub.IsUnsubscribed,
+		CreatedAt:      sub.CreatedAt,
+		PausedUntil:    sub.PausedUntil,
+	}
+}
+
+type CityCountView struct {
+	City      string `json:"city"`
+	Frequency string `json:"frequency"`
+	Count     int64  `json:"count"`
+}
+
+type StatsView struct {
+	Total          int64            `json:"total"`
+	Pending        int64            `json:"pending"`
+	Active         int64            `json:"active"`
+	Unsubscribed   int64            `json:"unsubscribed"`
+	Confirmed      int64            `json:"confirmed"`
+	ConversionRate float64          `json:"conversion_rate"` //nolint:tagliatelle
+	ByFrequency    map[string]int64 `json:"by_frequency"`    //nolint:tagliatelle
+	ByCity         []CityCountView  `json:"by_city"`         //nolint:tagliatelle
+}
+
+func searchFilter(c *gin.Context) subscription.SearchFilter {
+	return subscription.SearchFilter{
+		Email:     c.Query("email"),
+		City:      c.Query("city"),
+		Frequency: domain.Frequency(c.Query("frequency")),
+		Status:    domain.SubscriptionStatus(c.Query("status")),
+	}
+}
+
+func sendAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, subscription.ErrSubscriptionNotFound):
+		response.SendError(c, http.StatusNotFound, "Subscription not found")
+	case errors.Is(err, subscription.ErrInvalidFrequency):
+		response.SendError(c, http.StatusBadRequest, "Invalid frequency value")
+	case errors.Is(err, subscription.ErrInvalidStatus):
+		response.SendError(c, http.StatusBadRequest, "Invalid status value")
+	case errors.Is(err, subscription.ErrInvalidCursor):
+		response.SendError(c, http.StatusBadRequest, "Invalid cursor")
+	case errors.Is(err, subscription.ErrAlreadyConfirmed):
+		response.SendError(c, http.StatusConflict, "Subscription already confirmed")
+	case errors.Is(err, subscription.ErrSubscriptionInactive):
+		response.SendError(c, http.StatusConflict, "Subscription is unsubscribed")
+	default:
+		loggerPkg.From(c.Request.Context()).Error("Admin request failed", "err", err)
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+	}
+}
+
+type subscriptionSearcher interface {
+	Search(ctx context.Context, filter subscription.SearchFilter, cursor string, limit int) (subscription.SearchResult, error)
+}
+
+type SearchSubscriptions struct {
+	service subscriptionSearcher
+}
+
+func NewSearchSubscriptions(service subscriptionSearcher) SearchSubscriptions {
+	return SearchSubscriptions{service: service}
+}
+
+func (h SearchSubscriptions) Handle(c *gin.Context) {
+	limit, ok := queryLimit(c, defaultSubscriptionsLimit, maxSubscriptionsLimit)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Search(c.Request.Context(), searchFilter(c), c.Query("cursor"), limit)
+	if err != nil {
+		sendAdminError(c, err)
+		return
+	}
+
+	views := make([]SubscriptionView, 0, len(res.Subscriptions))
+	for _, sub := range res.Subscriptions {
+		views = append(views, toSubscriptionView(sub))
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": views, "next_cursor": res.NextCursor})
+}
+
+type subscriptionExporter interface {
+	Export(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error
+}
+
+type ExportSubscriptions struct {
+	service subscriptionExporter
+}
+
+func NewExportSubscriptions(service subscriptionExporter) ExportSubscriptions {
+	return ExportSubscriptions{service: service}
+}
+
+// Handle streams matching subscriptions as CSV. Filter errors surface before the first row,
+// so they can still be answered with a JSON error.
+func (h ExportSubscriptions) Handle(c *gin.Context) {
+	w := csv.NewWriter(c.Writer)
+	started := false
+
+	err := h.service.Export(c.Request.Context(), searchFilter(c), func(sub domain.Subscription) error {
+		if !started {
+			started = true
+			writeCSVHeader(c)
+			if err := w.Write(csvColumns); err != nil {
+				return err
+			}
+		}
+		return w.Write(csvRow(sub))
+	})
+	if err != nil {
+		if !started {
+			sendAdminError(c, err)
+			return
+		}
+		// Headers are already sent; the truncated file is the only signal left.
+		loggerPkg.From(c.Request.Context()).Error("Subscription export aborted", "err", err)
+		return
+	}
+
+	if !started {
+		writeCSVHeader(c)
+		_ = w.Write(csvColumns)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		loggerPkg.From(c.Request.Context()).Error("Failed to write subscription export", "err", err)
+	}
+}
+
+var csvColumns = []string{"id", "email", "city", "frequency", "delivery_hour", "status", "created_at"}
+
+func writeCSVHeader(c *gin.Context) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="subscriptions.csv"`)
+	c.Status(http.StatusOK)
+}
+
+func csvRow(sub domain.Subscription) []string {
+	return []string{
+		sub.ID,
+		sub.Email,
+		sub.City,
+		string(sub.Frequency),
+		strconv.Itoa(sub.DeliveryHour),
+		string(sub.Status()),
+		sub.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+type subscriptionGetter interface {
+	Get(ctx context.Context, id string) (*domain.Subscription, error)
+}
+
+type GetSubscription struct {
+	service subscriptionGetter
+}
+
+func NewGetSubscription(service subscriptionGetter) GetSubscription {
+	return GetSubscription{service: service}
+}
+
+func (h GetSubscription) Handle(c *gin.Context) {
+	sub, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toSubscriptionView(*sub))
+}
+
+type forceConfirmer interface {
+	ForceConfirm(ctx context.Context, id string) (*domain.Subscription, error)
+}
+
+type ForceConfirm struct {
+	service forceConfirmer
+}
+
+func NewForceConfirm(service forceConfirmer) ForceConfirm {
+	return ForceConfirm{service: service}
+}
+
+func (h ForceConfirm) Handle(c *gin.Context) {
+	sub, err := h.service.ForceConfirm(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toSubscriptionView(*sub))
+}
+
+type confirmationResender interface {
+	ResendConfirmation(ctx context.Context, id string) error
+}
+
+type ResendConfirmation struct {
+	service confirmationResender
+}
+
+func NewResendConfirmation(service confirmationResender) ResendConfirmation {
+	return ResendConfirmation{service: service}
+}
+
+func (h ResendConfirmation) Handle(c *gin.Context) {
+	if err := h.service.ResendConfirmation(c.Request.Context(), c.Param("id")); err != nil {
+		sendAdminError(c, err)
+		return
+	}
+	response.SendSuccess(c, "Confirmation email sent")
+}
+
+type deactivator interface {
+	Deactivate(ctx context.Context, id string) (*domain.Subscription, error)
+}
+
+type Deactivate struct {
+	service deactivator
+}
+
+func NewDeactivate(service deactivator) Deactivate {
+	return Deactivate{service: service}
+}
+
+func (h Deactivate) Handle(c *gin.Context) {
+	sub, err := h.service.Deactivate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sendAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toSubscriptionView(*sub))
+}
+
+type statsProvider interface {
+	Stats(ctx context.Context) (subscription.Stats, error)
+}
+
+type SubscriptionStats struct {
+	service statsProvider
+}
+
+func NewSubscriptionStats(service statsProvider) SubscriptionStats {
+	return SubscriptionStats{service: service}
+}
+
+func (h SubscriptionStats) Handle(c *gin.Context) {
+	stats, err := h.service.Stats(c.Request.Context())
+	if err != nil {
+		sendAdminError(c, err)
+		return
+	}
+
+	view := StatsView{
+		Total:          stats.Total,
+		Pending:        stats.Pending,
+		Active:         stats.Active,
+		Unsubscribed:   stats.Unsubscribed,
+		Confirmed:      stats.Confirmed,
+		ConversionRate: stats.ConversionRate(),
+		ByFrequency:    make(map[string]int64, len(stats.ByFrequency)),
+		ByCity:         make([]CityCountView, 0, len(stats.ByCity)),
+	}
+	for freq, n := range stats.ByFrequency {
+		view.ByFrequency[string(freq)] = n
+	}
+	for _, cc := range stats.ByCity {
+		view.ByCity = append(view.ByCity, CityCountView{City: cc.City, Frequency: string(cc.Frequency), Count: cc.Count})
+	}
+	c.JSON(http.StatusOK, view)
+}
@@ -0,0 +1,201 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"subscription/internal/delivery/middleware"
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAdminService struct {
+	searchFunc  func(ctx context.Context, filter subscription.SearchFilter, cursor string, limit int) (subscription.SearchResult, error)
+	exportFunc  func(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error
+	getFunc     func(ctx context.Context, id string) (*domain.Subscription, error)
+	confirmFunc func(ctx context.Context, id string) (*domain.Subscription, error)
+	resendFunc  func(ctx context.Context, id string) error
+	statsFunc   func(ctx context.Context) (subscription.Stats, error)
+}
+
+func (m *mockAdminService) Search(ctx context.Context, filter subscription.SearchFilter, cursor string, limit int) (subscription.SearchResult, error) {
+	return m.searchFunc(ctx, filter, cursor, limit)
+}
+
+func (m *mockAdminService) Export(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error {
+	return m.exportFunc(ctx, filter, fn)
+}
+
+func (m *mockAdminService) Get(ctx context.Context, id string) (*domain.Subscription, error) {
+	return m.getFunc(ctx, id)
+}
+
+func (m *mockAdminService) ForceConfirm(ctx context.Context, id string) (*domain.Subscription, error) {
+	return m.confirmFunc(ctx, id)
+}
+
+func (m *mockAdminService) ResendConfirmation(ctx context.Context, id string) error {
+	return m.resendFunc(ctx, id)
+}
+
+func (m *mockAdminService) Stats(ctx context.Context) (subscription.Stats, error) {
+	return m.statsFunc(ctx)
+}
+
+func setupSubscriptionsRouter(mock *mockAdminService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	adminAPI := r.Group("/api/admin", middleware.AdminAuth("secret"))
+	adminAPI.GET("/subscriptions", NewSearchSubscriptions(mock).Handle)
+	adminAPI.GET("/subscriptions/export", NewExportSubscriptions(mock).Handle)
+	adminAPI.GET("/subscriptions/stats", NewSubscriptionStats(mock).Handle)
+	adminAPI.GET("/subscriptions/:id", NewGetSubscription(mock).Handle)
+	adminAPI.POST("/subscriptions/:id/confirm", NewForceConfirm(mock).Handle)
+	adminAPI.POST("/subscriptions/:id/resend-confirmation", NewResendConfirmation(mock).Handle)
+	return r
+}
+
+var testSub = domain.Subscription{
+	ID:           "sub-1",
+	Email:        "a@example.com",
+	City:         "Kyiv",
+	Frequency:    domain.FreqDaily,
+	DeliveryHour: 9,
+	IsConfirmed:  true,
+	Token:        "secret-unsubscribe-token",
+	CreatedAt:    time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestSearchSubscriptionsHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockAdminService{
+			searchFunc: func(ctx context.Context, filter subscription.SearchFilter, cursor string, limit int) (subscription.SearchResult, error) {
+				assert.Equal(t, subscription.SearchFilter{Email: "a@", City: "Kyiv", Frequency: domain.FreqDaily, Status: domain.StatusActive}, filter)
+				assert.Equal(t, "abc", cursor)
+				assert.Equal(t, 10, limit)
+				return subscription.SearchResult{Subscriptions: []domain.Subscription{testSub}, NextCursor: "next"}, nil
+			},
+		}
+
+		w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions?email=a@&city=Kyiv&frequency=daily&status=active&cursor=abc&limit=10")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":"a@example.com"`)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+		assert.Contains(t, w.Body.String(), `"next_cursor":"next"`)
+		assert.NotContains(t, w.Body.String(), "secret-unsubscribe-token")
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		mock := &mockAdminService{
+			searchFunc: func(ctx context.Context, filter subscription.SearchFilter, cursor string, limit int) (subscription.SearchResult, error) {
+				return subscription.SearchResult{}, subscription.ErrInvalidCursor
+			},
+		}
+
+		w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions?cursor=bad")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Invalid cursor"}`, w.Body.String())
+	})
+}
+
+func TestExportSubscriptionsHandler(t *testing.T) {
+	t.Run("StreamsCSV", func(t *testing.T) {
+		mock := &mockAdminService{
+			exportFunc: func(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error {
+				assert.Equal(t, domain.StatusActive, filter.Status)
+				return fn(testSub)
+			},
+		}
+
+		w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions/export?status=active")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Equal(t, []string{
+			"id,email,city,frequency,delivery_hour,status,created_at",
+			"sub-1,a@example.com,Kyiv,daily,9,active,2025-01-01T12:00:00Z",
+		}, lines)
+	})
+
+	t.Run("EmptyResultHasHeader", func(t *testing.T) {
+		mock := &mockAdminService{
+			exportFunc: func(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error {
+				return nil
+			},
+		}
+
+		w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions/export")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,email,city,frequency,delivery_hour,status,created_at\n", w.Body.String())
+	})
+
+	t.Run("InvalidFilter", func(t *testing.T) {
+		mock := &mockAdminService{
+			exportFunc: func(ctx context.Context, filter subscription.SearchFilter, fn func(domain.Subscription) error) error {
+				return subscription.ErrInvalidStatus
+			},
+		}
+
+		w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions/export?status=gone")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSubscriptionStatsHandler(t *testing.T) {
+	mock := &mockAdminService{
+		statsFunc: func(ctx context.Context) (subscription.Stats, error) {
+			return subscription.Stats{
+				Total:       4,
+				Pending:     1,
+				Active:      2,
+				Confirmed:   3,
+				ByFrequency: map[domain.Frequency]int64{domain.FreqDaily: 2},
+				ByCity:      []subscription.CityCount{{City: "Kyiv", Frequency: domain.FreqDaily, Count: 2}},
+			}, nil
+		},
+	}
+
+	w := doAdminGet(setupSubscriptionsRouter(mock), "/api/admin/subscriptions/stats")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"conversion_rate":0.75`)
+	assert.Contains(t, w.Body.String(), `"by_frequency":{"daily":2}`)
+	assert.Contains(t, w.Body.String(), `"by_city":[{"city":"Kyiv","frequency":"daily","count":2}]`)
+}
+
+func TestSubscriptionActionsHandlers(t *testing.T) {
+	mock := &mockAdminService{
+		getFunc: func(ctx context.Context, id string) (*domain.Subscription, error) {
+			return nil, subscription.ErrSubscriptionNotFound
+		},
+		confirmFunc: func(ctx context.Context, id string) (*domain.Subscription, error) {
+			return nil, subscription.ErrSubscriptionInactive
+		},
+		resendFunc: func(ctx context.Context, id string) error {
+			assert.Equal(t, "sub-1", id)
+			return nil
+		},
+	}
+	r := setupSubscriptionsRouter(mock)
+
+	w := doAdminGet(r, "/api/admin/subscriptions/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = doAdminRequest(r, http.MethodPost, "/api/admin/subscriptions/sub-1/confirm")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = doAdminRequest(r, http.MethodPost, "/api/admin/subscriptions/sub-1/resend-confirmation")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Confirmation email sent"}`, w.Body.String())
+}
@@ -0,0 +1,124 @@
+package feed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/subscription"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	extAtom = ".atom"
+	extRSS  = ".rss"
+)
+
+type feedReader interface {
+	Feed(ctx context.Context, token string) (subscription.Feed, error)
+}
+
+// Feed serves /api/feed/{token}.atom and /api/feed/{token}.rss.
+type Feed struct {
+	service feedReader
+	baseURL string
+}
+
+func NewFeed(service feedReader, baseURL string) Feed {
+	return Feed{service: service, baseURL: baseURL}
+}
+
+// Handle renders the feed named by the file parameter. The ETag is a hash of the body, which
+// depends only on the stored reports, so readers polling with If-None-Match get a 304 until a
+// new report appears.
+func (h Feed) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+
+	file := c.Param("file")
+	token, ext := splitExt(file)
+	if token == "" {
+		response.SendError(c, http.StatusNotFound, "Feed not found")
+		return
+	}
+
+	f, err := h.service.Feed(c.Request.Context(), token)
+	if err != nil {
+		logger.Warn("feed failed", "err", err)
+		switch {
+		case errors.Is(err, subscription.ErrInvalidToken), errors.Is(err, subscription.ErrSubscriptionNotFound):
+			response.SendError(c, http.StatusNotFound, "Feed not found")
+		default:
+			response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+		}
+		return
+	}
+
+	links := URLs(h.baseURL, token)
+	var body []byte
+	contentType := atomContentType
+	if ext == extAtom {
+		body, err = renderAtom(f, links)
+	} else {
+		body, err = renderRSS(f, links)
+		contentType = rssContentType
+	}
+	if err != nil {
+		logger.Error("render feed failed", "err", err)
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	etag := bodyETag(body)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", f.Updated.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "private, max-age=300")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// Links are the absolute URLs of both formats of one feed.
+type Links struct {
+	Atom string
+	RSS  string
+}
+
+// URLs returns the links of the feed a token opens.
+func URLs(baseURL, token string) Links {
+	prefix := baseURL + "/api/feed/" + token
+	return Links{Atom: prefix + extAtom, RSS: prefix + extRSS}
+}
+
+func splitExt(file string) (string, string) {
+	for _, ext := range []string{extAtom, extRSS} {
+		if token, ok := strings.CutSuffix(file, ext); ok {
+			return token, ext
+		}
+	}
+	return "", ""
+}
+
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches implements the weak comparison If-None-Match asks for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeeds struct {
+	feed  subscription.Feed
+	err   error
+	token string
+}
+
+func (f *fakeFeeds) Feed(_ context.Context, token string) (subscription.Feed, error) {
+	f.token = token
+	return f.feed, f.err
+}
+
+func setupFeedRouter(service feedReader) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/feed/:file", NewFeed(service, "https://weather.example.com").Handle)
+	return r
+}
+
+func testFeed() subscription.Feed {
+	at := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	return subscription.Feed{
+		SubscriptionID: "sub-1",
+		City:           "Kyiv",
+		Frequency:      domain.FreqHourly,
+		Updated:        at,
+		Entries: []domain.ReportSnapshot{
+			{RunID: 2, City: "Kyiv", At: at, Report: domain.Report{Temperature: 21.5, Humidity: 60, Description: "Sunny"}},
+			{RunID: 1, City: "Kyiv", At: at.Add(-time.Hour), Report: domain.Report{Temperature: 19, Humidity: 70, Description: "Cloudy"}},
+		},
+	}
+}
+
+func get(r *gin.Engine, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestFeedHandler_Atom(t *testing.T) {
+	service := &fakeFeeds{feed: testFeed()}
+
+	w := get(setupFeedRouter(service), "/api/feed/feed-token.atom", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "feed-token", service.token)
+	assert.Equal(t, atomContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "Thu, 02 Jan 2025 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+
+	var doc atomFeed
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "urn:uuid:sub-1", doc.ID)
+	assert.Equal(t, "2025-01-02T12:00:00Z", doc.Updated)
+	assert.Equal(t, "https://weather.example.com/api/feed/feed-token.atom", doc.Links[0].Href)
+	require.Len(t, doc.Entries, 2)
+	assert.Equal(t, "Kyiv: 21.5°C, Sunny", doc.Entries[0].Title)
+	assert.Equal(t, "2025-01-02T12:00:00Z", doc.Entries[0].Updated)
+	assert.Equal(t, "urn:weather-report:Kyiv:hourly:1735819200", doc.Entries[0].ID)
+	assert.Contains(t, doc.Entries[0].Content.Body, "Humidity: 60%")
+}
+
+func TestFeedHandler_RSS(t *testing.T) {
+	w := get(setupFeedRouter(&fakeFeeds{feed: testFeed()}), "/api/feed/feed-token.rss", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, rssContentType, w.Header().Get("Content-Type"))
+
+	var doc rssFeed
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, 60, doc.Channel.TTL)
+	assert.Equal(t, "Thu, 02 Jan 2025 12:00:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+	assert.Equal(t, "urn:weather-report:Kyiv:hourly:1735819200", doc.Channel.Items[0].GUID.Value)
+	assert.Equal(t, "Thu, 02 Jan 2025 11:00:00 +0000", doc.Channel.Items[1].PubDate)
+}
+
+func TestFeedHandler_ETag(t *testing.T) {
+	router := setupFeedRouter(&fakeFeeds{feed: testFeed()})
+	etag := get(router, "/api/feed/feed-token.atom", nil).Header().Get("ETag")
+
+	t.Run("NotModified", func(t *testing.T) {
+		w := get(router, "/api/feed/feed-token.atom", http.Header{"If-None-Match": {`"other", W/` + etag}})
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("StaleETag", func(t *testing.T) {
+		w := get(router, "/api/feed/feed-token.atom", http.Header{"If-None-Match": {`"other"`}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("DiffersPerFormat", func(t *testing.T) {
+		rss := get(router, "/api/feed/feed-token.rss", nil)
+
+		assert.NotEqual(t, etag, rss.Header().Get("ETag"))
+	})
+}
+
+func TestFeedHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		err      error
+		wantCode int
+	}{
+		{name: "UnknownFormat", path: "/api/feed/feed-token.json", wantCode: http.StatusNotFound},
+		{name: "InvalidToken", path: "/api/feed/feed-token.atom", err: subscription.ErrInvalidToken, wantCode: http.StatusNotFound},
+		{name: "NotFound", path: "/api/feed/feed-token.rss", err: subscription.ErrSubscriptionNotFound, wantCode: http.StatusNotFound},
+		{name: "Internal", path: "/api/feed/feed-token.atom", err: errors.New("db down"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(setupFeedRouter(&fakeFeeds{err: tt.err}), tt.path, nil)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
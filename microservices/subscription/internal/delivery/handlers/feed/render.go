@@ -0,0 +1,177 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+)
+
+const (
+	atomNamespace   = "http://www.w3.org/2005/Atom"
+	atomContentType = "application/atom+xml; charset=utf-8"
+	rssContentType  = "application/rss+xml; charset=utf-8"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Content atomText `xml:"content"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssLink is the atom:link RSS feeds use to point to themselves.
+type rssLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func renderAtom(f subscription.Feed, links Links) ([]byte, error) {
+	doc := atomFeed{
+		ID:      "urn:uuid:" + f.SubscriptionID,
+		Title:   feedTitle(f),
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "Weather Subscription"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: links.Atom},
+			{Rel: "alternate", Type: "application/rss+xml", Href: links.RSS},
+		},
+	}
+	for _, e := range f.Entries {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:      entryID(f, e),
+			Title:   entryTitle(e),
+			Updated: e.At.UTC().Format(time.RFC3339),
+			Content: atomText{Type: "text", Body: entrySummary(e.Report)},
+		})
+	}
+	return marshal(doc)
+}
+
+func renderRSS(f subscription.Feed, links Links) ([]byte, error) {
+	doc := rssFeed{
+		Version: "2.0",
+		AtomNS:  atomNamespace,
+		Channel: rssChannel{
+			Title:         feedTitle(f),
+			Link:          links.RSS,
+			Description:   fmt.Sprintf("%s weather reports for %s", titleFrequency(f.Frequency), f.City),
+			SelfLink:      rssLink{Rel: "self", Type: "application/rss+xml", Href: links.RSS},
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			TTL:           pollMinutes(f.Frequency),
+		},
+	}
+	for _, e := range f.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entryTitle(e),
+			GUID:        rssGUID{Value: entryID(f, e)},
+			PubDate:     e.At.UTC().Format(time.RFC1123Z),
+			Description: entrySummary(e.Report),
+		})
+	}
+	return marshal(doc)
+}
+
+func marshal(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode feed: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func feedTitle(f subscription.Feed) string {
+	return fmt.Sprintf("Weather in %s (%s)", f.City, f.Frequency)
+}
+
+// entryID names a report by city, frequency and time, so the same report keeps its id whether
+// it came from a run or was fetched on demand.
+func entryID(f subscription.Feed, e domain.ReportSnapshot) string {
+	return fmt.Sprintf("urn:weather-report:%s:%s:%d", url.PathEscape(f.City), f.Frequency, e.At.Unix())
+}
+
+func entryTitle(e domain.ReportSnapshot) string {
+	return fmt.Sprintf("%s: %.1f°C, %s", e.City, e.Report.Temperature, e.Report.Description)
+}
+
+func entrySummary(r domain.Report) string {
+	return fmt.Sprintf("Temperature: %.1f°C\nHumidity: %d%%\n%s", r.Temperature, r.Humidity, r.Description)
+}
+
+func titleFrequency(f domain.Frequency) string {
+	if f == domain.FreqDaily {
+		return "Daily"
+	}
+	return "Hourly"
+}
+
+// pollMinutes tells RSS readers how often a new report can appear.
+func pollMinutes(f domain.Frequency) int {
+	if f == domain.FreqDaily {
+		return 24 * 60
+	}
+	return 60
+}
@@ -14,6 +14,13 @@ func SendError(c *gin.Context, code int, msg string) {
 	c.JSON(code, gin.H{"error": msg})
 }
 
+// SendErrorCode adds a machine-readable code for errors clients are expected to tell apart.
+func SendErrorCode(c *gin.Context, status int, code, msg string) {
+	logger := loggerPkg.From(c.Request.Context())
+	logger.Error("handler error", "msg", msg, "code", status, "error_code", code)
+	c.JSON(status, gin.H{"error": msg, "code": code})
+}
+
 func SendSuccess(c *gin.Context, message string) {
 	c.JSON(http.StatusOK, gin.H{"message": message})
 }
@@ -0,0 +1,38 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/emailcheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned with rejected addresses so clients can show a specific hint.
+const (
+	CodeInvalidEmail       = "invalid_email"
+	CodeDisposableEmail    = "disposable_email"
+	CodeUndeliverableEmail = "undeliverable_email"
+)
+
+type addressValidator interface {
+	Validate(ctx context.Context, email string) (string, error)
+}
+
+type addressNormalizer interface {
+	Normalize(email string) (string, error)
+}
+
+func sendAddressError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, emailcheck.ErrDisposableDomain):
+		response.SendErrorCode(c, http.StatusBadRequest, CodeDisposableEmail, "Disposable email addresses are not allowed")
+	case errors.Is(err, emailcheck.ErrUndeliverableDomain):
+		response.SendErrorCode(c, http.StatusBadRequest, CodeUndeliverableEmail, "Email domain cannot receive mail")
+	default:
+		response.SendErrorCode(c, http.StatusBadRequest, CodeInvalidEmail, "Invalid email address")
+	}
+}
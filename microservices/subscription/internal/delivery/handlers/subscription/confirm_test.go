@@ -7,6 +7,7 @@ import (
 	"os"
 	"testing"
 
+	"subscription/internal/domain"
 	"subscription/internal/subscription"
 	"subscription/internal/token/jwt"
 
@@ -40,10 +41,11 @@ func setupRouterWithMock(t *testing.T, mock *mockConfirmService) *gin.Engine {
 }
 
 func TestConfirmHandler(t *testing.T) {
-	jwt := jwt.NewJWT("test-secret")
+	jwt, err := jwt.New(jwt.Config{Keys: map[string]string{"test": "test-secret"}, ActiveKID: "test"})
+	require.NoError(t, err)
 
 	t.Run("Success", func(t *testing.T) {
-		token, err := jwt.Generate("confirmtest@example.com")
+		token, err := jwt.Generate("confirmtest@example.com", domain.TokenPurposeConfirm)
 		require.NoError(t, err)
 
 		mock := &mockConfirmService{
@@ -81,7 +83,7 @@ func TestConfirmHandler(t *testing.T) {
 	})
 
 	t.Run("TokenButNoSubscription", func(t *testing.T) {
-		token, err := jwt.Generate("ghost@example.com")
+		token, err := jwt.Generate("ghost@example.com", domain.TokenPurposeConfirm)
 		require.NoError(t, err)
 
 		mock := &mockConfirmService{
@@ -101,7 +103,7 @@ func TestConfirmHandler(t *testing.T) {
 	})
 
 	t.Run("AlreadyConfirmed", func(t *testing.T) {
-		token, err := jwt.Generate("already@confirmed.com")
+		token, err := jwt.Generate("already@confirmed.com", domain.TokenPurposeConfirm)
 		require.NoError(t, err)
 
 		mock := &mockConfirmService{
@@ -121,7 +123,7 @@ func TestConfirmHandler(t *testing.T) {
 	})
 
 	t.Run("InternalError", func(t *testing.T) {
-		token, err := jwt.Generate("broken@token.com")
+		token, err := jwt.Generate("broken@token.com", domain.TokenPurposeConfirm)
 		require.NoError(t, err)
 
 		mock := &mockConfirmService{
@@ -0,0 +1,53 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+
+	"subscription/internal/delivery/handlers/feed"
+	"subscription/internal/delivery/handlers/response"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedLinkResponse struct {
+	FeedToken string `json:"feed_token"` //nolint:tagliatelle
+	AtomURL   string `json:"atom_url"`   //nolint:tagliatelle
+	RSSURL    string `json:"rss_url"`    //nolint:tagliatelle
+}
+
+type issueFeedToken interface {
+	IssueToken(ctx context.Context, manageToken, id string) (string, error)
+}
+
+// FeedLink hands out the feed URLs of a subscription. Every call issues a new token and
+// revokes the previous one, so it doubles as the way to replace a leaked feed URL.
+type FeedLink struct {
+	service issueFeedToken
+	baseURL string
+}
+
+func NewFeedLink(service issueFeedToken, baseURL string) FeedLink {
+	return FeedLink{service: service, baseURL: baseURL}
+}
+
+func (h FeedLink) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	feedToken, err := h.service.IssueToken(c.Request.Context(), token, c.Param("id"))
+	if err != nil {
+		logger.Warn("issue feed token failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	links := feed.URLs(h.baseURL, feedToken)
+	c.JSON(http.StatusCreated, FeedLinkResponse{FeedToken: feedToken, AtomURL: links.Atom, RSSURL: links.RSS})
+}
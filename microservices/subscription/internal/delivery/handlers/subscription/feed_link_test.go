@@ -0,0 +1,70 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFeedTokens struct {
+	issueFunc func(ctx context.Context, manageToken, id string) (string, error)
+}
+
+func (m *mockFeedTokens) IssueToken(ctx context.Context, manageToken, id string) (string, error) {
+	return m.issueFunc(ctx, manageToken, id)
+}
+
+func setupFeedLinkRouter(mock *mockFeedTokens) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.POST("/api/subscriptions/:id/feed", NewFeedLink(mock, "https://weather.example.com").Handle)
+	return r
+}
+
+func TestFeedLinkHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockFeedTokens{issueFunc: func(_ context.Context, manageToken, id string) (string, error) {
+			assert.Equal(t, "manage-token", manageToken)
+			assert.Equal(t, "sub-1", id)
+			return "feed-token", nil
+		}}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-1/feed", nil)
+		req.Header.Set("Authorization", "Bearer manage-token")
+		w := httptest.NewRecorder()
+		setupFeedLinkRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{
+			"feed_token": "feed-token",
+			"atom_url": "https://weather.example.com/api/feed/feed-token.atom",
+			"rss_url": "https://weather.example.com/api/feed/feed-token.rss"
+		}`, w.Body.String())
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-1/feed", nil)
+		w := httptest.NewRecorder()
+		setupFeedLinkRouter(&mockFeedTokens{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("NotOwned", func(t *testing.T) {
+		mock := &mockFeedTokens{issueFunc: func(context.Context, string, string) (string, error) {
+			return "", subscription.ErrSubscriptionNotFound
+		}}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-2/feed?token=manage-token", nil)
+		w := httptest.NewRecorder()
+		setupFeedLinkRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
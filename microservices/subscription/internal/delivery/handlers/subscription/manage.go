@@ -0,0 +1,325 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionView struct {
+	ID           string    `json:"id"`
+	City         string    `json:"city"`
+	Frequency    string    `json:"frequency"`
+	DeliveryHour int       `json:"delivery_hour"` //nolint:tagliatelle
+	Locale       string    `json:"locale"`
+	Channel      string    `json:"channel"`
+	IsConfirmed  bool      `json:"is_confirmed"` //nolint:tagliatelle
+	CreatedAt    time.Time `json:"created_at"`   //nolint:tagliatelle
+	// PausedUntil and QuietHours are null when not set.
+	PausedUntil *time.Time      `json:"paused_until"` //nolint:tagliatelle
+	QuietHours  *QuietHoursView `json:"quiet_hours"`  //nolint:tagliatelle
+	Digest      bool            `json:"digest"`
+}
+
+// QuietHoursView is the window, in hours of the given IANA timezone, without hourly reports.
+type QuietHoursView struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+func toQuietHoursView(q *domain.QuietHours) *QuietHoursView {
+	if q == nil {
+		return nil
+	}
+	return &QuietHoursView{Start: q.Start, End: q.End, Timezone: q.Timezone}
+}
+
+func toView(sub domain.Subscription) SubscriptionView {
+	return SubscriptionView{
+		ID:           sub.ID,
+		City:         sub.City,
+		Frequency:    string(sub.Frequency),
+		DeliveryHour: sub.DeliveryHour,
+		Locale:       sub.Locale,
+		Channel:      string(sub.Channel),
+		IsConfirmed:  sub.IsConfirmed,
+		CreatedAt:    sub.CreatedAt,
+		PausedUntil:  sub.PausedUntil,
+		QuietHours:   toQuietHoursView(sub.QuietHours),
+		Digest:       sub.Digest,
+	}
+}
+
+// manageToken reads the magic link token from the Authorization header or the token query parameter.
+func manageToken(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return c.Query("token")
+}
+
+func sendManageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, subscription.ErrInvalidToken):
+		response.SendError(c, http.StatusUnauthorized, "Invalid token")
+	case errors.Is(err, subscription.ErrSubscriptionNotFound):
+		response.SendError(c, http.StatusNotFound, "Subscription not found")
+	case errors.Is(err, subscription.ErrCityNotFound):
+		response.SendError(c, http.StatusBadRequest, "City not found")
+	case errors.Is(err, subscription.ErrEmailAlreadyExists):
+		response.SendError(c, http.StatusConflict, "Already subscribed to this city")
+	case errors.Is(err, subscription.ErrInvalidFrequency):
+		response.SendError(c, http.StatusBadRequest, "Invalid frequency value")
+	case errors.Is(err, subscription.ErrInvalidDeliveryHour):
+		response.SendError(c, http.StatusBadRequest, "Invalid delivery hour")
+	case errors.Is(err, subscription.ErrInvalidQuietHours):
+		response.SendError(c, http.StatusBadRequest, "Invalid quiet hours")
+	case errors.Is(err, subscription.ErrInvalidPauseUntil):
+		response.SendError(c, http.StatusBadRequest, "Pause must end in the future and within a year")
+	case errors.Is(err, subscription.ErrNotWebhookChannel):
+		response.SendError(c, http.StatusConflict, "Subscription does not use webhooks")
+	case errors.Is(err, subscription.ErrWebhooksDisabled):
+		response.SendError(c, http.StatusBadRequest, "Webhook channel is not available")
+	default:
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+	}
+}
+
+type listSubscriptions interface {
+	ListSubscriptions(ctx context.Context, token string) ([]domain.Subscription, error)
+}
+
+type ListSubscriptions struct {
+	service listSubscriptions
+}
+
+func NewListSubscriptions(service listSubscriptions) ListSubscriptions {
+	return ListSubscriptions{service: service}
+}
+
+func (h ListSubscriptions) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	subs, err := h.service.ListSubscriptions(c.Request.Context(), token)
+	if err != nil {
+		logger.Warn("list subscriptions failed", "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	views := make([]SubscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		views = append(views, toView(sub))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": views})
+}
+
+type updateSubscription interface {
+	UpdateSubscription(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error)
+}
+
+type UpdateSubscription struct {
+	service updateSubscription
+}
+
+func NewUpdateSubscription(service updateSubscription) UpdateSubscription {
+	return UpdateSubscription{service: service}
+}
+
+type UpdateSubscriptionRequest struct {
+	City         *string `json:"city"`
+	Frequency    *string `json:"frequency" binding:"omitempty,oneof=daily hourly"`
+	DeliveryHour *int    `json:"delivery_hour" binding:"omitempty,min=0,max=23"` //nolint:tagliatelle
+	// QuietHours with equal start and end removes the quiet hours.
+	QuietHours *QuietHoursRequest `json:"quiet_hours"` //nolint:tagliatelle
+	// Digest combines the subscription's reports with the address's other digest subscriptions.
+	Digest *bool `json:"digest"`
+}
+
+type QuietHoursRequest struct {
+	Start *int `json:"start" binding:"required,min=0,max=23"`
+	End   *int `json:"end" binding:"required,min=0,max=23"`
+	// Timezone is an IANA name such as "Europe/Kyiv"; empty means UTC.
+	Timezone string `json:"timezone"`
+}
+
+func (h UpdateSubscription) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	var req UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid update input", "err", err)
+		response.SendError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	params := subscription.UpdateParams{
+		City:         req.City,
+		DeliveryHour: req.DeliveryHour,
+		Digest:       req.Digest,
+	}
+	if req.Frequency != nil {
+		freq := domain.Frequency(*req.Frequency)
+		params.Frequency = &freq
+	}
+	if q := req.QuietHours; q != nil {
+		timezone := q.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		params.QuietHours = &domain.QuietHours{Start: *q.Start, End: *q.End, Timezone: timezone}
+	}
+
+	sub, err := h.service.UpdateSubscription(c.Request.Context(), token, c.Param("id"), params)
+	if err != nil {
+		logger.Warn("update subscription failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toView(*sub))
+}
+
+type pauseSubscription interface {
+	PauseSubscription(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error)
+	ResumeSubscription(ctx context.Context, token, id string) (*domain.Subscription, error)
+}
+
+type PauseSubscription struct {
+	service pauseSubscription
+}
+
+func NewPauseSubscription(service pauseSubscription) PauseSubscription {
+	return PauseSubscription{service: service}
+}
+
+type PauseSubscriptionRequest struct {
+	Until time.Time `json:"until" binding:"required"`
+}
+
+// Pause handles POST /subscriptions/:id/pause with an RFC 3339 "until" time.
+func (h PauseSubscription) Pause(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	var req PauseSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid pause input", "err", err)
+		response.SendError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	sub, err := h.service.PauseSubscription(c.Request.Context(), token, c.Param("id"), req.Until)
+	if err != nil {
+		logger.Warn("pause subscription failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toView(*sub))
+}
+
+// Resume handles POST /subscriptions/:id/resume.
+func (h PauseSubscription) Resume(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	sub, err := h.service.ResumeSubscription(c.Request.Context(), token, c.Param("id"))
+	if err != nil {
+		logger.Warn("resume subscription failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toView(*sub))
+}
+
+type reverifyWebhook interface {
+	ReverifyWebhook(ctx context.Context, token, id string) error
+}
+
+type ReverifyWebhook struct {
+	service reverifyWebhook
+}
+
+func NewReverifyWebhook(service reverifyWebhook) ReverifyWebhook {
+	return ReverifyWebhook{service: service}
+}
+
+// Handle handles POST /subscriptions/:id/webhook/verify, which repeats the handshake with the
+// webhook URL and re-enables an endpoint disabled after failed deliveries.
+func (h ReverifyWebhook) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	if err := h.service.ReverifyWebhook(c.Request.Context(), token, c.Param("id")); err != nil {
+		logger.Warn("reverify webhook failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Webhook verification started"})
+}
+
+type deleteSubscription interface {
+	DeleteSubscription(ctx context.Context, token, id string) error
+}
+
+type DeleteSubscription struct {
+	service deleteSubscription
+}
+
+func NewDeleteSubscription(service deleteSubscription) DeleteSubscription {
+	return DeleteSubscription{service: service}
+}
+
+func (h DeleteSubscription) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), token, c.Param("id")); err != nil {
+		logger.Warn("delete subscription failed", "id", c.Param("id"), "err", err)
+		sendManageError(c, err)
+		return
+	}
+
+	response.SendSuccess(c, "Subscription deleted")
+}
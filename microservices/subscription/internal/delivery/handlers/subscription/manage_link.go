@@ -0,0 +1,56 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+
+	"subscription/internal/delivery/handlers/response"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type manageLink interface {
+	RequestManageLink(ctx context.Context, email string) error
+}
+
+type ManageLink struct {
+	service   manageLink
+	addresses addressNormalizer
+}
+
+func NewManageLink(service manageLink, addresses addressNormalizer) ManageLink {
+	return ManageLink{service: service, addresses: addresses}
+}
+
+type ManageLinkRequest struct {
+	Email string `form:"email" json:"email" binding:"required"`
+}
+
+func (h ManageLink) Handle(c *gin.Context) {
+	logger := loggerPkg.From(c.Request.Context())
+	var req ManageLinkRequest
+	if err := c.ShouldBind(&req); err != nil {
+		logger.Warn("invalid manage link input", "err", err)
+		response.SendError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	// Subscriptions are stored under the normalised address, so look them up the same way.
+	email, err := h.addresses.Normalize(req.Email)
+	if err != nil {
+		logger.Warn("invalid manage link address", "err", err)
+		sendAddressError(c, err)
+		return
+	}
+	req.Email = email
+
+	if err := h.service.RequestManageLink(c.Request.Context(), req.Email); err != nil {
+		logger.Warn("manage link request failed", "user", loggerPkg.HashEmail(req.Email), "err", err)
+		response.SendError(c, http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	response.SendSuccess(c, "If this email is subscribed, a management link has been sent.")
+}
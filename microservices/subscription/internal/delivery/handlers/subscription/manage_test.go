@@ -0,0 +1,281 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Mock service ---.
+type mockManageService struct {
+	listFunc   func(ctx context.Context, token string) ([]domain.Subscription, error)
+	updateFunc func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error)
+	deleteFunc func(ctx context.Context, token, id string) error
+	pauseFunc  func(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error)
+	resumeFunc func(ctx context.Context, token, id string) (*domain.Subscription, error)
+}
+
+func (m *mockManageService) ListSubscriptions(ctx context.Context, token string) ([]domain.Subscription, error) {
+	return m.listFunc(ctx, token)
+}
+
+func (m *mockManageService) UpdateSubscription(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+	return m.updateFunc(ctx, token, id, params)
+}
+
+func (m *mockManageService) DeleteSubscription(ctx context.Context, token, id string) error {
+	return m.deleteFunc(ctx, token, id)
+}
+
+func (m *mockManageService) PauseSubscription(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error) {
+	return m.pauseFunc(ctx, token, id, until)
+}
+
+func (m *mockManageService) ResumeSubscription(ctx context.Context, token, id string) (*domain.Subscription, error) {
+	return m.resumeFunc(ctx, token, id)
+}
+
+func setupManageRouter(mock *mockManageService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.GET("/api/subscriptions", NewListSubscriptions(mock).Handle)
+	r.PATCH("/api/subscriptions/:id", NewUpdateSubscription(mock).Handle)
+	r.DELETE("/api/subscriptions/:id", NewDeleteSubscription(mock).Handle)
+	r.POST("/api/subscriptions/:id/pause", NewPauseSubscription(mock).Pause)
+	r.POST("/api/subscriptions/:id/resume", NewPauseSubscription(mock).Resume)
+	return r
+}
+
+func TestListSubscriptionsHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockManageService{
+			listFunc: func(ctx context.Context, token string) ([]domain.Subscription, error) {
+				assert.Equal(t, "magic", token)
+				return []domain.Subscription{{ID: "sub-1", City: "Kyiv", Frequency: domain.FreqDaily, DeliveryHour: 9}}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/subscriptions?token=magic", nil)
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"sub-1"`)
+		assert.Contains(t, w.Body.String(), `"delivery_hour":9`)
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/subscriptions", nil)
+		w := httptest.NewRecorder()
+		setupManageRouter(&mockManageService{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		mock := &mockManageService{
+			listFunc: func(ctx context.Context, token string) ([]domain.Subscription, error) {
+				return nil, subscription.ErrInvalidToken
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/subscriptions", nil)
+		req.Header.Set("Authorization", "Bearer bad")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error":"Invalid token"}`, w.Body.String())
+	})
+}
+
+func TestUpdateSubscriptionHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockManageService{
+			updateFunc: func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+				assert.Equal(t, "sub-1", id)
+				assert.Equal(t, domain.FreqHourly, *params.Frequency)
+				assert.Nil(t, params.City)
+				return &domain.Subscription{ID: id, City: "Kyiv", Frequency: domain.FreqHourly}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"frequency":"hourly"}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"frequency":"hourly"`)
+	})
+
+	t.Run("InvalidDeliveryHour", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"delivery_hour":30}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(&mockManageService{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid input")
+	})
+
+	t.Run("QuietHoursDefaultToUTC", func(t *testing.T) {
+		mock := &mockManageService{
+			updateFunc: func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+				assert.Equal(t, &domain.QuietHours{Start: 22, End: 7, Timezone: "UTC"}, params.QuietHours)
+				return &domain.Subscription{ID: id, QuietHours: params.QuietHours}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"quiet_hours":{"start":22,"end":7}}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"quiet_hours":{"start":22,"end":7,"timezone":"UTC"}`)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		mock := &mockManageService{
+			updateFunc: func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+				assert.True(t, *params.Digest)
+				return &domain.Subscription{ID: id, Digest: *params.Digest}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"digest":true}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"digest":true`)
+	})
+
+	t.Run("CityAlreadyFollowed", func(t *testing.T) {
+		mock := &mockManageService{
+			updateFunc: func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+				return nil, subscription.ErrEmailAlreadyExists
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"city":"Lviv"}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("QuietHoursNeedBothEnds", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/sub-1", strings.NewReader(`{"quiet_hours":{"start":22}}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(&mockManageService{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock := &mockManageService{
+			updateFunc: func(ctx context.Context, token, id string, params subscription.UpdateParams) (*domain.Subscription, error) {
+				return nil, subscription.ErrSubscriptionNotFound
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/subscriptions/other", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeleteSubscriptionHandler(t *testing.T) {
+	mock := &mockManageService{
+		deleteFunc: func(ctx context.Context, token, id string) error {
+			assert.Equal(t, "magic", token)
+			assert.Equal(t, "sub-1", id)
+			return nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/subscriptions/sub-1", nil)
+	req.Header.Set("Authorization", "Bearer magic")
+	w := httptest.NewRecorder()
+	setupManageRouter(mock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Subscription deleted"}`, w.Body.String())
+}
+
+func TestPauseSubscriptionHandler(t *testing.T) {
+	t.Run("Pause", func(t *testing.T) {
+		mock := &mockManageService{
+			pauseFunc: func(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error) {
+				assert.Equal(t, "sub-1", id)
+				assert.True(t, until.Equal(time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)))
+				return &domain.Subscription{ID: id, PausedUntil: &until}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-1/pause", strings.NewReader(`{"until":"2026-08-01T03:00:00+03:00"}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"paused_until":"2026-08-01T03:00:00+03:00"`)
+	})
+
+	t.Run("PauseInPast", func(t *testing.T) {
+		mock := &mockManageService{
+			pauseFunc: func(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error) {
+				return nil, subscription.ErrInvalidPauseUntil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-1/pause", strings.NewReader(`{"until":"2020-01-01T00:00:00Z"}`))
+		req.Header.Set("Authorization", "Bearer magic")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		mock := &mockManageService{
+			resumeFunc: func(ctx context.Context, token, id string) (*domain.Subscription, error) {
+				return &domain.Subscription{ID: id}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/sub-1/resume", nil)
+		req.Header.Set("Authorization", "Bearer magic")
+		w := httptest.NewRecorder()
+		setupManageRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"paused_until":null`)
+	})
+}
@@ -0,0 +1,211 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportedSubscription struct {
+	ID           string          `json:"id"`
+	City         string          `json:"city"`
+	Frequency    string          `json:"frequency"`
+	DeliveryHour int             `json:"delivery_hour"` //nolint:tagliatelle
+	Locale       string          `json:"locale"`
+	Status       string          `json:"status"`
+	CreatedAt    time.Time       `json:"created_at"`   //nolint:tagliatelle
+	PausedUntil  *time.Time      `json:"paused_until"` //nolint:tagliatelle
+	QuietHours   *QuietHoursView `json:"quiet_hours"`  //nolint:tagliatelle
+}
+
+type ExportedWeather struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	Description string  `json:"description"`
+}
+
+type ExportedDelivery struct {
+	SubscriptionID string           `json:"subscription_id"` //nolint:tagliatelle
+	City           string           `json:"city"`
+	Weather        *ExportedWeather `json:"weather"`
+	Status         string           `json:"status"`
+	Error          string           `json:"error,omitempty"`
+	QueuedAt       time.Time        `json:"queued_at"`  //nolint:tagliatelle
+	SentAt         *time.Time       `json:"sent_at"`    //nolint:tagliatelle
+	FailedAt       *time.Time       `json:"failed_at"`  //nolint:tagliatelle
+	BouncedAt      *time.Time       `json:"bounced_at"` //nolint:tagliatelle
+}
+
+type ExportedMessage struct {
+	Type      string     `json:"type"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"` //nolint:tagliatelle
+	SentAt    *time.Time `json:"sent_at"`    //nolint:tagliatelle
+}
+
+// ExportedWebhook leaves out the signing secret: it is a credential the service issued rather
+// than data about the subscriber, and the export must not hand it to whoever holds a manage link.
+type ExportedWebhook struct {
+	SubscriptionID string     `json:"subscription_id"` //nolint:tagliatelle
+	URL            string     `json:"url"`
+	CreatedAt      time.Time  `json:"created_at"`  //nolint:tagliatelle
+	VerifiedAt     *time.Time `json:"verified_at"` //nolint:tagliatelle
+	DisabledAt     *time.Time `json:"disabled_at"` //nolint:tagliatelle
+}
+
+type ExportedWebhookDelivery struct {
+	SubscriptionID string     `json:"subscription_id"` //nolint:tagliatelle
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	CreatedAt      time.Time  `json:"created_at"` //nolint:tagliatelle
+	SentAt         *time.Time `json:"sent_at"`    //nolint:tagliatelle
+}
+
+type DataExportView struct {
+	Email             string                    `json:"email"`
+	ExportedAt        time.Time                 `json:"exported_at"` //nolint:tagliatelle
+	Subscriptions     []ExportedSubscription    `json:"subscriptions"`
+	Deliveries        []ExportedDelivery        `json:"deliveries"`
+	Messages          []ExportedMessage         `json:"messages"`
+	Webhooks          []ExportedWebhook         `json:"webhooks"`
+	WebhookDeliveries []ExportedWebhookDelivery `json:"webhook_deliveries"` //nolint:tagliatelle
+}
+
+func toDataExportView(data subscription.DataExport) DataExportView {
+	view := DataExportView{
+		Email:         data.Email,
+		ExportedAt:    data.ExportedAt,
+		Subscriptions: make([]ExportedSubscription, 0, len(data.Subscriptions)),
+		Deliveries:    make([]ExportedDelivery, 0, len(data.Deliveries)),
+		Messages:      make([]ExportedMessage, 0, len(data.Messages)),
+
+		Webhooks:          make([]ExportedWebhook, 0, len(data.Webhooks)),
+		WebhookDeliveries: make([]ExportedWebhookDelivery, 0, len(data.WebhookDeliveries)),
+	}
+	for _, sub := range data.Subscriptions {
+		view.Subscriptions = append(view.Subscriptions, ExportedSubscription{
+			ID:           sub.ID,
+			City:         sub.City,
+			Frequency:    string(sub.Frequency),
+			DeliveryHour: sub.DeliveryHour,
+			Locale:       sub.Locale,
+			Status:       string(sub.Status()),
+			CreatedAt:    sub.CreatedAt,
+			PausedUntil:  sub.PausedUntil,
+			QuietHours:   toQuietHoursView(sub.QuietHours),
+		})
+	}
+	for _, d := range data.Deliveries {
+		view.Deliveries = append(view.Deliveries, toExportedDelivery(d))
+	}
+	for _, m := range data.Messages {
+		view.Messages = append(view.Messages, ExportedMessage{
+			Type:      m.RoutingKey,
+			Status:    m.Status,
+			CreatedAt: m.CreatedAt,
+			SentAt:    m.SentAt,
+		})
+	}
+	for _, e := range data.Webhooks {
+		view.Webhooks = append(view.Webhooks, ExportedWebhook{
+			SubscriptionID: e.SubscriptionID,
+			URL:            e.URL,
+			CreatedAt:      e.CreatedAt,
+			VerifiedAt:     e.VerifiedAt,
+			DisabledAt:     e.DisabledAt,
+		})
+	}
+	for _, d := range data.WebhookDeliveries {
+		view.WebhookDeliveries = append(view.WebhookDeliveries, ExportedWebhookDelivery{
+			SubscriptionID: d.SubscriptionID,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			CreatedAt:      d.CreatedAt,
+			SentAt:         d.SentAt,
+		})
+	}
+	return view
+}
+
+func toExportedDelivery(d domain.Delivery) ExportedDelivery {
+	e := ExportedDelivery{
+		SubscriptionID: d.SubscriptionID,
+		City:           d.City,
+		Status:         string(d.Status),
+		Error:          d.Error,
+		QueuedAt:       d.QueuedAt,
+		SentAt:         d.SentAt,
+		FailedAt:       d.FailedAt,
+		BouncedAt:      d.BouncedAt,
+	}
+	if d.Weather != nil {
+		e.Weather = &ExportedWeather{
+			Temperature: d.Weather.Temperature,
+			Humidity:    d.Weather.Humidity,
+			Description: d.Weather.Description,
+		}
+	}
+	return e
+}
+
+type dataExporter interface {
+	Export(ctx context.Context, token string) (subscription.DataExport, error)
+}
+
+type ExportData struct {
+	service dataExporter
+}
+
+func NewExportData(service dataExporter) ExportData {
+	return ExportData{service: service}
+}
+
+func (h ExportData) Handle(c *gin.Context) {
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	data, err := h.service.Export(c.Request.Context(), token)
+	if err != nil {
+		sendManageError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="weather-subscription-data.json"`)
+	c.JSON(http.StatusOK, toDataExportView(data))
+}
+
+type dataEraser interface {
+	Erase(ctx context.Context, token string) (subscription.ErasureResult, error)
+}
+
+type EraseData struct {
+	service dataEraser
+}
+
+func NewEraseData(service dataEraser) EraseData {
+	return EraseData{service: service}
+}
+
+func (h EraseData) Handle(c *gin.Context) {
+	token := manageToken(c)
+	if token == "" {
+		response.SendError(c, http.StatusUnauthorized, "Token is required")
+		return
+	}
+
+	if _, err := h.service.Erase(c.Request.Context(), token); err != nil {
+		sendManageError(c, err)
+		return
+	}
+
+	response.SendSuccess(c, "Your data has been erased")
+}
@@ -0,0 +1,126 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Mock service ---.
+type mockPrivacyService struct {
+	exportFunc func(ctx context.Context, token string) (subscription.DataExport, error)
+	eraseFunc  func(ctx context.Context, token string) (subscription.ErasureResult, error)
+}
+
+func (m *mockPrivacyService) Export(ctx context.Context, token string) (subscription.DataExport, error) {
+	return m.exportFunc(ctx, token)
+}
+
+func (m *mockPrivacyService) Erase(ctx context.Context, token string) (subscription.ErasureResult, error) {
+	return m.eraseFunc(ctx, token)
+}
+
+func setupPrivacyRouter(mock *mockPrivacyService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.GET("/api/privacy/data", NewExportData(mock).Handle)
+	r.DELETE("/api/privacy/data", NewEraseData(mock).Handle)
+	return r
+}
+
+func TestExportDataHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		sentAt := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+		mock := &mockPrivacyService{
+			exportFunc: func(ctx context.Context, token string) (subscription.DataExport, error) {
+				assert.Equal(t, "magic", token)
+				return subscription.DataExport{
+					Email:         "user@example.com",
+					Subscriptions: []domain.Subscription{{ID: "sub-1", City: "Kyiv", IsConfirmed: true}},
+					Deliveries:    []domain.Delivery{{SubscriptionID: "sub-1", City: "Kyiv", Status: domain.DeliverySent, SentAt: &sentAt}},
+					Messages:      []subscription.StoredMessage{{RoutingKey: "email.confirmation", Status: "sent"}},
+					Webhooks: []domain.WebhookEndpoint{
+						{SubscriptionID: "sub-2", URL: "https://hooks.example.com/weather", Secret: "whsec_secret"},
+					},
+					WebhookDeliveries: []subscription.StoredWebhookDelivery{{SubscriptionID: "sub-2", Status: "sent", Attempts: 1}},
+				}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/privacy/data", nil)
+		req.Header.Set("Authorization", "Bearer magic")
+		w := httptest.NewRecorder()
+		setupPrivacyRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+		assert.Contains(t, w.Body.String(), `"email":"user@example.com"`)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+		assert.Contains(t, w.Body.String(), `"type":"email.confirmation"`)
+		assert.Contains(t, w.Body.String(), `"url":"https://hooks.example.com/weather"`)
+		assert.Contains(t, w.Body.String(), `"webhook_deliveries":[{"subscription_id":"sub-2","status":"sent","attempts":1`)
+		assert.NotContains(t, w.Body.String(), "whsec_secret", "the signing secret is not exported")
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/privacy/data", nil)
+		w := httptest.NewRecorder()
+		setupPrivacyRouter(&mockPrivacyService{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		mock := &mockPrivacyService{
+			exportFunc: func(ctx context.Context, token string) (subscription.DataExport, error) {
+				return subscription.DataExport{}, subscription.ErrInvalidToken
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/privacy/data?token=bad", nil)
+		w := httptest.NewRecorder()
+		setupPrivacyRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestEraseDataHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mock := &mockPrivacyService{
+			eraseFunc: func(ctx context.Context, token string) (subscription.ErasureResult, error) {
+				assert.Equal(t, "magic", token)
+				return subscription.ErasureResult{Subscriptions: 1}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/privacy/data?token=magic", nil)
+		w := httptest.NewRecorder()
+		setupPrivacyRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "erased")
+	})
+
+	t.Run("SubscriptionGone", func(t *testing.T) {
+		mock := &mockPrivacyService{
+			eraseFunc: func(ctx context.Context, token string) (subscription.ErasureResult, error) {
+				return subscription.ErasureResult{}, subscription.ErrSubscriptionNotFound
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/privacy/data?token=magic", nil)
+		w := httptest.NewRecorder()
+		setupPrivacyRouter(mock).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -3,11 +3,14 @@ package subscription
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"subscription/internal/domain"
 
 	"subscription/internal/delivery/handlers/response"
+	"subscription/internal/ratelimit"
 	"subscription/internal/subscription"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
@@ -16,21 +19,51 @@ import (
 )
 
 type subscribe interface {
-	Subscribe(ctx context.Context, email, city string, frequency domain.Frequency) error
+	Subscribe(ctx context.Context, email, city string, frequency domain.Frequency, locale string) error
+	SubscribeWebhook(ctx context.Context, email, city string, frequency domain.Frequency, locale, webhookURL string) (string, error)
+}
+
+// localeNegotiator picks a supported locale from the form value and the Accept-Language header.
+type localeNegotiator interface {
+	Negotiate(requested, acceptLanguage string) string
+}
+
+// subscribeGuard throttles subscribe attempts; a nil guard disables the limits.
+type subscribeGuard interface {
+	Check(ctx context.Context, ip, email, city string) error
+	ConfirmationSent(ctx context.Context, email, city string)
 }
 
 type Subscribe struct {
-	service subscribe
+	service   subscribe
+	addresses addressValidator
+	guard     subscribeGuard
+	locales   localeNegotiator
 }
 
-func NewSubscribe(service subscribe) Subscribe {
-	return Subscribe{service: service}
+func NewSubscribe(service subscribe, addresses addressValidator, guard subscribeGuard, locales localeNegotiator) Subscribe {
+	return Subscribe{service: service, addresses: addresses, guard: guard, locales: locales}
 }
 
 type SubscribeRequest struct {
-	Email     string `form:"email" binding:"required,email"`
+	// Email is checked and normalised by the address validator rather than the binding.
+	Email     string `form:"email" binding:"required"`
 	City      string `form:"city" binding:"required"`
 	Frequency string `form:"frequency" binding:"required,oneof=daily hourly"`
+	// Locale overrides the Accept-Language header; unsupported values fall back to it.
+	Locale string `form:"locale"`
+	// Nickname is a honeypot: the field is hidden on the form, so only bots fill it in.
+	Nickname string `form:"nickname"`
+	// Channel is "email" (the default) or "webhook", which needs WebhookURL.
+	Channel    string `form:"channel" binding:"omitempty,oneof=email webhook"`
+	WebhookURL string `form:"webhook_url" json:"webhook_url"` //nolint:tagliatelle
+}
+
+// SubscribeResponse answers a webhook subscription; WebhookSecret signs the requests to the URL
+// and is only shown here.
+type SubscribeResponse struct {
+	Message       string `json:"message"`
+	WebhookSecret string `json:"webhook_secret"` //nolint:tagliatelle
 }
 
 func (h Subscribe) Handle(c *gin.Context) {
@@ -42,6 +75,13 @@ func (h Subscribe) Handle(c *gin.Context) {
 		return
 	}
 
+	if req.Nickname != "" {
+		// Bots get the usual answer so they have nothing to adapt to.
+		logger.Warn("subscribe honeypot triggered", "ip", c.ClientIP())
+		response.SendSuccess(c, "Subscription successful. Confirmation email sent.")
+		return
+	}
+
 	freq := domain.Frequency(req.Frequency)
 	if !freq.Valid() {
 		logger.Warn("invalid frequency value", "value", req.Frequency)
@@ -49,19 +89,86 @@ func (h Subscribe) Handle(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Subscribe(c.Request.Context(), req.Email, req.City, freq)
+	email, err := h.addresses.Validate(c.Request.Context(), req.Email)
+	if err != nil {
+		logger.Warn("subscribe address rejected", "user", loggerPkg.HashEmail(req.Email), "err", err)
+		sendAddressError(c, err)
+		return
+	}
+	req.Email = email
+
+	if h.guard != nil {
+		if err := h.guard.Check(c.Request.Context(), c.ClientIP(), req.Email, req.City); err != nil {
+			logger.Warn("subscribe rate limited", "user", loggerPkg.HashEmail(req.Email), "ip", c.ClientIP(), "err", err)
+			sendRateLimited(c, err)
+			return
+		}
+	}
+
+	channel := domain.ChannelEmail
+	if req.Channel != "" {
+		channel = domain.Channel(req.Channel)
+	}
+	if channel == domain.ChannelWebhook && req.WebhookURL == "" {
+		logger.Warn("webhook subscribe without url")
+		response.SendError(c, http.StatusBadRequest, "Webhook URL is required")
+		return
+	}
+
+	locale := h.locales.Negotiate(req.Locale, c.GetHeader("Accept-Language"))
+	var secret string
+	if channel == domain.ChannelWebhook {
+		secret, err = h.service.SubscribeWebhook(c.Request.Context(), req.Email, req.City, freq, locale, req.WebhookURL)
+	} else {
+		err = h.service.Subscribe(c.Request.Context(), req.Email, req.City, freq, locale)
+	}
 	if err != nil {
-		logger.Warn("subscribe failed", "email", req.Email, "city", req.City, "err", err)
+		logger.Warn("subscribe failed", "email", req.Email, "city", req.City, "channel", channel, "err", err)
 		switch {
 		case errors.Is(err, subscription.ErrCityNotFound):
 			response.SendError(c, http.StatusBadRequest, "City not found")
 		case errors.Is(err, subscription.ErrEmailAlreadyExists):
 			response.SendError(c, http.StatusConflict, "Email already subscribed")
+		case errors.Is(err, subscription.ErrInvalidWebhookURL):
+			response.SendError(c, http.StatusBadRequest, "Invalid webhook URL")
+		case errors.Is(err, subscription.ErrWebhooksDisabled):
+			response.SendError(c, http.StatusBadRequest, "Webhook channel is not available")
 		default:
 			response.SendError(c, http.StatusInternalServerError, "Something went wrong")
 		}
 		return
 	}
 
+	if h.guard != nil {
+		h.guard.ConfirmationSent(c.Request.Context(), req.Email, req.City)
+	}
+
+	if channel == domain.ChannelWebhook {
+		c.JSON(http.StatusOK, SubscribeResponse{
+			Message:       "Subscription successful. Confirmation email sent, the webhook URL will be verified.",
+			WebhookSecret: secret,
+		})
+		return
+	}
 	response.SendSuccess(c, "Subscription successful. Confirmation email sent.")
 }
+
+func sendRateLimited(c *gin.Context, err error) {
+	var limitErr *ratelimit.LimitError
+	if !errors.As(err, &limitErr) {
+		response.SendError(c, http.StatusTooManyRequests, "Too many requests, please try again later")
+		return
+	}
+
+	if limitErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+	}
+	switch limitErr.Reason {
+	case ratelimit.ReasonCooldown:
+		response.SendError(c, http.StatusTooManyRequests, "A confirmation email was sent recently, please check your inbox")
+	case ratelimit.ReasonEmail:
+		response.SendError(c, http.StatusTooManyRequests, "Too many subscription attempts for this email, please try again later")
+	default:
+		response.SendError(c, http.StatusTooManyRequests, "Too many subscription attempts, please try again later")
+	}
+}
@@ -8,8 +8,12 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"subscription/internal/domain"
+	"subscription/internal/emailcheck"
+	"subscription/internal/locale"
+	"subscription/internal/ratelimit"
 	"subscription/internal/subscription"
 
 	"github.com/gin-gonic/gin"
@@ -20,14 +24,26 @@ import (
 
 type mockSubscribeService struct {
 	subscribeFunc func(ctx context.Context, email, city string, frequency domain.Frequency) error
+	webhookFunc   func(ctx context.Context, email, webhookURL string) (string, error)
+	locale        string
 }
 
-func (m *mockSubscribeService) Subscribe(ctx context.Context, email, city string, frequency domain.Frequency) error {
+func (m *mockSubscribeService) Subscribe(ctx context.Context, email, city string, frequency domain.Frequency, locale string) error {
+	m.locale = locale
 	return m.subscribeFunc(ctx, email, city, frequency)
 }
 
+func (m *mockSubscribeService) SubscribeWebhook(ctx context.Context, email, _ string, _ domain.Frequency, locale, webhookURL string) (string, error) {
+	m.locale = locale
+	return m.webhookFunc(ctx, email, webhookURL)
+}
+
 // --- Test Setup ---
 
+var testAddresses = emailcheck.NewValidator(nil, emailcheck.DefaultConfig())
+
+var testLocales, _ = locale.NewNegotiator("uk", []string{"uk", "en"})
+
 func setupTestRouter(handler Subscribe) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
@@ -45,7 +61,7 @@ func TestSubscribeHandler(t *testing.T) {
 				return nil
 			},
 		}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -65,7 +81,7 @@ func TestSubscribeHandler(t *testing.T) {
 
 	t.Run("MissingEmail", func(t *testing.T) {
 		service := &mockSubscribeService{}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -84,7 +100,7 @@ func TestSubscribeHandler(t *testing.T) {
 
 	t.Run("InvalidFrequency", func(t *testing.T) {
 		service := &mockSubscribeService{}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -108,7 +124,7 @@ func TestSubscribeHandler(t *testing.T) {
 				return subscription.ErrEmailAlreadyExists
 			},
 		}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -132,7 +148,7 @@ func TestSubscribeHandler(t *testing.T) {
 				return subscription.ErrCityNotFound
 			},
 		}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -156,7 +172,7 @@ func TestSubscribeHandler(t *testing.T) {
 				return errors.New("unexpected error")
 			},
 		}
-		handler := NewSubscribe(service)
+		handler := NewSubscribe(service, testAddresses, nil, testLocales)
 		router := setupTestRouter(handler)
 
 		form := url.Values{}
@@ -174,3 +190,236 @@ func TestSubscribeHandler(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Something went wrong")
 	})
 }
+
+type mockSubscribeGuard struct {
+	checkErr error
+	sent     []string
+}
+
+func (m *mockSubscribeGuard) Check(context.Context, string, string, string) error { return m.checkErr }
+
+func (m *mockSubscribeGuard) ConfirmationSent(_ context.Context, email, _ string) {
+	m.sent = append(m.sent, email)
+}
+
+func postSubscribeForm(router *gin.Engine, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func validSubscribeForm() url.Values {
+	form := url.Values{}
+	form.Add("email", "test@example.com")
+	form.Add("city", "Kyiv")
+	form.Add("frequency", "daily")
+	return form
+}
+
+func TestSubscribeHandler_AbuseProtection(t *testing.T) {
+	t.Run("HoneypotFilled", func(t *testing.T) {
+		service := &mockSubscribeService{
+			subscribeFunc: func(ctx context.Context, email, city string, frequency domain.Frequency) error {
+				t.Fatal("service must not be called for bots")
+				return nil
+			},
+		}
+		guard := &mockSubscribeGuard{}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, guard, testLocales))
+
+		form := validSubscribeForm()
+		form.Add("nickname", "bot")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message":"Subscription successful. Confirmation email sent."}`, w.Body.String())
+		assert.Empty(t, guard.sent)
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		guard := &mockSubscribeGuard{checkErr: &ratelimit.LimitError{Reason: ratelimit.ReasonIP, RetryAfter: 90500 * time.Millisecond}}
+		router := setupTestRouter(NewSubscribe(&mockSubscribeService{}, testAddresses, guard, testLocales))
+
+		w := postSubscribeForm(router, validSubscribeForm())
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "91", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "Too many subscription attempts")
+	})
+
+	t.Run("Cooldown", func(t *testing.T) {
+		guard := &mockSubscribeGuard{checkErr: &ratelimit.LimitError{Reason: ratelimit.ReasonCooldown, RetryAfter: time.Minute}}
+		router := setupTestRouter(NewSubscribe(&mockSubscribeService{}, testAddresses, guard, testLocales))
+
+		w := postSubscribeForm(router, validSubscribeForm())
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "confirmation email was sent recently")
+	})
+
+	t.Run("StartsCooldownAfterSuccess", func(t *testing.T) {
+		service := &mockSubscribeService{
+			subscribeFunc: func(ctx context.Context, email, city string, frequency domain.Frequency) error { return nil },
+		}
+		guard := &mockSubscribeGuard{}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, guard, testLocales))
+
+		w := postSubscribeForm(router, validSubscribeForm())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"test@example.com"}, guard.sent)
+	})
+
+	t.Run("NoCooldownWhenSubscribeFails", func(t *testing.T) {
+		service := &mockSubscribeService{
+			subscribeFunc: func(ctx context.Context, email, city string, frequency domain.Frequency) error {
+				return subscription.ErrEmailAlreadyExists
+			},
+		}
+		guard := &mockSubscribeGuard{}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, guard, testLocales))
+
+		w := postSubscribeForm(router, validSubscribeForm())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Empty(t, guard.sent)
+	})
+}
+
+func TestSubscribeHandler_AddressChecks(t *testing.T) {
+	t.Run("NormalisesAddress", func(t *testing.T) {
+		var got string
+		service := &mockSubscribeService{
+			subscribeFunc: func(ctx context.Context, email, city string, frequency domain.Frequency) error {
+				got = email
+				return nil
+			},
+		}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, nil, testLocales))
+
+		form := validSubscribeForm()
+		form.Set("email", "  Test@Example.COM ")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "test@example.com", got)
+	})
+
+	cases := []struct {
+		name, email, code string
+	}{
+		{"Malformed", "not-an-email", CodeInvalidEmail},
+		{"DisplayName", "Test <test@example.com>", CodeInvalidEmail},
+		{"Disposable", "someone@mailinator.com", CodeDisposableEmail},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setupTestRouter(NewSubscribe(&mockSubscribeService{}, testAddresses, nil, testLocales))
+
+			form := validSubscribeForm()
+			form.Set("email", tc.email)
+			w := postSubscribeForm(router, form)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
+
+func TestSubscribeHandler_Locale(t *testing.T) {
+	cases := []struct {
+		name, formLocale, acceptLanguage, want string
+	}{
+		{"Default", "", "", "uk"},
+		{"FromAcceptLanguage", "", "en-US,en;q=0.9", "en"},
+		{"FormOverridesHeader", "uk", "en-US", "uk"},
+		{"UnsupportedFallsBack", "de", "fr-FR", "uk"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &mockSubscribeService{
+				subscribeFunc: func(ctx context.Context, email, city string, frequency domain.Frequency) error {
+					return nil
+				},
+			}
+			router := setupTestRouter(NewSubscribe(service, testAddresses, nil, testLocales))
+
+			form := validSubscribeForm()
+			if tc.formLocale != "" {
+				form.Set("locale", tc.formLocale)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.want, service.locale)
+		})
+	}
+}
+
+func TestSubscribeHandler_Webhook(t *testing.T) {
+	t.Run("ReturnsSecret", func(t *testing.T) {
+		service := &mockSubscribeService{
+			webhookFunc: func(ctx context.Context, email, webhookURL string) (string, error) {
+				assert.Equal(t, "test@example.com", email)
+				assert.Equal(t, "https://hooks.example.com/weather", webhookURL)
+				return "whsec_abc", nil
+			},
+		}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, nil, testLocales))
+
+		form := validSubscribeForm()
+		form.Add("channel", "webhook")
+		form.Add("webhook_url", "https://hooks.example.com/weather")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"webhook_secret":"whsec_abc"`)
+	})
+
+	t.Run("MissingURL", func(t *testing.T) {
+		router := setupTestRouter(NewSubscribe(&mockSubscribeService{}, testAddresses, nil, testLocales))
+
+		form := validSubscribeForm()
+		form.Add("channel", "webhook")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Webhook URL is required"}`, w.Body.String())
+	})
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		service := &mockSubscribeService{
+			webhookFunc: func(context.Context, string, string) (string, error) {
+				return "", subscription.ErrInvalidWebhookURL
+			},
+		}
+		router := setupTestRouter(NewSubscribe(service, testAddresses, nil, testLocales))
+
+		form := validSubscribeForm()
+		form.Add("channel", "webhook")
+		form.Add("webhook_url", "ftp://hooks.example.com")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"Invalid webhook URL"}`, w.Body.String())
+	})
+
+	t.Run("UnknownChannel", func(t *testing.T) {
+		router := setupTestRouter(NewSubscribe(&mockSubscribeService{}, testAddresses, nil, testLocales))
+
+		form := validSubscribeForm()
+		form.Add("channel", "sms")
+		w := postSubscribeForm(router, form)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
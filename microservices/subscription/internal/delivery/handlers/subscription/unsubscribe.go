@@ -28,6 +28,21 @@ func NewUnsubscribe(service unsubscribe) Unsubscribe {
 }
 
 func (h Unsubscribe) Handle(c *gin.Context) {
+	h.unsubscribe(c)
+}
+
+// HandleOneClick serves RFC 8058 one-click unsubscribe: mailbox providers POST the form body
+// "List-Unsubscribe=One-Click" to the List-Unsubscribe URL and expect no confirmation step.
+func (h Unsubscribe) HandleOneClick(c *gin.Context) {
+	if c.PostForm("List-Unsubscribe") != "One-Click" {
+		loggerPkg.From(c.Request.Context()).Warn("invalid one-click unsubscribe body")
+		response.SendError(c, http.StatusBadRequest, "Invalid one-click unsubscribe request")
+		return
+	}
+	h.unsubscribe(c)
+}
+
+func (h Unsubscribe) unsubscribe(c *gin.Context) {
 	logger := loggerPkg.From(c.Request.Context())
 	token := c.Param("token")
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"subscription/internal/subscription"
@@ -28,6 +29,7 @@ func setupUnsubscribeRouter(s unsubscribe) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	r.GET("/api/unsubscribe/:token", handler.Handle)
+	r.POST("/api/unsubscribe/:token", handler.HandleOneClick)
 	return r
 }
 
@@ -98,3 +100,53 @@ func TestUnsubscribeHandler(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Something went wrong")
 	})
 }
+
+func TestOneClickUnsubscribeHandler(t *testing.T) {
+	postOneClick := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/unsubscribe/some-valid-token", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var got string
+		service := &mockUnsubscribeService{
+			unsubscribeFunc: func(ctx context.Context, token string) error {
+				got = token
+				return nil
+			},
+		}
+
+		w := postOneClick(setupUnsubscribeRouter(service), "List-Unsubscribe=One-Click")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "some-valid-token", got)
+	})
+
+	t.Run("MissingOneClickBody", func(t *testing.T) {
+		service := &mockUnsubscribeService{
+			unsubscribeFunc: func(ctx context.Context, token string) error {
+				t.Fatal("must not unsubscribe without the one-click body")
+				return nil
+			},
+		}
+
+		w := postOneClick(setupUnsubscribeRouter(service), "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		service := &mockUnsubscribeService{
+			unsubscribeFunc: func(ctx context.Context, token string) error {
+				return subscription.ErrInvalidToken
+			},
+		}
+
+		w := postOneClick(setupUnsubscribeRouter(service), "List-Unsubscribe=One-Click")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
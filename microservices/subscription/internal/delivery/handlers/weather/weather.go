@@ -22,15 +22,20 @@ type Report struct {
 var ErrCityNotFound = errors.New("city not found")
 
 type weatherCurrent interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
+}
+
+type localeNegotiator interface {
+	Negotiate(requested, acceptLanguage string) string
 }
 
 type WeatherCurrent struct {
 	service weatherCurrent
+	locales localeNegotiator
 }
 
-func NewWeatherCurrent(service weatherCurrent) *WeatherCurrent {
-	return &WeatherCurrent{service: service}
+func NewWeatherCurrent(service weatherCurrent, locales localeNegotiator) *WeatherCurrent {
+	return &WeatherCurrent{service: service, locales: locales}
 }
 
 func (h WeatherCurrent) Handle(c *gin.Context) {
@@ -42,7 +47,8 @@ func (h WeatherCurrent) Handle(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetWeather(c.Request.Context(), city)
+	lang := h.locales.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+	data, err := h.service.GetWeather(c.Request.Context(), city, lang)
 	if err != nil {
 		logger.Warn("failed to fetch weather data", "city", city, "err", err)
 		switch {
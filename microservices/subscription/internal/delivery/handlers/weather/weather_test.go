@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"subscription/internal/domain"
+	"subscription/internal/locale"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,16 +18,19 @@ import (
 type mockWeatherService struct {
 	getWeatherFunc func(ctx context.Context, city string) (
 		domain.Report, error)
+	lang string
 }
 
-func (m *mockWeatherService) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (m *mockWeatherService) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	m.lang = lang
 	return m.getWeatherFunc(ctx, city)
 }
 
 // --- setup router ---
 
 func setupWeatherRouter(service weatherCurrent) *gin.Engine {
-	handler := NewWeatherCurrent(service)
+	locales, _ := locale.NewNegotiator("uk", []string{"uk", "en"})
+	handler := NewWeatherCurrent(service, locales)
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
 	r.GET("/api/weather", handler.Handle)
@@ -88,4 +92,21 @@ func TestWeatherHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.JSONEq(t, `{"error":"City not found"}`, w.Body.String())
 	})
+
+	t.Run("LanguageFromAcceptLanguage", func(t *testing.T) {
+		service := &mockWeatherService{
+			getWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+				return domain.Report{Description: "Cloudy"}, nil
+			},
+		}
+		router := setupWeatherRouter(service)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/weather?city=Kyiv", nil)
+		req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "en", service.lang)
+	})
 }
@@ -4,46 +4,146 @@ import (
 	"net/http"
 
 	"subscription/internal/domain"
+	"subscription/internal/job"
 
 	"golang.org/x/net/context"
 
 	"subscription/internal/subscription"
 
+	"subscription/internal/delivery/handlers/admin"
+	"subscription/internal/delivery/handlers/feed"
 	subscription2 "subscription/internal/delivery/handlers/subscription"
 	handlers2 "subscription/internal/delivery/handlers/weather"
 	"subscription/internal/delivery/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 	metricsPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
 )
 
 type weatherService interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 }
 
-func SetupRoutes(subService subscription.Service, weatherClient weatherService, logger *loggerPkg.Logger, metrics *metricsPkg.Metrics) *gin.Engine {
+type localeNegotiator interface {
+	Negotiate(requested, acceptLanguage string) string
+}
+
+type leaderStatus interface {
+	IsLeader() bool
+}
+
+type dispatchRuns interface {
+	ListRecent(ctx context.Context, limit int) ([]job.Run, error)
+}
+
+type deliveryHistory interface {
+	ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error)
+	ListByRun(ctx context.Context, runID int64, limit int) ([]domain.Delivery, error)
+}
+
+type addressValidator interface {
+	Validate(ctx context.Context, email string) (string, error)
+	Normalize(email string) (string, error)
+}
+
+type subscribeGuard interface {
+	Check(ctx context.Context, ip, email, city string) error
+	ConfirmationSent(ctx context.Context, email, city string)
+}
+
+// Deps groups what the HTTP layer needs from the application.
+type Deps struct {
+	SubService    subscription.Service
+	AdminService  subscription.AdminService
+	Privacy       subscription.PrivacyService
+	WeatherClient weatherService
+	Leader        leaderStatus
+	DispatchRuns  dispatchRuns
+	Deliveries    deliveryHistory
+	AdminToken    string
+	Addresses     addressValidator
+	// SubscribeGuard rate limits subscribe attempts; nil disables the limits.
+	SubscribeGuard subscribeGuard
+	Locales        localeNegotiator
+	Feeds          subscription.FeedService
+	// BaseURL is the public address of the service, used in the feed links.
+	BaseURL string
+}
+
+func SetupRoutes(deps Deps, logger *loggerPkg.Logger, metrics *metricsPkg.Metrics) *gin.Engine {
 	router := gin.Default()
+	subService := deps.SubService
 
 	router.Use(middleware.RequestLoggingMiddleware(logger, metrics, "subscription"))
 
-	subscribeHandler := subscription2.NewSubscribe(subService)
+	subscribeHandler := subscription2.NewSubscribe(subService, deps.Addresses, deps.SubscribeGuard, deps.Locales)
 	confirmHandler := subscription2.NewConfirm(subService)
 	unsubscribeHandler := subscription2.NewUnsubscribe(subService)
-	weatherHandler := handlers2.NewWeatherCurrent(weatherClient)
+	manageLinkHandler := subscription2.NewManageLink(subService, deps.Addresses)
+	listHandler := subscription2.NewListSubscriptions(subService)
+	updateHandler := subscription2.NewUpdateSubscription(subService)
+	deleteHandler := subscription2.NewDeleteSubscription(subService)
+	pauseHandler := subscription2.NewPauseSubscription(subService)
+	reverifyWebhookHandler := subscription2.NewReverifyWebhook(subService)
+	exportDataHandler := subscription2.NewExportData(deps.Privacy)
+	eraseDataHandler := subscription2.NewEraseData(deps.Privacy)
+	feedLinkHandler := subscription2.NewFeedLink(deps.Feeds, deps.BaseURL)
+	feedHandler := feed.NewFeed(deps.Feeds, deps.BaseURL)
+	weatherHandler := handlers2.NewWeatherCurrent(deps.WeatherClient, deps.Locales)
+	runsHandler := admin.NewListRuns(deps.DispatchRuns)
+	subDeliveriesHandler := admin.NewSubscriptionDeliveries(deps.Deliveries)
+	runDeliveriesHandler := admin.NewRunDeliveries(deps.Deliveries)
+	adminSearchHandler := admin.NewSearchSubscriptions(deps.AdminService)
+	adminExportHandler := admin.NewExportSubscriptions(deps.AdminService)
+	adminStatsHandler := admin.NewSubscriptionStats(deps.AdminService)
+	adminGetHandler := admin.NewGetSubscription(deps.AdminService)
+	adminConfirmHandler := admin.NewForceConfirm(deps.AdminService)
+	adminResendHandler := admin.NewResendConfirmation(deps.AdminService)
+	adminDeactivateHandler := admin.NewDeactivate(deps.AdminService)
 
 	api := router.Group("/api")
 	{
 		api.POST("/subscribe", subscribeHandler.Handle)
 		api.GET("/confirm/:token", confirmHandler.Handle)
 		api.GET("/unsubscribe/:token", unsubscribeHandler.Handle)
+		api.POST("/unsubscribe/:token", unsubscribeHandler.HandleOneClick)
 		api.GET("/weather", weatherHandler.Handle)
+
+		api.POST("/subscriptions/manage-link", manageLinkHandler.Handle)
+		api.GET("/subscriptions", listHandler.Handle)
+		api.PATCH("/subscriptions/:id", updateHandler.Handle)
+		api.DELETE("/subscriptions/:id", deleteHandler.Handle)
+		api.POST("/subscriptions/:id/pause", pauseHandler.Pause)
+		api.POST("/subscriptions/:id/resume", pauseHandler.Resume)
+		api.POST("/subscriptions/:id/webhook/verify", reverifyWebhookHandler.Handle)
+		api.POST("/subscriptions/:id/feed", feedLinkHandler.Handle)
+		api.GET("/feed/:file", feedHandler.Handle)
+
+		api.GET("/privacy/data", exportDataHandler.Handle)
+		api.DELETE("/privacy/data", eraseDataHandler.Handle)
+	}
+
+	adminAPI := router.Group("/api/admin", middleware.AdminAuth(deps.AdminToken))
+	{
+		adminAPI.GET("/dispatch-runs", runsHandler.Handle)
+		adminAPI.GET("/dispatch-runs/:id/deliveries", runDeliveriesHandler.Handle)
+		adminAPI.GET("/subscriptions", adminSearchHandler.Handle)
+		adminAPI.GET("/subscriptions/export", adminExportHandler.Handle)
+		adminAPI.GET("/subscriptions/stats", adminStatsHandler.Handle)
+		adminAPI.GET("/subscriptions/:id", adminGetHandler.Handle)
+		adminAPI.POST("/subscriptions/:id/confirm", adminConfirmHandler.Handle)
+		adminAPI.POST("/subscriptions/:id/resend-confirmation", adminResendHandler.Handle)
+		adminAPI.POST("/subscriptions/:id/deactivate", adminDeactivateHandler.Handle)
+		adminAPI.GET("/subscriptions/:id/deliveries", subDeliveriesHandler.Handle)
 	}
 
 	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "scheduler_leader": deps.Leader.IsLeader()})
 	})
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	if gin.Mode() != gin.TestMode {
 		router.LoadHTMLGlob("templates/*.html")
@@ -52,6 +152,10 @@ func SetupRoutes(subService subscription.Service, weatherClient weatherService,
 	router.GET("/subscribe", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "subscribe.html", nil)
 	})
+	// The emailed manage link opens this page; it calls the manage API with the link's token.
+	router.GET("/manage", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "manage.html", nil)
+	})
 	router.GET("/", func(c *gin.Context) {
 		c.Redirect(http.StatusFound, "/subscribe")
 	})
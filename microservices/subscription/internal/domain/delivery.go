@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	DeliveryQueued  DeliveryStatus = "queued"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+	DeliveryBounced DeliveryStatus = "bounced"
+)
+
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryQueued, DeliverySent, DeliveryFailed, DeliveryBounced:
+		return true
+	}
+	return false
+}
+
+// Delivery is the audit record of one weather report sent to one subscriber.
+// IdempotencyKey matches the message id the email service reports status events for.
+type Delivery struct {
+	ID             int64
+	SubscriptionID string
+	Email          string
+	City           string
+	Locale         string // language of the weather snapshot
+	RunID          int64  // zero when the report was not dispatched by a recorded run
+	JobID          string
+	IdempotencyKey string
+	Weather        *Report
+	Status         DeliveryStatus
+	Error          string
+	QueuedAt       time.Time
+	SentAt         *time.Time
+	FailedAt       *time.Time
+	BouncedAt      *time.Time
+	UpdatedAt      time.Time
+}
+
+// ReportSnapshot is the weather report a dispatch run sent for a city, as the feeds show it.
+type ReportSnapshot struct {
+	RunID  int64 // zero for a report fetched on demand
+	City   string
+	Report Report
+	At     time.Time
+}
@@ -9,15 +9,119 @@ const (
 	FreqDaily  Frequency = "daily"
 )
 
+// DefaultDeliveryHour is the UTC hour at which daily reports are sent unless the subscriber picks another one.
+const DefaultDeliveryHour = 12
+
 func (f Frequency) Valid() bool { return f == FreqHourly || f == FreqDaily }
 
+// Channel is how a subscriber receives reports.
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelWebhook Channel = "webhook"
+)
+
+func (c Channel) Valid() bool { return c == ChannelEmail || c == ChannelWebhook }
+
 type Subscription struct {
-	ID             string
-	Email          string
-	City           string
-	Frequency      Frequency
+	ID           string
+	Email        string
+	City         string
+	Frequency    Frequency
+	DeliveryHour int
+	// Locale is the language of the subscriber's emails and weather descriptions, e.g. "en".
+	Locale string
+	// Channel carries the reports; the confirmation and manage links always go by email.
+	Channel        Channel
 	IsConfirmed    bool
 	IsUnsubscribed bool
 	Token          string
 	CreatedAt      time.Time
+	// ReminderSentAt is set once the confirmation reminder went out; a new subscribe clears it.
+	ReminderSentAt *time.Time
+	// PausedUntil stops all reports until the given time; nil means the subscription is not paused.
+	PausedUntil *time.Time
+	// QuietHours holds back hourly reports at night or whenever the subscriber asked; nil means none.
+	QuietHours *QuietHours
+	// FeedToken is the current token of the subscription's Atom and RSS feeds; empty until one
+	// was requested. Issuing a new one revokes it.
+	FeedToken string
+	// Digest combines the email reports of the address's digest subscriptions that are due in
+	// the same run into one email.
+	Digest bool
 }
+
+// IsDueAt reports whether the subscription should receive a report at the given time.
+// Paused subscriptions are never due. Hourly subscriptions are due outside their quiet hours,
+// daily ones only at their delivery hour.
+func (s Subscription) IsDueAt(t time.Time) bool {
+	if s.IsPausedAt(t) {
+		return false
+	}
+	if s.Frequency != FreqDaily {
+		return s.QuietHours == nil || !s.QuietHours.Contains(t)
+	}
+	return t.UTC().Hour() == s.DeliveryHour
+}
+
+// IsPausedAt reports whether reports are paused at the given time.
+func (s Subscription) IsPausedAt(t time.Time) bool {
+	return s.PausedUntil != nil && t.Before(*s.PausedUntil)
+}
+
+// QuietHours is a daily window, in the subscriber's timezone, in which hourly reports are not sent.
+// Start is inclusive and End exclusive; a window such as 22-7 wraps around midnight.
+type QuietHours struct {
+	Start    int
+	End      int
+	Timezone string
+}
+
+// Valid reports whether both hours are in range, the window is not empty and the timezone is known.
+func (q QuietHours) Valid() bool {
+	if !ValidDeliveryHour(q.Start) || !ValidDeliveryHour(q.End) || q.Start == q.End {
+		return false
+	}
+	_, err := time.LoadLocation(q.Timezone)
+	return err == nil
+}
+
+// Contains reports whether t falls into the window. An unknown timezone is treated as UTC.
+func (q QuietHours) Contains(t time.Time) bool {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	hour := t.In(loc).Hour()
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+type SubscriptionStatus string
+
+const (
+	StatusPending      SubscriptionStatus = "pending"
+	StatusActive       SubscriptionStatus = "active"
+	StatusUnsubscribed SubscriptionStatus = "unsubscribed"
+)
+
+func (s SubscriptionStatus) Valid() bool {
+	return s == StatusPending || s == StatusActive || s == StatusUnsubscribed
+}
+
+// Status derives the lifecycle state from the confirmation and unsubscribe flags.
+func (s Subscription) Status() SubscriptionStatus {
+	switch {
+	case s.IsUnsubscribed:
+		return StatusUnsubscribed
+	case s.IsConfirmed:
+		return StatusActive
+	default:
+		return StatusPending
+	}
+}
+
+func ValidDeliveryHour(hour int) bool { return hour >= 0 && hour <= 23 }
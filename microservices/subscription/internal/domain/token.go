@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type TokenPurpose string
+
+const (
+	TokenPurposeConfirm     TokenPurpose = "confirm"
+	TokenPurposeUnsubscribe TokenPurpose = "unsubscribe"
+	TokenPurposeManage      TokenPurpose = "manage"
+	TokenPurposeFeed        TokenPurpose = "feed"
+)
+
+func (p TokenPurpose) Valid() bool {
+	return p == TokenPurposeConfirm || p == TokenPurposeUnsubscribe || p == TokenPurposeManage || p == TokenPurposeFeed
+}
+
+// TokenClaims is what a signed link token tells us about its holder.
+// A zero ExpiresAt means the token never expires.
+type TokenClaims struct {
+	ID             string
+	SubscriptionID string
+	// Address names the subscriber in tokens bound to an address rather than a subscription,
+	// such as manage links, which stay valid while any subscription of the address is left.
+	Address   string
+	Purpose   TokenPurpose
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Email names the subscriber in legacy tokens, issued before purposes and key ids existed;
+	// those carry no SubscriptionID.
+	Email string
+}
+
+// Legacy reports whether the claims come from a token issued before purpose scoping.
+func (c TokenClaims) Legacy() bool {
+	return c.Email != ""
+}
@@ -5,3 +5,11 @@ type Report struct {
 	Humidity    int
 	Description string
 }
+
+// DigestEntry is one city of a digest email. Token is the unsubscribe token of the city's
+// subscription, so each city can be cancelled on its own.
+type DigestEntry struct {
+	City   string
+	Report Report
+	Token  string
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// WebhookEndpoint is the URL the reports of a webhook subscription are posted to.
+// Reports are only posted once the endpoint passed the verification handshake.
+type WebhookEndpoint struct {
+	SubscriptionID string
+	URL            string
+	// Secret signs every request to the endpoint; the subscriber gets it once, at subscribe time.
+	Secret     string
+	VerifiedAt *time.Time
+	// VerifyAttempts counts failed handshakes; NextVerifyAt is nil when no handshake is pending.
+	VerifyAttempts int
+	NextVerifyAt   *time.Time
+	// Failures counts deliveries in a row that exhausted their retries; a delivered report resets it.
+	Failures   int
+	DisabledAt *time.Time
+	LastError  string
+	CreatedAt  time.Time
+}
+
+// Active reports whether reports may be posted to the endpoint.
+func (e WebhookEndpoint) Active() bool {
+	return e.VerifiedAt != nil && e.DisabledAt == nil
+}
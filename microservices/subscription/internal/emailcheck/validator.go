@@ -0,0 +1,199 @@
+package emailcheck
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+var (
+	ErrInvalidAddress      = errors.New("invalid email address")
+	ErrDisposableDomain    = errors.New("disposable email domain")
+	ErrUndeliverableDomain = errors.New("email domain does not accept mail")
+)
+
+//go:embed disposable_domains.txt
+var defaultBlocklist string
+
+// Resolver is the part of net.Resolver used for the MX check.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+type Config struct {
+	// StripPlusTags drops the "+tag" part of the local part, so user+news@ and user@ are one subscriber.
+	StripPlusTags bool
+	// BlockedDomains are rejected together with their subdomains.
+	BlockedDomains []string
+	// CheckMX rejects domains that have neither MX nor address records.
+	CheckMX       bool
+	LookupTimeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BlockedDomains: DefaultBlocklist(),
+		LookupTimeout:  2 * time.Second,
+	}
+}
+
+// Validator normalises addresses and rejects the ones that would bounce or are throwaway.
+type Validator struct {
+	resolver Resolver
+	blocked  map[string]struct{}
+	cfg      Config
+}
+
+func NewValidator(resolver Resolver, cfg Config) *Validator {
+	blocked := make(map[string]struct{}, len(cfg.BlockedDomains))
+	for _, d := range cfg.BlockedDomains {
+		if d = normalizeDomain(d); d != "" {
+			blocked[d] = struct{}{}
+		}
+	}
+	return &Validator{resolver: resolver, blocked: blocked, cfg: cfg}
+}
+
+// Normalize returns the canonical form of the address without any network checks.
+func (v *Validator) Normalize(email string) (string, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || addr.Name != "" {
+		return "", ErrInvalidAddress
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	if at <= 0 || at == len(addr.Address)-1 {
+		return "", ErrInvalidAddress
+	}
+	local, domain := addr.Address[:at], normalizeDomain(addr.Address[at+1:])
+	if !strings.Contains(domain, ".") {
+		return "", ErrInvalidAddress
+	}
+
+	local = strings.ToLower(local)
+	if v.cfg.StripPlusTags {
+		if plus := strings.Index(local, "+"); plus > 0 {
+			local = local[:plus]
+		}
+	}
+	return local + "@" + domain, nil
+}
+
+// Validate normalises the address and checks that its domain is allowed and can receive mail.
+func (v *Validator) Validate(ctx context.Context, email string) (string, error) {
+	normalized, err := v.Normalize(email)
+	if err != nil {
+		return "", err
+	}
+	domain := normalized[strings.LastIndex(normalized, "@")+1:]
+
+	if v.isBlocked(domain) {
+		return "", ErrDisposableDomain
+	}
+	if v.cfg.CheckMX && v.resolver != nil {
+		if err := v.checkMX(ctx, domain); err != nil {
+			return "", err
+		}
+	}
+	return normalized, nil
+}
+
+func (v *Validator) isBlocked(domain string) bool {
+	for {
+		if _, ok := v.blocked[domain]; ok {
+			return true
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+func (v *Validator) checkMX(ctx context.Context, domain string) error {
+	if v.cfg.LookupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.cfg.LookupTimeout)
+		defer cancel()
+	}
+
+	records, err := v.resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		// RFC 7505 null MX: the domain explicitly accepts no mail.
+		if len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "" {
+			return ErrUndeliverableDomain
+		}
+		return nil
+	}
+	if err != nil && !isNotFound(err) {
+		// A flaky resolver must not block sign-ups; the address gets the benefit of the doubt.
+		loggerPkg.From(ctx).Warn("MX lookup failed, skipping check", "domain", domain, "err", err)
+		return nil
+	}
+
+	// Without MX records mail goes to the domain's address records (RFC 5321 implicit MX).
+	hosts, err := v.resolver.LookupHost(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		loggerPkg.From(ctx).Warn("Host lookup failed, skipping check", "domain", domain, "err", err)
+		return nil
+	}
+	if len(hosts) == 0 {
+		return ErrUndeliverableDomain
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// DefaultBlocklist returns the bundled list of disposable email domains.
+func DefaultBlocklist() []string {
+	domains, _ := parseBlocklist(strings.NewReader(defaultBlocklist))
+	return domains
+}
+
+// LoadBlocklist reads a domain list from a file, one domain per line; '#' starts a comment.
+func LoadBlocklist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open blocklist: %w", err)
+	}
+	defer f.Close()
+	return parseBlocklist(f)
+}
+
+func parseBlocklist(r io.Reader) ([]string, error) {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if hash := strings.Index(line, "#"); hash >= 0 {
+			line = line[:hash]
+		}
+		if line = normalizeDomain(line); line != "" {
+			domains = append(domains, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read blocklist: %w", err)
+	}
+	return domains, nil
+}
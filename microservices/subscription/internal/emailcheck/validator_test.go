@@ -0,0 +1,124 @@
+package emailcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	mx     map[string][]*net.MX
+	hosts  map[string][]string
+	err    error
+	lookup int
+}
+
+func (r *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.lookup++
+	if r.err != nil {
+		return nil, r.err
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name      string
+		stripPlus bool
+		in, want  string
+		wantErr   error
+	}{
+		{name: "case and spaces", in: "  John.Doe@Example.COM ", want: "john.doe@example.com"},
+		{name: "plus tag kept", in: "a+news@example.com", want: "a+news@example.com"},
+		{name: "plus tag stripped", stripPlus: true, in: "a+news@example.com", want: "a@example.com"},
+		{name: "leading plus kept", stripPlus: true, in: "+a@example.com", want: "+a@example.com"},
+		{name: "missing at", in: "example.com", wantErr: ErrInvalidAddress},
+		{name: "display name", in: "A <a@example.com>", wantErr: ErrInvalidAddress},
+		{name: "dotless domain", in: "a@localhost", wantErr: ErrInvalidAddress},
+		{name: "empty", in: " ", wantErr: ErrInvalidAddress},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(nil, Config{StripPlusTags: tc.stripPlus})
+			got, err := v.Normalize(tc.in)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidate_Blocklist(t *testing.T) {
+	v := NewValidator(nil, Config{BlockedDomains: []string{"Throwaway.io", " mailinator.com "}})
+
+	for _, email := range []string{"a@throwaway.io", "a@MAILINATOR.com", "a@eu.mailinator.com"} {
+		_, err := v.Validate(context.Background(), email)
+		assert.ErrorIs(t, err, ErrDisposableDomain, email)
+	}
+
+	got, err := v.Validate(context.Background(), "a@notmailinator.com")
+	require.NoError(t, err)
+	assert.Equal(t, "a@notmailinator.com", got)
+}
+
+func TestValidate_MX(t *testing.T) {
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.com":  {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"implicit.com": {"192.0.2.1"}},
+	}
+	v := NewValidator(resolver, Config{CheckMX: true})
+	ctx := context.Background()
+
+	_, err := v.Validate(ctx, "a@example.com")
+	require.NoError(t, err)
+
+	_, err = v.Validate(ctx, "a@implicit.com")
+	require.NoError(t, err, "address records act as an implicit MX")
+
+	_, err = v.Validate(ctx, "a@nomail.com")
+	assert.ErrorIs(t, err, ErrUndeliverableDomain, "null MX")
+
+	_, err = v.Validate(ctx, "a@missing.com")
+	assert.ErrorIs(t, err, ErrUndeliverableDomain)
+}
+
+func TestValidate_MXSkippedWhenDisabledOrResolverFails(t *testing.T) {
+	resolver := &fakeResolver{}
+	_, err := NewValidator(resolver, Config{}).Validate(context.Background(), "a@missing.com")
+	require.NoError(t, err)
+	assert.Zero(t, resolver.lookup)
+
+	resolver.err = errors.New("i/o timeout")
+	_, err = NewValidator(resolver, Config{CheckMX: true}).Validate(context.Background(), "a@missing.com")
+	assert.NoError(t, err, "resolver outages must not block sign-ups")
+}
+
+func TestBlocklistParsing(t *testing.T) {
+	domains, err := parseBlocklist(strings.NewReader("# comment\nMailinator.com  # inline\n\n yopmail.com\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mailinator.com", "yopmail.com"}, domains)
+
+	assert.Contains(t, DefaultBlocklist(), "mailinator.com")
+}
@@ -0,0 +1,176 @@
+// Package events publishes subscription lifecycle events for other services, such as
+// analytics and CRM sync, to a topic exchange.
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"subscription/internal/adapter/email/async"
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is bumped on breaking changes to Event; additive fields keep the version.
+const SchemaVersion = 1
+
+// Event types. The routing key is the type followed by the schema version, for example
+// "subscription.created.v1", so consumers can bind to "subscription.#" or to one version.
+const (
+	TypeCreated      = "subscription.created"
+	TypeConfirmed    = "subscription.confirmed"
+	TypeUnsubscribed = "subscription.unsubscribed"
+	TypeUpdated      = "subscription.updated"
+	TypeRemoved      = "subscription.removed"
+)
+
+// AMQP headers set on every event.
+const (
+	HeaderCorrelationID = "x-correlation-id"
+	HeaderEventType     = "x-event-type"
+	HeaderSchemaVersion = "x-schema-version"
+)
+
+// Event is the JSON schema shared by all lifecycle events.
+type Event struct {
+	ID         string           `json:"event_id"`   //nolint:tagliatelle
+	Type       string           `json:"event_type"` //nolint:tagliatelle
+	Version    int              `json:"version"`
+	OccurredAt time.Time        `json:"occurred_at"` //nolint:tagliatelle
+	Data       SubscriptionData `json:"data"`
+
+	exchange      string
+	correlationID string
+}
+
+// SubscriptionData is the subscription state after the change. The address is sent as an
+// HMAC-SHA256 of its lowercase form under a service key, so consumers can join on it without
+// holding it and cannot recover it by hashing candidate addresses.
+type SubscriptionData struct {
+	SubscriptionID string     `json:"subscription_id"` //nolint:tagliatelle
+	EmailHash      string     `json:"email_hash"`      //nolint:tagliatelle
+	City           string     `json:"city"`
+	Frequency      string     `json:"frequency"`
+	DeliveryHour   int        `json:"delivery_hour"` //nolint:tagliatelle
+	Locale         string     `json:"locale"`
+	Status         string     `json:"status"`
+	PausedUntil    *time.Time `json:"paused_until,omitempty"` //nolint:tagliatelle
+	Reason         string     `json:"reason,omitempty"`
+	Changes        []string   `json:"changes,omitempty"`
+}
+
+func (e Event) GetIdKey() string { return e.ID }
+
+func (e Event) GetExchange() string { return e.exchange }
+
+func (e Event) GetHeaders() map[string]string {
+	headers := map[string]string{
+		HeaderEventType:     e.Type,
+		HeaderSchemaVersion: fmt.Sprint(e.Version),
+	}
+	if e.correlationID != "" {
+		headers[HeaderCorrelationID] = e.correlationID
+	}
+	return headers
+}
+
+// RoutingKey returns the key the event is published with.
+func (e Event) RoutingKey() string {
+	return fmt.Sprintf("%s.v%d", e.Type, e.Version)
+}
+
+type writer interface {
+	Publish(ctx context.Context, routingKey string, msg async.IdKeyGetter) error
+}
+
+// Publisher turns subscription changes into events. With the outbox writer, events written
+// inside a transaction are committed together with the change.
+type Publisher struct {
+	writer   writer
+	exchange string
+	hashKey  []byte
+	now      func() time.Time
+	newID    func() string
+}
+
+func NewPublisher(writer writer, exchange, hashKey string) *Publisher {
+	return &Publisher{
+		writer:   writer,
+		exchange: exchange,
+		hashKey:  []byte(hashKey),
+		now:      time.Now,
+		newID:    uuid.NewString,
+	}
+}
+
+func (p *Publisher) Created(ctx context.Context, sub domain.Subscription) error {
+	return p.publish(ctx, TypeCreated, p.newData(sub))
+}
+
+func (p *Publisher) Confirmed(ctx context.Context, sub domain.Subscription) error {
+	return p.publish(ctx, TypeConfirmed, p.newData(sub))
+}
+
+func (p *Publisher) Unsubscribed(ctx context.Context, sub domain.Subscription, reason string) error {
+	data := p.newData(sub)
+	data.Reason = reason
+	return p.publish(ctx, TypeUnsubscribed, data)
+}
+
+// Updated announces changed settings; changes lists the changed fields by their JSON names.
+func (p *Publisher) Updated(ctx context.Context, sub domain.Subscription, changes []string) error {
+	data := p.newData(sub)
+	data.Changes = slices.Clone(changes)
+	return p.publish(ctx, TypeUpdated, data)
+}
+
+// Removed announces a deleted subscription; the data is its last stored state.
+func (p *Publisher) Removed(ctx context.Context, sub domain.Subscription, reason string) error {
+	data := p.newData(sub)
+	data.Reason = reason
+	return p.publish(ctx, TypeRemoved, data)
+}
+
+func (p *Publisher) publish(ctx context.Context, eventType string, data SubscriptionData) error {
+	event := Event{
+		ID:            p.newID(),
+		Type:          eventType,
+		Version:       SchemaVersion,
+		OccurredAt:    p.now().UTC(),
+		Data:          data,
+		exchange:      p.exchange,
+		correlationID: loggerPkg.GetCorrelationID(ctx),
+	}
+	if err := p.writer.Publish(ctx, event.RoutingKey(), event); err != nil {
+		return fmt.Errorf("publish %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+func (p *Publisher) newData(sub domain.Subscription) SubscriptionData {
+	return SubscriptionData{
+		SubscriptionID: sub.ID,
+		EmailHash:      p.hashEmail(sub.Email),
+		City:           sub.City,
+		Frequency:      string(sub.Frequency),
+		DeliveryHour:   sub.DeliveryHour,
+		Locale:         sub.Locale,
+		Status:         string(sub.Status()),
+		PausedUntil:    sub.PausedUntil,
+	}
+}
+
+func (p *Publisher) hashEmail(email string) string {
+	mac := hmac.New(sha256.New, p.hashKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/adapter/email/async"
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type publishedMessage struct {
+	routingKey string
+	msg        async.IdKeyGetter
+}
+
+type fakeWriter struct {
+	err  error
+	sent []publishedMessage
+}
+
+func (w *fakeWriter) Publish(_ context.Context, routingKey string, msg async.IdKeyGetter) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.sent = append(w.sent, publishedMessage{routingKey: routingKey, msg: msg})
+	return nil
+}
+
+func newTestPublisher(w *fakeWriter) *Publisher {
+	p := NewPublisher(w, "subscription.events", "hash-key")
+	p.now = func() time.Time { return time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("EET", 2*3600)) }
+	p.newID = func() string { return "evt-1" }
+	return p
+}
+
+var testSub = domain.Subscription{
+	ID:           "sub-1",
+	Email:        " User@Example.com",
+	City:         "Kyiv",
+	Frequency:    domain.FreqDaily,
+	DeliveryHour: 8,
+	Locale:       "en",
+	IsConfirmed:  true,
+}
+
+func TestPublisher_ConfirmedSchema(t *testing.T) {
+	w := &fakeWriter{}
+	ctx := loggerPkg.WithCorrelationID(context.Background(), "corr-1")
+
+	require.NoError(t, newTestPublisher(w).Confirmed(ctx, testSub))
+
+	require.Len(t, w.sent, 1)
+	sent := w.sent[0]
+	assert.Equal(t, "subscription.confirmed.v1", sent.routingKey)
+	assert.Equal(t, "evt-1", sent.msg.GetIdKey())
+	assert.Equal(t, "subscription.events", sent.msg.(async.ExchangeGetter).GetExchange())
+	assert.Equal(t, map[string]string{
+		HeaderCorrelationID: "corr-1",
+		HeaderEventType:     TypeConfirmed,
+		HeaderSchemaVersion: "1",
+	}, sent.msg.(async.HeadersGetter).GetHeaders())
+
+	body, err := json.Marshal(sent.msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"event_id": "evt-1",
+		"event_type": "subscription.confirmed",
+		"version": 1,
+		"occurred_at": "2026-03-01T07:30:00Z",
+		"data": {
+			"subscription_id": "sub-1",
+			"email_hash": "d179246c0acdd1f4e9ec0fabcaee44e15841a451fc3e5cff6a28453d614dfa0d",
+			"city": "Kyiv",
+			"frequency": "daily",
+			"delivery_hour": 8,
+			"locale": "en",
+			"status": "active"
+		}
+	}`, string(body))
+}
+
+func TestPublisher_EmailHashIsKeyed(t *testing.T) {
+	w := &fakeWriter{}
+	other := NewPublisher(w, "subscription.events", "other-key")
+
+	require.NoError(t, newTestPublisher(w).Created(context.Background(), testSub))
+	require.NoError(t, other.Created(context.Background(), testSub))
+
+	first := w.sent[0].msg.(Event).Data.EmailHash
+	second := w.sent[1].msg.(Event).Data.EmailHash
+	assert.NotEqual(t, first, second, "without the key the hash cannot be recomputed from an address")
+}
+
+func TestPublisher_UnsubscribedCarriesReason(t *testing.T) {
+	w := &fakeWriter{}
+	sub := testSub
+	sub.IsUnsubscribed = true
+
+	require.NoError(t, newTestPublisher(w).Unsubscribed(context.Background(), sub, "bounce"))
+
+	event := w.sent[0].msg.(Event)
+	assert.Equal(t, "subscription.unsubscribed.v1", w.sent[0].routingKey)
+	assert.Equal(t, "unsubscribed", event.Data.Status)
+	assert.Equal(t, "bounce", event.Data.Reason)
+	assert.NotContains(t, event.GetHeaders(), HeaderCorrelationID)
+}
+
+func TestPublisher_RemovedCarriesReason(t *testing.T) {
+	w := &fakeWriter{}
+	sub := testSub
+	sub.IsConfirmed = false
+
+	require.NoError(t, newTestPublisher(w).Removed(context.Background(), sub, "expired"))
+
+	event := w.sent[0].msg.(Event)
+	assert.Equal(t, "subscription.removed.v1", w.sent[0].routingKey)
+	assert.Equal(t, "pending", event.Data.Status)
+	assert.Equal(t, "expired", event.Data.Reason)
+}
+
+func TestPublisher_UpdatedListsChanges(t *testing.T) {
+	w := &fakeWriter{}
+
+	require.NoError(t, newTestPublisher(w).Updated(context.Background(), testSub, []string{"city", "frequency"}))
+
+	event := w.sent[0].msg.(Event)
+	assert.Equal(t, TypeUpdated, event.Type)
+	assert.Equal(t, []string{"city", "frequency"}, event.Data.Changes)
+}
+
+func TestPublisher_WrapsWriterError(t *testing.T) {
+	w := &fakeWriter{err: errors.New("db down")}
+
+	err := newTestPublisher(w).Created(context.Background(), testSub)
+
+	assert.ErrorContains(t, err, "publish subscription.created event")
+	assert.ErrorIs(t, err, w.err)
+}
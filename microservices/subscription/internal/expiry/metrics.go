@@ -0,0 +1,40 @@
+package expiry
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	reminders prometheus.Counter
+	expired   prometheus.Counter
+	once      sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		reminders: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "subscription_confirmation_reminders_total",
+			Help: "Confirmation reminders enqueued for pending subscriptions",
+		}),
+		expired: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "subscription_expired_total",
+			Help: "Unconfirmed subscriptions deleted after the expiry window",
+		}),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.reminders, m.expired)
+	})
+}
+
+func (m *Metrics) RecordReminder() {
+	m.reminders.Inc()
+}
+
+func (m *Metrics) RecordExpired(n int64) {
+	m.expired.Add(float64(n))
+}
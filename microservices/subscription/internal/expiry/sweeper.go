@@ -0,0 +1,221 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+type store interface {
+	DueForReminder(ctx context.Context, createdBefore time.Time, limit int) ([]domain.Subscription, error)
+	// MarkReminded reports false when the subscription no longer needs a reminder,
+	// e.g. because another replica already sent it or the owner confirmed meanwhile.
+	MarkReminded(ctx context.Context, id string, at time.Time) (bool, error)
+	// DeleteUnconfirmedBefore returns the deleted subscriptions.
+	DeleteUnconfirmedBefore(ctx context.Context, createdBefore time.Time) ([]domain.Subscription, error)
+}
+
+// ReasonExpired is reported with the removed events of expired subscriptions.
+const ReasonExpired = "expired"
+
+// removalEvents announces deleted subscriptions through the outbox, like the subscription flows.
+type removalEvents interface {
+	Removed(ctx context.Context, sub domain.Subscription, reason string) error
+}
+
+type reminderSender interface {
+	SendConfirmationReminder(ctx context.Context, email, locale, token string, expiresAt time.Time, idKey string) error
+}
+
+type tokenGenerator interface {
+	Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error)
+}
+
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type leader interface {
+	IsLeader() bool
+}
+
+type sweeperMetrics interface {
+	RecordReminder()
+	RecordExpired(n int64)
+}
+
+type Config struct {
+	Interval time.Duration
+	// RemindAfter is the age at which a pending subscription gets its reminder; zero disables reminders.
+	RemindAfter time.Duration
+	// ExpireAfter is the age at which a pending subscription is deleted; zero keeps them forever.
+	ExpireAfter time.Duration
+	BatchSize   int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval:    10 * time.Minute,
+		RemindAfter: 24 * time.Hour,
+		ExpireAfter: 72 * time.Hour,
+		BatchSize:   100,
+	}
+}
+
+// Sweeper reminds owners of unconfirmed subscriptions once and later deletes the ones
+// still unconfirmed, so the address can subscribe again from scratch.
+type Sweeper struct {
+	store   store
+	emails  reminderSender
+	tokens  tokenGenerator
+	tx      transactor
+	events  removalEvents
+	leader  leader
+	metrics sweeperMetrics
+	cfg     Config
+	now     func() time.Time
+}
+
+func NewSweeper(
+	store store,
+	emails reminderSender,
+	tokens tokenGenerator,
+	tx transactor,
+	events removalEvents,
+	leader leader,
+	metrics sweeperMetrics,
+	cfg Config,
+) *Sweeper {
+	return &Sweeper{
+		store:   store,
+		emails:  emails,
+		tokens:  tokens,
+		tx:      tx,
+		events:  events,
+		leader:  leader,
+		metrics: metrics,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// Start sweeps every interval while this replica is the scheduler leader.
+func (s *Sweeper) Start(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Expiry sweeper stopped")
+			return
+		case <-ticker.C:
+			if !s.leader.IsLeader() {
+				continue
+			}
+			if err := s.Sweep(ctx); err != nil {
+				logger.Error("Expiry sweep failed", "err", err)
+			}
+		}
+	}
+}
+
+// Sweep sends due reminders, one batch per call, then deletes expired subscriptions.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	now := s.now()
+
+	if s.cfg.RemindAfter > 0 && (s.cfg.ExpireAfter <= 0 || s.cfg.RemindAfter < s.cfg.ExpireAfter) {
+		if err := s.remind(ctx, now); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.ExpireAfter > 0 {
+		if err := s.expire(ctx, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expire deletes the expired subscriptions and announces them in the same transaction.
+func (s *Sweeper) expire(ctx context.Context, now time.Time) error {
+	var expired []domain.Subscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		expired, err = s.store.DeleteUnconfirmedBefore(ctx, now.Add(-s.cfg.ExpireAfter))
+		if err != nil {
+			return fmt.Errorf("delete expired subscriptions: %w", err)
+		}
+		for _, sub := range expired {
+			if err := s.events.Removed(ctx, sub, ReasonExpired); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if n := int64(len(expired)); n > 0 {
+		s.metrics.RecordExpired(n)
+		loggerPkg.From(ctx).Info("Expired unconfirmed subscriptions", "count", n)
+	}
+	return nil
+}
+
+func (s *Sweeper) remind(ctx context.Context, now time.Time) error {
+	due, err := s.store.DueForReminder(ctx, now.Add(-s.cfg.RemindAfter), s.cfg.BatchSize)
+	if err != nil {
+		return fmt.Errorf("load subscriptions due for reminder: %w", err)
+	}
+
+	for _, sub := range due {
+		if err := s.remindOne(ctx, sub, now); err != nil {
+			// One broken row must not block the rest of the batch.
+			loggerPkg.From(ctx).Error("Failed to send confirmation reminder", "subscription_id", sub.ID, "err", err)
+		}
+	}
+	return nil
+}
+
+func (s *Sweeper) remindOne(ctx context.Context, sub domain.Subscription, now time.Time) error {
+	token, err := s.tokens.Generate(sub.ID, domain.TokenPurposeConfirm)
+	if err != nil {
+		return fmt.Errorf("generate token: %w", err)
+	}
+
+	var expiresAt time.Time
+	if s.cfg.ExpireAfter > 0 {
+		expiresAt = sub.CreatedAt.Add(s.cfg.ExpireAfter)
+	}
+
+	sent := false
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := s.store.MarkReminded(ctx, sub.ID, now)
+		if err != nil || !ok {
+			return err
+		}
+		sent = true
+		return s.emails.SendConfirmationReminder(ctx, sub.Email, sub.Locale, token, expiresAt, reminderIdempotencyKey(sub.Email, token))
+	})
+	if err != nil {
+		return err
+	}
+
+	if sent {
+		s.metrics.RecordReminder()
+		loggerPkg.From(ctx).Info("Confirmation reminder sent", "subscription_id", sub.ID)
+	}
+	return nil
+}
+
+func reminderIdempotencyKey(email, token string) string {
+	return fmt.Sprintf("reminder:%s:%s", email, token)
+}
@@ -0,0 +1,207 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	pending   []domain.Subscription
+	reminded  map[string]time.Time
+	dueCutoff time.Time
+	expiredAt time.Time
+	expired   []domain.Subscription
+}
+
+func newFakeStore(subs ...domain.Subscription) *fakeStore {
+	return &fakeStore{pending: subs, reminded: map[string]time.Time{}}
+}
+
+func (s *fakeStore) DueForReminder(_ context.Context, createdBefore time.Time, limit int) ([]domain.Subscription, error) {
+	s.dueCutoff = createdBefore
+	var due []domain.Subscription
+	for _, sub := range s.pending {
+		if _, done := s.reminded[sub.ID]; !done && sub.CreatedAt.Before(createdBefore) && len(due) < limit {
+			due = append(due, sub)
+		}
+	}
+	return due, nil
+}
+
+func (s *fakeStore) MarkReminded(_ context.Context, id string, at time.Time) (bool, error) {
+	if _, done := s.reminded[id]; done {
+		return false, nil
+	}
+	s.reminded[id] = at
+	return true, nil
+}
+
+func (s *fakeStore) DeleteUnconfirmedBefore(_ context.Context, createdBefore time.Time) ([]domain.Subscription, error) {
+	s.expiredAt = createdBefore
+	return s.expired, nil
+}
+
+type sentReminder struct {
+	email, locale, token, idKey string
+	expiresAt                   time.Time
+}
+
+type fakeEmails struct {
+	err  error
+	sent []sentReminder
+}
+
+func (e *fakeEmails) SendConfirmationReminder(_ context.Context, email, locale, token string, expiresAt time.Time, idKey string) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.sent = append(e.sent, sentReminder{email: email, locale: locale, token: token, idKey: idKey, expiresAt: expiresAt})
+	return nil
+}
+
+type fakeTokens struct{}
+
+func (fakeTokens) Generate(subscriptionID string, _ domain.TokenPurpose) (string, error) {
+	return "tok-" + subscriptionID, nil
+}
+
+// fakeTx drops the writes made inside fn when it fails, like a rolled back transaction.
+type fakeTx struct{ store *fakeStore }
+
+func (t fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make(map[string]time.Time, len(t.store.reminded))
+	for k, v := range t.store.reminded {
+		snapshot[k] = v
+	}
+	if err := fn(ctx); err != nil {
+		t.store.reminded = snapshot
+		return err
+	}
+	return nil
+}
+
+type removal struct {
+	id, reason string
+}
+
+type fakeEvents struct {
+	err     error
+	removed []removal
+}
+
+func (e *fakeEvents) Removed(_ context.Context, sub domain.Subscription, reason string) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.removed = append(e.removed, removal{id: sub.ID, reason: reason})
+	return nil
+}
+
+type alwaysLeader struct{}
+
+func (alwaysLeader) IsLeader() bool { return true }
+
+type countingMetrics struct {
+	reminders int
+	expired   int64
+}
+
+func (m *countingMetrics) RecordReminder()       { m.reminders++ }
+func (m *countingMetrics) RecordExpired(n int64) { m.expired += n }
+
+var testNow = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestSweeper(store *fakeStore, emails *fakeEmails, cfg Config) (*Sweeper, *countingMetrics, *fakeEvents) {
+	metrics := &countingMetrics{}
+	events := &fakeEvents{}
+	s := NewSweeper(store, emails, fakeTokens{}, fakeTx{store: store}, events, alwaysLeader{}, metrics, cfg)
+	s.now = func() time.Time { return testNow }
+	return s, metrics, events
+}
+
+func TestSweep_RemindsOnceAndExpires(t *testing.T) {
+	old := domain.Subscription{ID: "sub-1", Email: "old@example.com", Locale: "en", CreatedAt: testNow.Add(-30 * time.Hour)}
+	fresh := domain.Subscription{ID: "sub-2", Email: "fresh@example.com", CreatedAt: testNow.Add(-time.Hour)}
+	store := newFakeStore(old, fresh)
+	store.expired = []domain.Subscription{{ID: "sub-3"}, {ID: "sub-4"}, {ID: "sub-5"}}
+	emails := &fakeEmails{}
+	sweeper, metrics, events := newTestSweeper(store, emails, DefaultConfig())
+
+	require.NoError(t, sweeper.Sweep(context.Background()))
+	require.NoError(t, sweeper.Sweep(context.Background()))
+
+	require.Len(t, emails.sent, 1, "a subscription is reminded only once")
+	assert.Equal(t, "old@example.com", emails.sent[0].email)
+	assert.Equal(t, "en", emails.sent[0].locale)
+	assert.Equal(t, "tok-sub-1", emails.sent[0].token)
+	assert.Equal(t, "reminder:old@example.com:tok-sub-1", emails.sent[0].idKey)
+	assert.Equal(t, old.CreatedAt.Add(72*time.Hour), emails.sent[0].expiresAt)
+	assert.Equal(t, testNow.Add(-24*time.Hour), store.dueCutoff)
+
+	assert.Equal(t, testNow.Add(-72*time.Hour), store.expiredAt)
+	assert.Equal(t, 1, metrics.reminders)
+	assert.Equal(t, int64(6), metrics.expired)
+	require.Len(t, events.removed, 6)
+	assert.Equal(t, removal{id: "sub-3", reason: ReasonExpired}, events.removed[0])
+}
+
+func TestSweep_ExpiryFailsWithoutEvent(t *testing.T) {
+	store := newFakeStore()
+	store.expired = []domain.Subscription{{ID: "sub-1"}}
+	sweeper, metrics, events := newTestSweeper(store, &fakeEmails{}, DefaultConfig())
+	events.err = errors.New("outbox down")
+
+	err := sweeper.Sweep(context.Background())
+
+	assert.Error(t, err)
+	assert.Zero(t, metrics.expired)
+}
+
+func TestSweep_FailedReminderIsRetried(t *testing.T) {
+	store := newFakeStore(domain.Subscription{ID: "sub-1", Email: "a@example.com", CreatedAt: testNow.Add(-48 * time.Hour)})
+	emails := &fakeEmails{err: errors.New("outbox down")}
+	sweeper, metrics, _ := newTestSweeper(store, emails, DefaultConfig())
+
+	require.NoError(t, sweeper.Sweep(context.Background()))
+	assert.Empty(t, store.reminded, "mark is rolled back with the failed send")
+	assert.Zero(t, metrics.reminders)
+
+	emails.err = nil
+	require.NoError(t, sweeper.Sweep(context.Background()))
+	assert.Len(t, emails.sent, 1)
+	assert.Equal(t, 1, metrics.reminders)
+}
+
+func TestSweep_DisabledSteps(t *testing.T) {
+	store := newFakeStore(domain.Subscription{ID: "sub-1", Email: "a@example.com", CreatedAt: testNow.Add(-48 * time.Hour)})
+	emails := &fakeEmails{}
+	cfg := DefaultConfig()
+	cfg.RemindAfter = 0
+	cfg.ExpireAfter = 0
+	sweeper, _, _ := newTestSweeper(store, emails, cfg)
+
+	require.NoError(t, sweeper.Sweep(context.Background()))
+
+	assert.Empty(t, emails.sent)
+	assert.True(t, store.expiredAt.IsZero())
+}
+
+func TestSweep_ReminderWithoutExpiry(t *testing.T) {
+	store := newFakeStore(domain.Subscription{ID: "sub-1", Email: "a@example.com", CreatedAt: testNow.Add(-48 * time.Hour)})
+	emails := &fakeEmails{}
+	cfg := DefaultConfig()
+	cfg.ExpireAfter = 0
+	sweeper, _, _ := newTestSweeper(store, emails, cfg)
+
+	require.NoError(t, sweeper.Sweep(context.Background()))
+
+	require.Len(t, emails.sent, 1)
+	assert.True(t, emails.sent[0].expiresAt.IsZero())
+}
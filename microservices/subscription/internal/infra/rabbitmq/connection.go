@@ -1,17 +1,42 @@
 package rabbitmq
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+var ErrConnectionClosed = errors.New("rabbitmq connection closed")
+
+// Backoff bounds the delay between reconnect attempts; it doubles from Base up to Max.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func DefaultBackoff() Backoff {
+	return Backoff{Base: 500 * time.Millisecond, Max: 30 * time.Second}
+}
+
+// Connection owns the AMQP connection and redials it with backoff when a channel is requested
+// after the broker dropped it.
 type Connection struct {
+	url     string
+	backoff Backoff
+
+	mu      sync.Mutex
 	conn    *amqp.Connection
 	channel *amqp.Channel
+	closed  bool
 }
 
-func NewConnection(url string) (*Connection, error) {
+func NewConnection(url string, backoff Backoff) (*Connection, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
@@ -24,17 +49,75 @@ func NewConnection(url string) (*Connection, error) {
 	}
 
 	return &Connection{
+		url:     url,
+		backoff: backoff,
 		conn:    conn,
 		channel: ch,
 	}, nil
 }
 
+// Channel is the channel opened with the first connection, meant for declaring topology at
+// startup. It is not replaced after a reconnect.
 func (c *Connection) Channel() *amqp.Channel {
 	return c.channel
 }
 
+// OpenChannel opens an additional channel, so consumers and the publisher do not share one.
+// A lost connection is redialed first, waiting at most until ctx is done.
+// Channels are closed together with the connection.
+func (c *Connection) OpenChannel(ctx context.Context) (*amqp.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrConnectionClosed
+	}
+	if err := c.reconnect(ctx); err != nil {
+		return nil, err
+	}
+
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch, nil
+}
+
+// reconnect redials until the connection is up; callers hold c.mu.
+func (c *Connection) reconnect(ctx context.Context) error {
+	if !c.conn.IsClosed() {
+		return nil
+	}
+
+	logger := loggerPkg.From(ctx)
+	delay := c.backoff.Base
+	for attempt := 1; ; attempt++ {
+		conn, err := amqp.Dial(c.url)
+		if err == nil {
+			c.conn = conn
+			logger.Info("Reconnected to RabbitMQ", "attempt", attempt)
+			return nil
+		}
+		logger.Warn("RabbitMQ reconnect failed", "attempt", attempt, "retry_in", delay, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("reconnect to RabbitMQ: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.backoff.Max)
+	}
+}
+
 func (c *Connection) Close() error {
-	if err := c.channel.Close(); err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn.IsClosed() {
+		return nil
+	}
+	if err := c.channel.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
 		_ = c.conn.Close()
 		return fmt.Errorf("failed to close channel: %w", err)
 	}
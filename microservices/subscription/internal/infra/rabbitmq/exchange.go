@@ -0,0 +1,15 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeclareExchange declares a durable exchange owned by this service.
+func DeclareExchange(ch *amqp.Channel, exchange, kind string) error {
+	if err := ch.ExchangeDeclare(exchange, kind, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange %s: %w", exchange, err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeclareQueue declares a durable queue and binds it to the exchange with the given routing keys.
+func DeclareQueue(ch *amqp.Channel, queue, exchange string, keys ...string) error {
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue %s: %w", queue, err)
+	}
+	for _, key := range keys {
+		if err := ch.QueueBind(queue, key, exchange, false, nil); err != nil {
+			return fmt.Errorf("bind queue %s with key %s: %w", queue, key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func NewRedisClient(ctx context.Context, url string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	opts.DialTimeout = 5 * time.Second
+	opts.ReadTimeout = time.Second
+	opts.WriteTimeout = time.Second
+
+	client := redis.NewClient(opts)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		if closeErr := client.Close(); closeErr != nil {
+			return nil, fmt.Errorf("redis connection failed: %w, also failed to close client: %w", err, closeErr)
+		}
+		return nil, fmt.Errorf("redis connection failed: %w", err)
+	}
+
+	return client, nil
+}
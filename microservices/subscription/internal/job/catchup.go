@@ -0,0 +1,138 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+type runLookup interface {
+	// LatestScheduled returns the newest recorded slot of a frequency; ok is false for an empty ledger.
+	LatestScheduled(ctx context.Context, frequency string) (slot time.Time, ok bool, err error)
+	Start(ctx context.Context, run Run) (Run, error)
+}
+
+type emitter interface {
+	Emit(ctx context.Context, ev Event)
+}
+
+// CatchUp replays runs missed while no replica was leading. It checks every time this replica
+// becomes the leader. Every missed daily slot is replayed, since each one serves different
+// delivery hours. For hourly reports only the latest slot is sent; older ones are recorded as
+// skipped, because stale hourly weather has no value.
+type CatchUp struct {
+	runs     runLookup
+	emitter  emitter
+	gate     leaderGate
+	window   time.Duration
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewCatchUp(runs runLookup, emitter emitter, gate leaderGate, window time.Duration) *CatchUp {
+	return &CatchUp{
+		runs:     runs,
+		emitter:  emitter,
+		gate:     gate,
+		window:   window,
+		interval: 10 * time.Second,
+		now:      time.Now,
+	}
+}
+
+func (c *CatchUp) Start(ctx context.Context) {
+	if c.window <= 0 {
+		return
+	}
+
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	wasLeader := false
+	for {
+		isLeader := c.gate.IsLeader()
+		if isLeader && !wasLeader {
+			if err := c.Run(ctx); err != nil {
+				logger.Error("Missed run catch-up failed", "error", err)
+				isLeader = false // retry on the next tick
+			}
+		}
+		wasLeader = isLeader
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run emits events for the slots after the newest ledger entry, limited to the window.
+// An empty ledger means a first deployment, so nothing is replayed.
+func (c *CatchUp) Run(ctx context.Context) error {
+	logger := loggerPkg.From(ctx)
+	now := c.now()
+	since := scheduledHour(now.Add(-c.window))
+	current := scheduledHour(now)
+
+	for _, freq := range []string{"hourly", "daily"} {
+		missed, err := c.missedSlots(ctx, freq, since, current)
+		if err != nil {
+			return err
+		}
+		if len(missed) == 0 {
+			continue
+		}
+
+		if freq == "hourly" {
+			for _, slot := range missed[:len(missed)-1] {
+				c.skip(ctx, freq, slot)
+			}
+			missed = missed[len(missed)-1:]
+		}
+
+		for _, slot := range missed {
+			logger.Info("Replaying missed run", "frequency", freq, "scheduled_at", slot)
+			c.emitter.Emit(ctx, Event{Frequency: freq, ScheduledAt: slot, CatchUp: true})
+		}
+	}
+	return nil
+}
+
+// missedSlots lists hourly slots in [since, current] newer than the latest recorded one, oldest first.
+func (c *CatchUp) missedSlots(ctx context.Context, freq string, since, current time.Time) ([]time.Time, error) {
+	latest, ok, err := c.runs.LatestScheduled(ctx, freq)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	from := latest.UTC().Add(time.Hour)
+	if from.Before(since) {
+		from = since
+	}
+
+	var missed []time.Time
+	for slot := from; !slot.After(current); slot = slot.Add(time.Hour) {
+		missed = append(missed, slot)
+	}
+	return missed, nil
+}
+
+func (c *CatchUp) skip(ctx context.Context, freq string, slot time.Time) {
+	now := c.now()
+	_, err := c.runs.Start(ctx, Run{
+		Frequency:   freq,
+		ScheduledAt: slot,
+		StartedAt:   now,
+		FinishedAt:  &now,
+		Status:      RunSkipped,
+		CatchUp:     true,
+		Error:       "superseded by a later hourly run",
+	})
+	if err != nil && !errors.Is(err, ErrRunExists) {
+		loggerPkg.From(ctx).Warn("Failed to record skipped run", "frequency", freq, "scheduled_at", slot, "error", err)
+	}
+}
@@ -2,21 +2,34 @@ package job
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 
 	"github.com/robfig/cron/v3"
 )
 
+// leaderGate tells whether this replica may emit cron events.
+type leaderGate interface {
+	IsLeader() bool
+}
+
+// CronEventSource runs on every replica but only the leader emits events,
+// so scaling out does not multiply report deliveries.
 type CronEventSource struct {
 	cron   *cron.Cron
-	events chan string
+	events chan Event
+	gate   leaderGate
+	mu     sync.RWMutex
+	closed bool
 }
 
-func NewCronEventSource() *CronEventSource {
+func NewCronEventSource(gate leaderGate) *CronEventSource {
 	return &CronEventSource{
 		cron:   cron.New(),
-		events: make(chan string, 10),
+		events: make(chan Event, 10),
+		gate:   gate,
 	}
 }
 
@@ -28,29 +41,31 @@ func (s *CronEventSource) Start(ctx context.Context) {
 			logger.Info("Hourly cron skipped: context canceled")
 			return
 		}
-		logger.Info("Hourly cron triggered")
-		select {
-		case s.events <- "hourly":
-		case <-ctx.Done():
-			logger.Info("Hourly cron event send canceled")
+		if !s.gate.IsLeader() {
+			logger.Debug("Hourly cron skipped: not the leader")
+			return
 		}
+		logger.Info("Hourly cron triggered")
+		s.Emit(ctx, Event{Frequency: "hourly", ScheduledAt: scheduledHour(time.Now())})
 	})
 	if err != nil {
 		logger.Error("Failed to schedule hourly cron: %v", err)
 		return
 	}
 
-	_, err = s.cron.AddFunc("0 12 * * *", func() {
+	// Daily subscribers pick their own delivery hour, so the daily event fires every hour
+	// and the service filters out subscriptions that are not due yet.
+	_, err = s.cron.AddFunc("0 * * * *", func() {
 		if ctx.Err() != nil {
 			logger.Info("Daily cron skipped: context canceled")
 			return
 		}
-		logger.Info("Daily cron triggered")
-		select {
-		case s.events <- "daily":
-		case <-ctx.Done():
-			logger.Info("Daily cron event send canceled")
+		if !s.gate.IsLeader() {
+			logger.Debug("Daily cron skipped: not the leader")
+			return
 		}
+		logger.Info("Daily cron triggered")
+		s.Emit(ctx, Event{Frequency: "daily", ScheduledAt: scheduledHour(time.Now())})
 	})
 	if err != nil {
 		logger.Error("Failed to schedule daily cron: %v", err)
@@ -65,13 +80,40 @@ func (s *CronEventSource) Start(ctx context.Context) {
 	}()
 }
 
-func (s *CronEventSource) Events() <-chan string {
+func (s *CronEventSource) Events() <-chan Event {
 	return s.events
 }
 
+// Emit hands an event to the dispatcher; it is also used to replay missed runs.
+func (s *CronEventSource) Emit(ctx context.Context, ev Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.events <- ev:
+	case <-ctx.Done():
+		loggerPkg.From(ctx).Info("Cron event send canceled", "frequency", ev.Frequency)
+	}
+}
+
+// scheduledHour maps a tick to the top of its hour, which identifies the run in the ledger.
+func scheduledHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
 func (s *CronEventSource) Stop(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
 	logger := loggerPkg.From(ctx)
 	logger.Info("Cron scheduler stopped")
 	s.cron.Stop()
+	s.closed = true
 	close(s.events)
 }
@@ -2,12 +2,15 @@ package job
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 )
 
 type eventSource interface {
-	Events() <-chan string
+	Events() <-chan Event
 }
 
 type taskQueue interface {
@@ -15,20 +18,34 @@ type taskQueue interface {
 }
 
 type subservice interface {
-	GenerateWeatherReportTasks(ctx context.Context, frequency string) ([]Task, error)
+	GenerateWeatherReportTasks(ctx context.Context, frequency string, at time.Time) ([]Task, error)
+}
+
+type runRecorder interface {
+	// Start records a new run and returns ErrRunExists if the slot was already dispatched.
+	Start(ctx context.Context, run Run) (Run, error)
+	Finish(ctx context.Context, run Run) error
+}
+
+type dispatchMetrics interface {
+	ObserveRun(frequency string, d time.Duration)
 }
 
 type EmailDispatcher struct {
 	SubService  subservice
 	TaskQueue   taskQueue
 	EventSource eventSource
+	Runs        runRecorder
+	Metrics     dispatchMetrics
 }
 
-func NewEmailDispatcher(subService subservice, taskQueue taskQueue, eventSource eventSource) *EmailDispatcher {
+func NewEmailDispatcher(subService subservice, taskQueue taskQueue, eventSource eventSource, runs runRecorder, metrics dispatchMetrics) *EmailDispatcher {
 	return &EmailDispatcher{
 		SubService:  subService,
 		TaskQueue:   taskQueue,
 		EventSource: eventSource,
+		Runs:        runs,
+		Metrics:     metrics,
 	}
 }
 
@@ -40,30 +57,80 @@ func (d *EmailDispatcher) Start(ctx context.Context) {
 			case <-ctx.Done():
 				logger.Info("Dispatcher context cancelled, stopping")
 				return
-			case freq, ok := <-d.EventSource.Events():
+			case ev, ok := <-d.EventSource.Events():
 				if !ok {
 					logger.Info("Event source closed, dispatcher exiting")
 					return
 				}
-				logger.Info("Event received", "frequency", freq)
-				d.DispatchScheduledEmails(ctx, freq)
+				logger.Info("Event received", "frequency", ev.Frequency, "scheduled_at", ev.ScheduledAt, "catch_up", ev.CatchUp)
+				d.DispatchScheduledEmails(ctx, ev)
 			}
 		}
 	}()
 }
 
-func (d *EmailDispatcher) DispatchScheduledEmails(ctx context.Context, freq string) {
+// DispatchScheduledEmails runs a scheduled slot at most once; the ledger rejects slots
+// that another replica or an earlier catch-up already dispatched.
+func (d *EmailDispatcher) DispatchScheduledEmails(ctx context.Context, ev Event) {
+	logger := loggerPkg.From(ctx)
+	start := time.Now()
+	defer func() { d.Metrics.ObserveRun(ev.Frequency, time.Since(start)) }()
+
+	run, err := d.Runs.Start(ctx, Run{
+		Frequency:   ev.Frequency,
+		ScheduledAt: ev.ScheduledAt,
+		StartedAt:   start,
+		Status:      RunRunning,
+		CatchUp:     ev.CatchUp,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRunExists) {
+			logger.Info("Run already dispatched, skipping", "frequency", ev.Frequency, "scheduled_at", ev.ScheduledAt)
+			return
+		}
+		logger.Error("Failed to record dispatch run", "error", err)
+		return
+	}
+
+	d.dispatch(ctx, ev, &run)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	if err := d.Runs.Finish(ctx, run); err != nil {
+		logger.Error("Failed to finish dispatch run", "id", run.ID, "error", err)
+	}
+}
+
+func (d *EmailDispatcher) dispatch(ctx context.Context, ev Event, run *Run) {
 	logger := loggerPkg.From(ctx)
-	tasks, err := d.SubService.GenerateWeatherReportTasks(ctx, freq)
+
+	tasks, err := d.SubService.GenerateWeatherReportTasks(ctx, ev.Frequency, ev.ScheduledAt)
 	if err != nil {
 		logger.Error("Failed to generate tasks", "error", err)
+		run.Status = RunFailed
+		run.Error = err.Error()
 		return
 	}
 
+	run.TasksTotal = len(tasks)
+	var lastErr error
 	for _, task := range tasks {
-		logger.Info("Enqueuing task", "email", task.Email)
+		task.RunID = run.ID
+		task.ScheduledAt = ev.ScheduledAt
+		run.Recipients += len(task.Recipients)
+		logger.Info("Enqueuing task", "city", task.City, "recipients", len(task.Recipients))
 		if err := d.TaskQueue.Enqueue(ctx, task); err != nil {
 			logger.Error("Failed to enqueue", "error", err)
+			run.TasksFailed++
+			lastErr = err
+			continue
 		}
+		run.TasksEnqueued++
+	}
+
+	run.Status = RunSucceeded
+	if lastErr != nil {
+		run.Status = RunFailed
+		run.Error = fmt.Sprintf("%d of %d tasks not enqueued: %v", run.TasksFailed, run.TasksTotal, lastErr)
 	}
 }
@@ -0,0 +1,172 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRuns struct {
+	slots    map[string]time.Time
+	started  []Run
+	finished []Run
+}
+
+func newFakeRuns() *fakeRuns {
+	return &fakeRuns{slots: map[string]time.Time{}}
+}
+
+func (r *fakeRuns) Start(_ context.Context, run Run) (Run, error) {
+	key := run.Frequency + run.ScheduledAt.String()
+	if _, ok := r.slots[key]; ok {
+		return Run{}, ErrRunExists
+	}
+	r.slots[key] = run.ScheduledAt
+	run.ID = int64(len(r.started) + 1)
+	r.started = append(r.started, run)
+	return run, nil
+}
+
+func (r *fakeRuns) Finish(_ context.Context, run Run) error {
+	r.finished = append(r.finished, run)
+	return nil
+}
+
+func (r *fakeRuns) LatestScheduled(_ context.Context, frequency string) (time.Time, bool, error) {
+	var latest time.Time
+	for _, run := range r.started {
+		if run.Frequency == frequency && run.ScheduledAt.After(latest) {
+			latest = run.ScheduledAt
+		}
+	}
+	return latest, !latest.IsZero(), nil
+}
+
+type fakeSubService struct {
+	tasks []Task
+	at    time.Time
+}
+
+func (s *fakeSubService) GenerateWeatherReportTasks(_ context.Context, _ string, at time.Time) ([]Task, error) {
+	s.at = at
+	return s.tasks, nil
+}
+
+type failingQueue struct {
+	failCity string
+	tasks    []Task
+}
+
+func (q *failingQueue) Enqueue(_ context.Context, task Task) error {
+	if task.City == q.failCity {
+		return errors.New("queue full")
+	}
+	q.tasks = append(q.tasks, task)
+	return nil
+}
+
+func TestDispatcher_RecordsRunOutcome(t *testing.T) {
+	runs := newFakeRuns()
+	svc := &fakeSubService{tasks: []Task{testTask("kyiv"), testTask("lviv")}}
+	queue := &failingQueue{failCity: "lviv"}
+	d := NewEmailDispatcher(svc, queue, nil, runs, NewNoopMetrics())
+	slot := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d.DispatchScheduledEmails(context.Background(), Event{Frequency: "daily", ScheduledAt: slot})
+
+	require.Len(t, runs.finished, 1)
+	run := runs.finished[0]
+	assert.Equal(t, slot, svc.at, "tasks must be built for the scheduled hour")
+	assert.Equal(t, RunFailed, run.Status)
+	assert.Equal(t, 2, run.TasksTotal)
+	assert.Equal(t, 1, run.TasksEnqueued)
+	assert.Equal(t, 1, run.TasksFailed)
+	assert.NotNil(t, run.FinishedAt)
+	require.Len(t, queue.tasks, 1)
+	assert.Equal(t, run.ID, queue.tasks[0].RunID, "tasks must reference their run for delivery history")
+	assert.Equal(t, slot, queue.tasks[0].ScheduledAt, "reports must be keyed by the slot, not the send time")
+}
+
+func TestDispatcher_SkipsSlotAlreadyDispatched(t *testing.T) {
+	runs := newFakeRuns()
+	queue := &failingQueue{}
+	d := NewEmailDispatcher(&fakeSubService{tasks: []Task{testTask("kyiv")}}, queue, nil, runs, NewNoopMetrics())
+	ev := Event{Frequency: "hourly", ScheduledAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	d.DispatchScheduledEmails(context.Background(), ev)
+	d.DispatchScheduledEmails(context.Background(), ev)
+
+	assert.Len(t, queue.tasks, 1)
+	assert.Len(t, runs.finished, 1)
+}
+
+type recordingEmitter struct {
+	events []Event
+}
+
+func (e *recordingEmitter) Emit(_ context.Context, ev Event) {
+	e.events = append(e.events, ev)
+}
+
+type staticGate bool
+
+func (g staticGate) IsLeader() bool { return bool(g) }
+
+func TestCatchUp_ReplaysMissedDailySlotsAndLatestHourly(t *testing.T) {
+	runs := newFakeRuns()
+	last := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	_, _ = runs.Start(context.Background(), Run{Frequency: "daily", ScheduledAt: last})
+	_, _ = runs.Start(context.Background(), Run{Frequency: "hourly", ScheduledAt: last})
+
+	emitter := &recordingEmitter{}
+	c := NewCatchUp(runs, emitter, staticGate(true), 6*time.Hour)
+	c.now = func() time.Time { return time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, c.Run(context.Background()))
+
+	var hourly, daily []time.Time
+	for _, ev := range emitter.events {
+		assert.True(t, ev.CatchUp)
+		if ev.Frequency == "hourly" {
+			hourly = append(hourly, ev.ScheduledAt)
+		} else {
+			daily = append(daily, ev.ScheduledAt)
+		}
+	}
+	assert.Equal(t, []time.Time{last.Add(3 * time.Hour)}, hourly)
+	assert.Equal(t, []time.Time{last.Add(time.Hour), last.Add(2 * time.Hour), last.Add(3 * time.Hour)}, daily)
+
+	skipped := 0
+	for _, run := range runs.started {
+		if run.Status == RunSkipped {
+			skipped++
+		}
+	}
+	assert.Equal(t, 2, skipped, "older hourly slots are recorded as skipped")
+}
+
+func TestCatchUp_EmptyLedgerReplaysNothing(t *testing.T) {
+	emitter := &recordingEmitter{}
+	c := NewCatchUp(newFakeRuns(), emitter, staticGate(true), 6*time.Hour)
+
+	require.NoError(t, c.Run(context.Background()))
+
+	assert.Empty(t, emitter.events)
+}
+
+func TestCatchUp_LimitsReplayToWindow(t *testing.T) {
+	runs := newFakeRuns()
+	_, _ = runs.Start(context.Background(), Run{Frequency: "daily", ScheduledAt: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)})
+
+	emitter := &recordingEmitter{}
+	c := NewCatchUp(runs, emitter, staticGate(true), 2*time.Hour)
+	c.now = func() time.Time { return time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, c.Run(context.Background()))
+
+	assert.Len(t, emitter.events, 3) // 10:00, 11:00 and 12:00
+}
@@ -0,0 +1,145 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+type jobStore interface {
+	Insert(ctx context.Context, task Task) error
+	// Claim leases the next visible task until visibleUntil and increments its attempt count.
+	// It returns false when nothing is due.
+	Claim(ctx context.Context, now, visibleUntil time.Time, maxAttempts int) (Task, bool, error)
+	Complete(ctx context.Context, id string) error
+	Retry(ctx context.Context, id string, visibleAt time.Time, lastErr string) error
+	Bury(ctx context.Context, id string, lastErr string) error
+	CountQueued(ctx context.Context) (int64, error)
+}
+
+type DurableQueueConfig struct {
+	PollInterval      time.Duration
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+}
+
+func DefaultDurableQueueConfig() DurableQueueConfig {
+	return DurableQueueConfig{
+		PollInterval:      time.Second,
+		VisibilityTimeout: 2 * time.Minute,
+		MaxAttempts:       5,
+		BaseBackoff:       30 * time.Second,
+		MaxBackoff:        30 * time.Minute,
+	}
+}
+
+// DurableQueue keeps tasks in the database. A task stays invisible while a worker holds it;
+// if the worker dies the visibility timeout expires and another worker picks the task up.
+type DurableQueue struct {
+	store  jobStore
+	cfg    DurableQueueConfig
+	now    func() time.Time
+	closed chan struct{}
+	once   sync.Once
+}
+
+func NewDurableQueue(store jobStore, cfg DurableQueueConfig) *DurableQueue {
+	return &DurableQueue{
+		store:  store,
+		cfg:    cfg,
+		now:    time.Now,
+		closed: make(chan struct{}),
+	}
+}
+
+func (q *DurableQueue) Enqueue(ctx context.Context, task Task) error {
+	logger := loggerPkg.From(ctx)
+	if len(task.Recipients) == 0 {
+		logger.Warn("Skip enqueue: no recipients", "city", task.City)
+		return fmt.Errorf("cannot enqueue task: missing recipients")
+	}
+
+	if err := q.store.Insert(ctx, task); err != nil {
+		return fmt.Errorf("insert job: %w", err)
+	}
+
+	logger.Info("Task enqueued", "city", task.City)
+	return nil
+}
+
+// Dequeue blocks until a task is due, the context is cancelled or the queue is closed.
+func (q *DurableQueue) Dequeue(ctx context.Context) (Task, error) {
+	for {
+		select {
+		case <-q.closed:
+			return Task{}, io.EOF
+		default:
+		}
+
+		now := q.now()
+		task, ok, err := q.store.Claim(ctx, now, now.Add(q.cfg.VisibilityTimeout), q.cfg.MaxAttempts)
+		if err == nil && ok {
+			return task, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Task{}, ctx.Err()
+		case <-q.closed:
+			return Task{}, io.EOF
+		case <-time.After(q.cfg.PollInterval):
+		}
+
+		if err != nil {
+			return Task{}, fmt.Errorf("claim job: %w", err)
+		}
+	}
+}
+
+func (q *DurableQueue) Ack(ctx context.Context, task Task) error {
+	return q.store.Complete(ctx, task.ID)
+}
+
+// Nack schedules the task again with exponential backoff or buries it once MaxAttempts is reached.
+func (q *DurableQueue) Nack(ctx context.Context, task Task, cause error) error {
+	logger := loggerPkg.From(ctx)
+
+	if task.Attempts >= q.cfg.MaxAttempts {
+		logger.Error("Task exhausted retries", "id", task.ID, "attempts", task.Attempts, "error", cause)
+		return q.store.Bury(ctx, task.ID, cause.Error())
+	}
+
+	next := q.now().Add(q.backoff(task.Attempts))
+	logger.Warn("Task failed, will retry", "id", task.ID, "attempts", task.Attempts, "next_attempt_at", next)
+	return q.store.Retry(ctx, task.ID, next, cause.Error())
+}
+
+func (q *DurableQueue) backoff(attempts int) time.Duration {
+	d := q.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= q.cfg.MaxBackoff {
+			return q.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+func (q *DurableQueue) Depth(ctx context.Context) (int64, error) {
+	return q.store.CountQueued(ctx)
+}
+
+func (q *DurableQueue) Close(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	logger.Info("Queue stopping")
+	q.once.Do(func() {
+		close(q.closed)
+	})
+	logger.Info("Queue stopped")
+}
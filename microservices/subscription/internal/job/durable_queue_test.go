@@ -0,0 +1,114 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJobStore struct {
+	due     []Task
+	retried map[string]time.Time
+	buried  []string
+	done    []string
+}
+
+func newFakeJobStore(due ...Task) *fakeJobStore {
+	return &fakeJobStore{due: due, retried: map[string]time.Time{}}
+}
+
+func (s *fakeJobStore) Insert(_ context.Context, task Task) error {
+	s.due = append(s.due, task)
+	return nil
+}
+
+func (s *fakeJobStore) Claim(context.Context, time.Time, time.Time, int) (Task, bool, error) {
+	if len(s.due) == 0 {
+		return Task{}, false, nil
+	}
+	task := s.due[0]
+	s.due = s.due[1:]
+	task.Attempts++
+	return task, true, nil
+}
+
+func (s *fakeJobStore) Complete(_ context.Context, id string) error {
+	s.done = append(s.done, id)
+	return nil
+}
+
+func (s *fakeJobStore) Retry(_ context.Context, id string, visibleAt time.Time, _ string) error {
+	s.retried[id] = visibleAt
+	return nil
+}
+
+func (s *fakeJobStore) Bury(_ context.Context, id string, _ string) error {
+	s.buried = append(s.buried, id)
+	return nil
+}
+
+func (s *fakeJobStore) CountQueued(context.Context) (int64, error) {
+	return int64(len(s.due)), nil
+}
+
+func newTestQueue(store *fakeJobStore) *DurableQueue {
+	cfg := DefaultDurableQueueConfig()
+	cfg.PollInterval = time.Millisecond
+	q := NewDurableQueue(store, cfg)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	return q
+}
+
+func TestDurableQueue_DequeueReturnsClaimedTask(t *testing.T) {
+	q := newTestQueue(newFakeJobStore())
+	ctx := context.Background()
+
+	require.NoError(t, q.Enqueue(ctx, Task{ID: "1", City: "Kyiv", Recipients: []Recipient{{Email: "a@b.c"}}}))
+
+	task, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Kyiv", task.City)
+	assert.Equal(t, 1, task.Attempts)
+}
+
+func TestDurableQueue_EnqueueRejectsTaskWithoutRecipients(t *testing.T) {
+	q := newTestQueue(newFakeJobStore())
+
+	assert.Error(t, q.Enqueue(context.Background(), Task{City: "Kyiv"}))
+}
+
+func TestDurableQueue_DequeueStopsWhenClosed(t *testing.T) {
+	q := newTestQueue(newFakeJobStore())
+	ctx := context.Background()
+
+	go q.Close(ctx)
+
+	_, err := q.Dequeue(ctx)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestDurableQueue_NackSchedulesRetryWithBackoff(t *testing.T) {
+	store := newFakeJobStore()
+	q := newTestQueue(store)
+
+	require.NoError(t, q.Nack(context.Background(), Task{ID: "7", Attempts: 2}, errors.New("weather down")))
+
+	assert.Equal(t, q.now().Add(time.Minute), store.retried["7"])
+	assert.Empty(t, store.buried)
+}
+
+func TestDurableQueue_NackBuriesAfterMaxAttempts(t *testing.T) {
+	store := newFakeJobStore()
+	q := newTestQueue(store)
+
+	require.NoError(t, q.Nack(context.Background(), Task{ID: "7", Attempts: q.cfg.MaxAttempts}, errors.New("weather down")))
+
+	assert.Equal(t, []string{"7"}, store.buried)
+	assert.Empty(t, store.retried)
+}
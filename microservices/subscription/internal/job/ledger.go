@@ -0,0 +1,40 @@
+package job
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRunExists = errors.New("dispatch run already recorded")
+
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+	RunSkipped   RunStatus = "skipped"
+)
+
+// Event asks the dispatcher to deliver reports of a frequency for a scheduled hour.
+type Event struct {
+	Frequency   string
+	ScheduledAt time.Time
+	CatchUp     bool
+}
+
+// Run is one entry of the dispatch ledger.
+type Run struct {
+	ID            int64
+	Frequency     string
+	ScheduledAt   time.Time
+	StartedAt     time.Time
+	FinishedAt    *time.Time
+	Status        RunStatus
+	CatchUp       bool
+	TasksTotal    int
+	TasksEnqueued int
+	TasksFailed   int
+	Recipients    int
+	Error         string
+}
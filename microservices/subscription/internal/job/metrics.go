@@ -0,0 +1,58 @@
+package job
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	inFlight    prometheus.Gauge
+	queueDepth  prometheus.Gauge
+	runDuration *prometheus.HistogramVec
+	once        sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "subscription_jobs_in_flight",
+			Help: "Weather report tasks currently being processed",
+		}),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "subscription_jobs_queue_depth",
+			Help: "Weather report tasks waiting in the queue",
+		}),
+		runDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "subscription_dispatch_run_duration_seconds",
+				Help:    "Time taken to build and enqueue the tasks of one dispatch run",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"frequency"},
+		),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.inFlight, m.queueDepth, m.runDuration)
+	})
+}
+
+func (m *Metrics) IncInFlight() {
+	m.inFlight.Inc()
+}
+
+func (m *Metrics) DecInFlight() {
+	m.inFlight.Dec()
+}
+
+func (m *Metrics) SetQueueDepth(depth int64) {
+	m.queueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) ObserveRun(frequency string, d time.Duration) {
+	m.runDuration.WithLabelValues(frequency).Observe(d.Seconds())
+}
@@ -0,0 +1,15 @@
+package job
+
+import "time"
+
+type NoopMetrics struct{}
+
+func NewNoopMetrics() NoopMetrics {
+	return NoopMetrics{}
+}
+
+func (n NoopMetrics) Register()                                    {}
+func (n NoopMetrics) IncInFlight()                                 {}
+func (n NoopMetrics) DecInFlight()                                 {}
+func (n NoopMetrics) SetQueueDepth(depth int64)                    {}
+func (n NoopMetrics) ObserveRun(frequency string, d time.Duration) {}
@@ -22,18 +22,18 @@ func NewLocalQueue(bufferSize int) *LocalQueue {
 
 func (q *LocalQueue) Enqueue(ctx context.Context, task Task) error {
 	logger := loggerPkg.From(ctx)
-	if task.Email == "" {
-		logger.Warn("Skip enqueue: empty email", "city", task.City)
-		return fmt.Errorf("cannot enqueue task: missing email")
+	if len(task.Recipients) == 0 {
+		logger.Warn("Skip enqueue: no recipients", "city", task.City)
+		return fmt.Errorf("cannot enqueue task: missing recipients")
 	}
 
-	logger.Info("Task enqueued", "email", task.Email)
+	logger.Info("Task enqueued", "city", task.City)
 
 	select {
 	case q.queue <- task:
 		return nil
 	case <-ctx.Done():
-		logger.Warn("Enqueue cancelled by context", "email", task.Email)
+		logger.Warn("Enqueue cancelled by context", "city", task.City)
 		return ctx.Err()
 	}
 }
@@ -50,6 +50,10 @@ func (q *LocalQueue) Dequeue(ctx context.Context) (Task, error) {
 	}
 }
 
+func (q *LocalQueue) Depth(context.Context) (int64, error) {
+	return int64(len(q.queue)), nil
+}
+
 func (q *LocalQueue) Close(ctx context.Context) {
 	logger := loggerPkg.From(ctx)
 	logger.Info("Queue stopping")
@@ -4,38 +4,150 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 	"time"
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 )
 
+// Recipient is a single subscriber of a city task.
+type Recipient struct {
+	SubscriptionID string `json:"subscription_id"` //nolint:tagliatelle
+	Email          string `json:"email"`
+	Token          string `json:"token"`
+	Locale         string `json:"locale,omitempty"`
+	// Channel is "email" or "webhook"; tasks queued before channels existed leave it empty.
+	Channel string `json:"channel,omitempty"`
+	// City is the city of the subscription; only digest tasks, which span cities, set it.
+	City string `json:"city,omitempty"`
+}
+
+// Task delivers one weather report run for a city to all of its due subscribers. A digest task
+// instead delivers the run to one address, combining its subscriptions in several cities.
 type Task struct {
-	Email string
-	City  string
-	Token string
+	// ID and Attempts are set by durable queues; tasks from LocalQueue leave them empty.
+	ID       string
+	Attempts int
+	// RunID links the task to its dispatch run; zero for tasks enqueued outside the ledger.
+	RunID int64
+	// ScheduledAt is the slot of the run; reports are keyed by it, so replays of several missed
+	// slots stay apart and a retry after the hour keeps its key. Zero for tasks queued before it
+	// was recorded.
+	ScheduledAt time.Time
+	City        string
+	Recipients  []Recipient
+	// Digest marks a task of one address; City is empty and each recipient names its city.
+	Digest bool
 }
 
 type taskSource interface {
 	Dequeue(ctx context.Context) (Task, error)
 }
 
+// taskAcker is implemented by queues that track task outcome and retry failed tasks.
+type taskAcker interface {
+	Ack(ctx context.Context, task Task) error
+	Nack(ctx context.Context, task Task, cause error) error
+}
+
+// depthReporter is implemented by queues that can tell how many tasks are waiting.
+type depthReporter interface {
+	Depth(ctx context.Context) (int64, error)
+}
+
 type taskService interface {
 	ProcessWeatherReportTask(ctx context.Context, task Task) error
 }
 
+type workerMetrics interface {
+	IncInFlight()
+	DecInFlight()
+	SetQueueDepth(depth int64)
+}
+
+type WorkerConfig struct {
+	Concurrency   int
+	TaskTimeout   time.Duration
+	DepthInterval time.Duration
+}
+
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		Concurrency:   8,
+		TaskTimeout:   30 * time.Second,
+		DepthInterval: 15 * time.Second,
+	}
+}
+
+// Worker runs a fixed number of consumers. Stop stops dequeuing and lets in-flight tasks finish.
 type Worker struct {
 	queue      taskSource
 	subService taskService
+	metrics    workerMetrics
+	cfg        WorkerConfig
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
 }
 
-func NewWorker(queue taskSource, subservice taskService) *Worker {
+func NewWorker(queue taskSource, subservice taskService, metrics workerMetrics, cfg WorkerConfig) *Worker {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
 	return &Worker{
 		queue:      queue,
 		subService: subservice,
+		metrics:    metrics,
+		cfg:        cfg,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 }
 
+// Start blocks until the context is cancelled or Stop is called and every consumer has drained.
 func (w *Worker) Start(ctx context.Context) {
+	defer close(w.done)
+	logger := loggerPkg.From(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go w.reportDepth(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.consume(ctx)
+		}()
+	}
+	logger.Info("Worker pool started", "concurrency", w.cfg.Concurrency)
+
+	wg.Wait()
+	logger.Info("Worker pool drained")
+}
+
+// Stop waits for in-flight tasks until ctx expires.
+func (w *Worker) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) consume(ctx context.Context) {
 	logger := loggerPkg.From(ctx)
 
 	for {
@@ -54,27 +166,82 @@ func (w *Worker) Start(ctx context.Context) {
 			}
 		}
 
-		if task.Email == "" {
-			logger.Warn("Empty email in task, skipping")
+		if len(task.Recipients) == 0 {
+			logger.Warn("Task without recipients, skipping", "city", task.City)
 			continue
 		}
 
-		go func(parentCtx context.Context, t Task) {
-			taskCtx, cancel := context.WithTimeout(parentCtx, 30*time.Second)
-			defer cancel()
-			defer func() {
-				if r := recover(); r != nil {
-					logger := loggerPkg.From(taskCtx)
-					logger.Error("Panic recovered while handling task for %s: %v", t.Email, r)
-				}
-			}()
-			logger := loggerPkg.From(taskCtx)
-			err := w.subService.ProcessWeatherReportTask(taskCtx, t)
+		// In-flight tasks must survive shutdown, so they only inherit values from ctx.
+		w.process(context.WithoutCancel(ctx), task)
+	}
+}
+
+func (w *Worker) process(ctx context.Context, task Task) {
+	w.metrics.IncInFlight()
+	defer w.metrics.DecInFlight()
+
+	taskCtx, cancel := context.WithTimeout(ctx, w.cfg.TaskTimeout)
+	defer cancel()
+
+	logger := loggerPkg.From(taskCtx)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("Panic recovered while handling task for %s: %v", task.City, r)
+				err = errors.New("task panicked")
+			}
+		}()
+		return w.subService.ProcessWeatherReportTask(taskCtx, task)
+	}()
+	if err != nil {
+		logger.Error("Failed to process task", "city", task.City, "recipients", len(task.Recipients), "error", err)
+	} else {
+		logger.Info("Task processed", "city", task.City, "recipients", len(task.Recipients))
+	}
+
+	w.settle(ctx, task, err)
+}
+
+// settle reports the task outcome to queues that support acknowledgements.
+// It uses the worker context, so a task that hit its own timeout is still nacked.
+func (w *Worker) settle(ctx context.Context, task Task, taskErr error) {
+	acker, ok := w.queue.(taskAcker)
+	if !ok {
+		return
+	}
+
+	logger := loggerPkg.From(ctx)
+	if taskErr == nil {
+		if err := acker.Ack(ctx, task); err != nil {
+			logger.Error("Failed to ack task", "id", task.ID, "error", err)
+		}
+		return
+	}
+	if err := acker.Nack(ctx, task, taskErr); err != nil {
+		logger.Error("Failed to nack task", "id", task.ID, "error", err)
+	}
+}
+
+func (w *Worker) reportDepth(ctx context.Context) {
+	reporter, ok := w.queue.(depthReporter)
+	if !ok || w.cfg.DepthInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.DepthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			depth, err := reporter.Depth(ctx)
 			if err != nil {
-				logger.Error("Failed to process task", "email", t.Email, "error", err)
-			} else {
-				logger.Info("Task processed", "email", t.Email)
+				loggerPkg.From(ctx).Warn("Failed to read queue depth", "error", err)
+				continue
 			}
-		}(ctx, task)
+			w.metrics.SetQueueDepth(depth)
+		}
 	}
 }
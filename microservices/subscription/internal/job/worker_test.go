@@ -0,0 +1,92 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskService struct {
+	err     error
+	delay   time.Duration
+	active  atomic.Int32
+	peak    atomic.Int32
+	handled atomic.Int32
+}
+
+func (s *fakeTaskService) ProcessWeatherReportTask(ctx context.Context, _ Task) error {
+	n := s.active.Add(1)
+	defer s.active.Add(-1)
+	for {
+		p := s.peak.Load()
+		if n <= p || s.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.handled.Add(1)
+	return s.err
+}
+
+func testTask(city string) Task {
+	return Task{City: city, Recipients: []Recipient{{Email: city + "@example.com"}}}
+}
+
+func TestWorker_RespectsConcurrencyLimit(t *testing.T) {
+	queue := NewLocalQueue(10)
+	svc := &fakeTaskService{delay: 20 * time.Millisecond}
+	w := NewWorker(queue, svc, NewNoopMetrics(), WorkerConfig{Concurrency: 2, TaskTimeout: time.Second})
+	ctx := context.Background()
+
+	for _, city := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, queue.Enqueue(ctx, testTask(city)))
+	}
+
+	go w.Start(ctx)
+	require.Eventually(t, func() bool { return svc.handled.Load() == 5 }, time.Second, 5*time.Millisecond)
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, w.Stop(stopCtx))
+	assert.LessOrEqual(t, svc.peak.Load(), int32(2))
+}
+
+func TestWorker_StopDrainsInFlightTasks(t *testing.T) {
+	queue := NewLocalQueue(10)
+	svc := &fakeTaskService{delay: 50 * time.Millisecond}
+	w := NewWorker(queue, svc, NewNoopMetrics(), WorkerConfig{Concurrency: 1, TaskTimeout: time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, queue.Enqueue(ctx, testTask("kyiv")))
+	go w.Start(ctx)
+	require.Eventually(t, func() bool { return svc.active.Load() == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second)
+	defer stopCancel()
+
+	require.NoError(t, w.Stop(stopCtx))
+	assert.Equal(t, int32(1), svc.handled.Load(), "in-flight task must finish despite shutdown")
+}
+
+func TestWorker_SettlesTasksWithDurableQueue(t *testing.T) {
+	store := newFakeJobStore()
+	q := newTestQueue(store)
+	w := NewWorker(q, &fakeTaskService{}, NewNoopMetrics(), DefaultWorkerConfig())
+
+	w.settle(context.Background(), Task{ID: "1"}, nil)
+	w.settle(context.Background(), Task{ID: "2", Attempts: 1}, errors.New("boom"))
+
+	assert.Equal(t, []string{"1"}, store.done)
+	assert.Contains(t, store.retried, "2")
+}
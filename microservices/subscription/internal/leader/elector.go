@@ -0,0 +1,107 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// Locker is a cluster-wide exclusive lock. Held must report false once the lock was lost,
+// e.g. because the database session holding it died.
+type Locker interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Held(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type electorMetrics interface {
+	SetLeader(leader bool)
+}
+
+// Elector keeps trying to take the lock and reports whether this replica currently leads.
+type Elector struct {
+	locker   Locker
+	metrics  electorMetrics
+	interval time.Duration
+	leader   atomic.Bool
+}
+
+func NewElector(locker Locker, metrics electorMetrics, interval time.Duration) *Elector {
+	return &Elector{
+		locker:   locker,
+		metrics:  metrics,
+		interval: interval,
+	}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run checks leadership every interval until ctx is cancelled, then releases the lock
+// so another replica can take over without waiting for the session to time out.
+func (e *Elector) Run(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			e.resign(context.WithoutCancel(ctx))
+			logger.Info("Leader election stopped")
+			return
+		case <-ticker.C:
+			e.tick(ctx)
+		}
+	}
+}
+
+func (e *Elector) tick(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+
+	if e.IsLeader() {
+		held, err := e.locker.Held(ctx)
+		if err == nil && held {
+			return
+		}
+		logger.Warn("Lost scheduler leadership", "err", err)
+		e.resign(ctx)
+		return
+	}
+
+	acquired, err := e.locker.TryAcquire(ctx)
+	if err != nil {
+		logger.Warn("Leader lock acquisition failed", "err", err)
+		return
+	}
+	if acquired {
+		logger.Info("Acquired scheduler leadership")
+		e.setLeader(true)
+	}
+}
+
+func (e *Elector) resign(ctx context.Context) {
+	wasLeader := e.IsLeader()
+	e.setLeader(false)
+	if err := e.locker.Release(ctx); err != nil {
+		loggerPkg.From(ctx).Warn("Failed to release leader lock", "err", err)
+		return
+	}
+	if wasLeader {
+		loggerPkg.From(ctx).Info("Released scheduler leadership")
+	}
+}
+
+func (e *Elector) setLeader(leader bool) {
+	e.leader.Store(leader)
+	e.metrics.SetLeader(leader)
+}
+
+// AlwaysLeader is used when leader election is disabled, e.g. for a single replica.
+type AlwaysLeader struct{}
+
+func (AlwaysLeader) IsLeader() bool { return true }
@@ -0,0 +1,93 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLocker struct {
+	acquire  bool
+	held     bool
+	heldErr  error
+	released int
+}
+
+func (l *fakeLocker) TryAcquire(context.Context) (bool, error) {
+	if l.acquire {
+		l.held = true
+	}
+	return l.acquire, nil
+}
+
+func (l *fakeLocker) Held(context.Context) (bool, error) {
+	return l.held, l.heldErr
+}
+
+func (l *fakeLocker) Release(context.Context) error {
+	l.released++
+	l.held = false
+	return nil
+}
+
+type fakeMetrics struct {
+	leader bool
+}
+
+func (m *fakeMetrics) SetLeader(leader bool) { m.leader = leader }
+
+func TestElector_BecomesLeaderWhenLockAcquired(t *testing.T) {
+	locker := &fakeLocker{acquire: true}
+	metrics := &fakeMetrics{}
+	e := NewElector(locker, metrics, time.Second)
+
+	e.tick(context.Background())
+
+	assert.True(t, e.IsLeader())
+	assert.True(t, metrics.leader)
+}
+
+func TestElector_StaysFollowerWhileLockTaken(t *testing.T) {
+	e := NewElector(&fakeLocker{acquire: false}, &fakeMetrics{}, time.Second)
+
+	e.tick(context.Background())
+
+	assert.False(t, e.IsLeader())
+}
+
+func TestElector_StepsDownWhenLockLost(t *testing.T) {
+	locker := &fakeLocker{acquire: true}
+	metrics := &fakeMetrics{}
+	e := NewElector(locker, metrics, time.Second)
+	e.tick(context.Background())
+
+	locker.acquire = false
+	locker.heldErr = errors.New("connection reset")
+	e.tick(context.Background())
+
+	assert.False(t, e.IsLeader())
+	assert.False(t, metrics.leader)
+	assert.Equal(t, 1, locker.released)
+}
+
+func TestElector_ReleasesLockOnShutdown(t *testing.T) {
+	locker := &fakeLocker{acquire: true}
+	e := NewElector(locker, &fakeMetrics{}, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, e.IsLeader, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	assert.False(t, e.IsLeader())
+	assert.Equal(t, 1, locker.released)
+}
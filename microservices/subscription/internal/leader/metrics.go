@@ -0,0 +1,57 @@
+package leader
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	leader      prometheus.Gauge
+	transitions *prometheus.CounterVec
+	current     bool
+	mu          sync.Mutex
+	once        sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		leader: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "subscription_scheduler_leader",
+			Help: "1 if this replica is the scheduler leader, 0 otherwise",
+		}),
+		transitions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_scheduler_leader_transitions_total",
+				Help: "Leadership changes of this replica",
+			},
+			[]string{"to"},
+		),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.leader, m.transitions)
+	})
+}
+
+func (m *Metrics) SetLeader(leader bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if leader != m.current {
+		to := "follower"
+		if leader {
+			to = "leader"
+		}
+		m.transitions.WithLabelValues(to).Inc()
+		m.current = leader
+	}
+
+	if leader {
+		m.leader.Set(1)
+	} else {
+		m.leader.Set(0)
+	}
+}
@@ -0,0 +1,64 @@
+// Package locale picks the language a subscriber receives emails and weather descriptions in.
+package locale
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Negotiator matches requested languages against the supported ones.
+type Negotiator struct {
+	matcher   language.Matcher
+	supported []string
+}
+
+// NewNegotiator supports fallback plus the given locales; fallback is used when nothing the
+// client asked for is supported. Locales are reduced to their language, e.g. "en-GB" to "en".
+func NewNegotiator(fallback string, supported []string) (*Negotiator, error) {
+	n := &Negotiator{}
+	var tags []language.Tag
+	for _, s := range append([]string{fallback}, supported...) {
+		tag, err := language.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse locale %q: %w", s, err)
+		}
+		base, _ := tag.Base()
+		if slices.Contains(n.supported, base.String()) {
+			continue
+		}
+		n.supported = append(n.supported, base.String())
+		tags = append(tags, language.Make(base.String()))
+	}
+	n.matcher = language.NewMatcher(tags)
+	return n, nil
+}
+
+// Default returns the fallback locale.
+func (n *Negotiator) Default() string { return n.supported[0] }
+
+// Negotiate returns the supported locale closest to requested, an explicit choice such as a
+// form field, or else to the Accept-Language header value. Malformed input is ignored.
+func (n *Negotiator) Negotiate(requested, acceptLanguage string) string {
+	var desired []language.Tag
+	if requested != "" {
+		if tag, err := language.Parse(requested); err == nil {
+			desired = append(desired, tag)
+		}
+	}
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+			desired = append(desired, tags...)
+		}
+	}
+	if len(desired) == 0 {
+		return n.Default()
+	}
+
+	_, i, confidence := n.matcher.Match(desired...)
+	if confidence == language.No {
+		return n.Default()
+	}
+	return n.supported[i]
+}
@@ -0,0 +1,39 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	n, err := NewNegotiator("uk", []string{"uk", "en-GB"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		requested      string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "nothing requested", want: "uk"},
+		{name: "form field", requested: "en", acceptLanguage: "uk", want: "en"},
+		{name: "form field with region", requested: "en-US", want: "en"},
+		{name: "unsupported form field falls back to header", requested: "de", acceptLanguage: "en;q=0.8", want: "en"},
+		{name: "header order by quality", acceptLanguage: "de-DE,de;q=0.9,en;q=0.8,uk;q=0.7", want: "en"},
+		{name: "header prefers ukrainian", acceptLanguage: "uk-UA,uk;q=0.9,en-US;q=0.8", want: "uk"},
+		{name: "unsupported only", acceptLanguage: "ja", want: "uk"},
+		{name: "malformed", requested: "!!", acceptLanguage: "en;q=x", want: "uk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, n.Negotiate(tt.requested, tt.acceptLanguage))
+		})
+	}
+}
+
+func TestNewNegotiator_RejectsInvalidLocale(t *testing.T) {
+	_, err := NewNegotiator("uk", []string{"not a locale"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	backlog   prometheus.Gauge
+	oldestAge prometheus.Gauge
+	published *prometheus.CounterVec
+	once      sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		backlog: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "subscription_outbox_backlog_size",
+			Help: "Number of outbox messages waiting to be published",
+		}),
+		oldestAge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "subscription_outbox_oldest_pending_age_seconds",
+			Help: "Age of the oldest pending outbox message",
+		}),
+		published: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_outbox_publish_total",
+				Help: "Outbox publish attempts by result",
+			},
+			[]string{"result"},
+		),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.backlog, m.oldestAge, m.published)
+	})
+}
+
+func (m *Metrics) SetBacklog(pending int64, oldestAge time.Duration) {
+	m.backlog.Set(float64(pending))
+	m.oldestAge.Set(oldestAge.Seconds())
+}
+
+func (m *Metrics) RecordPublished() {
+	m.published.WithLabelValues("sent").Inc()
+}
+
+func (m *Metrics) RecordRetry() {
+	m.published.WithLabelValues("retry").Inc()
+}
+
+func (m *Metrics) RecordDead() {
+	m.published.WithLabelValues("dead").Inc()
+}
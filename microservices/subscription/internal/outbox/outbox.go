@@ -0,0 +1,83 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"subscription/internal/adapter/email/async"
+)
+
+// Message is a command or event waiting in the outbox table to be published to the broker.
+type Message struct {
+	ID        int64
+	MessageID string
+	// Exchange is empty for messages to the relay publisher's own exchange.
+	Exchange   string
+	RoutingKey string
+	Headers    map[string]string
+	Payload    []byte
+	Attempts   int
+	CreatedAt  time.Time
+}
+
+// Stats describes the pending part of the outbox.
+type Stats struct {
+	Pending         int64
+	OldestCreatedAt time.Time
+}
+
+type appender interface {
+	Add(ctx context.Context, msg Message) error
+}
+
+// Writer stores messages in the outbox instead of publishing them. It satisfies async.Publisher,
+// so the email client writes into the same transaction as the subscription change.
+type Writer struct {
+	store appender
+}
+
+func NewWriter(store appender) *Writer {
+	return &Writer{store: store}
+}
+
+func (w *Writer) Publish(ctx context.Context, routingKey string, msg async.IdKeyGetter) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal error: %w", err)
+	}
+
+	stored := Message{
+		MessageID:  msg.GetIdKey(),
+		RoutingKey: routingKey,
+		Payload:    payload,
+	}
+	if e, ok := msg.(async.ExchangeGetter); ok {
+		stored.Exchange = e.GetExchange()
+	}
+	if h, ok := msg.(async.HeadersGetter); ok {
+		stored.Headers = h.GetHeaders()
+	}
+
+	if err := w.store.Add(ctx, stored); err != nil {
+		return fmt.Errorf("outbox write error: %w", err)
+	}
+	return nil
+}
+
+// envelope replays a stored payload through a publisher that marshals its argument.
+type envelope struct {
+	id       string
+	exchange string
+	headers  map[string]string
+	payload  json.RawMessage
+}
+
+func (e envelope) GetIdKey() string { return e.id }
+
+func (e envelope) GetExchange() string { return e.exchange }
+
+func (e envelope) GetHeaders() map[string]string { return e.headers }
+
+func (e envelope) MarshalJSON() ([]byte, error) { return e.payload, nil }
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"subscription/internal/adapter/email/async"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+type store interface {
+	// Claim returns up to limit due messages and hides them from other relays until leaseUntil.
+	Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Message, error)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error
+	MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error
+	Stats(ctx context.Context) (Stats, error)
+	// DeleteSentBefore drops sent messages; their payloads hold addresses and links.
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type relayMetrics interface {
+	SetBacklog(pending int64, oldestAge time.Duration)
+	RecordPublished()
+	RecordRetry()
+	RecordDead()
+}
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+	// Retention is how long sent messages are kept for debugging; zero keeps them forever.
+	Retention time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    50,
+		MaxAttempts:  10,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   5 * time.Minute,
+		Lease:        time.Minute,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// Relay moves pending outbox rows to RabbitMQ. Delivery is at-least-once: a crash between
+// publishing and MarkSent republishes the row, which the email service drops by message id.
+type Relay struct {
+	store     store
+	publisher async.Publisher
+	metrics   relayMetrics
+	cfg       RelayConfig
+	now       func() time.Time
+	lastPurge time.Time
+}
+
+func NewRelay(store store, publisher async.Publisher, metrics relayMetrics, cfg RelayConfig) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		metrics:   metrics,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+func (r *Relay) Start(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			for {
+				n, err := r.ProcessBatch(ctx)
+				if err != nil {
+					logger.Error("Outbox relay batch failed", "err", err)
+					break
+				}
+				if n < r.cfg.BatchSize {
+					break
+				}
+			}
+			r.refreshStats(ctx)
+			r.purgeSent(ctx)
+		}
+	}
+}
+
+const purgeInterval = time.Hour
+
+// purgeSent applies the retention at most once per purgeInterval.
+func (r *Relay) purgeSent(ctx context.Context) {
+	now := r.now()
+	if r.cfg.Retention <= 0 || now.Sub(r.lastPurge) < purgeInterval {
+		return
+	}
+	r.lastPurge = now
+
+	n, err := r.store.DeleteSentBefore(ctx, now.Add(-r.cfg.Retention))
+	if err != nil {
+		loggerPkg.From(ctx).Error("Failed to purge sent outbox messages", "err", err)
+		return
+	}
+	if n > 0 {
+		loggerPkg.From(ctx).Info("Purged sent outbox messages", "count", n)
+	}
+}
+
+// ProcessBatch publishes one batch of due messages and returns how many were claimed.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	now := r.now()
+	msgs, err := r.store.Claim(ctx, r.cfg.BatchSize, now, now.Add(r.cfg.Lease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range msgs {
+		r.publish(ctx, msg)
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) publish(ctx context.Context, msg Message) {
+	logger := loggerPkg.From(ctx)
+
+	pubErr := r.publisher.Publish(ctx, msg.RoutingKey, envelope{
+		id:       msg.MessageID,
+		exchange: msg.Exchange,
+		headers:  msg.Headers,
+		payload:  msg.Payload,
+	})
+	if pubErr == nil {
+		if err := r.store.MarkSent(ctx, msg.ID, r.now()); err != nil {
+			logger.Error("Failed to mark outbox message sent", "id", msg.ID, "err", err)
+		}
+		r.metrics.RecordPublished()
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	if attempts >= r.cfg.MaxAttempts {
+		logger.Error("Outbox message exhausted retries", "id", msg.ID, "routingKey", msg.RoutingKey, "attempts", attempts, "err", pubErr)
+		if err := r.store.MarkDead(ctx, msg.ID, attempts, pubErr.Error()); err != nil {
+			logger.Error("Failed to mark outbox message dead", "id", msg.ID, "err", err)
+		}
+		r.metrics.RecordDead()
+		return
+	}
+
+	next := r.now().Add(r.backoff(attempts))
+	logger.Warn("Outbox publish failed, will retry", "id", msg.ID, "attempts", attempts, "next_attempt_at", next, "err", pubErr)
+	if err := r.store.MarkRetry(ctx, msg.ID, attempts, next, pubErr.Error()); err != nil {
+		logger.Error("Failed to schedule outbox retry", "id", msg.ID, "err", err)
+	}
+	r.metrics.RecordRetry()
+}
+
+// backoff doubles the delay for every failed attempt, capped at MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+func (r *Relay) refreshStats(ctx context.Context) {
+	stats, err := r.store.Stats(ctx)
+	if err != nil {
+		loggerPkg.From(ctx).Error("Failed to read outbox stats", "err", err)
+		return
+	}
+
+	var age time.Duration
+	if stats.Pending > 0 && !stats.OldestCreatedAt.IsZero() {
+		age = r.now().Sub(stats.OldestCreatedAt)
+	}
+	r.metrics.SetBacklog(stats.Pending, age)
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/adapter/email/async"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	pending []Message
+	added   []Message
+	sent    []int64
+	retries map[int64]time.Time
+	dead    []int64
+	purges  []time.Time
+}
+
+func newFakeStore(msgs ...Message) *fakeStore {
+	return &fakeStore{pending: msgs, retries: map[int64]time.Time{}}
+}
+
+func (s *fakeStore) Add(_ context.Context, msg Message) error {
+	s.added = append(s.added, msg)
+	return nil
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _, _ time.Time) ([]Message, error) {
+	if limit > len(s.pending) {
+		limit = len(s.pending)
+	}
+	claimed := s.pending[:limit]
+	s.pending = s.pending[limit:]
+	return claimed, nil
+}
+
+func (s *fakeStore) MarkSent(_ context.Context, id int64, _ time.Time) error {
+	s.sent = append(s.sent, id)
+	return nil
+}
+
+func (s *fakeStore) MarkRetry(_ context.Context, id int64, _ int, next time.Time, _ string) error {
+	s.retries[id] = next
+	return nil
+}
+
+func (s *fakeStore) MarkDead(_ context.Context, id int64, _ int, _ string) error {
+	s.dead = append(s.dead, id)
+	return nil
+}
+
+func (s *fakeStore) Stats(context.Context) (Stats, error) {
+	return Stats{Pending: int64(len(s.pending))}, nil
+}
+
+func (s *fakeStore) DeleteSentBefore(_ context.Context, before time.Time) (int64, error) {
+	s.purges = append(s.purges, before)
+	return 0, nil
+}
+
+type fakePublisher struct {
+	err       error
+	published map[string][]byte
+	msgs      []async.IdKeyGetter
+}
+
+func (p *fakePublisher) Publish(_ context.Context, routingKey string, msg async.IdKeyGetter) error {
+	if p.err != nil {
+		return p.err
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if p.published == nil {
+		p.published = map[string][]byte{}
+	}
+	p.published[routingKey+"/"+msg.GetIdKey()] = body
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) SetBacklog(int64, time.Duration) {}
+func (noopMetrics) RecordPublished()                {}
+func (noopMetrics) RecordRetry()                    {}
+func (noopMetrics) RecordDead()                     {}
+
+func newTestRelay(store *fakeStore, pub *fakePublisher) *Relay {
+	relay := NewRelay(store, pub, noopMetrics{}, DefaultRelayConfig())
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	relay.now = func() time.Time { return now }
+	return relay
+}
+
+func TestWriter_StoresMarshalledMessage(t *testing.T) {
+	store := newFakeStore()
+	writer := NewWriter(store)
+
+	msg := async.EmailMessage{IdKey: "key-1", To: "a@b.c", Template: "confirmation"}
+	require.NoError(t, writer.Publish(context.Background(), "email.confirmation", msg))
+
+	require.Len(t, store.added, 1)
+	assert.Equal(t, "key-1", store.added[0].MessageID)
+	assert.Equal(t, "email.confirmation", store.added[0].RoutingKey)
+	assert.JSONEq(t, `{"correlation_id":"","to":"a@b.c","template":"confirmation","data":null}`, string(store.added[0].Payload))
+}
+
+type routedMessage struct {
+	async.EmailMessage
+}
+
+func (routedMessage) GetExchange() string { return "subscription.events" }
+
+func (routedMessage) GetHeaders() map[string]string {
+	return map[string]string{"x-correlation-id": "corr-1"}
+}
+
+func TestWriter_StoresExchangeAndHeaders(t *testing.T) {
+	store := newFakeStore()
+	writer := NewWriter(store)
+
+	msg := routedMessage{async.EmailMessage{IdKey: "evt-1"}}
+	require.NoError(t, writer.Publish(context.Background(), "subscription.created.v1", msg))
+
+	require.Len(t, store.added, 1)
+	assert.Equal(t, "subscription.events", store.added[0].Exchange)
+	assert.Equal(t, map[string]string{"x-correlation-id": "corr-1"}, store.added[0].Headers)
+}
+
+func TestRelay_ForwardsExchangeAndHeaders(t *testing.T) {
+	headers := map[string]string{"x-correlation-id": "corr-1"}
+	store := newFakeStore(Message{
+		ID:         1,
+		MessageID:  "evt-1",
+		Exchange:   "subscription.events",
+		RoutingKey: "subscription.created.v1",
+		Headers:    headers,
+		Payload:    []byte(`{"event_id":"evt-1"}`),
+	})
+	pub := &fakePublisher{}
+
+	_, err := newTestRelay(store, pub).ProcessBatch(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, pub.msgs, 1)
+	assert.Equal(t, "subscription.events", pub.msgs[0].(async.ExchangeGetter).GetExchange())
+	assert.Equal(t, headers, pub.msgs[0].(async.HeadersGetter).GetHeaders())
+}
+
+func TestRelay_PublishesStoredPayloadAndMarksSent(t *testing.T) {
+	payload := []byte(`{"to":"a@b.c"}`)
+	store := newFakeStore(Message{ID: 1, MessageID: "key-1", RoutingKey: "email.confirmation", Payload: payload})
+	pub := &fakePublisher{}
+
+	n, err := newTestRelay(store, pub).ProcessBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []int64{1}, store.sent)
+	assert.JSONEq(t, string(payload), string(pub.published["email.confirmation/key-1"]))
+}
+
+func TestRelay_PublishFails_SchedulesRetryWithBackoff(t *testing.T) {
+	store := newFakeStore(Message{ID: 7, Attempts: 2, Payload: []byte(`{}`)})
+	relay := newTestRelay(store, &fakePublisher{err: errors.New("broker down")})
+
+	_, err := relay.ProcessBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, store.sent)
+	assert.Equal(t, relay.now().Add(20*time.Second), store.retries[7])
+}
+
+func TestRelay_PublishFails_MarksDeadAfterMaxAttempts(t *testing.T) {
+	cfg := DefaultRelayConfig()
+	store := newFakeStore(Message{ID: 3, Attempts: cfg.MaxAttempts - 1, Payload: []byte(`{}`)})
+	relay := newTestRelay(store, &fakePublisher{err: errors.New("broker down")})
+
+	_, err := relay.ProcessBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, store.dead)
+	assert.Empty(t, store.retries)
+}
+
+func TestRelay_BackoffIsCapped(t *testing.T) {
+	relay := newTestRelay(newFakeStore(), &fakePublisher{})
+
+	assert.Equal(t, 5*time.Second, relay.backoff(1))
+	assert.Equal(t, 10*time.Second, relay.backoff(2))
+	assert.Equal(t, 5*time.Minute, relay.backoff(20))
+}
+
+func TestRelay_PurgesSentMessagesOncePerInterval(t *testing.T) {
+	store := newFakeStore()
+	relay := newTestRelay(store, &fakePublisher{})
+	now := relay.now()
+
+	relay.purgeSent(context.Background())
+	relay.purgeSent(context.Background())
+
+	require.Len(t, store.purges, 1)
+	assert.Equal(t, now.Add(-DefaultRelayConfig().Retention), store.purges[0])
+
+	relay.now = func() time.Time { return now.Add(purgeInterval) }
+	relay.purgeSent(context.Background())
+	assert.Len(t, store.purges, 2)
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+var ErrRateLimited = errors.New("rate limited")
+
+// Reasons a subscribe attempt was rejected; also used as metric labels.
+const (
+	ReasonIP       = "ip"
+	ReasonEmail    = "email"
+	ReasonCooldown = "cooldown"
+)
+
+// LimitError tells the caller which limit was hit and when to retry.
+type LimitError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("rate limited by %s, retry after %s", e.Reason, e.RetryAfter)
+}
+
+func (e *LimitError) Unwrap() error { return ErrRateLimited }
+
+type store interface {
+	// Incr counts a hit in the fixed window starting with the first hit and returns the
+	// count so far and the time left in the window.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	// TTL returns how long the key lives on, zero when it does not exist.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Set(ctx context.Context, key string, ttl time.Duration) error
+}
+
+type guardMetrics interface {
+	RecordRejected(reason string)
+}
+
+type Config struct {
+	IPLimit     int64
+	IPWindow    time.Duration
+	EmailLimit  int64
+	EmailWindow time.Duration
+	// Cooldown is the minimum gap between two confirmation emails for the same address and city.
+	Cooldown time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		IPLimit:     10,
+		IPWindow:    time.Hour,
+		EmailLimit:  5,
+		EmailWindow: 24 * time.Hour,
+		Cooldown:    5 * time.Minute,
+	}
+}
+
+// Guard throttles subscribe attempts so the endpoint cannot be used to flood inboxes.
+// It fails open: when the store is unavailable, requests are let through and logged.
+type Guard struct {
+	store   store
+	metrics guardMetrics
+	cfg     Config
+}
+
+func NewGuard(store store, metrics guardMetrics, cfg Config) *Guard {
+	return &Guard{store: store, metrics: metrics, cfg: cfg}
+}
+
+// Check counts the attempt against the IP and email limits and rejects it with a *LimitError
+// while one is exceeded or the address is still in its confirmation cooldown for the city.
+func (g *Guard) Check(ctx context.Context, ip, email, city string) error {
+	addr := addressKey(email)
+
+	if err := g.count(ctx, "ratelimit:subscribe:ip:"+ip, g.cfg.IPLimit, g.cfg.IPWindow, ReasonIP); err != nil {
+		return err
+	}
+	if err := g.count(ctx, "ratelimit:subscribe:email:"+addr, g.cfg.EmailLimit, g.cfg.EmailWindow, ReasonEmail); err != nil {
+		return err
+	}
+
+	ttl, err := g.store.TTL(ctx, cooldownKey(email, city))
+	if err != nil {
+		loggerPkg.From(ctx).Warn("Confirmation cooldown check failed, allowing request", "err", err)
+		return nil
+	}
+	if ttl > 0 {
+		return g.reject(ReasonCooldown, ttl)
+	}
+	return nil
+}
+
+// ConfirmationSent starts the cooldown for the address and city; an address can follow several
+// cities, so confirming another one is not held back.
+func (g *Guard) ConfirmationSent(ctx context.Context, email, city string) {
+	if g.cfg.Cooldown <= 0 {
+		return
+	}
+	if err := g.store.Set(ctx, cooldownKey(email, city), g.cfg.Cooldown); err != nil {
+		loggerPkg.From(ctx).Warn("Failed to start confirmation cooldown", "err", err)
+	}
+}
+
+func (g *Guard) count(ctx context.Context, key string, limit int64, window time.Duration, reason string) error {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	n, ttl, err := g.store.Incr(ctx, key, window)
+	if err != nil {
+		loggerPkg.From(ctx).Warn("Rate limit check failed, allowing request", "reason", reason, "err", err)
+		return nil
+	}
+	if n > limit {
+		return g.reject(reason, ttl)
+	}
+	return nil
+}
+
+func (g *Guard) reject(reason string, retryAfter time.Duration) error {
+	g.metrics.RecordRejected(reason)
+	return &LimitError{Reason: reason, RetryAfter: retryAfter}
+}
+
+func cooldownKey(email, city string) string {
+	return "cooldown:confirmation:" + addressKey(email) + ":" + strings.ToLower(strings.TrimSpace(city))
+}
+
+// addressKey keeps raw addresses out of Redis.
+func addressKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
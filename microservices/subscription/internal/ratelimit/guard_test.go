@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	counts map[string]int64
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{counts: map[string]int64{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *fakeStore) Incr(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	s.counts[key]++
+	if _, ok := s.ttls[key]; !ok {
+		s.ttls[key] = window
+	}
+	return s.counts[key], s.ttls[key], nil
+}
+
+func (s *fakeStore) TTL(_ context.Context, key string) (time.Duration, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.ttls[key], nil
+}
+
+func (s *fakeStore) Set(_ context.Context, key string, ttl time.Duration) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.ttls[key] = ttl
+	return nil
+}
+
+type countingMetrics struct {
+	rejected map[string]int
+}
+
+func (m *countingMetrics) RecordRejected(reason string) { m.rejected[reason]++ }
+
+func newTestGuard(store *fakeStore, cfg Config) (*Guard, *countingMetrics) {
+	metrics := &countingMetrics{rejected: map[string]int{}}
+	return NewGuard(store, metrics, cfg), metrics
+}
+
+func assertLimited(t *testing.T, err error, reason string, retryAfter time.Duration) {
+	t.Helper()
+	require.ErrorIs(t, err, ErrRateLimited)
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, reason, limitErr.Reason)
+	assert.Equal(t, retryAfter, limitErr.RetryAfter)
+}
+
+func TestGuard_IPLimit(t *testing.T) {
+	cfg := Config{IPLimit: 2, IPWindow: time.Hour}
+	guard, metrics := newTestGuard(newFakeStore(), cfg)
+	ctx := context.Background()
+
+	require.NoError(t, guard.Check(ctx, "1.2.3.4", "a@example.com", "Kyiv"))
+	require.NoError(t, guard.Check(ctx, "1.2.3.4", "b@example.com", "Kyiv"))
+	assertLimited(t, guard.Check(ctx, "1.2.3.4", "c@example.com", "Kyiv"), ReasonIP, time.Hour)
+
+	require.NoError(t, guard.Check(ctx, "5.6.7.8", "c@example.com", "Kyiv"), "other clients are unaffected")
+	assert.Equal(t, 1, metrics.rejected[ReasonIP])
+}
+
+func TestGuard_EmailLimitIgnoresCaseAndSpaces(t *testing.T) {
+	cfg := Config{EmailLimit: 1, EmailWindow: 24 * time.Hour}
+	guard, _ := newTestGuard(newFakeStore(), cfg)
+	ctx := context.Background()
+
+	require.NoError(t, guard.Check(ctx, "1.1.1.1", "a@example.com", "Kyiv"))
+	assertLimited(t, guard.Check(ctx, "2.2.2.2", " A@Example.com ", "Kyiv"), ReasonEmail, 24*time.Hour)
+}
+
+func TestGuard_ConfirmationCooldown(t *testing.T) {
+	store := newFakeStore()
+	guard, metrics := newTestGuard(store, Config{Cooldown: 5 * time.Minute})
+	ctx := context.Background()
+
+	require.NoError(t, guard.Check(ctx, "1.1.1.1", "a@example.com", "Kyiv"))
+	guard.ConfirmationSent(ctx, "a@example.com", "Kyiv")
+
+	assertLimited(t, guard.Check(ctx, "1.1.1.1", "a@example.com", " kyiv"), ReasonCooldown, 5*time.Minute)
+	require.NoError(t, guard.Check(ctx, "1.1.1.1", "b@example.com", "Kyiv"))
+	require.NoError(t, guard.Check(ctx, "1.1.1.1", "a@example.com", "Lviv"), "another city of the address is not held back")
+	assert.Equal(t, 1, metrics.rejected[ReasonCooldown])
+
+	for key := range store.ttls {
+		assert.NotContains(t, key, "a@example.com", "addresses are hashed in keys")
+	}
+}
+
+func TestGuard_FailsOpen(t *testing.T) {
+	store := newFakeStore()
+	store.err = errors.New("redis down")
+	guard, _ := newTestGuard(store, DefaultConfig())
+
+	assert.NoError(t, guard.Check(context.Background(), "1.1.1.1", "a@example.com", "Kyiv"))
+	guard.ConfirmationSent(context.Background(), "a@example.com", "Kyiv")
+}
@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	rejected *prometheus.CounterVec
+	once     sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		rejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "subscription_subscribe_rejected_total",
+				Help: "Subscribe attempts rejected by abuse protection, by reason",
+			},
+			[]string{"reason"},
+		),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.rejected)
+	})
+}
+
+func (m *Metrics) RecordRejected(reason string) {
+	m.rejected.WithLabelValues(reason).Inc()
+}
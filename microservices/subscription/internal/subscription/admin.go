@@ -0,0 +1,281 @@
+package subscription
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+var (
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrInvalidStatus        = errors.New("invalid status")
+	ErrAlreadyConfirmed     = errors.New("subscription already confirmed")
+	ErrSubscriptionInactive = errors.New("subscription is unsubscribed")
+)
+
+const exportBatchSize = 500
+
+// SearchFilter narrows the admin subscription search; zero fields match everything.
+// Email matches case-insensitively as a substring, City case-insensitively as a whole.
+type SearchFilter struct {
+	Email     string
+	City      string
+	Frequency domain.Frequency
+	Status    domain.SubscriptionStatus
+}
+
+// Cursor is the keyset position after the last subscription of a page,
+// in the search order of newest first.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type SearchResult struct {
+	Subscriptions []domain.Subscription
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+// CityCount is the number of active subscriptions for a city and frequency.
+type CityCount struct {
+	City      string
+	Frequency domain.Frequency
+	Count     int64
+}
+
+type Stats struct {
+	Total        int64
+	Pending      int64
+	Active       int64
+	Unsubscribed int64
+	// Confirmed counts every subscription that was ever confirmed, including later unsubscribes.
+	Confirmed   int64
+	ByFrequency map[domain.Frequency]int64
+	ByCity      []CityCount
+}
+
+// ConversionRate is the share of subscriptions that completed email confirmation.
+func (s Stats) ConversionRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Confirmed) / float64(s.Total)
+}
+
+type adminRepo interface {
+	GetByID(ctx context.Context, id string) (*domain.Subscription, error)
+	Update(ctx context.Context, sub *domain.Subscription) error
+	Search(ctx context.Context, filter SearchFilter, after *Cursor, limit int) ([]domain.Subscription, error)
+	Stats(ctx context.Context) (Stats, error)
+}
+
+type confirmationSender interface {
+	SendConfirmationEmail(ctx context.Context, email, locale, token string, idKey string) error
+}
+
+type confirmTokenGenerator interface {
+	Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error)
+}
+
+// AdminService backs the operations API; it bypasses the token checks of the public flows.
+// State changes announce the same lifecycle events as the public flows.
+type AdminService struct {
+	repo         adminRepo
+	emailService confirmationSender
+	tokenService confirmTokenGenerator
+	tx           transactor
+	events       lifecycleEvents
+}
+
+func NewAdminService(
+	repo adminRepo,
+	emailService confirmationSender,
+	tokenService confirmTokenGenerator,
+	tx transactor,
+	events lifecycleEvents,
+) AdminService {
+	return AdminService{
+		repo:         repo,
+		emailService: emailService,
+		tokenService: tokenService,
+		tx:           tx,
+		events:       events,
+	}
+}
+
+func (s AdminService) Search(ctx context.Context, filter SearchFilter, cursor string, limit int) (SearchResult, error) {
+	if filter.Frequency != "" && !filter.Frequency.Valid() {
+		return SearchResult{}, ErrInvalidFrequency
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return SearchResult{}, ErrInvalidStatus
+	}
+	filter.Email = strings.TrimSpace(filter.Email)
+	filter.City = strings.TrimSpace(filter.City)
+
+	var after *Cursor
+	if cursor != "" {
+		c, err := DecodeCursor(cursor)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		after = c
+	}
+
+	// One extra row tells whether another page follows.
+	subs, err := s.repo.Search(ctx, filter, after, limit+1)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("failed to search subscriptions: %w", err)
+	}
+
+	res := SearchResult{Subscriptions: subs}
+	if len(subs) > limit {
+		res.Subscriptions = subs[:limit]
+		last := res.Subscriptions[limit-1]
+		res.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return res, nil
+}
+
+// Export walks every subscription matching the filter in search order and passes it to fn.
+func (s AdminService) Export(ctx context.Context, filter SearchFilter, fn func(domain.Subscription) error) error {
+	cursor := ""
+	for {
+		page, err := s.Search(ctx, filter, cursor, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, sub := range page.Subscriptions {
+			if err := fn(sub); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func (s AdminService) Get(ctx context.Context, id string) (*domain.Subscription, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// ForceConfirm confirms a subscription whose owner cannot complete the email flow.
+func (s AdminService) ForceConfirm(ctx context.Context, id string) (*domain.Subscription, error) {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.IsUnsubscribed {
+		return nil, ErrSubscriptionInactive
+	}
+	if sub.IsConfirmed {
+		return sub, nil
+	}
+
+	sub.IsConfirmed = true
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to confirm subscription: %w", err)
+		}
+		return s.events.Confirmed(ctx, *sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loggerPkg.From(ctx).Info("Subscription force-confirmed by admin", "subscription_id", id)
+	return sub, nil
+}
+
+// ResendConfirmation emails a fresh confirmation link to a pending subscription.
+func (s AdminService) ResendConfirmation(ctx context.Context, id string) error {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch sub.Status() {
+	case domain.StatusActive:
+		return ErrAlreadyConfirmed
+	case domain.StatusUnsubscribed:
+		return ErrSubscriptionInactive
+	}
+
+	token, err := s.tokenService.Generate(sub.ID, domain.TokenPurposeConfirm)
+	if err != nil {
+		return fmt.Errorf("could not generate token: %w", err)
+	}
+	if err := s.emailService.SendConfirmationEmail(ctx, sub.Email, sub.Locale, token, confirmIdempotencyKey(sub.Email, token)); err != nil {
+		return fmt.Errorf("failed to enqueue confirmation email: %w", err)
+	}
+
+	loggerPkg.From(ctx).Info("Confirmation resent by admin", "subscription_id", id)
+	return nil
+}
+
+// Deactivate stops deliveries to a subscription as if its owner had unsubscribed.
+func (s AdminService) Deactivate(ctx context.Context, id string) (*domain.Subscription, error) {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.IsUnsubscribed {
+		return sub, nil
+	}
+
+	sub.IsUnsubscribed = true
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to deactivate subscription: %w", err)
+		}
+		return s.events.Unsubscribed(ctx, *sub, UnsubscribeReasonAdmin)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loggerPkg.From(ctx).Info("Subscription deactivated by admin", "subscription_id", id)
+	return sub, nil
+}
+
+func (s AdminService) Stats(ctx context.Context) (Stats, error) {
+	stats, err := s.repo.Stats(ctx)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to load subscription stats: %w", err)
+	}
+	return stats, nil
+}
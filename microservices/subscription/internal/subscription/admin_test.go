@@ -0,0 +1,216 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAdminRepo struct{ mock.Mock }
+
+func (m *mockAdminRepo) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	args := m.Called(id)
+	sub, _ := args.Get(0).(*domain.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *mockAdminRepo) Update(ctx context.Context, sub *domain.Subscription) error {
+	return m.Called(sub).Error(0)
+}
+
+func (m *mockAdminRepo) Search(ctx context.Context, filter subscription.SearchFilter, after *subscription.Cursor, limit int) ([]domain.Subscription, error) {
+	args := m.Called(filter, after, limit)
+	subs, _ := args.Get(0).([]domain.Subscription)
+	return subs, args.Error(1)
+}
+
+func (m *mockAdminRepo) Stats(ctx context.Context) (subscription.Stats, error) {
+	args := m.Called()
+	return args.Get(0).(subscription.Stats), args.Error(1)
+}
+
+func newAdminService() (subscription.AdminService, *mockAdminRepo, *mockEmailService, *mockTokenService, *fakeEvents) {
+	repo := new(mockAdminRepo)
+	emails := new(mockEmailService)
+	tokens := new(mockTokenService)
+	events := &fakeEvents{}
+	return subscription.NewAdminService(repo, emails, tokens, fakeTransactor{}, events), repo, emails, tokens, events
+}
+
+func testSubs(n int) []domain.Subscription {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	subs := make([]domain.Subscription, 0, n)
+	for i := 0; i < n; i++ {
+		subs = append(subs, domain.Subscription{ID: fmt.Sprintf("sub-%d", i), CreatedAt: base.Add(-time.Duration(i) * time.Minute)})
+	}
+	return subs
+}
+
+func TestAdminSearch_ReturnsCursorWhenMorePagesFollow(t *testing.T) {
+	svc, repo, _, _, _ := newAdminService()
+	filter := subscription.SearchFilter{City: "Kyiv", Status: domain.StatusActive}
+	repo.On("Search", filter, (*subscription.Cursor)(nil), 3).Return(testSubs(3), nil).Once()
+
+	res, err := svc.Search(context.Background(), subscription.SearchFilter{City: " Kyiv ", Status: domain.StatusActive}, "", 2)
+
+	require.NoError(t, err)
+	assert.Len(t, res.Subscriptions, 2)
+	require.NotEmpty(t, res.NextCursor)
+
+	cursor, err := subscription.DecodeCursor(res.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", cursor.ID)
+	assert.True(t, cursor.CreatedAt.Equal(res.Subscriptions[1].CreatedAt))
+
+	repo.On("Search", filter, cursor, 3).Return(testSubs(1), nil).Once()
+	res, err = svc.Search(context.Background(), filter, res.NextCursor, 2)
+
+	require.NoError(t, err)
+	assert.Len(t, res.Subscriptions, 1)
+	assert.Empty(t, res.NextCursor)
+}
+
+func TestAdminSearch_RejectsInvalidInput(t *testing.T) {
+	svc, repo, _, _, _ := newAdminService()
+	ctx := context.Background()
+
+	_, err := svc.Search(ctx, subscription.SearchFilter{Frequency: "weekly"}, "", 10)
+	assert.ErrorIs(t, err, subscription.ErrInvalidFrequency)
+
+	_, err = svc.Search(ctx, subscription.SearchFilter{Status: "deleted"}, "", 10)
+	assert.ErrorIs(t, err, subscription.ErrInvalidStatus)
+
+	_, err = svc.Search(ctx, subscription.SearchFilter{}, "not-a-cursor!", 10)
+	assert.ErrorIs(t, err, subscription.ErrInvalidCursor)
+
+	repo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminExport_WalksAllPages(t *testing.T) {
+	svc, repo, _, _, _ := newAdminService()
+	first := testSubs(501)
+	repo.On("Search", subscription.SearchFilter{}, (*subscription.Cursor)(nil), 501).Return(first, nil).Once()
+	repo.On("Search", subscription.SearchFilter{}, mock.AnythingOfType("*subscription.Cursor"), 501).Return(first[500:], nil).Once()
+
+	var ids []string
+	err := svc.Export(context.Background(), subscription.SearchFilter{}, func(sub domain.Subscription) error {
+		ids = append(ids, sub.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, ids, 501)
+	repo.AssertExpectations(t)
+}
+
+func TestAdminForceConfirm(t *testing.T) {
+	t.Run("ConfirmsPending", func(t *testing.T) {
+		svc, repo, _, _, events := newAdminService()
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1"}, nil)
+		repo.On("Update", mock.MatchedBy(func(s *domain.Subscription) bool { return s.IsConfirmed })).Return(nil).Once()
+
+		sub, err := svc.ForceConfirm(context.Background(), "sub-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusActive, sub.Status())
+		repo.AssertExpectations(t)
+		if assert.Len(t, events.events, 1) {
+			assert.Equal(t, "confirmed", events.events[0].Type)
+		}
+	})
+
+	t.Run("EventFails", func(t *testing.T) {
+		svc, repo, _, _, events := newAdminService()
+		events.err = errors.New("outbox down")
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1"}, nil)
+		repo.On("Update", mock.Anything).Return(nil)
+
+		_, err := svc.ForceConfirm(context.Background(), "sub-1")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("RejectsUnsubscribed", func(t *testing.T) {
+		svc, repo, _, _, _ := newAdminService()
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1", IsUnsubscribed: true}, nil)
+
+		_, err := svc.ForceConfirm(context.Background(), "sub-1")
+
+		assert.ErrorIs(t, err, subscription.ErrSubscriptionInactive)
+		repo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		svc, repo, _, _, _ := newAdminService()
+		repo.On("GetByID", "missing").Return(nil, subscription.ErrSubscriptionNotFound)
+
+		_, err := svc.ForceConfirm(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+	})
+}
+
+func TestAdminResendConfirmation(t *testing.T) {
+	t.Run("SendsFreshLink", func(t *testing.T) {
+		svc, repo, emails, tokens, _ := newAdminService()
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1", Email: "a@example.com", Locale: "en"}, nil)
+		tokens.On("Generate", "sub-1", domain.TokenPurposeConfirm).Return("confirm-token", nil)
+		emails.On("SendConfirmationEmail", "a@example.com", "en", "confirm-token").Return(nil).Once()
+
+		err := svc.ResendConfirmation(context.Background(), "sub-1")
+
+		require.NoError(t, err)
+		emails.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyConfirmed", func(t *testing.T) {
+		svc, repo, emails, _, _ := newAdminService()
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1", IsConfirmed: true}, nil)
+
+		err := svc.ResendConfirmation(context.Background(), "sub-1")
+
+		assert.ErrorIs(t, err, subscription.ErrAlreadyConfirmed)
+		emails.AssertNotCalled(t, "SendConfirmationEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("EnqueueFails", func(t *testing.T) {
+		svc, repo, emails, tokens, _ := newAdminService()
+		repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1", Email: "a@example.com"}, nil)
+		tokens.On("Generate", "sub-1", domain.TokenPurposeConfirm).Return("confirm-token", nil)
+		emails.On("SendConfirmationEmail", "a@example.com", "", "confirm-token").Return(errors.New("outbox down"))
+
+		err := svc.ResendConfirmation(context.Background(), "sub-1")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAdminDeactivate(t *testing.T) {
+	svc, repo, _, _, events := newAdminService()
+	repo.On("GetByID", "sub-1").Return(&domain.Subscription{ID: "sub-1", IsConfirmed: true}, nil)
+	repo.On("Update", mock.MatchedBy(func(s *domain.Subscription) bool { return s.IsUnsubscribed })).Return(nil).Once()
+
+	sub, err := svc.Deactivate(context.Background(), "sub-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusUnsubscribed, sub.Status())
+	repo.AssertExpectations(t)
+	if assert.Len(t, events.events, 1) {
+		assert.Equal(t, "unsubscribed", events.events[0].Type)
+		assert.Equal(t, subscription.UnsubscribeReasonAdmin, events.events[0].Reason)
+	}
+}
+
+func TestStats_ConversionRate(t *testing.T) {
+	assert.InDelta(t, 0.75, subscription.Stats{Total: 4, Confirmed: 3}.ConversionRate(), 1e-9)
+	assert.Zero(t, subscription.Stats{}.ConversionRate())
+}
@@ -0,0 +1,99 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"subscription/internal/domain"
+	"subscription/internal/job"
+)
+
+var (
+	ErrInvalidWebhookURL = errors.New("invalid webhook url")
+	ErrWebhooksDisabled  = errors.New("webhook channel is not enabled")
+	ErrNotWebhookChannel = errors.New("subscription does not use the webhook channel")
+)
+
+// notifier hands a weather report to one notification channel. It is called inside the report
+// transaction, so implementations only enqueue and the report goes out once that commits.
+type notifier interface {
+	SendWeatherReport(ctx context.Context, r job.Recipient, city string, report domain.Report, idKey string) error
+}
+
+// webhookEndpoints keeps the URLs reports of webhook subscriptions are posted to.
+type webhookEndpoints interface {
+	ValidateURL(raw string) (string, error)
+	// Register stores the URL with a new signing secret, returns the secret and schedules the
+	// verification handshake.
+	Register(ctx context.Context, subscriptionID, url string) (string, error)
+	// Reverify schedules a new handshake and re-enables an endpoint disabled after failures.
+	Reverify(ctx context.Context, subscriptionID string) error
+}
+
+// emailNotifier is the email channel, backed by the email service.
+type emailNotifier struct {
+	client emailClient
+}
+
+func (n emailNotifier) SendWeatherReport(ctx context.Context, r job.Recipient, city string, report domain.Report, idKey string) error {
+	return n.client.SendWeatherReport(ctx, r.Email, r.Locale, report, city, r.Token, idKey)
+}
+
+// notifierFor picks the channel of a recipient; tasks queued before channels existed carry none
+// and go by email.
+func (s Service) notifierFor(channel string) (notifier, error) {
+	switch domain.Channel(channel) {
+	case "", domain.ChannelEmail:
+		return s.email, nil
+	case domain.ChannelWebhook:
+		if s.webhook == nil {
+			return nil, ErrWebhooksDisabled
+		}
+		return s.webhook, nil
+	default:
+		return nil, fmt.Errorf("unknown channel %q", channel)
+	}
+}
+
+// SubscribeWebhook subscribes like Subscribe, but reports are posted to webhookURL instead of
+// emailed. The address still receives the confirmation email. The returned secret signs every
+// webhook request and is not shown again; reports start once the URL passed its handshake.
+func (s Service) SubscribeWebhook(ctx context.Context, email, city string, frequency domain.Frequency, locale, webhookURL string) (string, error) {
+	if s.webhooks == nil {
+		return "", ErrWebhooksDisabled
+	}
+	url, err := s.webhooks.ValidateURL(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidWebhookURL, err)
+	}
+
+	var secret string
+	err = s.subscribe(ctx, email, city, frequency, locale, domain.ChannelWebhook, func(ctx context.Context, sub *domain.Subscription) error {
+		var err error
+		if secret, err = s.webhooks.Register(ctx, sub.ID, url); err != nil {
+			return fmt.Errorf("failed to register webhook: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ReverifyWebhook starts a new handshake for an owned webhook subscription, e.g. after its
+// endpoint was disabled or failed the first one.
+func (s Service) ReverifyWebhook(ctx context.Context, token, id string) error {
+	if s.webhooks == nil {
+		return ErrWebhooksDisabled
+	}
+	sub, err := s.ownedSubscription(ctx, token, id)
+	if err != nil {
+		return err
+	}
+	if sub.Channel != domain.ChannelWebhook {
+		return ErrNotWebhookChannel
+	}
+	return s.webhooks.Reverify(ctx, sub.ID)
+}
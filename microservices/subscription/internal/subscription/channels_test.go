@@ -0,0 +1,174 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/job"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookEndpoints struct {
+	invalid    bool
+	registered map[string]string
+	reverified []string
+}
+
+func (f *fakeWebhookEndpoints) ValidateURL(raw string) (string, error) {
+	if f.invalid {
+		return "", errors.New("scheme must be https")
+	}
+	return raw, nil
+}
+
+func (f *fakeWebhookEndpoints) Register(_ context.Context, subscriptionID, url string) (string, error) {
+	if f.registered == nil {
+		f.registered = map[string]string{}
+	}
+	f.registered[subscriptionID] = url
+	return "whsec_test", nil
+}
+
+func (f *fakeWebhookEndpoints) Reverify(_ context.Context, subscriptionID string) error {
+	f.reverified = append(f.reverified, subscriptionID)
+	return nil
+}
+
+type fakeNotifier struct {
+	sent []job.Recipient
+}
+
+func (f *fakeNotifier) SendWeatherReport(_ context.Context, r job.Recipient, _ string, _ domain.Report, _ string) error {
+	f.sent = append(f.sent, r)
+	return nil
+}
+
+func withWebhooks(d *testDeps) (*fakeWebhookEndpoints, *fakeNotifier) {
+	endpoints := &fakeWebhookEndpoints{}
+	channel := &fakeNotifier{}
+	d.service = subscription.NewService(d.repo, d.emails, d.validator, d.tokens, fakeTransactor{}, d.deliveries, d.events, endpoints, channel)
+	return endpoints, channel
+}
+
+func TestSubscribeWebhook_RegistersEndpoint(t *testing.T) {
+	d := createTestService()
+	endpoints, _ := withWebhooks(d)
+	ctx := context.Background()
+	email := "hooks@example.com"
+
+	d.validator.On("CityIsValid", ctx, "Kyiv").Return(true, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, "Kyiv").Return(nil, subscription.ErrSubscriptionNotFound)
+	d.expectTokens("confirm-token")
+	d.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil)
+	d.emails.On("SendConfirmationEmail", email, "en", "confirm-token").Return(nil).Once()
+
+	secret, err := d.service.SubscribeWebhook(ctx, email, "Kyiv", domain.FreqHourly, "en", "https://hooks.example.com/weather")
+
+	require.NoError(t, err)
+	assert.Equal(t, "whsec_test", secret)
+	created := d.repo.Calls[1].Arguments.Get(1).(*domain.Subscription)
+	assert.Equal(t, domain.ChannelWebhook, created.Channel)
+	assert.Equal(t, map[string]string{created.ID: "https://hooks.example.com/weather"}, endpoints.registered)
+	d.emails.AssertExpectations(t)
+}
+
+func TestSubscribeWebhook_InvalidURL(t *testing.T) {
+	d := createTestService()
+	endpoints, _ := withWebhooks(d)
+	endpoints.invalid = true
+
+	_, err := d.service.SubscribeWebhook(context.Background(), "hooks@example.com", "Kyiv", domain.FreqDaily, "en", "ftp://example.com")
+
+	assert.ErrorIs(t, err, subscription.ErrInvalidWebhookURL)
+	d.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSubscribeWebhook_NotEnabled(t *testing.T) {
+	d := createTestService()
+
+	_, err := d.service.SubscribeWebhook(context.Background(), "hooks@example.com", "Kyiv", domain.FreqDaily, "en", "https://hooks.example.com")
+
+	assert.ErrorIs(t, err, subscription.ErrWebhooksDisabled)
+}
+
+func TestProcessWeatherReportTask_RoutesRecipientsByChannel(t *testing.T) {
+	d := createTestService()
+	_, webhooks := withWebhooks(d)
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{
+		{SubscriptionID: "sub-a", Email: "a@example.com", Token: "token1"},
+		{SubscriptionID: "sub-b", Email: "b@example.com", Token: "token2", Channel: "webhook"},
+	}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(report, nil).Once()
+	d.emails.On("SendWeatherReport", "a@example.com", "", report, "Kyiv", "token1").Return(nil).Once()
+	d.deliveries.On("Record", mock.Anything).Return(nil).Twice()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	require.NoError(t, err)
+	d.emails.AssertExpectations(t)
+	d.deliveries.AssertExpectations(t)
+	require.Len(t, webhooks.sent, 1)
+	assert.Equal(t, "sub-b", webhooks.sent[0].SubscriptionID)
+}
+
+func TestProcessWeatherReportTask_WebhookRecipientWithoutChannel_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{{Email: "a@example.com", Channel: "webhook"}}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(domain.Report{}, nil).Once()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.ErrorIs(t, err, subscription.ErrWebhooksDisabled)
+}
+
+func TestGenerateWeatherReportTasks_CarriesChannel(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	subs := []domain.Subscription{
+		{ID: "1", Email: "a@example.com", City: "Kyiv", Frequency: domain.FreqHourly, Channel: domain.ChannelWebhook},
+	}
+	d.repo.On("GetConfirmedByFrequency", ctx, "hourly").Return(subs, nil)
+
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, "hourly", time.Now())
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "webhook", tasks[0].Recipients[0].Channel)
+}
+
+func TestReverifyWebhook_RejectsEmailSubscription(t *testing.T) {
+	d := createTestService()
+	endpoints, _ := withWebhooks(d)
+	ctx := context.Background()
+	d.tokens.On("Parse", "manage-token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(&domain.Subscription{ID: "sub-1", Channel: domain.ChannelEmail}, nil)
+
+	err := d.service.ReverifyWebhook(ctx, "manage-token", "sub-1")
+
+	assert.ErrorIs(t, err, subscription.ErrNotWebhookChannel)
+	assert.Empty(t, endpoints.reverified)
+}
+
+func TestReverifyWebhook_SchedulesHandshake(t *testing.T) {
+	d := createTestService()
+	endpoints, _ := withWebhooks(d)
+	ctx := context.Background()
+	d.tokens.On("Parse", "manage-token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(&domain.Subscription{ID: "sub-1", Channel: domain.ChannelWebhook}, nil)
+
+	require.NoError(t, d.service.ReverifyWebhook(ctx, "manage-token", "sub-1"))
+
+	assert.Equal(t, []string{"sub-1"}, endpoints.reverified)
+}
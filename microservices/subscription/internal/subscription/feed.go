@@ -0,0 +1,160 @@
+package subscription
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// DefaultFeedEntries is how many reports a feed shows unless configured otherwise.
+const DefaultFeedEntries = 10
+
+// Feed is what the Atom and RSS feeds of a subscription show: the latest reports for its city
+// and frequency in its locale, newest first.
+type Feed struct {
+	SubscriptionID string
+	City           string
+	Frequency      domain.Frequency
+	// Updated is the time of the newest entry, or of the subscription when there is none.
+	Updated time.Time
+	Entries []domain.ReportSnapshot
+}
+
+type feedRepo interface {
+	GetByID(ctx context.Context, id string) (*domain.Subscription, error)
+	Update(ctx context.Context, sub *domain.Subscription) error
+}
+
+// reportSnapshots reads the reports dispatch runs already sent, from the delivery history.
+type reportSnapshots interface {
+	LatestReports(ctx context.Context, city, locale string, frequency domain.Frequency, limit int) ([]domain.ReportSnapshot, error)
+}
+
+type feedWeather interface {
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
+}
+
+// FeedService issues feed tokens and builds the feeds they open. Feed tokens do not expire by
+// default, since feed readers cannot follow a magic link; a subscriber replaces a leaked one by
+// issuing a new token.
+type FeedService struct {
+	repo         feedRepo
+	tokenService tokenService
+	snapshots    reportSnapshots
+	weather      feedWeather
+	entries      int
+	now          func() time.Time
+}
+
+func NewFeedService(repo feedRepo, tokenService tokenService, snapshots reportSnapshots, weather feedWeather, entries int) FeedService {
+	if entries <= 0 {
+		entries = DefaultFeedEntries
+	}
+	return FeedService{
+		repo:         repo,
+		tokenService: tokenService,
+		snapshots:    snapshots,
+		weather:      weather,
+		entries:      entries,
+		now:          time.Now,
+	}
+}
+
+// IssueToken creates a feed token for an owned subscription and revokes the previous one.
+func (s FeedService) IssueToken(ctx context.Context, manageToken, id string) (string, error) {
+	sub, err := s.ownedSubscription(ctx, manageToken, id)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.tokenService.Generate(sub.ID, domain.TokenPurposeFeed)
+	if err != nil {
+		return "", fmt.Errorf("could not generate token: %w", err)
+	}
+
+	previous := sub.FeedToken
+	sub.FeedToken = token
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return "", fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	if previous != "" {
+		if err := s.tokenService.Revoke(ctx, previous); err != nil {
+			loggerPkg.From(ctx).Warn("Failed to revoke previous feed token", "subscription_id", sub.ID, "err", err)
+		}
+	}
+	return token, nil
+}
+
+// Feed builds the feed a token was issued for. Only the subscription's current feed token opens
+// it, so a replaced token stops working even if its revocation failed. Reports come from the
+// snapshots of past dispatch runs; until the first run covered the city, the feed shows the
+// current weather. Feeds of unconfirmed and cancelled subscriptions are reported as not found.
+func (s FeedService) Feed(ctx context.Context, token string) (Feed, error) {
+	claims, err := s.tokenService.Parse(ctx, token, domain.TokenPurposeFeed)
+	if err != nil {
+		return Feed{}, ErrInvalidToken
+	}
+
+	sub, err := s.repo.GetByID(ctx, claims.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return Feed{}, ErrSubscriptionNotFound
+		}
+		return Feed{}, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if sub.FeedToken == "" || subtle.ConstantTimeCompare([]byte(sub.FeedToken), []byte(token)) != 1 {
+		return Feed{}, ErrInvalidToken
+	}
+	if !sub.IsConfirmed || sub.IsUnsubscribed {
+		return Feed{}, ErrSubscriptionNotFound
+	}
+
+	entries, err := s.snapshots.LatestReports(ctx, sub.City, sub.Locale, sub.Frequency, s.entries)
+	if err != nil {
+		return Feed{}, fmt.Errorf("failed to load reports: %w", err)
+	}
+	if len(entries) == 0 {
+		entries = s.currentReport(ctx, sub)
+	}
+
+	feed := Feed{
+		SubscriptionID: sub.ID,
+		City:           sub.City,
+		Frequency:      sub.Frequency,
+		Updated:        sub.CreatedAt,
+		Entries:        entries,
+	}
+	if len(entries) > 0 {
+		feed.Updated = entries[0].At
+	}
+	return feed, nil
+}
+
+// currentReport fetches the weather on demand. The entry is dated to the start of the hour, so
+// polling readers keep seeing the same entry until the next one; a failure leaves the feed empty
+// rather than failing readers that poll it anyway.
+func (s FeedService) currentReport(ctx context.Context, sub *domain.Subscription) []domain.ReportSnapshot {
+	report, err := s.weather.GetWeather(ctx, sub.City, sub.Locale)
+	if err != nil {
+		loggerPkg.From(ctx).Warn("Failed to get weather for feed", "subscription_id", sub.ID, "city", sub.City, "err", err)
+		return nil
+	}
+	return []domain.ReportSnapshot{{
+		City:   sub.City,
+		Report: report,
+		At:     s.now().UTC().Truncate(time.Hour),
+	}}
+}
+
+// ownedSubscription resolves a manage token and makes sure the subscription belongs to its holder,
+// like Service.ownedSubscription.
+func (s FeedService) ownedSubscription(ctx context.Context, manageToken, id string) (*domain.Subscription, error) {
+	return ownedSubscription(ctx, s.tokenService, s.repo, manageToken, id)
+}
@@ -0,0 +1,221 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSnapshots struct {
+	snapshots []domain.ReportSnapshot
+	err       error
+	query     string
+}
+
+func (f *fakeSnapshots) LatestReports(_ context.Context, city, locale string, frequency domain.Frequency, limit int) ([]domain.ReportSnapshot, error) {
+	f.query = city + "/" + locale + "/" + string(frequency)
+	if limit < len(f.snapshots) {
+		return f.snapshots[:limit], f.err
+	}
+	return f.snapshots, f.err
+}
+
+type feedDeps struct {
+	repo      *mockRepo
+	tokens    *mockTokenService
+	weather   *mockCityValidator
+	snapshots *fakeSnapshots
+	service   subscription.FeedService
+}
+
+func createFeedService(entries int) *feedDeps {
+	d := &feedDeps{
+		repo:      new(mockRepo),
+		tokens:    new(mockTokenService),
+		weather:   new(mockCityValidator),
+		snapshots: &fakeSnapshots{},
+	}
+	d.service = subscription.NewFeedService(d.repo, d.tokens, d.snapshots, d.weather, entries)
+	return d
+}
+
+func feedSubscription() *domain.Subscription {
+	return &domain.Subscription{
+		ID:          "sub-1",
+		Email:       "reader@example.com",
+		City:        "Kyiv",
+		Frequency:   domain.FreqHourly,
+		Locale:      "en",
+		IsConfirmed: true,
+		CreatedAt:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		FeedToken:   "feed-token",
+	}
+}
+
+func TestFeed_ReturnsLatestSnapshots(t *testing.T) {
+	d := createFeedService(2)
+	ctx := context.Background()
+	at := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	d.snapshots.snapshots = []domain.ReportSnapshot{
+		{RunID: 3, City: "Kyiv", At: at},
+		{RunID: 2, City: "Kyiv", At: at.Add(-time.Hour)},
+		{RunID: 1, City: "Kyiv", At: at.Add(-2 * time.Hour)},
+	}
+	d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(feedSubscription(), nil)
+
+	feed, err := d.service.Feed(ctx, "feed-token")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Kyiv/en/hourly", d.snapshots.query)
+	assert.Equal(t, "sub-1", feed.SubscriptionID)
+	assert.Equal(t, at, feed.Updated)
+	assert.Len(t, feed.Entries, 2)
+	d.weather.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFeed_FetchesWeatherWithoutSnapshots(t *testing.T) {
+	d := createFeedService(0)
+	ctx := context.Background()
+	report := domain.Report{Temperature: 5, Humidity: 80, Description: "Rain"}
+	d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(feedSubscription(), nil)
+	d.weather.On("GetWeather", ctx, "Kyiv", "en").Return(report, nil).Once()
+
+	feed, err := d.service.Feed(ctx, "feed-token")
+
+	require.NoError(t, err)
+	require.Len(t, feed.Entries, 1)
+	entry := feed.Entries[0]
+	assert.Equal(t, report, entry.Report)
+	assert.Zero(t, entry.RunID)
+	assert.Equal(t, entry.At.Truncate(time.Hour), entry.At)
+	assert.WithinDuration(t, time.Now(), entry.At, time.Hour)
+	assert.Equal(t, entry.At, feed.Updated)
+}
+
+func TestFeed_WeatherFailureLeavesFeedEmpty(t *testing.T) {
+	d := createFeedService(0)
+	ctx := context.Background()
+	sub := feedSubscription()
+	d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.weather.On("GetWeather", ctx, "Kyiv", "en").Return(domain.Report{}, errors.New("weather down"))
+
+	feed, err := d.service.Feed(ctx, "feed-token")
+
+	require.NoError(t, err)
+	assert.Empty(t, feed.Entries)
+	assert.Equal(t, sub.CreatedAt, feed.Updated)
+}
+
+func TestFeed_Errors(t *testing.T) {
+	unconfirmed := feedSubscription()
+	unconfirmed.IsConfirmed = false
+	cancelled := feedSubscription()
+	cancelled.IsUnsubscribed = true
+
+	tests := []struct {
+		name    string
+		setup   func(d *feedDeps)
+		wantErr error
+	}{
+		{
+			name: "InvalidToken",
+			setup: func(d *feedDeps) {
+				d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{}, errors.New("revoked"))
+			},
+			wantErr: subscription.ErrInvalidToken,
+		},
+		{
+			name: "ReplacedToken",
+			setup: func(d *feedDeps) {
+				replaced := feedSubscription()
+				replaced.FeedToken = "newer-feed-token"
+				d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+				d.repo.On("GetByID", mock.Anything, "sub-1").Return(replaced, nil)
+			},
+			wantErr: subscription.ErrInvalidToken,
+		},
+		{
+			name: "Deleted",
+			setup: func(d *feedDeps) {
+				d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+				d.repo.On("GetByID", mock.Anything, "sub-1").Return(nil, subscription.ErrSubscriptionNotFound)
+			},
+			wantErr: subscription.ErrSubscriptionNotFound,
+		},
+		{
+			name: "Unconfirmed",
+			setup: func(d *feedDeps) {
+				d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+				d.repo.On("GetByID", mock.Anything, "sub-1").Return(unconfirmed, nil)
+			},
+			wantErr: subscription.ErrSubscriptionNotFound,
+		},
+		{
+			name: "Unsubscribed",
+			setup: func(d *feedDeps) {
+				d.tokens.On("Parse", "feed-token", domain.TokenPurposeFeed).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+				d.repo.On("GetByID", mock.Anything, "sub-1").Return(cancelled, nil)
+			},
+			wantErr: subscription.ErrSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := createFeedService(0)
+			tt.setup(d)
+
+			_, err := d.service.Feed(context.Background(), "feed-token")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestIssueFeedToken_RevokesPrevious(t *testing.T) {
+	d := createFeedService(0)
+	ctx := context.Background()
+	sub := feedSubscription()
+	sub.FeedToken = "old-feed-token"
+	d.tokens.On("Parse", "manage-token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.tokens.On("Generate", "sub-1", domain.TokenPurposeFeed).Return("new-feed-token", nil)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(s *domain.Subscription) bool {
+		return s.FeedToken == "new-feed-token"
+	})).Return(nil).Once()
+	d.tokens.On("Revoke", "old-feed-token").Return(nil).Once()
+
+	token, err := d.service.IssueToken(ctx, "manage-token", "sub-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new-feed-token", token)
+	d.repo.AssertExpectations(t)
+	d.tokens.AssertExpectations(t)
+}
+
+func TestIssueFeedToken_ForeignSubscription(t *testing.T) {
+	d := createFeedService(0)
+	ctx := context.Background()
+	other := feedSubscription()
+	other.ID = "sub-2"
+	other.Email = "someone@example.com"
+	d.tokens.On("Parse", "manage-token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(feedSubscription(), nil)
+	d.repo.On("GetByID", ctx, "sub-2").Return(other, nil)
+
+	_, err := d.service.IssueToken(ctx, "manage-token", "sub-2")
+
+	assert.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+	d.tokens.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+}
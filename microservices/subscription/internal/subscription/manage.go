@@ -0,0 +1,283 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// MaxPause is how far ahead a subscription can be paused, so a forgotten pause still ends.
+const MaxPause = 365 * 24 * time.Hour
+
+// UpdateParams describes a partial subscription update; nil fields are left unchanged.
+type UpdateParams struct {
+	City         *string
+	Frequency    *domain.Frequency
+	DeliveryHour *int
+	// QuietHours replaces the quiet hours; a window with equal start and end removes them.
+	QuietHours *domain.QuietHours
+	Digest     *bool
+}
+
+// RequestManageLink emails a magic link for managing subscriptions of the given address.
+// Unknown addresses are ignored so the endpoint cannot be used to probe for subscribers.
+// The link is bound to the address and manages all of its subscriptions.
+func (s Service) RequestManageLink(ctx context.Context, email string) error {
+	logger := loggerPkg.From(ctx)
+
+	subs, err := s.repo.ListByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		logger.Info("Manage link requested for unknown email", "user", loggerPkg.HashEmail(email))
+		return nil
+	}
+	sub := subs[0]
+
+	token, err := s.tokenService.GenerateForAddress(sub.Email, domain.TokenPurposeManage)
+	if err != nil {
+		return fmt.Errorf("could not generate token: %w", err)
+	}
+
+	idKey := fmt.Sprintf("manage:%s:%s", loggerPkg.HashEmail(sub.Email), token)
+	if err := s.emailService.SendManageLink(ctx, sub.Email, sub.Locale, token, idKey); err != nil {
+		return fmt.Errorf("failed to send manage link: %w", err)
+	}
+
+	return nil
+}
+
+func (s Service) ListSubscriptions(ctx context.Context, token string) ([]domain.Subscription, error) {
+	address, err := manageAddress(ctx, s.tokenService, s.repo, token)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return []domain.Subscription{}, nil
+		}
+		return nil, err
+	}
+
+	subs, err := s.repo.ListByEmail(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// UpdateSubscription changes city, frequency, delivery hour, quiet hours or the digest option of
+// an owned subscription without resetting its confirmation state. The address cannot follow a
+// city twice.
+func (s Service) UpdateSubscription(ctx context.Context, token, id string, params UpdateParams) (*domain.Subscription, error) {
+	sub, err := s.ownedSubscription(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+
+	if params.Frequency != nil {
+		if !params.Frequency.Valid() {
+			return nil, ErrInvalidFrequency
+		}
+		if *params.Frequency != sub.Frequency {
+			sub.Frequency = *params.Frequency
+			changes = append(changes, "frequency")
+		}
+	}
+
+	if params.DeliveryHour != nil {
+		if !domain.ValidDeliveryHour(*params.DeliveryHour) {
+			return nil, ErrInvalidDeliveryHour
+		}
+		if *params.DeliveryHour != sub.DeliveryHour {
+			sub.DeliveryHour = *params.DeliveryHour
+			changes = append(changes, "delivery_hour")
+		}
+	}
+
+	if params.QuietHours != nil {
+		quiet := params.QuietHours
+		if quiet.Start == quiet.End {
+			quiet = nil
+		} else if !quiet.Valid() {
+			return nil, ErrInvalidQuietHours
+		}
+		if !equalQuietHours(quiet, sub.QuietHours) {
+			sub.QuietHours = quiet
+			changes = append(changes, "quiet_hours")
+		}
+	}
+
+	if params.Digest != nil && *params.Digest != sub.Digest {
+		sub.Digest = *params.Digest
+		changes = append(changes, "digest")
+	}
+
+	if params.City != nil {
+		city := strings.TrimSpace(*params.City)
+		if city != sub.City {
+			if _, err := s.weatherService.CityIsValid(ctx, city); err != nil {
+				if errors.Is(err, ErrCityNotFound) {
+					return nil, ErrCityNotFound
+				}
+				return nil, fmt.Errorf("failed to validate city: %w", err)
+			}
+			if err := s.checkCityFree(ctx, sub, city); err != nil {
+				return nil, err
+			}
+			sub.City = city
+			changes = append(changes, "city")
+		}
+	}
+
+	if err := s.saveChanges(ctx, sub, changes); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// PauseSubscription stops the reports of an owned subscription until the given time, so a
+// traveller does not have to unsubscribe and confirm again. Pausing again moves the end.
+func (s Service) PauseSubscription(ctx context.Context, token, id string, until time.Time) (*domain.Subscription, error) {
+	now := time.Now()
+	if !until.After(now) || until.After(now.Add(MaxPause)) {
+		return nil, ErrInvalidPauseUntil
+	}
+
+	sub, err := s.ownedSubscription(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	until = until.UTC()
+	sub.PausedUntil = &until
+	if err := s.saveChanges(ctx, sub, []string{"paused_until"}); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ResumeSubscription ends a pause early; resuming a subscription that is not paused is a no-op.
+func (s Service) ResumeSubscription(ctx context.Context, token, id string) (*domain.Subscription, error) {
+	sub, err := s.ownedSubscription(ctx, token, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.PausedUntil == nil {
+		return sub, nil
+	}
+
+	sub.PausedUntil = nil
+	if err := s.saveChanges(ctx, sub, []string{"paused_until"}); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// checkCityFree makes sure no other subscription of the address follows the city.
+func (s Service) checkCityFree(ctx context.Context, sub *domain.Subscription, city string) error {
+	other, err := s.repo.GetByEmailAndCity(ctx, sub.Email, city)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check existing subscription: %w", err)
+	}
+	if other.ID != sub.ID {
+		return ErrEmailAlreadyExists
+	}
+	return nil
+}
+
+// saveChanges stores the subscription and announces the changed fields in one transaction.
+func (s Service) saveChanges(ctx context.Context, sub *domain.Subscription, changes []string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to update subscription: %w", err)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return s.events.Updated(ctx, *sub, changes)
+	})
+}
+
+func equalQuietHours(a, b *domain.QuietHours) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s Service) DeleteSubscription(ctx context.Context, token, id string) error {
+	sub, err := s.ownedSubscription(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, sub.ID); err != nil {
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return ErrSubscriptionNotFound
+			}
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		return announceRemoval(ctx, s.events, *sub, UnsubscribeReasonDeleted)
+	})
+}
+
+// manageAddress resolves the subscriber address a manage token was issued for. Manage links are
+// bound to the address, so deleting one subscription leaves the link working for the others;
+// links issued for a subscription before that resolve through it while it exists.
+func manageAddress(ctx context.Context, tokens tokenService, repo subscriptionGetter, token string) (string, error) {
+	claims, err := tokens.Parse(ctx, token, domain.TokenPurposeManage)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if claims.Address != "" {
+		return claims.Address, nil
+	}
+
+	owner, err := repo.GetByID(ctx, claims.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return "", ErrSubscriptionNotFound
+		}
+		return "", fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return owner.Email, nil
+}
+
+// ownedSubscription loads a subscription and makes sure it belongs to the token holder.
+// Foreign subscriptions are reported as not found to avoid leaking their existence.
+func (s Service) ownedSubscription(ctx context.Context, token, id string) (*domain.Subscription, error) {
+	return ownedSubscription(ctx, s.tokenService, s.repo, token, id)
+}
+
+func ownedSubscription(ctx context.Context, tokens tokenService, repo subscriptionGetter, token, id string) (*domain.Subscription, error) {
+	address, err := manageAddress(ctx, tokens, repo, token)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	if !strings.EqualFold(sub.Email, address) {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	return sub, nil
+}
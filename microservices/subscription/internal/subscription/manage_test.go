@@ -0,0 +1,403 @@
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- REQUEST MANAGE LINK ---
+
+func TestRequestManageLink_SendsEmail(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	subs := []domain.Subscription{{ID: "sub-1", Email: email, Locale: "en"}, {ID: "sub-2", Email: email, Locale: "uk"}}
+
+	d.repo.On("ListByEmail", ctx, email).Return(subs, nil)
+	d.tokens.On("GenerateForAddress", email, domain.TokenPurposeManage).Return("manage-token", nil)
+	d.emails.On("SendManageLink", email, "en", "manage-token").Return(nil).Once()
+
+	err := d.service.RequestManageLink(ctx, email)
+
+	assert.NoError(t, err)
+	d.emails.AssertExpectations(t)
+}
+
+func TestRequestManageLink_UnknownEmail_NoError(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "ghost@example.com"
+
+	d.repo.On("ListByEmail", ctx, email).Return([]domain.Subscription{}, nil)
+
+	err := d.service.RequestManageLink(ctx, email)
+
+	assert.NoError(t, err)
+	d.emails.AssertNotCalled(t, "SendManageLink", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestManageLink_SendFails_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	subs := []domain.Subscription{{ID: "sub-1", Email: email}}
+
+	d.repo.On("ListByEmail", ctx, email).Return(subs, nil)
+	d.tokens.On("GenerateForAddress", email, domain.TokenPurposeManage).Return("manage-token", nil)
+	d.emails.On("SendManageLink", email, "", "manage-token").Return(assert.AnError)
+
+	err := d.service.RequestManageLink(ctx, email)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+// --- LIST ---
+
+func TestListSubscriptions_ReturnsEveryCityOfTheAddress(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email, City: "Kyiv"}
+	all := []domain.Subscription{*sub, {ID: "sub-2", Email: email, City: "Lviv"}}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("ListByEmail", ctx, email).Return(all, nil)
+
+	subs, err := d.service.ListSubscriptions(ctx, "token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, all, subs)
+}
+
+func TestListSubscriptions_AddressToken_OutlivesDeletedSubscription(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	left := []domain.Subscription{{ID: "sub-2", Email: email, City: "Lviv"}}
+
+	// The link was requested while sub-1 existed; it names the address, not sub-1.
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{Address: email}, nil)
+	d.repo.On("ListByEmail", ctx, email).Return(left, nil)
+
+	subs, err := d.service.ListSubscriptions(ctx, "token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, left, subs)
+	d.repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestListSubscriptions_InvalidToken(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+
+	d.tokens.On("Parse", "bad", domain.TokenPurposeManage).Return(domain.TokenClaims{}, assert.AnError)
+
+	_, err := d.service.ListSubscriptions(ctx, "bad")
+
+	assert.ErrorIs(t, err, subscription.ErrInvalidToken)
+}
+
+// --- UPDATE ---
+
+func TestUpdateSubscription_ChangesFieldsWithoutResettingConfirmation(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email, City: "Kyiv", Frequency: domain.FreqDaily, DeliveryHour: 12, IsConfirmed: true}
+
+	city := "Lviv"
+	freq := domain.FreqHourly
+	hour := 8
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(nil, subscription.ErrSubscriptionNotFound)
+	d.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil)
+
+	updated, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{
+		City:         &city,
+		Frequency:    &freq,
+		DeliveryHour: &hour,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Lviv", updated.City)
+	assert.Equal(t, domain.FreqHourly, updated.Frequency)
+	assert.Equal(t, 8, updated.DeliveryHour)
+	assert.True(t, updated.IsConfirmed)
+	d.repo.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, "updated", d.events.events[0].Type)
+		assert.Equal(t, []string{"frequency", "delivery_hour", "city"}, d.events.events[0].Changes)
+	}
+}
+
+func TestUpdateSubscription_NoChanges_NoEvent(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com", City: "Kyiv", Frequency: domain.FreqDaily, DeliveryHour: 12}
+
+	city := "Kyiv"
+	hour := 12
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, sub).Return(nil)
+
+	_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{City: &city, DeliveryHour: &hour})
+
+	assert.NoError(t, err)
+	assert.Empty(t, d.events.events)
+}
+
+func TestUpdateSubscription_ForeignSubscription_NotFound(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	intruder := &domain.Subscription{ID: "sub-2", Email: "intruder@example.com"}
+	sub := &domain.Subscription{ID: "sub-1", Email: "owner@example.com"}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-2"}, nil)
+	d.repo.On("GetByID", ctx, "sub-2").Return(intruder, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+
+	_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{})
+
+	assert.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+	d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription_InvalidDeliveryHour(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email}
+	hour := 24
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+
+	_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{DeliveryHour: &hour})
+
+	assert.ErrorIs(t, err, subscription.ErrInvalidDeliveryHour)
+}
+
+func TestUpdateSubscription_UnknownCity(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email, City: "Kyiv"}
+	city := "Atlantis"
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.validator.On("CityIsValid", ctx, city).Return(false, subscription.ErrCityNotFound)
+
+	_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{City: &city})
+
+	assert.ErrorIs(t, err, subscription.ErrCityNotFound)
+}
+
+func TestUpdateSubscription_CityAlreadyFollowed(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email, City: "Kyiv"}
+	city := "Lviv"
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(&domain.Subscription{ID: "sub-2", Email: email, City: city}, nil)
+
+	_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{City: &city})
+
+	assert.ErrorIs(t, err, subscription.ErrEmailAlreadyExists)
+	d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription_TogglesDigest(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com", City: "Kyiv", IsConfirmed: true}
+	digest := true
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(s *domain.Subscription) bool { return s.Digest })).Return(nil)
+
+	updated, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{Digest: &digest})
+
+	assert.NoError(t, err)
+	assert.True(t, updated.Digest)
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, []string{"digest"}, d.events.events[0].Changes)
+	}
+}
+
+func TestUpdateSubscription_SetsAndClearsQuietHours(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com", Frequency: domain.FreqHourly}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, sub).Return(nil)
+
+	quiet := domain.QuietHours{Start: 22, End: 7, Timezone: "Europe/Kyiv"}
+	updated, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{QuietHours: &quiet})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &quiet, updated.QuietHours)
+
+	_, err = d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{QuietHours: &domain.QuietHours{}})
+
+	assert.NoError(t, err)
+	assert.Nil(t, sub.QuietHours)
+	if assert.Len(t, d.events.events, 2) {
+		assert.Equal(t, []string{"quiet_hours"}, d.events.events[1].Changes)
+	}
+}
+
+func TestUpdateSubscription_InvalidQuietHours(t *testing.T) {
+	cases := map[string]domain.QuietHours{
+		"UnknownTimezone": {Start: 22, End: 7, Timezone: "Mars/Olympus"},
+		"HourOutOfRange":  {Start: 22, End: 24, Timezone: "UTC"},
+	}
+	for name, quiet := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := createTestService()
+			ctx := context.Background()
+			sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com"}
+
+			d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+			d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+
+			_, err := d.service.UpdateSubscription(ctx, "token", "sub-1", subscription.UpdateParams{QuietHours: &quiet})
+
+			assert.ErrorIs(t, err, subscription.ErrInvalidQuietHours)
+			d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// --- PAUSE / RESUME ---
+
+func TestPauseSubscription_StoresEndAndPublishesUpdate(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com", IsConfirmed: true}
+	until := time.Now().Add(7 * 24 * time.Hour)
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, sub).Return(nil).Once()
+
+	paused, err := d.service.PauseSubscription(ctx, "token", "sub-1", until)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, paused.PausedUntil) {
+		assert.True(t, until.Equal(*paused.PausedUntil))
+	}
+	assert.True(t, paused.IsConfirmed, "pausing keeps the confirmation")
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, []string{"paused_until"}, d.events.events[0].Changes)
+	}
+	d.repo.AssertExpectations(t)
+}
+
+func TestPauseSubscription_RejectsPastAndFarFuture(t *testing.T) {
+	for name, until := range map[string]time.Time{
+		"Past":      time.Now().Add(-time.Hour),
+		"FarFuture": time.Now().Add(subscription.MaxPause + time.Hour),
+	} {
+		t.Run(name, func(t *testing.T) {
+			d := createTestService()
+
+			_, err := d.service.PauseSubscription(context.Background(), "token", "sub-1", until)
+
+			assert.ErrorIs(t, err, subscription.ErrInvalidPauseUntil)
+			d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestResumeSubscription_ClearsPause(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	until := time.Now().Add(time.Hour)
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com", PausedUntil: &until}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, sub).Return(nil).Once()
+
+	resumed, err := d.service.ResumeSubscription(ctx, "token", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, resumed.PausedUntil)
+	assert.Len(t, d.events.events, 1)
+}
+
+func TestResumeSubscription_NotPaused_NoOp(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com"}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+
+	_, err := d.service.ResumeSubscription(ctx, "token", "sub-1")
+
+	assert.NoError(t, err)
+	d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	assert.Empty(t, d.events.events)
+}
+
+// --- DELETE ---
+
+func TestDeleteSubscription_Success(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	sub := &domain.Subscription{ID: "sub-1", Email: email}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Delete", ctx, "sub-1").Return(nil)
+
+	err := d.service.DeleteSubscription(ctx, "token", "sub-1")
+
+	assert.NoError(t, err)
+	d.repo.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 2) {
+		assert.Equal(t, "unsubscribed", d.events.events[0].Type)
+		assert.Equal(t, subscription.UnsubscribeReasonDeleted, d.events.events[0].Reason)
+		assert.Equal(t, domain.StatusUnsubscribed, d.events.events[0].Sub.Status())
+		assert.Equal(t, "removed", d.events.events[1].Type)
+		assert.Equal(t, subscription.UnsubscribeReasonDeleted, d.events.events[1].Reason)
+	}
+}
+
+func TestDeleteSubscription_NotFound(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+
+	owner := &domain.Subscription{ID: "sub-1", Email: "user@example.com"}
+
+	d.tokens.On("Parse", "token", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(owner, nil)
+	d.repo.On("GetByID", ctx, "missing").Return(nil, subscription.ErrSubscriptionNotFound)
+
+	err := d.service.DeleteSubscription(ctx, "token", "missing")
+
+	assert.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+}
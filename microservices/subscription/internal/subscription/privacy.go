@@ -0,0 +1,160 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// StoredMessage is an email command kept in the outbox for an address.
+type StoredMessage struct {
+	RoutingKey string
+	Status     string
+	CreatedAt  time.Time
+	SentAt     *time.Time
+}
+
+// StoredWebhookDelivery is a report queued for the webhook of one of the address's subscriptions.
+type StoredWebhookDelivery struct {
+	SubscriptionID string
+	Status         string
+	Attempts       int
+	CreatedAt      time.Time
+	SentAt         *time.Time
+}
+
+// DataExport is everything the service stores about one email address.
+type DataExport struct {
+	Email         string
+	ExportedAt    time.Time
+	Subscriptions []domain.Subscription
+	Deliveries    []domain.Delivery
+	Messages      []StoredMessage
+	// Webhooks are the endpoints of the address's webhook subscriptions.
+	Webhooks          []domain.WebhookEndpoint
+	WebhookDeliveries []StoredWebhookDelivery
+}
+
+// ErasureResult counts the records removed for an address.
+type ErasureResult struct {
+	Subscriptions     int64
+	Deliveries        int64
+	Messages          int64
+	Jobs              int64
+	Webhooks          int64
+	WebhookDeliveries int64
+	// Removed are the deleted subscriptions as they were stored.
+	Removed []domain.Subscription
+}
+
+type privacyStore interface {
+	Collect(ctx context.Context, email string) (DataExport, error)
+	// Erase deletes the address from every table; queued jobs keep their other recipients.
+	Erase(ctx context.Context, email string) (ErasureResult, error)
+}
+
+type addressPurger interface {
+	// PurgeAddress asks the email service to forget the address.
+	PurgeAddress(ctx context.Context, email string) error
+}
+
+type subscriptionGetter interface {
+	GetByID(ctx context.Context, id string) (*domain.Subscription, error)
+}
+
+// PrivacyService implements data export and erasure requests, authorised by a manage link token.
+type PrivacyService struct {
+	repo         subscriptionGetter
+	tokenService tokenService
+	store        privacyStore
+	purger       addressPurger
+	tx           transactor
+	events       lifecycleEvents
+}
+
+func NewPrivacyService(
+	repo subscriptionGetter,
+	tokenService tokenService,
+	store privacyStore,
+	purger addressPurger,
+	tx transactor,
+	events lifecycleEvents,
+) PrivacyService {
+	return PrivacyService{
+		repo:         repo,
+		tokenService: tokenService,
+		store:        store,
+		purger:       purger,
+		tx:           tx,
+		events:       events,
+	}
+}
+
+func (s PrivacyService) Export(ctx context.Context, token string) (DataExport, error) {
+	address, err := s.tokenAddress(ctx, token)
+	if err != nil {
+		return DataExport{}, err
+	}
+
+	data, err := s.store.Collect(ctx, address)
+	if err != nil {
+		return DataExport{}, fmt.Errorf("failed to collect data: %w", err)
+	}
+	data.Email = address
+	data.ExportedAt = time.Now().UTC()
+
+	loggerPkg.From(ctx).Info("Personal data exported", "user", loggerPkg.HashEmail(address))
+	return data, nil
+}
+
+// Erase removes every record of the token holder's address and, in the same transaction,
+// enqueues the purge command for the email service and announces the removed subscriptions.
+// The token is revoked afterwards.
+func (s PrivacyService) Erase(ctx context.Context, token string) (ErasureResult, error) {
+	address, err := s.tokenAddress(ctx, token)
+	if err != nil {
+		return ErasureResult{}, err
+	}
+
+	var res ErasureResult
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if res, err = s.store.Erase(ctx, address); err != nil {
+			return fmt.Errorf("failed to erase data: %w", err)
+		}
+		if err := s.purger.PurgeAddress(ctx, address); err != nil {
+			return fmt.Errorf("failed to enqueue purge: %w", err)
+		}
+		for _, sub := range res.Removed {
+			if err := announceRemoval(ctx, s.events, sub, UnsubscribeReasonErased); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ErasureResult{}, err
+	}
+
+	if err := s.tokenService.Revoke(ctx, token); err != nil {
+		loggerPkg.From(ctx).Warn("Failed to revoke token after erasure", "err", err)
+	}
+
+	loggerPkg.From(ctx).Info("Personal data erased",
+		"user", loggerPkg.HashEmail(address),
+		"subscriptions", res.Subscriptions,
+		"deliveries", res.Deliveries,
+		"messages", res.Messages,
+		"jobs", res.Jobs,
+		"webhooks", res.Webhooks,
+		"webhook_deliveries", res.WebhookDeliveries)
+	return res, nil
+}
+
+func (s PrivacyService) tokenAddress(ctx context.Context, token string) (string, error) {
+	return manageAddress(ctx, s.tokenService, s.repo, token)
+}
@@ -0,0 +1,123 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPrivacyStore struct{ mock.Mock }
+
+func (m *mockPrivacyStore) Collect(ctx context.Context, email string) (subscription.DataExport, error) {
+	args := m.Called(email)
+	return args.Get(0).(subscription.DataExport), args.Error(1)
+}
+
+func (m *mockPrivacyStore) Erase(ctx context.Context, email string) (subscription.ErasureResult, error) {
+	args := m.Called(email)
+	return args.Get(0).(subscription.ErasureResult), args.Error(1)
+}
+
+type mockPurger struct{ mock.Mock }
+
+func (m *mockPurger) PurgeAddress(ctx context.Context, email string) error {
+	return m.Called(email).Error(0)
+}
+
+func newPrivacyService() (subscription.PrivacyService, *mockRepo, *mockTokenService, *mockPrivacyStore, *mockPurger, *fakeEvents) {
+	repo := new(mockRepo)
+	tokens := new(mockTokenService)
+	store := new(mockPrivacyStore)
+	purger := new(mockPurger)
+	events := &fakeEvents{}
+	return subscription.NewPrivacyService(repo, tokens, store, purger, fakeTransactor{}, events), repo, tokens, store, purger, events
+}
+
+func TestPrivacyExport_InvalidToken(t *testing.T) {
+	svc, _, tokens, store, _, _ := newPrivacyService()
+	tokens.On("Parse", "bad", domain.TokenPurposeManage).Return(domain.TokenClaims{}, errors.New("expired"))
+
+	_, err := svc.Export(context.Background(), "bad")
+
+	assert.ErrorIs(t, err, subscription.ErrInvalidToken)
+	store.AssertNotCalled(t, "Collect", mock.Anything)
+}
+
+func TestPrivacyExport_CollectsDataOfTokenOwner(t *testing.T) {
+	svc, repo, tokens, store, _, _ := newPrivacyService()
+	tokens.On("Parse", "magic", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	repo.On("GetByID", mock.Anything, "sub-1").Return(&domain.Subscription{ID: "sub-1", Email: "user@example.com"}, nil)
+	store.On("Collect", "user@example.com").Return(subscription.DataExport{
+		Subscriptions: []domain.Subscription{{ID: "sub-1"}, {ID: "sub-2"}},
+	}, nil)
+
+	data, err := svc.Export(context.Background(), "magic")
+
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", data.Email)
+	assert.False(t, data.ExportedAt.IsZero())
+	assert.Len(t, data.Subscriptions, 2)
+}
+
+func TestPrivacyExport_AddressToken(t *testing.T) {
+	svc, repo, tokens, store, _, _ := newPrivacyService()
+	tokens.On("Parse", "magic", domain.TokenPurposeManage).Return(domain.TokenClaims{Address: "user@example.com"}, nil)
+	store.On("Collect", "user@example.com").Return(subscription.DataExport{}, nil)
+
+	data, err := svc.Export(context.Background(), "magic")
+
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", data.Email)
+	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestPrivacyErase_PurgesAddressAndRevokesToken(t *testing.T) {
+	svc, repo, tokens, store, purger, events := newPrivacyService()
+	tokens.On("Parse", "magic", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	tokens.On("Revoke", "magic").Return(nil)
+	repo.On("GetByID", mock.Anything, "sub-1").Return(&domain.Subscription{ID: "sub-1", Email: "user@example.com"}, nil)
+	store.On("Erase", "user@example.com").Return(subscription.ErasureResult{
+		Subscriptions: 2,
+		Deliveries:    5,
+		Removed: []domain.Subscription{
+			{ID: "sub-1", IsConfirmed: true},
+			{ID: "sub-2", IsUnsubscribed: true},
+		},
+	}, nil)
+	purger.On("PurgeAddress", "user@example.com").Return(nil)
+
+	res, err := svc.Erase(context.Background(), "magic")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Subscriptions)
+	assert.Equal(t, int64(5), res.Deliveries)
+	purger.AssertExpectations(t)
+	tokens.AssertCalled(t, "Revoke", "magic")
+
+	var types []string
+	for _, e := range events.events {
+		types = append(types, e.Type+":"+e.Sub.ID)
+		assert.Equal(t, subscription.UnsubscribeReasonErased, e.Reason)
+	}
+	assert.Equal(t, []string{"unsubscribed:sub-1", "removed:sub-1", "removed:sub-2"}, types)
+}
+
+func TestPrivacyErase_FailsWhenPurgeCannotBeEnqueued(t *testing.T) {
+	svc, repo, tokens, store, purger, _ := newPrivacyService()
+	tokens.On("Parse", "magic", domain.TokenPurposeManage).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	repo.On("GetByID", mock.Anything, "sub-1").Return(&domain.Subscription{ID: "sub-1", Email: "user@example.com"}, nil)
+	store.On("Erase", "user@example.com").Return(subscription.ErasureResult{Subscriptions: 1}, nil)
+	purger.On("PurgeAddress", "user@example.com").Return(errors.New("outbox down"))
+
+	_, err := svc.Erase(context.Background(), "magic")
+
+	require.Error(t, err)
+	tokens.AssertNotCalled(t, "Revoke", mock.Anything)
+}
@@ -19,52 +19,143 @@ var (
 	ErrEmailAlreadyExists   = errors.New("email already subscribed")
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidFrequency     = errors.New("invalid frequency")
+	ErrInvalidDeliveryHour  = errors.New("invalid delivery hour")
+	ErrInvalidQuietHours    = errors.New("invalid quiet hours")
+	ErrInvalidPauseUntil    = errors.New("invalid pause end")
 )
 
 type repo interface {
-	GetByEmail(ctx context.Context, email string) (*domain.Subscription, error)
+	ListByEmail(ctx context.Context, email string) ([]domain.Subscription, error)
+	GetByEmailAndCity(ctx context.Context, email, city string) (*domain.Subscription, error)
+	GetByID(ctx context.Context, id string) (*domain.Subscription, error)
 	Create(ctx context.Context, sub *domain.Subscription) error
 	Update(ctx context.Context, sub *domain.Subscription) error
+	Delete(ctx context.Context, id string) error
 	GetConfirmedByFrequency(ctx context.Context, frequency string) ([]domain.Subscription, error)
 }
 
 type emailClient interface {
-	SendConfirmationEmail(ctx context.Context, email, token string, idKey string) error
-	SendWeatherReport(ctx context.Context, email string, weather domain.Report, city, token string, idKey string) error
+	SendConfirmationEmail(ctx context.Context, email, locale, token string, idKey string) error
+	SendWeatherReport(ctx context.Context, email, locale string, weather domain.Report, city, token string, idKey string) error
+	SendWeatherDigest(ctx context.Context, email, locale string, entries []domain.DigestEntry, idKey string) error
+	SendManageLink(ctx context.Context, email, locale, token string, idKey string) error
 }
 
 type WeatherClient interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValid(ctx context.Context, city string) (bool, error)
 }
 
 type tokenService interface {
-	Generate(email string) (string, error)
-	Parse(tokenStr string) (string, error)
+	Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error)
+	GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error)
+	Parse(ctx context.Context, tokenStr string, purpose domain.TokenPurpose) (domain.TokenClaims, error)
+	Revoke(ctx context.Context, tokenStr string) error
+}
+
+// deliveryRecorder keeps the audit trail of weather reports handed to the email service.
+type deliveryRecorder interface {
+	Record(ctx context.Context, d domain.Delivery) error
+}
+
+// transactor runs fn in a single database transaction; email commands enqueued through the
+// outbox inside fn are committed together with the subscription change.
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// lifecycleEvents announces subscription changes to other services. Like email commands,
+// the events go through the outbox and are emitted inside the transaction of the change.
+type lifecycleEvents interface {
+	Created(ctx context.Context, sub domain.Subscription) error
+	Confirmed(ctx context.Context, sub domain.Subscription) error
+	Unsubscribed(ctx context.Context, sub domain.Subscription, reason string) error
+	Updated(ctx context.Context, sub domain.Subscription, changes []string) error
+	Removed(ctx context.Context, sub domain.Subscription, reason string) error
+}
+
+// Reasons reported with unsubscribed events, next to the provider's bounce and complaint.
+// Deletions report theirs with the removed event as well.
+const (
+	UnsubscribeReasonLink    = "unsubscribe_link"
+	UnsubscribeReasonAdmin   = "admin"
+	UnsubscribeReasonDeleted = "deleted"
+	UnsubscribeReasonErased  = "erased"
+)
+
+// announceRemoval emits the events of a deleted subscription. One still active is announced
+// as unsubscribed first, so consumers that only track the active state stay correct.
+func announceRemoval(ctx context.Context, events lifecycleEvents, sub domain.Subscription, reason string) error {
+	if !sub.IsUnsubscribed {
+		unsubscribed := sub
+		unsubscribed.IsUnsubscribed = true
+		if err := events.Unsubscribed(ctx, unsubscribed, reason); err != nil {
+			return err
+		}
+	}
+	return events.Removed(ctx, sub, reason)
 }
 
 type Service struct {
 	repo           repo
+	tx             transactor
 	emailService   emailClient
 	weatherService WeatherClient
 	tokenService   tokenService
+	deliveries     deliveryRecorder
+	events         lifecycleEvents
+	// email and webhook are the notification channels of reports; webhook and webhooks are nil
+	// when NewService was given none, and webhook subscriptions are then rejected.
+	email    notifier
+	webhook  notifier
+	webhooks webhookEndpoints
 }
 
+// NewService builds the subscription service. webhooks and webhook enable the webhook channel;
+// when they are nil, webhook subscriptions are rejected.
 func NewService(
 	repo repo,
 	emailService emailClient,
 	weatherService WeatherClient,
 	tokenService tokenService,
+	tx transactor,
+	deliveries deliveryRecorder,
+	events lifecycleEvents,
+	webhooks webhookEndpoints,
+	webhook notifier,
 ) Service {
 	return Service{
 		repo:           repo,
+		tx:             tx,
 		emailService:   emailService,
 		weatherService: weatherService,
 		tokenService:   tokenService,
+		deliveries:     deliveries,
+		events:         events,
+		email:          emailNotifier{client: emailService},
+		webhook:        webhook,
+		webhooks:       webhooks,
 	}
 }
 
-func (s Service) Subscribe(ctx context.Context, email, city string, frequency domain.Frequency) error {
+// Subscribe creates an unconfirmed subscription, or restarts an unconfirmed or cancelled one, and
+// sends the confirmation email in the given locale. An address has one subscription per city.
+// Reports go out by email.
+func (s Service) Subscribe(ctx context.Context, email, city string, frequency domain.Frequency, locale string) error {
+	return s.subscribe(ctx, email, city, frequency, locale, domain.ChannelEmail, nil)
+}
+
+// subscribe implements Subscribe for any channel; register, if set, runs in the transaction
+// right after the subscription is stored.
+func (s Service) subscribe(
+	ctx context.Context,
+	email, city string,
+	frequency domain.Frequency,
+	locale string,
+	channel domain.Channel,
+	register func(ctx context.Context, sub *domain.Subscription) error,
+) error {
 	_, err := s.weatherService.CityIsValid(ctx, city)
 	if err != nil {
 		if errors.Is(err, ErrCityNotFound) {
@@ -73,7 +164,7 @@ func (s Service) Subscribe(ctx context.Context, email, city string, frequency do
 		return fmt.Errorf("failed to validate city: %w", err)
 	}
 
-	existing, err := s.repo.GetByEmail(ctx, email)
+	existing, err := s.repo.GetByEmailAndCity(ctx, email, city)
 	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
 		return fmt.Errorf("failed to check existing subscription: %w", err)
 	}
@@ -82,58 +173,85 @@ func (s Service) Subscribe(ctx context.Context, email, city string, frequency do
 		return ErrEmailAlreadyExists
 	}
 
-	token, err := s.tokenService.Generate(email)
+	id := uuid.New().String()
+	if existing != nil {
+		id = existing.ID
+	}
+
+	unsubscribeToken, err := s.tokenService.Generate(id, domain.TokenPurposeUnsubscribe)
+	if err != nil {
+		return fmt.Errorf("could not generate token: %w", err)
+	}
+	confirmToken, err := s.tokenService.Generate(id, domain.TokenPurposeConfirm)
 	if err != nil {
 		return fmt.Errorf("could not generate token: %w", err)
 	}
 
-	if err := s.createOrUpdateSubscription(ctx, existing, email, city, frequency, token); err != nil {
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.createOrUpdateSubscription(ctx, existing, id, email, city, frequency, locale, channel, unsubscribeToken)
+		if err != nil {
+			return err
+		}
+		if register != nil {
+			if err := register(ctx, sub); err != nil {
+				return err
+			}
+		}
+		if err := s.events.Created(ctx, *sub); err != nil {
+			return err
+		}
+
+		idKey := confirmIdempotencyKey(email, confirmToken)
+		if err := s.emailService.SendConfirmationEmail(ctx, email, locale, confirmToken, idKey); err != nil {
+			return fmt.Errorf("failed to enqueue confirmation email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	idKey := s.generateIdempotencyKey(email, token)
-	if err := s.emailService.SendConfirmationEmail(ctx, email, token, idKey); err != nil {
-		logger := loggerPkg.From(ctx)
-		logger.Error("Failed to send confirmation email to %s: %v", email, err)
+	if existing != nil && existing.Token != "" {
+		if err := s.tokenService.Revoke(ctx, existing.Token); err != nil {
+			loggerPkg.From(ctx).Warn("Failed to revoke previous unsubscribe token", "subscription_id", id, "err", err)
+		}
 	}
 
 	return nil
 }
 
 func (s Service) Confirm(ctx context.Context, token string) error {
-	email, err := s.tokenService.Parse(token)
+	claims, err := s.tokenService.Parse(ctx, token, domain.TokenPurposeConfirm)
 	if err != nil {
 		return ErrInvalidToken
 	}
 
-	sub, err := s.repo.GetByEmail(ctx, email)
+	sub, err := s.subscriptionFor(ctx, claims)
 	if err != nil {
-		if errors.Is(err, ErrSubscriptionNotFound) {
-			return ErrSubscriptionNotFound
-		}
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return err
 	}
 	if sub.IsConfirmed {
 		return nil
 	}
 
 	sub.IsConfirmed = true
-	if err := s.repo.Update(ctx, sub); err != nil {
-		return fmt.Errorf("failed to confirm subscription: %w", err)
-	}
-
-	return nil
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to confirm subscription: %w", err)
+		}
+		return s.events.Confirmed(ctx, *sub)
+	})
 }
 
 func (s Service) Unsubscribe(ctx context.Context, token string) error {
-	email, err := s.tokenService.Parse(token)
+	claims, err := s.tokenService.Parse(ctx, token, domain.TokenPurposeUnsubscribe)
 	if err != nil {
 		return ErrInvalidToken
 	}
 
-	sub, err := s.repo.GetByEmail(ctx, email)
+	sub, err := s.subscriptionFor(ctx, claims)
 	if err != nil {
-		return fmt.Errorf("failed to get subscription: %w", err)
+		return err
 	}
 
 	if sub.IsUnsubscribed {
@@ -141,50 +259,246 @@ func (s Service) Unsubscribe(ctx context.Context, token string) error {
 	}
 
 	sub.IsUnsubscribed = true
-	if err := s.repo.Update(ctx, sub); err != nil {
-		return fmt.Errorf("failed to unsubscribe: %w", err)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+		return s.events.Unsubscribed(ctx, *sub, UnsubscribeReasonLink)
+	})
+}
+
+// subscriptionFor loads the subscription a link token was issued for. Legacy tokens name
+// the subscriber by address instead of by subscription id; they were issued while an address
+// could follow only one city, so they stand for its oldest subscription.
+func (s Service) subscriptionFor(ctx context.Context, claims domain.TokenClaims) (*domain.Subscription, error) {
+	if claims.Legacy() {
+		subs, err := s.repo.ListByEmail(ctx, claims.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subscription: %w", err)
+		}
+		if len(subs) == 0 {
+			return nil, ErrSubscriptionNotFound
+		}
+		return &subs[0], nil
 	}
 
-	return nil
+	sub, err := s.repo.GetByID(ctx, claims.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return sub, nil
 }
 
-func (s Service) GenerateWeatherReportTasks(ctx context.Context, frequency string) ([]job.Task, error) {
+// GenerateWeatherReportTasks groups subscriptions due at the scheduled hour into one task per city,
+// so weather is fetched once per city per run. When more than one digest subscription of an
+// address is due, they go into one digest task of that address instead.
+func (s Service) GenerateWeatherReportTasks(ctx context.Context, frequency string, at time.Time) ([]job.Task, error) {
 	subs, err := s.listConfirmedByFrequency(ctx, frequency)
 	if err != nil {
 		return nil, err
 	}
 
-	tasks := make([]job.Task, 0, len(subs))
+	due := make([]domain.Subscription, 0, len(subs))
+	digestsDue := make(map[string]int)
 	for _, sub := range subs {
-		tasks = append(tasks, job.Task{
-			Email: sub.Email,
-			City:  sub.City,
-			Token: sub.Token,
-		})
+		if !sub.IsDueAt(at) {
+			continue
+		}
+		due = append(due, sub)
+		if inDigest(sub) {
+			digestsDue[sub.Email]++
+		}
+	}
+
+	tasks := make([]job.Task, 0)
+	byCity := make(map[string]int)
+	byEmail := make(map[string]int)
+	for _, sub := range due {
+		r := job.Recipient{
+			SubscriptionID: sub.ID,
+			Email:          sub.Email,
+			Token:          sub.Token,
+			Locale:         sub.Locale,
+			Channel:        string(sub.Channel),
+		}
+		if inDigest(sub) && digestsDue[sub.Email] > 1 {
+			i, ok := byEmail[sub.Email]
+			if !ok {
+				i = len(tasks)
+				byEmail[sub.Email] = i
+				tasks = append(tasks, job.Task{Digest: true})
+			}
+			r.City = sub.City
+			tasks[i].Recipients = append(tasks[i].Recipients, r)
+			continue
+		}
+
+		i, ok := byCity[sub.City]
+		if !ok {
+			i = len(tasks)
+			byCity[sub.City] = i
+			tasks = append(tasks, job.Task{City: sub.City})
+		}
+		tasks[i].Recipients = append(tasks[i].Recipients, r)
 	}
 	return tasks, nil
 }
 
+// inDigest reports whether the subscription's reports are combined into digests; only emails are.
+func inDigest(sub domain.Subscription) bool {
+	return sub.Digest && sub.Channel != domain.ChannelWebhook
+}
+
+// ProcessWeatherReportTask fetches the city's weather once per recipient locale and enqueues a report
+// for every recipient on its channel. Reports and their delivery records are written in one transaction,
+// so a retried task never delivers a partial set twice.
 func (s Service) ProcessWeatherReportTask(ctx context.Context, task job.Task) error {
-	report, err := s.weatherService.GetWeather(ctx, task.City)
-	if err != nil {
-		return fmt.Errorf("get weather for %s: %w", task.City, err)
+	if task.Digest {
+		return s.processDigestTask(ctx, task)
 	}
+	hour := reportHour(task)
 
-	nowHour := time.Now().UTC().Format("2006-01-02T15")
-	idKey := fmt.Sprintf("report:%s:%s", task.Email, nowHour)
-	if err := s.emailService.SendWeatherReport(ctx, task.Email, report, task.City, task.Token, idKey); err != nil {
-		return fmt.Errorf("send email to %s: %w", task.Email, err)
+	reports := make(map[string]domain.Report)
+	for _, r := range task.Recipients {
+		if _, ok := reports[r.Locale]; ok {
+			continue
+		}
+		report, err := s.weatherService.GetWeather(ctx, task.City, r.Locale)
+		if err != nil {
+			s.recordFailedDeliveries(ctx, task, hour, err)
+			return fmt.Errorf("get weather for %s: %w", task.City, err)
+		}
+		reports[r.Locale] = report
 	}
 
-	return nil
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, r := range task.Recipients {
+			report := reports[r.Locale]
+			idKey := reportIdempotencyKey(task, r, hour)
+			n, err := s.notifierFor(r.Channel)
+			if err != nil {
+				return fmt.Errorf("send report to %s: %w", r.Email, err)
+			}
+			if err := n.SendWeatherReport(ctx, r, task.City, report, idKey); err != nil {
+				return fmt.Errorf("send report to %s: %w", r.Email, err)
+			}
+
+			d := newDelivery(task, r, idKey, domain.DeliveryQueued)
+			d.Weather = &report
+			if err := s.deliveries.Record(ctx, d); err != nil {
+				return fmt.Errorf("record delivery to %s: %w", r.Email, err)
+			}
+		}
+		return nil
+	})
+}
+
+// processDigestTask fetches the weather of every city of a digest task and enqueues one email
+// with all of them. The digest goes out in one language, that of the address's first due
+// subscription. Each subscription gets a delivery record; they share the key of the email.
+func (s Service) processDigestTask(ctx context.Context, task job.Task) error {
+	hour := reportHour(task)
+
+	locale := task.Recipients[0].Locale
+	recipients := make([]job.Recipient, len(task.Recipients))
+	for i, r := range task.Recipients {
+		r.Locale = locale
+		recipients[i] = r
+	}
+	task.Recipients = recipients
+
+	entries := make([]domain.DigestEntry, 0, len(recipients))
+	for _, r := range recipients {
+		report, err := s.weatherService.GetWeather(ctx, r.City, locale)
+		if err != nil {
+			s.recordFailedDeliveries(ctx, task, hour, err)
+			return fmt.Errorf("get weather for %s: %w", r.City, err)
+		}
+		entries = append(entries, domain.DigestEntry{City: r.City, Report: report, Token: r.Token})
+	}
+
+	email := recipients[0].Email
+	idKey := reportIdempotencyKey(task, recipients[0], hour)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.emailService.SendWeatherDigest(ctx, email, locale, entries, idKey); err != nil {
+			return fmt.Errorf("send digest to %s: %w", email, err)
+		}
+		for i, r := range recipients {
+			d := newDelivery(task, r, idKey, domain.DeliveryQueued)
+			d.Weather = &entries[i].Report
+			if err := s.deliveries.Record(ctx, d); err != nil {
+				return fmt.Errorf("record delivery to %s: %w", r.Email, err)
+			}
+		}
+		return nil
+	})
+}
+
+// recordFailedDeliveries marks the task's reports as failed so the history explains a missing email.
+// The task is retried by the queue, which moves the records back to queued on success.
+func (s Service) recordFailedDeliveries(ctx context.Context, task job.Task, hour string, cause error) {
+	logger := loggerPkg.From(ctx)
+	now := time.Now()
+	for _, r := range task.Recipients {
+		d := newDelivery(task, r, reportIdempotencyKey(task, r, hour), domain.DeliveryFailed)
+		d.Error = cause.Error()
+		d.FailedAt = &now
+		if err := s.deliveries.Record(ctx, d); err != nil {
+			logger.Error("Failed to record failed delivery", "user", loggerPkg.HashEmail(r.Email), "err", err)
+		}
+	}
+}
+
+// newDelivery records a report to a recipient; recipients of digest tasks name their own city.
+func newDelivery(task job.Task, r job.Recipient, idKey string, status domain.DeliveryStatus) domain.Delivery {
+	now := time.Now()
+	city := task.City
+	if r.City != "" {
+		city = r.City
+	}
+	return domain.Delivery{
+		SubscriptionID: r.SubscriptionID,
+		Email:          r.Email,
+		City:           city,
+		Locale:         r.Locale,
+		RunID:          task.RunID,
+		JobID:          task.ID,
+		IdempotencyKey: idKey,
+		Status:         status,
+		QueuedAt:       now,
+		UpdatedAt:      now,
+	}
+}
+
+// reportHour is the hour a task's reports are keyed by: the scheduled slot of its run, so a
+// catch-up replay of several slots sends each one. Tasks queued without a slot use the current hour.
+func reportHour(task job.Task) string {
+	at := task.ScheduledAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return at.UTC().Format("2006-01-02T15")
+}
+
+// reportIdempotencyKey keys the email of a recipient's report of the hour. An address may get
+// reports for several cities in the same hour, so the key names the subscription; all recipients
+// of a digest share the key of its one email. An address may also get an hourly and a daily digest
+// in the same hour, so digest keys name the run.
+func reportIdempotencyKey(task job.Task, r job.Recipient, hour string) string {
+	if task.Digest {
+		return fmt.Sprintf("digest:%s:%d:%s", r.Email, task.RunID, hour)
+	}
+	return fmt.Sprintf("report:%s:%s:%s", r.Email, r.SubscriptionID, hour)
 }
 
 func (s Service) listConfirmedByFrequency(ctx context.Context, frequency string) ([]domain.Subscription, error) {
 	return s.repo.GetConfirmedByFrequency(ctx, frequency)
 }
 
-func (s Service) createOrUpdateSubscription(ctx context.Context, existing *domain.Subscription, email, city string, frequency domain.Frequency, token string) error {
+func (s Service) createOrUpdateSubscription(ctx context.Context, existing *domain.Subscription, id, email, city string, frequency domain.Frequency, locale string, channel domain.Channel, token string) (*domain.Subscription, error) {
 	now := time.Now()
 
 	if existing != nil {
@@ -193,33 +507,45 @@ func (s Service) createOrUpdateSubscription(ctx context.Context, existing *domai
 			Email:          existing.Email,
 			City:           city,
 			Frequency:      frequency,
+			DeliveryHour:   existing.DeliveryHour,
+			Locale:         locale,
+			Channel:        channel,
 			Token:          token,
 			IsConfirmed:    false,
 			IsUnsubscribed: false,
 			CreatedAt:      now,
+			// Kept so that issuing the next feed token still revokes this one.
+			FeedToken: existing.FeedToken,
+			// Preferences outlive a resubscribe like the delivery hour; only confirmation starts over.
+			PausedUntil: existing.PausedUntil,
+			QuietHours:  existing.QuietHours,
+			Digest:      existing.Digest,
 		}
 		if err := s.repo.Update(ctx, updatedSub); err != nil {
-			return fmt.Errorf("failed to update subscription: %w", err)
-		}
-	} else {
-		sub := &domain.Subscription{
-			ID:             uuid.New().String(),
-			Email:          email,
-			City:           city,
-			Frequency:      frequency,
-			Token:          token,
-			IsConfirmed:    false,
-			IsUnsubscribed: false,
-			CreatedAt:      now,
-		}
-		if err := s.repo.Create(ctx, sub); err != nil {
-			return fmt.Errorf("failed to create subscription: %w", err)
+			return nil, fmt.Errorf("failed to update subscription: %w", err)
 		}
+		return updatedSub, nil
 	}
 
-	return nil
+	sub := &domain.Subscription{
+		ID:             id,
+		Email:          email,
+		City:           city,
+		Frequency:      frequency,
+		DeliveryHour:   domain.DefaultDeliveryHour,
+		Locale:         locale,
+		Channel:        channel,
+		Token:          token,
+		IsConfirmed:    false,
+		IsUnsubscribed: false,
+		CreatedAt:      now,
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return sub, nil
 }
 
-func (s Service) generateIdempotencyKey(email, token string) string {
+func confirmIdempotencyKey(email, token string) string {
 	return fmt.Sprintf("confirm:%s:%s", email, token)
 }
@@ -3,9 +3,12 @@ package subscription_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"subscription/internal/domain"
+	"subscription/internal/job"
 
 	"subscription/internal/subscription"
 
@@ -17,14 +20,32 @@ import (
 
 type mockRepo struct{ mock.Mock }
 
-func (m *mockRepo) GetByEmail(ctx context.Context, email string) (*domain.Subscription, error) {
+func (m *mockRepo) ListByEmail(ctx context.Context, email string) ([]domain.Subscription, error) {
 	args := m.Called(ctx, email)
+	subs, _ := args.Get(0).([]domain.Subscription)
+	return subs, args.Error(1)
+}
+
+func (m *mockRepo) GetByEmailAndCity(ctx context.Context, email, city string) (*domain.Subscription, error) {
+	args := m.Called(ctx, email, city)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Subscription), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepo) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	args := m.Called(ctx, id)
 	if s := args.Get(0); s != nil {
 		return s.(*domain.Subscription), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+func (m *mockRepo) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
 func (m *mockRepo) Create(ctx context.Context, sub *domain.Subscription) error {
 	return m.Called(ctx, sub).Error(0)
 }
@@ -40,30 +61,47 @@ func (m *mockRepo) GetConfirmedByFrequency(ctx context.Context, frequency string
 
 type mockTokenService struct{ mock.Mock }
 
-func (m *mockTokenService) Generate(email string) (string, error) {
-	args := m.Called(email)
+func (m *mockTokenService) Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error) {
+	args := m.Called(subscriptionID, purpose)
 	return args.String(0), args.Error(1)
 }
 
-func (m *mockTokenService) Parse(token string) (string, error) {
-	args := m.Called(token)
+func (m *mockTokenService) GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error) {
+	args := m.Called(address, purpose)
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockTokenService) Parse(_ context.Context, token string, purpose domain.TokenPurpose) (domain.TokenClaims, error) {
+	args := m.Called(token, purpose)
+	return args.Get(0).(domain.TokenClaims), args.Error(1)
+}
+
+func (m *mockTokenService) Revoke(_ context.Context, token string) error {
+	return m.Called(token).Error(0)
+}
+
 type mockEmailService struct{ mock.Mock }
 
-func (m *mockEmailService) SendConfirmationEmail(ctx context.Context, email, token string, _ string) error {
-	return m.Called(email, token).Error(0)
+func (m *mockEmailService) SendConfirmationEmail(ctx context.Context, email, locale, token string, _ string) error {
+	return m.Called(email, locale, token).Error(0)
+}
+
+func (m *mockEmailService) SendWeatherReport(ctx context.Context, email, locale string, weatherReport domain.Report, city, token string, _ string) error {
+	return m.Called(email, locale, weatherReport, city, token).Error(0)
 }
 
-func (m *mockEmailService) SendWeatherReport(ctx context.Context, email string, weatherReport domain.Report, city, token string, _ string) error {
-	return m.Called(email, weatherReport, city, token).Error(0)
+func (m *mockEmailService) SendWeatherDigest(ctx context.Context, email, locale string, entries []domain.DigestEntry, _ string) error {
+	return m.Called(email, locale, entries).Error(0)
+}
+
+func (m *mockEmailService) SendManageLink(ctx context.Context, email, locale, token string, _ string) error {
+	return m.Called(email, locale, token).Error(0)
 }
 
 type mockCityValidator struct{ mock.Mock }
 
-func (m *mockCityValidator) GetWeather(ctx context.Context, city string) (domain.Report, error) {
-	args := m.Called(ctx, city)
+func (m *mockCityValidator) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	args := m.Called(ctx, city, lang)
 	return args.Get(0).(domain.Report), args.Error(1)
 }
 
@@ -72,12 +110,67 @@ func (m *mockCityValidator) CityIsValid(ctx context.Context, city string) (bool,
 	return args.Bool(0), args.Error(1)
 }
 
+type mockDeliveries struct{ mock.Mock }
+
+func (m *mockDeliveries) Record(ctx context.Context, d domain.Delivery) error {
+	return m.Called(d).Error(0)
+}
+
+// recordedEvent is a lifecycle event captured by fakeEvents.
+type recordedEvent struct {
+	Type    string
+	Sub     domain.Subscription
+	Reason  string
+	Changes []string
+}
+
+type fakeEvents struct {
+	err    error
+	events []recordedEvent
+}
+
+func (f *fakeEvents) record(e recordedEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, e)
+	return nil
+}
+
+func (f *fakeEvents) Created(_ context.Context, sub domain.Subscription) error {
+	return f.record(recordedEvent{Type: "created", Sub: sub})
+}
+
+func (f *fakeEvents) Confirmed(_ context.Context, sub domain.Subscription) error {
+	return f.record(recordedEvent{Type: "confirmed", Sub: sub})
+}
+
+func (f *fakeEvents) Unsubscribed(_ context.Context, sub domain.Subscription, reason string) error {
+	return f.record(recordedEvent{Type: "unsubscribed", Sub: sub, Reason: reason})
+}
+
+func (f *fakeEvents) Updated(_ context.Context, sub domain.Subscription, changes []string) error {
+	return f.record(recordedEvent{Type: "updated", Sub: sub, Changes: changes})
+}
+
+func (f *fakeEvents) Removed(_ context.Context, sub domain.Subscription, reason string) error {
+	return f.record(recordedEvent{Type: "removed", Sub: sub, Reason: reason})
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 type testDeps struct {
-	repo      *mockRepo
-	tokens    *mockTokenService
-	emails    *mockEmailService
-	validator *mockCityValidator
-	service   subscription.Service
+	repo       *mockRepo
+	tokens     *mockTokenService
+	emails     *mockEmailService
+	validator  *mockCityValidator
+	deliveries *mockDeliveries
+	events     *fakeEvents
+	service    subscription.Service
 }
 
 func createTestService() *testDeps {
@@ -85,9 +178,17 @@ func createTestService() *testDeps {
 	tokens := new(mockTokenService)
 	emails := new(mockEmailService)
 	validator := new(mockCityValidator)
-	service := subscription.NewService(repo, emails, validator, tokens)
+	deliveries := new(mockDeliveries)
+	events := new(fakeEvents)
+	service := subscription.NewService(repo, emails, validator, tokens, fakeTransactor{}, deliveries, events, nil, nil)
 
-	return &testDeps{repo, tokens, emails, validator, service}
+	return &testDeps{repo, tokens, emails, validator, deliveries, events, service}
+}
+
+// expectTokens stubs token generation for a subscribe call.
+func (d *testDeps) expectTokens(confirmToken string) {
+	d.tokens.On("Generate", mock.Anything, domain.TokenPurposeUnsubscribe).Return("unsubscribe-token", nil)
+	d.tokens.On("Generate", mock.Anything, domain.TokenPurposeConfirm).Return(confirmToken, nil)
 }
 
 // --- SUBSCRIBE ---
@@ -101,20 +202,26 @@ func TestSubscribe_SendsConfirmationEmail_Success(t *testing.T) {
 	token := "abc-token"
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, subscription.ErrSubscriptionNotFound)
-	d.tokens.On("Generate", email).Return(token, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(nil, subscription.ErrSubscriptionNotFound)
+	d.expectTokens(token)
 	d.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil)
 
-	d.emails.On("SendConfirmationEmail", email, token).Return(nil).Once()
+	d.emails.On("SendConfirmationEmail", email, "uk", token).Return(nil).Once()
 
-	err := d.service.Subscribe(ctx, email, city, frequency)
+	err := d.service.Subscribe(ctx, email, city, frequency, "uk")
 
 	assert.NoError(t, err)
-	d.emails.AssertCalled(t, "SendConfirmationEmail", email, token)
+	d.emails.AssertCalled(t, "SendConfirmationEmail", email, "uk", token)
 	d.emails.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, "created", d.events.events[0].Type)
+		assert.Equal(t, domain.StatusPending, d.events.events[0].Sub.Status())
+		assert.Equal(t, city, d.events.events[0].Sub.City)
+		assert.Equal(t, "uk", d.events.events[0].Sub.Locale)
+	}
 }
 
-func TestSubscribe_EmailSendFails_ButSubscribeStillSuccess(t *testing.T) {
+func TestSubscribe_EmailEnqueueFails_ReturnsErr(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
 	email := "fail@example.com"
@@ -123,16 +230,16 @@ func TestSubscribe_EmailSendFails_ButSubscribeStillSuccess(t *testing.T) {
 	token := "fail-token"
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, subscription.ErrSubscriptionNotFound)
-	d.tokens.On("Generate", email).Return(token, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(nil, subscription.ErrSubscriptionNotFound)
+	d.expectTokens(token)
 	d.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil)
 
-	d.emails.On("SendConfirmationEmail", email, token).Return(errors.New("smtp timeout")).Once()
+	d.emails.On("SendConfirmationEmail", email, "uk", token).Return(errors.New("outbox write error")).Once()
 
-	err := d.service.Subscribe(ctx, email, city, frequency)
+	err := d.service.Subscribe(ctx, email, city, frequency, "uk")
 
-	assert.NoError(t, err) // ми не ламаємо логіку підписки
-	d.emails.AssertCalled(t, "SendConfirmationEmail", email, token)
+	assert.Error(t, err) // the transaction rolls back, so the subscription is not stored without its email
+	d.emails.AssertCalled(t, "SendConfirmationEmail", email, "uk", token)
 }
 
 func TestSubscribe_RenewsUnsubscribedUser(t *testing.T) {
@@ -146,12 +253,12 @@ func TestSubscribe_RenewsUnsubscribedUser(t *testing.T) {
 	existing := &domain.Subscription{Email: email, IsConfirmed: true, IsUnsubscribed: true}
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(existing, nil)
-	d.tokens.On("Generate", email).Return(token, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
+	d.expectTokens(token)
 	d.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil)
-	d.emails.On("SendConfirmationEmail", email, token).Maybe().Return(nil)
+	d.emails.On("SendConfirmationEmail", email, "uk", token).Maybe().Return(nil)
 
-	err := d.service.Subscribe(ctx, email, city, frequency)
+	err := d.service.Subscribe(ctx, email, city, frequency, "uk")
 
 	assert.NoError(t, err)
 	d.validator.AssertExpectations(t)
@@ -160,6 +267,60 @@ func TestSubscribe_RenewsUnsubscribedUser(t *testing.T) {
 	d.emails.AssertExpectations(t)
 }
 
+func TestSubscribe_ResubscribeKeepsPreferences(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	city := "Kyiv"
+	token := "new-token"
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	quiet := &domain.QuietHours{Start: 22, End: 7}
+
+	existing := &domain.Subscription{
+		ID: "sub-1", Email: email, City: city, IsConfirmed: true, IsUnsubscribed: true,
+		DeliveryHour: 9, PausedUntil: &until, QuietHours: quiet, Digest: true, FeedToken: "feed-token",
+	}
+
+	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
+	d.expectTokens(token)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(sub *domain.Subscription) bool {
+		return !sub.IsConfirmed && sub.DeliveryHour == 9 && sub.PausedUntil == &until &&
+			sub.QuietHours == quiet && sub.Digest && sub.FeedToken == "feed-token"
+	})).Return(nil).Once()
+	d.emails.On("SendConfirmationEmail", email, "uk", token).Maybe().Return(nil)
+
+	err := d.service.Subscribe(ctx, email, city, domain.FreqHourly, "uk")
+
+	assert.NoError(t, err)
+	d.repo.AssertExpectations(t)
+}
+
+func TestSubscribe_RevokesPreviousUnsubscribeToken(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	city := "Kyiv"
+
+	existing := &domain.Subscription{ID: "sub-1", Email: email, Token: "old-unsubscribe-token"}
+
+	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
+	d.tokens.On("Generate", "sub-1", domain.TokenPurposeUnsubscribe).Return("new-unsubscribe-token", nil)
+	d.tokens.On("Generate", "sub-1", domain.TokenPurposeConfirm).Return("confirm-token", nil)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(sub *domain.Subscription) bool {
+		return sub.ID == "sub-1" && sub.Token == "new-unsubscribe-token"
+	})).Return(nil)
+	d.tokens.On("Revoke", "old-unsubscribe-token").Return(nil).Once()
+	d.emails.On("SendConfirmationEmail", email, "uk", "confirm-token").Return(nil)
+
+	err := d.service.Subscribe(ctx, email, city, domain.FreqDaily, "uk")
+
+	assert.NoError(t, err)
+	d.tokens.AssertExpectations(t)
+	d.repo.AssertExpectations(t)
+}
+
 func TestSubscribe_CityValidatorFails_Error(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
@@ -168,7 +329,7 @@ func TestSubscribe_CityValidatorFails_Error(t *testing.T) {
 
 	d.validator.On("CityIsValid", ctx, city).Return(false, subscription.ErrCityNotFound)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.ErrorIs(t, err, subscription.ErrCityNotFound)
 	d.validator.AssertExpectations(t)
@@ -183,7 +344,7 @@ func TestSubscribe_CityValidatorUnexpectedError(t *testing.T) {
 	validatorErr := errors.New("validator service down")
 	d.validator.On("CityIsValid", ctx, city).Return(false, validatorErr)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to validate city")
@@ -205,23 +366,23 @@ func TestSubscribe_AlreadySubscribed_ReturnsErr(t *testing.T) {
 	}
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(existing, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.ErrorIs(t, err, subscription.ErrEmailAlreadyExists)
 }
 
-func TestSubscribe_GetByEmailUnexpectedError_ReturnsErr(t *testing.T) {
+func TestSubscribe_LookupUnexpectedError_ReturnsErr(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
 	email := "user@example.com"
 	city := "Kyiv"
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, assert.AnError)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(nil, assert.AnError)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to check existing subscription")
@@ -240,10 +401,10 @@ func TestSubscribe_TokenGenerationFails_ReturnsErr(t *testing.T) {
 	}
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(existing, nil)
-	d.tokens.On("Generate", email).Return("", assert.AnError)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
+	d.tokens.On("Generate", mock.Anything, domain.TokenPurposeUnsubscribe).Return("", assert.AnError)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "could not generate token")
@@ -263,11 +424,11 @@ func TestSubscribe_UpdateFails_ReturnsErr(t *testing.T) {
 	}
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(existing, nil)
-	d.tokens.On("Generate", email).Return(token, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(existing, nil)
+	d.expectTokens(token)
 	d.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(assert.AnError)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to update subscription")
@@ -281,11 +442,11 @@ func TestSubscribe_CreateFails_ReturnsErr(t *testing.T) {
 	token := "token456"
 
 	d.validator.On("CityIsValid", ctx, city).Return(true, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, subscription.ErrSubscriptionNotFound)
-	d.tokens.On("Generate", email).Return(token, nil)
+	d.repo.On("GetByEmailAndCity", ctx, email, city).Return(nil, subscription.ErrSubscriptionNotFound)
+	d.expectTokens(token)
 	d.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(assert.AnError)
 
-	err := d.service.Subscribe(ctx, email, city, "daily")
+	err := d.service.Subscribe(ctx, email, city, "daily", "uk")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to create subscription")
@@ -300,8 +461,8 @@ func TestConfirm_ValidToken_Success(t *testing.T) {
 	token := "valid-token"
 	sub := &domain.Subscription{Email: email, IsConfirmed: false}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 	d.repo.On("Update", ctx, sub).Return(nil)
 
 	err := d.service.Confirm(ctx, token)
@@ -309,16 +470,33 @@ func TestConfirm_ValidToken_Success(t *testing.T) {
 	assert.NoError(t, err)
 	d.tokens.AssertExpectations(t)
 	d.repo.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, "confirmed", d.events.events[0].Type)
+	}
+}
+
+func TestConfirm_EventFails_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	sub := &domain.Subscription{ID: "sub-1", Email: "user@example.com"}
+	d.events.err = errors.New("outbox write failed")
+
+	d.tokens.On("Parse", "valid-token", domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
+	d.repo.On("Update", ctx, sub).Return(nil)
+
+	err := d.service.Confirm(ctx, "valid-token")
+
+	assert.ErrorIs(t, err, d.events.err)
 }
 
 func TestConfirm_SubscriptionNotFound(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
-	email := "notfound@example.com"
 	token := "token-404"
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, subscription.ErrSubscriptionNotFound)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(nil, subscription.ErrSubscriptionNotFound)
 
 	err := d.service.Confirm(ctx, token)
 
@@ -327,15 +505,14 @@ func TestConfirm_SubscriptionNotFound(t *testing.T) {
 	d.repo.AssertExpectations(t)
 }
 
-func TestConfirm_UnexpectedGetByEmailError(t *testing.T) {
+func TestConfirm_UnexpectedGetByIDError(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
 	token := "token123"
-	email := "user@example.com"
 	fakeErr := errors.New("db timeout")
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, fakeErr)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(nil, fakeErr)
 
 	err := d.service.Confirm(ctx, token)
 
@@ -352,7 +529,7 @@ func TestConfirm_InvalidToken(t *testing.T) {
 	ctx := context.Background()
 	token := "invalid-token"
 
-	d.tokens.On("Parse", token).Return("", subscription.ErrInvalidToken)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{}, subscription.ErrInvalidToken)
 
 	err := d.service.Confirm(ctx, token)
 
@@ -367,14 +544,15 @@ func TestConfirm_AlreadyConfirmed(t *testing.T) {
 	token := "valid-token"
 	sub := &domain.Subscription{Email: email, IsConfirmed: true}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 
 	err := d.service.Confirm(ctx, token)
 
 	assert.NoError(t, err)
 	d.tokens.AssertExpectations(t)
 	d.repo.AssertExpectations(t)
+	assert.Empty(t, d.events.events)
 }
 
 func TestConfirm_UpdateFails(t *testing.T) {
@@ -384,8 +562,8 @@ func TestConfirm_UpdateFails(t *testing.T) {
 	token := "token"
 	sub := &domain.Subscription{Email: email, IsConfirmed: false}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeConfirm).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 	d.repo.On("Update", ctx, sub).Return(assert.AnError)
 
 	err := d.service.Confirm(ctx, token)
@@ -405,8 +583,8 @@ func TestUnsubscribe_ValidToken_Success(t *testing.T) {
 	token := "valid-token"
 	sub := &domain.Subscription{Email: email, IsUnsubscribed: false}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 	d.repo.On("Update", ctx, sub).Return(nil)
 
 	err := d.service.Unsubscribe(ctx, token)
@@ -414,6 +592,41 @@ func TestUnsubscribe_ValidToken_Success(t *testing.T) {
 	assert.NoError(t, err)
 	d.tokens.AssertExpectations(t)
 	d.repo.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 1) {
+		assert.Equal(t, "unsubscribed", d.events.events[0].Type)
+		assert.Equal(t, subscription.UnsubscribeReasonLink, d.events.events[0].Reason)
+	}
+}
+
+func TestUnsubscribe_LegacyToken_FindsOldestByEmail(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	email := "user@example.com"
+	subs := []domain.Subscription{{ID: "sub-1", Email: email, City: "Kyiv"}, {ID: "sub-2", Email: email, City: "Lviv"}}
+
+	d.tokens.On("Parse", "legacy-token", domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{ID: "legacy-1", Email: email}, nil)
+	d.repo.On("ListByEmail", ctx, email).Return(subs, nil)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(s *domain.Subscription) bool {
+		return s.ID == "sub-1" && s.IsUnsubscribed
+	})).Return(nil).Once()
+
+	err := d.service.Unsubscribe(ctx, "legacy-token")
+
+	assert.NoError(t, err)
+	d.repo.AssertExpectations(t)
+	d.repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUnsubscribe_LegacyToken_UnknownAddress(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+
+	d.tokens.On("Parse", "legacy-token", domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{ID: "legacy-1", Email: "gone@example.com"}, nil)
+	d.repo.On("ListByEmail", ctx, "gone@example.com").Return([]domain.Subscription{}, nil)
+
+	err := d.service.Unsubscribe(ctx, "legacy-token")
+
+	assert.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
 }
 
 func TestUnsubscribe_AlreadyUnsubscribed(t *testing.T) {
@@ -423,8 +636,8 @@ func TestUnsubscribe_AlreadyUnsubscribed(t *testing.T) {
 	token := "token"
 	sub := &domain.Subscription{Email: email, IsUnsubscribed: true}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 
 	err := d.service.Unsubscribe(ctx, token)
 
@@ -438,7 +651,7 @@ func TestUnsubscribe_InvalidToken_ReturnsErr(t *testing.T) {
 	ctx := context.Background()
 	token := "bad-token"
 
-	d.tokens.On("Parse", token).Return("", subscription.ErrInvalidToken)
+	d.tokens.On("Parse", token, domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{}, subscription.ErrInvalidToken)
 
 	err := d.service.Unsubscribe(ctx, token)
 
@@ -446,14 +659,13 @@ func TestUnsubscribe_InvalidToken_ReturnsErr(t *testing.T) {
 	d.tokens.AssertExpectations(t)
 }
 
-func TestUnsubscribe_GetByEmailFails_ReturnsErr(t *testing.T) {
+func TestUnsubscribe_GetByIDFails_ReturnsErr(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
-	email := "user@example.com"
 	token := "valid-token"
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(nil, assert.AnError)
+	d.tokens.On("Parse", token, domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(nil, assert.AnError)
 
 	err := d.service.Unsubscribe(ctx, token)
 
@@ -470,8 +682,8 @@ func TestUnsubscribe_UpdateFails_ReturnsErr(t *testing.T) {
 	token := "valid-token"
 	sub := &domain.Subscription{Email: email, IsUnsubscribed: false}
 
-	d.tokens.On("Parse", token).Return(email, nil)
-	d.repo.On("GetByEmail", ctx, email).Return(sub, nil)
+	d.tokens.On("Parse", token, domain.TokenPurposeUnsubscribe).Return(domain.TokenClaims{SubscriptionID: "sub-1"}, nil)
+	d.repo.On("GetByID", ctx, "sub-1").Return(sub, nil)
 	d.repo.On("Update", ctx, sub).Return(assert.AnError)
 
 	err := d.service.Unsubscribe(ctx, token)
@@ -484,25 +696,118 @@ func TestUnsubscribe_UpdateFails_ReturnsErr(t *testing.T) {
 
 // ---GENERATE_WEATHER_REPORT_TASKS ---
 
-func TestGenerateWeatherReportTasks_Success(t *testing.T) {
+func TestGenerateWeatherReportTasks_GroupsByCity(t *testing.T) {
 	d := createTestService()
 	ctx := context.Background()
-	frequency := "daily"
+	frequency := "hourly"
 
 	subs := []domain.Subscription{
-		{Email: "a@example.com", City: "Kyiv", Token: "token1"},
-		{Email: "b@example.com", City: "Lviv", Token: "token2"},
+		{Email: "a@example.com", City: "Kyiv", Frequency: domain.FreqHourly, Token: "token1"},
+		{Email: "b@example.com", City: "Lviv", Frequency: domain.FreqHourly, Token: "token2"},
+		{Email: "c@example.com", City: "Kyiv", Frequency: domain.FreqHourly, Token: "token3", Locale: "en"},
 	}
 
 	d.repo.On("GetConfirmedByFrequency", ctx, frequency).Return(subs, nil)
 
-	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency)
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency, time.Now())
 
 	assert.NoError(t, err)
 	assert.Len(t, tasks, 2)
-	assert.Equal(t, "a@example.com", tasks[0].Email)
 	assert.Equal(t, "Kyiv", tasks[0].City)
-	assert.Equal(t, "token1", tasks[0].Token)
+	assert.Equal(t, []job.Recipient{
+		{Email: "a@example.com", Token: "token1"},
+		{Email: "c@example.com", Token: "token3", Locale: "en"},
+	}, tasks[0].Recipients)
+	assert.Equal(t, "Lviv", tasks[1].City)
+	assert.Len(t, tasks[1].Recipients, 1)
+}
+
+func TestGenerateWeatherReportTasks_DailySkipsOtherDeliveryHours(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	frequency := "daily"
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	subs := []domain.Subscription{
+		{Email: "due@example.com", City: "Kyiv", Frequency: domain.FreqDaily, DeliveryHour: 12},
+		{Email: "later@example.com", City: "Lviv", Frequency: domain.FreqDaily, DeliveryHour: 13},
+	}
+
+	d.repo.On("GetConfirmedByFrequency", ctx, frequency).Return(subs, nil)
+
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency, at)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "due@example.com", tasks[0].Recipients[0].Email)
+}
+
+func TestGenerateWeatherReportTasks_SkipsPausedAndQuietHours(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	frequency := "hourly"
+	at := time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC) // 23:00 in Kyiv
+	pausedUntil := at.Add(48 * time.Hour)
+	pauseOver := at.Add(-time.Hour)
+
+	subs := []domain.Subscription{
+		{Email: "due@example.com", City: "Kyiv", Frequency: domain.FreqHourly},
+		{Email: "paused@example.com", City: "Kyiv", Frequency: domain.FreqHourly, PausedUntil: &pausedUntil},
+		{Email: "back@example.com", City: "Kyiv", Frequency: domain.FreqHourly, PausedUntil: &pauseOver},
+		{Email: "asleep@example.com", City: "Kyiv", Frequency: domain.FreqHourly,
+			QuietHours: &domain.QuietHours{Start: 22, End: 7, Timezone: "Europe/Kyiv"}},
+		{Email: "evening@example.com", City: "Kyiv", Frequency: domain.FreqHourly,
+			QuietHours: &domain.QuietHours{Start: 1, End: 6, Timezone: "Europe/Kyiv"}},
+	}
+
+	d.repo.On("GetConfirmedByFrequency", ctx, frequency).Return(subs, nil)
+
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency, at)
+
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 1) {
+		var emails []string
+		for _, r := range tasks[0].Recipients {
+			emails = append(emails, r.Email)
+		}
+		assert.Equal(t, []string{"due@example.com", "back@example.com", "evening@example.com"}, emails)
+	}
+}
+
+func TestGenerateWeatherReportTasks_GroupsDigestsByAddress(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	frequency := "hourly"
+
+	subs := []domain.Subscription{
+		{ID: "a-kyiv", Email: "a@example.com", City: "Kyiv", Frequency: domain.FreqHourly, Token: "t1", Digest: true},
+		{ID: "b-kyiv", Email: "b@example.com", City: "Kyiv", Frequency: domain.FreqHourly, Token: "t2"},
+		{ID: "a-lviv", Email: "a@example.com", City: "Lviv", Frequency: domain.FreqHourly, Token: "t3", Digest: true, Locale: "en"},
+		// A single digest subscription due, or one on the webhook channel, gets its own report.
+		{ID: "c-lviv", Email: "c@example.com", City: "Lviv", Frequency: domain.FreqHourly, Token: "t4", Digest: true},
+		{ID: "a-odesa", Email: "a@example.com", City: "Odesa", Frequency: domain.FreqHourly, Token: "t5", Digest: true,
+			Channel: domain.ChannelWebhook},
+	}
+
+	d.repo.On("GetConfirmedByFrequency", ctx, frequency).Return(subs, nil)
+
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency, time.Now())
+
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 4) {
+		assert.True(t, tasks[0].Digest)
+		assert.Empty(t, tasks[0].City)
+		assert.Equal(t, []job.Recipient{
+			{SubscriptionID: "a-kyiv", Email: "a@example.com", Token: "t1", City: "Kyiv"},
+			{SubscriptionID: "a-lviv", Email: "a@example.com", Token: "t3", Locale: "en", City: "Lviv"},
+		}, tasks[0].Recipients)
+		assert.Equal(t, "Kyiv", tasks[1].City)
+		assert.Equal(t, "b-kyiv", tasks[1].Recipients[0].SubscriptionID)
+		assert.Equal(t, "Lviv", tasks[2].City)
+		assert.Equal(t, "c-lviv", tasks[2].Recipients[0].SubscriptionID)
+		assert.Equal(t, "Odesa", tasks[3].City)
+		assert.False(t, tasks[3].Digest)
+	}
 }
 
 func TestGenerateWeatherReportTasks_ListFails_ReturnsError(t *testing.T) {
@@ -513,9 +818,289 @@ func TestGenerateWeatherReportTasks_ListFails_ReturnsError(t *testing.T) {
 	d.repo.On("GetConfirmedByFrequency", ctx, frequency).
 		Return([]domain.Subscription(nil), assert.AnError)
 
-	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency)
+	tasks, err := d.service.GenerateWeatherReportTasks(ctx, frequency, time.Now())
 
 	assert.Nil(t, tasks)
 	assert.Error(t, err)
 	d.repo.AssertExpectations(t)
 }
+
+// ---PROCESS_WEATHER_REPORT_TASK ---
+
+func TestProcessWeatherReportTask_FetchesWeatherOncePerCity(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	task := job.Task{ID: "7", RunID: 3, City: "Kyiv", Recipients: []job.Recipient{
+		{SubscriptionID: "sub-a", Email: "a@example.com", Token: "token1"},
+		{SubscriptionID: "sub-b", Email: "b@example.com", Token: "token2"},
+	}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(report, nil).Once()
+	d.emails.On("SendWeatherReport", "a@example.com", "", report, "Kyiv", "token1").Return(nil).Once()
+	d.emails.On("SendWeatherReport", "b@example.com", "", report, "Kyiv", "token2").Return(nil).Once()
+	d.deliveries.On("Record", mock.Anything).Return(nil).Twice()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.NoError(t, err)
+	d.validator.AssertExpectations(t)
+	d.emails.AssertExpectations(t)
+	d.deliveries.AssertExpectations(t)
+
+	recorded := d.deliveries.Calls[0].Arguments.Get(0).(domain.Delivery)
+	assert.Equal(t, "sub-a", recorded.SubscriptionID)
+	assert.Equal(t, int64(3), recorded.RunID)
+	assert.Equal(t, "7", recorded.JobID)
+	assert.Equal(t, domain.DeliveryQueued, recorded.Status)
+	assert.Equal(t, &report, recorded.Weather)
+	assert.True(t, strings.HasPrefix(recorded.IdempotencyKey, "report:a@example.com:"))
+}
+
+func TestProcessWeatherReportTask_FetchesWeatherOncePerLocale(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	uk := domain.Report{Temperature: 20, Humidity: 50, Description: "Сонячно"}
+	en := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{
+		{Email: "a@example.com", Token: "token1", Locale: "uk"},
+		{Email: "b@example.com", Token: "token2", Locale: "en"},
+		{Email: "c@example.com", Token: "token3", Locale: "uk"},
+	}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "uk").Return(uk, nil).Once()
+	d.validator.On("GetWeather", ctx, "Kyiv", "en").Return(en, nil).Once()
+	d.emails.On("SendWeatherReport", "a@example.com", "uk", uk, "Kyiv", "token1").Return(nil).Once()
+	d.emails.On("SendWeatherReport", "b@example.com", "en", en, "Kyiv", "token2").Return(nil).Once()
+	d.emails.On("SendWeatherReport", "c@example.com", "uk", uk, "Kyiv", "token3").Return(nil).Once()
+	d.deliveries.On("Record", mock.Anything).Return(nil).Times(3)
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.NoError(t, err)
+	d.validator.AssertExpectations(t)
+	d.emails.AssertExpectations(t)
+}
+
+func TestProcessWeatherReportTask_RecordFails_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{{Email: "a@example.com", Token: "token1"}}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(report, nil)
+	d.emails.On("SendWeatherReport", "a@example.com", "", report, "Kyiv", "token1").Return(nil)
+	d.deliveries.On("Record", mock.Anything).Return(errors.New("db down"))
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.Error(t, err)
+}
+
+func TestProcessWeatherReportTask_WeatherFails_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{{Email: "a@example.com", Token: "token1"}}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(domain.Report{}, errors.New("weather down"))
+	d.deliveries.On("Record", mock.MatchedBy(func(del domain.Delivery) bool {
+		return del.Status == domain.DeliveryFailed && del.Error == "weather down" && del.FailedAt != nil
+	})).Return(nil).Once()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.Error(t, err)
+	d.emails.AssertNotCalled(t, "SendWeatherReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.deliveries.AssertExpectations(t)
+}
+
+func TestProcessWeatherReportTask_ReportsOfOneAddressUseOwnKeys(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	task := job.Task{City: "Kyiv", Recipients: []job.Recipient{
+		{SubscriptionID: "sub-a", Email: "a@example.com", Token: "token1"},
+	}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(report, nil)
+	d.emails.On("SendWeatherReport", "a@example.com", "", report, "Kyiv", "token1").Return(nil)
+	d.deliveries.On("Record", mock.Anything).Return(nil)
+
+	assert.NoError(t, d.service.ProcessWeatherReportTask(ctx, task))
+
+	recorded := d.deliveries.Calls[0].Arguments.Get(0).(domain.Delivery)
+	assert.True(t, strings.HasPrefix(recorded.IdempotencyKey, "report:a@example.com:sub-a:"))
+}
+
+func TestProcessWeatherReportTask_KeysReportsBySlot(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	recipients := []job.Recipient{{SubscriptionID: "sub-a", Email: "a@example.com", Token: "token1"}}
+	// Two missed daily slots replayed in the same hour.
+	first := job.Task{RunID: 1, ScheduledAt: time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC), City: "Kyiv", Recipients: recipients}
+	second := job.Task{RunID: 2, ScheduledAt: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), City: "Kyiv", Recipients: recipients}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(report, nil)
+	d.emails.On("SendWeatherReport", "a@example.com", "", report, "Kyiv", "token1").Return(nil).Twice()
+	d.deliveries.On("Record", mock.Anything).Return(nil)
+
+	assert.NoError(t, d.service.ProcessWeatherReportTask(ctx, first))
+	assert.NoError(t, d.service.ProcessWeatherReportTask(ctx, second))
+
+	keys := []string{
+		d.deliveries.Calls[0].Arguments.Get(0).(domain.Delivery).IdempotencyKey,
+		d.deliveries.Calls[1].Arguments.Get(0).(domain.Delivery).IdempotencyKey,
+	}
+	assert.Equal(t, []string{
+		"report:a@example.com:sub-a:2025-01-01T08",
+		"report:a@example.com:sub-a:2025-01-02T08",
+	}, keys)
+}
+
+func TestProcessWeatherReportTask_Digest_SendsOneEmail(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	kyiv := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	lviv := domain.Report{Temperature: 15, Humidity: 70, Description: "Rain"}
+	task := job.Task{ID: "9", RunID: 4, Digest: true, Recipients: []job.Recipient{
+		{SubscriptionID: "sub-kyiv", Email: "a@example.com", Token: "token1", Locale: "en", City: "Kyiv"},
+		{SubscriptionID: "sub-lviv", Email: "a@example.com", Token: "token2", Locale: "uk", City: "Lviv"},
+	}}
+
+	// The whole digest is in the language of its first subscription.
+	d.validator.On("GetWeather", ctx, "Kyiv", "en").Return(kyiv, nil).Once()
+	d.validator.On("GetWeather", ctx, "Lviv", "en").Return(lviv, nil).Once()
+	d.emails.On("SendWeatherDigest", "a@example.com", "en", []domain.DigestEntry{
+		{City: "Kyiv", Report: kyiv, Token: "token1"},
+		{City: "Lviv", Report: lviv, Token: "token2"},
+	}).Return(nil).Once()
+	d.deliveries.On("Record", mock.Anything).Return(nil).Twice()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.NoError(t, err)
+	d.validator.AssertExpectations(t)
+	d.emails.AssertExpectations(t)
+	d.emails.AssertNotCalled(t, "SendWeatherReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	first := d.deliveries.Calls[0].Arguments.Get(0).(domain.Delivery)
+	second := d.deliveries.Calls[1].Arguments.Get(0).(domain.Delivery)
+	assert.Equal(t, "sub-kyiv", first.SubscriptionID)
+	assert.Equal(t, "Kyiv", first.City)
+	assert.Equal(t, &kyiv, first.Weather)
+	assert.Equal(t, "sub-lviv", second.SubscriptionID)
+	assert.Equal(t, "Lviv", second.City)
+	assert.Equal(t, "en", second.Locale)
+	assert.Equal(t, int64(4), second.RunID)
+	assert.True(t, strings.HasPrefix(first.IdempotencyKey, "digest:a@example.com:"))
+	assert.Equal(t, first.IdempotencyKey, second.IdempotencyKey)
+	assert.Equal(t, "uk", task.Recipients[1].Locale, "the queued task is left as it was")
+}
+
+func TestProcessWeatherReportTask_Digest_KeysHourlyAndDailyRunsApart(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	report := domain.Report{Temperature: 20, Humidity: 50, Description: "Sunny"}
+	slot := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	// One address with an hourly and a daily digest subscription, both due at 08:00.
+	hourly := job.Task{RunID: 7, ScheduledAt: slot, Digest: true, Recipients: []job.Recipient{
+		{SubscriptionID: "sub-kyiv", Email: "a@example.com", Token: "token1", City: "Kyiv"},
+	}}
+	daily := job.Task{RunID: 8, ScheduledAt: slot, Digest: true, Recipients: []job.Recipient{
+		{SubscriptionID: "sub-lviv", Email: "a@example.com", Token: "token2", City: "Lviv"},
+	}}
+
+	d.validator.On("GetWeather", ctx, mock.Anything, "").Return(report, nil)
+	d.emails.On("SendWeatherDigest", "a@example.com", "", mock.Anything).Return(nil).Twice()
+	d.deliveries.On("Record", mock.Anything).Return(nil)
+
+	assert.NoError(t, d.service.ProcessWeatherReportTask(ctx, hourly))
+	assert.NoError(t, d.service.ProcessWeatherReportTask(ctx, daily))
+
+	keys := []string{
+		d.deliveries.Calls[0].Arguments.Get(0).(domain.Delivery).IdempotencyKey,
+		d.deliveries.Calls[1].Arguments.Get(0).(domain.Delivery).IdempotencyKey,
+	}
+	assert.Equal(t, []string{
+		"digest:a@example.com:7:2025-01-01T08",
+		"digest:a@example.com:8:2025-01-01T08",
+	}, keys)
+}
+
+func TestProcessWeatherReportTask_DigestWeatherFails_RecordsEveryCity(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	task := job.Task{Digest: true, Recipients: []job.Recipient{
+		{SubscriptionID: "sub-kyiv", Email: "a@example.com", City: "Kyiv"},
+		{SubscriptionID: "sub-lviv", Email: "a@example.com", City: "Lviv"},
+	}}
+
+	d.validator.On("GetWeather", ctx, "Kyiv", "").Return(domain.Report{}, nil)
+	d.validator.On("GetWeather", ctx, "Lviv", "").Return(domain.Report{}, errors.New("weather down"))
+	d.deliveries.On("Record", mock.MatchedBy(func(del domain.Delivery) bool {
+		return del.Status == domain.DeliveryFailed && del.City != ""
+	})).Return(nil).Twice()
+
+	err := d.service.ProcessWeatherReportTask(ctx, task)
+
+	assert.Error(t, err)
+	d.emails.AssertNotCalled(t, "SendWeatherDigest", mock.Anything, mock.Anything, mock.Anything)
+	d.deliveries.AssertExpectations(t)
+}
+
+// --- SUPPRESSION ---
+
+func TestDeactivateAddress_DeactivatesEveryActiveSubscription(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+	subs := []domain.Subscription{
+		{ID: "sub-1", Email: "user@example.com", City: "Kyiv", IsConfirmed: true},
+		{ID: "sub-2", Email: "user@example.com", City: "Lviv", IsConfirmed: true, IsUnsubscribed: true},
+		{ID: "sub-3", Email: "user@example.com", City: "Odesa"},
+	}
+
+	d.repo.On("ListByEmail", ctx, "user@example.com").Return(subs, nil)
+	d.repo.On("Update", ctx, mock.MatchedBy(func(s *domain.Subscription) bool { return s.IsUnsubscribed })).Return(nil).Twice()
+
+	deactivated, err := d.service.DeactivateAddress(ctx, " User@Example.com", "bounce")
+
+	assert.NoError(t, err)
+	assert.True(t, deactivated)
+	d.repo.AssertExpectations(t)
+	if assert.Len(t, d.events.events, 2) {
+		assert.Equal(t, "unsubscribed", d.events.events[0].Type)
+		assert.Equal(t, "bounce", d.events.events[0].Reason)
+		assert.Equal(t, "sub-1", d.events.events[0].Sub.ID)
+		assert.Equal(t, "sub-3", d.events.events[1].Sub.ID)
+	}
+}
+
+func TestDeactivateAddress_UnknownOrInactiveIsNoop(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+
+	d.repo.On("ListByEmail", ctx, "gone@example.com").Return([]domain.Subscription{}, nil)
+	d.repo.On("ListByEmail", ctx, "left@example.com").Return([]domain.Subscription{{ID: "sub-2", IsUnsubscribed: true}}, nil)
+
+	deactivated, err := d.service.DeactivateAddress(ctx, "gone@example.com", "bounce")
+	assert.NoError(t, err)
+	assert.False(t, deactivated)
+
+	deactivated, err = d.service.DeactivateAddress(ctx, "left@example.com", "complaint")
+	assert.NoError(t, err)
+	assert.False(t, deactivated)
+
+	d.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeactivateAddress_LookupFails_ReturnsErr(t *testing.T) {
+	d := createTestService()
+	ctx := context.Background()
+
+	d.repo.On("ListByEmail", ctx, "user@example.com").Return(nil, errors.New("db down"))
+
+	_, err := d.service.DeactivateAddress(ctx, "user@example.com", "bounce")
+
+	assert.Error(t, err)
+}
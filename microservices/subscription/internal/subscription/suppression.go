@@ -0,0 +1,54 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+// DeactivateAddress stops deliveries to an address the email provider reported as bouncing
+// or complaining. It reports whether any active subscription was deactivated.
+func (s Service) DeactivateAddress(ctx context.Context, email, reason string) (bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	subs, err := s.repo.ListByEmail(ctx, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	active := make([]domain.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if !sub.IsUnsubscribed {
+			sub.IsUnsubscribed = true
+			active = append(active, sub)
+		}
+	}
+	if len(active) == 0 {
+		return false, nil
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for i := range active {
+			if err := s.repo.Update(ctx, &active[i]); err != nil {
+				return fmt.Errorf("failed to deactivate subscription: %w", err)
+			}
+			if err := s.events.Unsubscribed(ctx, active[i], reason); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	logger := loggerPkg.From(ctx)
+	for _, sub := range active {
+		logger.Info("Subscription deactivated after provider report",
+			"subscription_id", sub.ID, "reason", reason)
+	}
+	return true, nil
+}
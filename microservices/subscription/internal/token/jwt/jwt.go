@@ -1,51 +1,225 @@
 package jwt
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"subscription/internal/domain"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// DefaultKID is the key id JWT_SECRET is registered under.
+const DefaultKID = "default"
+
+var (
+	ErrNoSigningKey = errors.New("no signing key configured")
+	ErrUnknownKeyID = errors.New("unknown key id")
 )
 
+// Config describes the signing keyring and how long each kind of token lives.
+// Keys maps a key id (kid) to its HMAC secret; only ActiveKID is used for signing,
+// the rest are kept so links issued before a rotation keep validating.
+// A zero TTL means tokens of that purpose never expire.
+// LegacyKID names the key that verifies tokens without a kid header, issued before key ids
+// and purposes existed; when empty such tokens are rejected.
+type Config struct {
+	Keys      map[string]string
+	ActiveKID string
+	LegacyKID string
+	TTLs      map[domain.TokenPurpose]time.Duration
+}
+
 type JWT struct {
-	secret string
+	keys      map[string][]byte
+	activeKID string
+	legacyKey []byte
+	ttls      map[domain.TokenPurpose]time.Duration
+	now       func() time.Time
 }
 
-func NewJWT(jwt_secret string) *JWT {
-	return &JWT{jwt_secret}
+// claims name either a subscription, as the subject, or a subscriber address.
+type claims struct {
+	Purpose domain.TokenPurpose `json:"purpose"`
+	Address string              `json:"addr,omitempty"`
+	jwt.RegisteredClaims
 }
 
-func (j *JWT) Generate(email string) (string, error) {
-	if j.secret == "" {
-		return "", errors.New("JWT_SECRET is not set")
+// legacyClaims is the payload of tokens issued before purpose scoping: just the subscriber's address.
+type legacyClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+func New(cfg Config) (*JWT, error) {
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for kid, secret := range cfg.Keys {
+		if kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid key %q: kid and secret are required", kid)
+		}
+		keys[kid] = []byte(secret)
+	}
+
+	if _, ok := keys[cfg.ActiveKID]; !ok {
+		return nil, fmt.Errorf("%w: active kid %q", ErrNoSigningKey, cfg.ActiveKID)
 	}
 
-	claims := jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(24 * time.Hour).Unix(),
+	var legacyKey []byte
+	if cfg.LegacyKID != "" {
+		key, ok := keys[cfg.LegacyKID]
+		if !ok {
+			return nil, fmt.Errorf("%w: legacy kid %q", ErrUnknownKeyID, cfg.LegacyKID)
+		}
+		legacyKey = key
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secret))
+	return &JWT{
+		keys:      keys,
+		activeKID: cfg.ActiveKID,
+		legacyKey: legacyKey,
+		ttls:      cfg.TTLs,
+		now:       time.Now,
+	}, nil
 }
 
-func (j *JWT) Parse(tokenStr string) (string, error) {
-	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-		return []byte(j.secret), nil
-	})
-	if err != nil || !token.Valid {
-		return "", err
+// ParseKeys reads a keyring in the "kid1:secret1,kid2:secret2" format.
+func ParseKeys(spec string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("malformed key entry %q, expected kid:secret", pair)
+		}
+		keys[kid] = secret
 	}
+	return keys, nil
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", jwt.ErrTokenMalformed
+func (j *JWT) Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error) {
+	if subscriptionID == "" {
+		return "", errors.New("subscription id is required")
+	}
+	return j.sign(claims{Purpose: purpose, RegisteredClaims: jwt.RegisteredClaims{Subject: subscriptionID}})
+}
+
+// GenerateForAddress issues a token bound to a subscriber address instead of one subscription.
+func (j *JWT) GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error) {
+	if address == "" {
+		return "", errors.New("address is required")
+	}
+	return j.sign(claims{Purpose: purpose, Address: address})
+}
+
+func (j *JWT) sign(c claims) (string, error) {
+	if !c.Purpose.Valid() {
+		return "", fmt.Errorf("unknown token purpose %q", c.Purpose)
 	}
 
-	email, ok := claims["email"].(string)
+	now := j.now()
+	c.ID = uuid.NewString()
+	c.IssuedAt = jwt.NewNumericDate(now)
+	if ttl := j.ttls[c.Purpose]; ttl > 0 {
+		c.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	token.Header["kid"] = j.activeKID
+	return token.SignedString(j.keys[j.activeKID])
+}
+
+func (j *JWT) Parse(tokenStr string) (domain.TokenClaims, error) {
+	if j.legacyKey != nil && !hasKID(tokenStr) {
+		return j.parseLegacy(tokenStr)
+	}
+
+	var c claims
+	token, err := jwt.ParseWithClaims(tokenStr, &c, j.keyFor,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithTimeFunc(j.now),
+	)
+	if err != nil {
+		return domain.TokenClaims{}, err
+	}
+	if !token.Valid {
+		return domain.TokenClaims{}, jwt.ErrTokenInvalidClaims
+	}
+
+	if (c.Subject == "") == (c.Address == "") || c.ID == "" || !c.Purpose.Valid() {
+		return domain.TokenClaims{}, jwt.ErrTokenMalformed
+	}
+
+	result := domain.TokenClaims{
+		ID:             c.ID,
+		SubscriptionID: c.Subject,
+		Address:        c.Address,
+		Purpose:        c.Purpose,
+	}
+	if c.IssuedAt != nil {
+		result.IssuedAt = c.IssuedAt.Time
+	}
+	if c.ExpiresAt != nil {
+		result.ExpiresAt = c.ExpiresAt.Time
+	}
+	return result, nil
+}
+
+func (j *JWT) keyFor(t *jwt.Token) (interface{}, error) {
+	kid, ok := t.Header["kid"].(string)
 	if !ok {
-		return "", jwt.ErrTokenMalformed
+		return nil, fmt.Errorf("%w: missing kid header", ErrUnknownKeyID)
+	}
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+	}
+	return key, nil
+}
+
+// parseLegacy verifies a token issued before purpose scoping. Its expiry is reported but not
+// enforced here: these tokens served as unsubscribe links, which must keep working, so the caller
+// decides per purpose. The id is derived from the token, since legacy tokens have no jti to revoke.
+func (j *JWT) parseLegacy(tokenStr string) (domain.TokenClaims, error) {
+	var c legacyClaims
+	_, err := jwt.ParseWithClaims(tokenStr, &c, func(*jwt.Token) (interface{}, error) {
+		return j.legacyKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return domain.TokenClaims{}, err
+	}
+	if c.Email == "" {
+		return domain.TokenClaims{}, jwt.ErrTokenMalformed
 	}
 
-	return email, nil
+	sum := sha256.Sum256([]byte(tokenStr))
+	result := domain.TokenClaims{
+		ID:    "legacy-" + hex.EncodeToString(sum[:16]),
+		Email: c.Email,
+	}
+	if c.ExpiresAt != nil {
+		result.ExpiresAt = c.ExpiresAt.Time
+	}
+	return result, nil
+}
+
+// hasKID reports whether the token header names a key. Malformed tokens report true, so the
+// regular path rejects them with its own error.
+func hasKID(tokenStr string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+	if err != nil {
+		return true
+	}
+	_, ok := token.Header["kid"]
+	return ok
 }
@@ -4,91 +4,210 @@ import (
 	"testing"
 	"time"
 
+	"subscription/internal/domain"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func newTestJWT(t *testing.T, keys map[string]string, activeKID string) *JWT {
+	t.Helper()
+	svc, err := New(Config{
+		Keys:      keys,
+		ActiveKID: activeKID,
+		TTLs: map[domain.TokenPurpose]time.Duration{
+			domain.TokenPurposeConfirm: time.Hour,
+			domain.TokenPurposeManage:  time.Minute,
+		},
+	})
+	require.NoError(t, err)
+	return svc
+}
+
 func TestJWTService_GenerateAndParse_Valid(t *testing.T) {
-	svc := NewJWT("secret123")
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
 
-	token, err := svc.Generate("user@example.com")
+	token, err := svc.Generate("sub-1", domain.TokenPurposeConfirm)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	email, err := svc.Parse(token)
+	claims, err := svc.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", claims.SubscriptionID)
+	assert.Equal(t, domain.TokenPurposeConfirm, claims.Purpose)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt, time.Minute)
+}
+
+func TestJWTService_GenerateForAddress(t *testing.T) {
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
+
+	token, err := svc.GenerateForAddress("user@example.com", domain.TokenPurposeManage)
+	require.NoError(t, err)
+
+	claims, err := svc.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", claims.Address)
+	assert.Empty(t, claims.SubscriptionID)
+	assert.False(t, claims.Legacy())
+	assert.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiresAt, time.Minute)
+}
+
+func TestJWTService_UnsubscribeToken_NeverExpires(t *testing.T) {
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
+
+	token, err := svc.Generate("sub-1", domain.TokenPurposeUnsubscribe)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().AddDate(10, 0, 0) }
+
+	claims, err := svc.Parse(token)
 	require.NoError(t, err)
-	assert.Equal(t, "user@example.com", email)
+	assert.True(t, claims.ExpiresAt.IsZero())
 }
 
-func TestJWTService_Generate_MissingSecret(t *testing.T) {
-	svc := NewJWT("")
+func TestJWTService_Parse_ExpiredToken(t *testing.T) {
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
+
+	token, err := svc.Generate("sub-1", domain.TokenPurposeManage)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+	_, err = svc.Parse(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
 
-	token, err := svc.Generate("user@example.com")
-	require.Error(t, err)
-	assert.Empty(t, token)
+func TestJWTService_New_MissingActiveKey(t *testing.T) {
+	_, err := New(Config{Keys: map[string]string{"k1": "secret123"}, ActiveKID: "k2"})
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestJWTService_Rotation_OldTokensStillValid(t *testing.T) {
+	old := newTestJWT(t, map[string]string{"k1": "old-secret"}, "k1")
+	token, err := old.Generate("sub-1", domain.TokenPurposeUnsubscribe)
+	require.NoError(t, err)
+
+	rotated := newTestJWT(t, map[string]string{"k1": "old-secret", "k2": "new-secret"}, "k2")
+
+	claims, err := rotated.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", claims.SubscriptionID)
+
+	fresh, err := rotated.Generate("sub-1", domain.TokenPurposeUnsubscribe)
+	require.NoError(t, err)
+	_, err = old.Parse(fresh)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
 }
 
 func TestJWTService_Parse_InvalidToken(t *testing.T) {
-	svc := NewJWT("secret123")
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
 
 	_, err := svc.Parse("not-a-real-token")
 	assert.Error(t, err)
 }
 
 func TestJWTService_Parse_TamperedToken(t *testing.T) {
-	svc := NewJWT("secret123")
-	tamperedSvc := NewJWT("othersecret")
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
+	tamperedSvc := newTestJWT(t, map[string]string{"k1": "othersecret"}, "k1")
 
-	token, err := tamperedSvc.Generate("user@example.com")
+	token, err := tamperedSvc.Generate("sub-1", domain.TokenPurposeConfirm)
 	require.NoError(t, err)
 
 	_, err = svc.Parse(token)
 	assert.Error(t, err)
 }
 
-func TestJWTService_Parse_ExpiredToken(t *testing.T) {
-	svc := NewJWT("secret123")
+func TestJWTService_Parse_MissingKID(t *testing.T) {
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
 
 	claims := jwt.MapClaims{
-		"email": "user@example.com",
-		"exp":   time.Now().Add(-1 * time.Hour).Unix(), // expired
+		"sub":     "sub-1",
+		"jti":     "id",
+		"purpose": "confirm",
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(svc.secret))
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret123"))
 	require.NoError(t, err)
 
 	_, err = svc.Parse(tokenStr)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
 }
 
-func TestJWTService_Parse_MissingEmailClaim(t *testing.T) {
-	svc := NewJWT("secret123")
+func TestJWTService_Parse_UnknownPurpose(t *testing.T) {
+	svc := newTestJWT(t, map[string]string{"k1": "secret123"}, "k1")
 
-	claims := jwt.MapClaims{
-		"exp": time.Now().Add(1 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(svc.secret))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     "sub-1",
+		"jti":     "id",
+		"purpose": "admin",
+	})
+	token.Header["kid"] = "k1"
+	tokenStr, err := token.SignedString([]byte("secret123"))
 	require.NoError(t, err)
 
-	email, err := svc.Parse(tokenStr)
+	_, err = svc.Parse(tokenStr)
+	assert.ErrorIs(t, err, jwt.ErrTokenMalformed)
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("k1:one, k2:two")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"k1": "one", "k2": "two"}, keys)
+
+	_, err = ParseKeys("broken")
 	assert.Error(t, err)
-	assert.Empty(t, email)
 }
 
-func TestJWTService_Parse_EmailIsNotString(t *testing.T) {
-	svc := NewJWT("secret123")
+func legacyToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return tokenStr
+}
 
-	claims := jwt.MapClaims{
-		"email": 12345,
-		"exp":   time.Now().Add(1 * time.Hour).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(svc.secret))
+func TestJWTService_Parse_LegacyToken(t *testing.T) {
+	svc, err := New(Config{
+		Keys:      map[string]string{DefaultKID: "legacy-secret", "k2": "secret123"},
+		ActiveKID: "k2",
+		LegacyKID: DefaultKID,
+	})
 	require.NoError(t, err)
+	expired := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	t.Run("Valid", func(t *testing.T) {
+		tokenStr := legacyToken(t, "legacy-secret", jwt.MapClaims{"email": "user@example.com", "exp": expired.Unix()})
+
+		claims, err := svc.Parse(tokenStr)
+		require.NoError(t, err)
+		assert.True(t, claims.Legacy())
+		assert.Equal(t, "user@example.com", claims.Email)
+		assert.Empty(t, claims.SubscriptionID)
+		assert.Empty(t, claims.Purpose)
+		assert.Equal(t, expired, claims.ExpiresAt.Local())
+		assert.NotEmpty(t, claims.ID)
+
+		again, err := svc.Parse(tokenStr)
+		require.NoError(t, err)
+		assert.Equal(t, claims.ID, again.ID)
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		tokenStr := legacyToken(t, "secret123", jwt.MapClaims{"email": "user@example.com"})
+
+		_, err := svc.Parse(tokenStr)
+		assert.Error(t, err)
+	})
+
+	t.Run("MissingEmail", func(t *testing.T) {
+		tokenStr := legacyToken(t, "legacy-secret", jwt.MapClaims{"sub": "sub-1"})
+
+		_, err := svc.Parse(tokenStr)
+		assert.ErrorIs(t, err, jwt.ErrTokenMalformed)
+	})
+}
 
-	email, err := svc.Parse(tokenStr)
-	assert.Error(t, err)
-	assert.Empty(t, email)
+func TestJWTService_New_UnknownLegacyKey(t *testing.T) {
+	_, err := New(Config{Keys: map[string]string{"k1": "secret"}, ActiveKID: "k1", LegacyKID: DefaultKID})
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
 }
@@ -1,21 +1,102 @@
 package token
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrWrongPurpose = errors.New("token issued for another purpose")
+	ErrRevoked      = errors.New("token revoked")
+)
+
 type Provider interface {
-	Generate(email string) (string, error)
-	Parse(token string) (string, error)
+	Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error)
+	GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error)
+	Parse(token string) (domain.TokenClaims, error)
+}
+
+// RevocationStore keeps track of tokens that must no longer be accepted even though their signature is valid.
+type RevocationStore interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	Revoke(ctx context.Context, claims domain.TokenClaims) error
 }
+
 type Service struct {
-	provider Provider
+	provider    Provider
+	revocations RevocationStore
+	now         func() time.Time
+}
+
+func NewService(p Provider, revocations RevocationStore) *Service {
+	return &Service{provider: p, revocations: revocations, now: time.Now}
 }
 
-func NewService(p Provider) *Service {
-	return &Service{provider: p}
+func (s *Service) Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error) {
+	return s.provider.Generate(subscriptionID, purpose)
 }
 
-func (s *Service) Generate(email string) (string, error) {
-	return s.provider.Generate(email)
+func (s *Service) GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error) {
+	return s.provider.GenerateForAddress(address, purpose)
 }
 
-func (s *Service) Parse(token string) (string, error) {
-	return s.provider.Parse(token)
+// Parse validates the signature, the expected purpose and the revocation list.
+func (s *Service) Parse(ctx context.Context, token string, purpose domain.TokenPurpose) (domain.TokenClaims, error) {
+	claims, err := s.provider.Parse(token)
+	if err != nil {
+		return domain.TokenClaims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if claims.Legacy() {
+		if err := s.checkLegacy(claims, purpose); err != nil {
+			return domain.TokenClaims{}, err
+		}
+		claims.Purpose = purpose
+	} else if claims.Purpose != purpose {
+		return domain.TokenClaims{}, ErrWrongPurpose
+	}
+
+	revoked, err := s.revocations.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return domain.TokenClaims{}, fmt.Errorf("check token revocation: %w", err)
+	}
+	if revoked {
+		return domain.TokenClaims{}, ErrRevoked
+	}
+
+	return claims, nil
+}
+
+// checkLegacy accepts a token issued before purpose scoping. Such a token was emailed both as the
+// confirmation and as the unsubscribe link: it unsubscribes for good, like current unsubscribe
+// tokens, and confirms until it expires.
+func (s *Service) checkLegacy(claims domain.TokenClaims, purpose domain.TokenPurpose) error {
+	switch purpose {
+	case domain.TokenPurposeUnsubscribe:
+		return nil
+	case domain.TokenPurposeConfirm:
+		if !claims.ExpiresAt.IsZero() && !s.now().Before(claims.ExpiresAt) {
+			return fmt.Errorf("%w: token expired", ErrInvalidToken)
+		}
+		return nil
+	default:
+		return ErrWrongPurpose
+	}
+}
+
+func (s *Service) Revoke(ctx context.Context, token string) error {
+	claims, err := s.provider.Parse(token)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if err := s.revocations.Revoke(ctx, claims); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
 }
@@ -1,48 +1,153 @@
 package token
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"subscription/internal/domain"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 type mockProvider struct {
-	expectedEmail string
-	returnToken   string
-	returnEmail   string
-	genCalled     bool
-	parseCalled   bool
+	returnToken  string
+	returnClaims domain.TokenClaims
+	parseErr     error
+	genPurpose   domain.TokenPurpose
 }
 
-func (m *mockProvider) Generate(email string) (string, error) {
-	m.genCalled = true
-	m.expectedEmail = email
+func (m *mockProvider) Generate(subscriptionID string, purpose domain.TokenPurpose) (string, error) {
+	m.genPurpose = purpose
 	return m.returnToken, nil
 }
 
-func (m *mockProvider) Parse(token string) (string, error) {
-	m.parseCalled = true
-	return m.returnEmail, nil
+func (m *mockProvider) GenerateForAddress(address string, purpose domain.TokenPurpose) (string, error) {
+	m.genPurpose = purpose
+	return m.returnToken, nil
+}
+
+func (m *mockProvider) Parse(token string) (domain.TokenClaims, error) {
+	return m.returnClaims, m.parseErr
+}
+
+type mockRevocations struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (m *mockRevocations) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return m.revoked[tokenID], m.err
+}
+
+func (m *mockRevocations) Revoke(ctx context.Context, claims domain.TokenClaims) error {
+	m.revoked[claims.ID] = true
+	return nil
+}
+
+func newRevocations() *mockRevocations {
+	return &mockRevocations{revoked: map[string]bool{}}
 }
 
 func TestTokenService_Generate(t *testing.T) {
 	mock := &mockProvider{returnToken: "mocktoken"}
-	svc := NewService(mock)
+	svc := NewService(mock, newRevocations())
 
-	token, err := svc.Generate("test@example.com")
+	token, err := svc.Generate("sub-1", domain.TokenPurposeManage)
 	require.NoError(t, err)
 	assert.Equal(t, "mocktoken", token)
-	assert.True(t, mock.genCalled)
-	assert.Equal(t, "test@example.com", mock.expectedEmail)
+	assert.Equal(t, domain.TokenPurposeManage, mock.genPurpose)
 }
 
 func TestTokenService_Parse(t *testing.T) {
-	mock := &mockProvider{returnEmail: "parsed@example.com"}
-	svc := NewService(mock)
+	claims := domain.TokenClaims{ID: "jti-1", SubscriptionID: "sub-1", Purpose: domain.TokenPurposeConfirm}
+	svc := NewService(&mockProvider{returnClaims: claims}, newRevocations())
 
-	email, err := svc.Parse("sometoken")
+	got, err := svc.Parse(context.Background(), "sometoken", domain.TokenPurposeConfirm)
 	require.NoError(t, err)
-	assert.Equal(t, "parsed@example.com", email)
-	assert.True(t, mock.parseCalled)
+	assert.Equal(t, claims, got)
+}
+
+func TestTokenService_Parse_WrongPurpose(t *testing.T) {
+	claims := domain.TokenClaims{ID: "jti-1", SubscriptionID: "sub-1", Purpose: domain.TokenPurposeUnsubscribe}
+	svc := NewService(&mockProvider{returnClaims: claims}, newRevocations())
+
+	_, err := svc.Parse(context.Background(), "sometoken", domain.TokenPurposeManage)
+	assert.ErrorIs(t, err, ErrWrongPurpose)
+}
+
+func TestTokenService_Parse_InvalidSignature(t *testing.T) {
+	svc := NewService(&mockProvider{parseErr: errors.New("bad signature")}, newRevocations())
+
+	_, err := svc.Parse(context.Background(), "sometoken", domain.TokenPurposeConfirm)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenService_RevokedTokenRejected(t *testing.T) {
+	claims := domain.TokenClaims{ID: "jti-1", SubscriptionID: "sub-1", Purpose: domain.TokenPurposeUnsubscribe}
+	revocations := newRevocations()
+	svc := NewService(&mockProvider{returnClaims: claims}, revocations)
+
+	require.NoError(t, svc.Revoke(context.Background(), "sometoken"))
+
+	_, err := svc.Parse(context.Background(), "sometoken", domain.TokenPurposeUnsubscribe)
+	assert.ErrorIs(t, err, ErrRevoked)
+}
+
+func TestTokenService_RevocationCheckFails(t *testing.T) {
+	claims := domain.TokenClaims{ID: "jti-1", SubscriptionID: "sub-1", Purpose: domain.TokenPurposeConfirm}
+	revocations := &mockRevocations{err: errors.New("db down")}
+	svc := NewService(&mockProvider{returnClaims: claims}, revocations)
+
+	_, err := svc.Parse(context.Background(), "sometoken", domain.TokenPurposeConfirm)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRevoked)
+}
+
+func TestTokenService_Parse_Legacy(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	valid := domain.TokenClaims{ID: "legacy-1", Email: "user@example.com", ExpiresAt: now.Add(time.Hour)}
+	expired := domain.TokenClaims{ID: "legacy-2", Email: "user@example.com", ExpiresAt: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name    string
+		claims  domain.TokenClaims
+		purpose domain.TokenPurpose
+		wantErr error
+	}{
+		{name: "Confirm", claims: valid, purpose: domain.TokenPurposeConfirm},
+		{name: "ExpiredConfirm", claims: expired, purpose: domain.TokenPurposeConfirm, wantErr: ErrInvalidToken},
+		{name: "ExpiredUnsubscribe", claims: expired, purpose: domain.TokenPurposeUnsubscribe},
+		{name: "Manage", claims: valid, purpose: domain.TokenPurposeManage, wantErr: ErrWrongPurpose},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(&mockProvider{returnClaims: tt.claims}, newRevocations())
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.Parse(context.Background(), "legacy-token", tt.purpose)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.purpose, got.Purpose)
+			assert.Equal(t, "user@example.com", got.Email)
+		})
+	}
+}
+
+func TestTokenService_Parse_RevokedLegacy(t *testing.T) {
+	claims := domain.TokenClaims{ID: "legacy-1", Email: "user@example.com"}
+	revocations := newRevocations()
+	svc := NewService(&mockProvider{returnClaims: claims}, revocations)
+
+	require.NoError(t, svc.Revoke(context.Background(), "legacy-token"))
+
+	_, err := svc.Parse(context.Background(), "legacy-token", domain.TokenPurposeUnsubscribe)
+	assert.ErrorIs(t, err, ErrRevoked)
 }
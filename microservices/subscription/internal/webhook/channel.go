@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+	"subscription/internal/job"
+)
+
+// Delivery is a report waiting to be posted to a subscription's endpoint.
+type Delivery struct {
+	ID             int64
+	SubscriptionID string
+	// IdempotencyKey matches the delivery record of the report and is sent as the webhook id.
+	IdempotencyKey string
+	Payload        []byte
+	Attempts       int
+	CreatedAt      time.Time
+}
+
+type deliveryQueue interface {
+	// Enqueue adds a delivery; one with the same idempotency key already queued is kept.
+	Enqueue(ctx context.Context, d Delivery) error
+}
+
+// Channel is the webhook notification channel. It only queues the report, so inside the report
+// transaction it commits together with the delivery record; the Dispatcher posts it afterwards.
+type Channel struct {
+	queue deliveryQueue
+	now   func() time.Time
+}
+
+func NewChannel(queue deliveryQueue) *Channel {
+	return &Channel{queue: queue, now: time.Now}
+}
+
+func (c *Channel) SendWeatherReport(ctx context.Context, r job.Recipient, city string, report domain.Report, idKey string) error {
+	payload, err := json.Marshal(ReportPayload{
+		ID:             idKey,
+		Type:           EventWeatherReport,
+		SubscriptionID: r.SubscriptionID,
+		City:           city,
+		Locale:         r.Locale,
+		CreatedAt:      c.now().UTC(),
+		Weather: WeatherPayload{
+			Temperature: report.Temperature,
+			Humidity:    report.Humidity,
+			Description: report.Description,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	return c.queue.Enqueue(ctx, Delivery{
+		SubscriptionID: r.SubscriptionID,
+		IdempotencyKey: idKey,
+		Payload:        payload,
+	})
+}
@@ -0,0 +1,325 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"subscription/internal/domain"
+
+	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
+)
+
+var errChallengeMismatch = errors.New("webhook answered the handshake without the challenge")
+
+type store interface {
+	GetEndpoint(ctx context.Context, subscriptionID string) (*domain.WebhookEndpoint, error)
+	// ClaimVerifications returns endpoints with a due handshake and hides them until leaseUntil.
+	ClaimVerifications(ctx context.Context, limit int, now, leaseUntil time.Time) ([]domain.WebhookEndpoint, error)
+	// SaveVerification stores the handshake outcome unless the endpoint got a new secret meanwhile.
+	SaveVerification(ctx context.Context, e domain.WebhookEndpoint) error
+	// Claim returns up to limit due deliveries and hides them from other dispatchers until leaseUntil.
+	Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]Delivery, error)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error
+	MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error
+	ResetFailures(ctx context.Context, subscriptionID string) error
+	// RecordFailure counts a delivery that exhausted its retries and disables the endpoint once
+	// disableAfter deliveries in a row failed. It reports whether this call disabled it.
+	RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, at time.Time, lastErr string) (bool, error)
+}
+
+type sender interface {
+	Send(ctx context.Context, url, secret, id string, body []byte) ([]byte, error)
+}
+
+// statusUpdater moves the report's delivery record to its outcome, as the email service's
+// status events do for emails.
+type statusUpdater interface {
+	UpdateStatus(ctx context.Context, idKey string, status domain.DeliveryStatus, at time.Time, errMsg string) (bool, error)
+}
+
+type dispatcherMetrics interface {
+	RecordDelivery(result string)
+	RecordVerification(result string)
+	RecordDisabled()
+}
+
+// Delivery and handshake outcomes, used as metric labels.
+const (
+	ResultSent     = "sent"
+	ResultRetry    = "retry"
+	ResultDead     = "dead"
+	ResultDropped  = "dropped"
+	ResultVerified = "verified"
+	ResultFailed   = "failed"
+)
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is how many times a report is posted before it is given up.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+	// DisableAfter is how many reports in a row may be given up before the endpoint is disabled.
+	DisableAfter int
+	// VerifyAttempts is how many times the handshake is tried before the subscriber has to
+	// request a new one.
+	VerifyAttempts int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:   5 * time.Second,
+		BatchSize:      20,
+		MaxAttempts:    8,
+		BaseBackoff:    30 * time.Second,
+		MaxBackoff:     time.Hour,
+		Lease:          2 * time.Minute,
+		DisableAfter:   3,
+		VerifyAttempts: 5,
+	}
+}
+
+// Dispatcher performs pending handshakes and posts queued reports. Failed posts are retried
+// with backoff; delivery is at-least-once, and receivers drop duplicates by the webhook id.
+type Dispatcher struct {
+	store      store
+	sender     sender
+	deliveries statusUpdater
+	metrics    dispatcherMetrics
+	cfg        Config
+	now        func() time.Time
+	challenge  func() (string, error)
+}
+
+func NewDispatcher(store store, sender sender, deliveries statusUpdater, metrics dispatcherMetrics, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		store:      store,
+		sender:     sender,
+		deliveries: deliveries,
+		metrics:    metrics,
+		cfg:        cfg,
+		now:        time.Now,
+		challenge:  func() (string, error) { return randomHex(16) },
+	}
+}
+
+func (d *Dispatcher) Start(ctx context.Context) {
+	logger := loggerPkg.From(ctx)
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			if _, err := d.VerifyBatch(ctx); err != nil {
+				logger.Error("Webhook verification batch failed", "err", err)
+			}
+			for {
+				n, err := d.DeliverBatch(ctx)
+				if err != nil {
+					logger.Error("Webhook delivery batch failed", "err", err)
+					break
+				}
+				if n < d.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// VerifyBatch runs one batch of due handshakes and returns how many were claimed.
+func (d *Dispatcher) VerifyBatch(ctx context.Context) (int, error) {
+	now := d.now()
+	endpoints, err := d.store.ClaimVerifications(ctx, d.cfg.BatchSize, now, now.Add(d.cfg.Lease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range endpoints {
+		d.verify(ctx, e)
+	}
+	return len(endpoints), nil
+}
+
+func (d *Dispatcher) verify(ctx context.Context, e domain.WebhookEndpoint) {
+	logger := loggerPkg.From(ctx)
+
+	verifyErr := d.handshake(ctx, e)
+	now := d.now()
+	switch {
+	case verifyErr == nil:
+		e.VerifiedAt = &now
+		e.VerifyAttempts = 0
+		e.NextVerifyAt = nil
+		e.LastError = ""
+		logger.Info("Webhook endpoint verified", "subscription_id", e.SubscriptionID)
+		d.metrics.RecordVerification(ResultVerified)
+	case e.VerifyAttempts+1 >= d.cfg.VerifyAttempts:
+		e.VerifyAttempts++
+		e.NextVerifyAt = nil
+		e.LastError = verifyErr.Error()
+		logger.Warn("Webhook verification gave up", "subscription_id", e.SubscriptionID, "attempts", e.VerifyAttempts, "err", verifyErr)
+		d.metrics.RecordVerification(ResultFailed)
+	default:
+		e.VerifyAttempts++
+		next := now.Add(d.backoff(e.VerifyAttempts))
+		e.NextVerifyAt = &next
+		e.LastError = verifyErr.Error()
+		logger.Warn("Webhook verification failed, will retry", "subscription_id", e.SubscriptionID, "attempts", e.VerifyAttempts, "next_attempt_at", next, "err", verifyErr)
+		d.metrics.RecordVerification(ResultRetry)
+	}
+
+	if err := d.store.SaveVerification(ctx, e); err != nil {
+		logger.Error("Failed to save webhook verification", "subscription_id", e.SubscriptionID, "err", err)
+	}
+}
+
+// handshake posts a fresh challenge and checks that the endpoint echoes it.
+func (d *Dispatcher) handshake(ctx context.Context, e domain.WebhookEndpoint) error {
+	challenge, err := d.challenge()
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("verify:%s:%s", e.SubscriptionID, challenge)
+	body, err := json.Marshal(VerificationPayload{
+		ID:             id,
+		Type:           EventVerification,
+		SubscriptionID: e.SubscriptionID,
+		Challenge:      challenge,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal verification payload: %w", err)
+	}
+
+	answer, err := d.sender.Send(ctx, e.URL, e.Secret, id, body)
+	if err != nil {
+		return err
+	}
+
+	var parsed verificationAnswer
+	if json.Unmarshal(answer, &parsed) == nil && parsed.Challenge == challenge {
+		return nil
+	}
+	if string(bytes.TrimSpace(answer)) == challenge {
+		return nil
+	}
+	return errChallengeMismatch
+}
+
+// DeliverBatch posts one batch of due reports and returns how many were claimed.
+func (d *Dispatcher) DeliverBatch(ctx context.Context) (int, error) {
+	now := d.now()
+	batch, err := d.store.Claim(ctx, d.cfg.BatchSize, now, now.Add(d.cfg.Lease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, del := range batch {
+		d.deliver(ctx, del)
+	}
+	return len(batch), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del Delivery) {
+	logger := loggerPkg.From(ctx)
+
+	endpoint, err := d.store.GetEndpoint(ctx, del.SubscriptionID)
+	if errors.Is(err, ErrEndpointNotFound) {
+		d.drop(ctx, del, "webhook endpoint removed")
+		return
+	}
+	if err != nil {
+		// The lease runs out and the delivery is claimed again.
+		logger.Error("Failed to load webhook endpoint", "subscription_id", del.SubscriptionID, "err", err)
+		return
+	}
+	switch {
+	case endpoint.DisabledAt != nil:
+		d.drop(ctx, del, "webhook endpoint disabled")
+		return
+	case !endpoint.Active():
+		d.drop(ctx, del, "webhook endpoint not verified")
+		return
+	}
+
+	_, sendErr := d.sender.Send(ctx, endpoint.URL, endpoint.Secret, del.IdempotencyKey, del.Payload)
+	if sendErr == nil {
+		now := d.now()
+		if err := d.store.MarkSent(ctx, del.ID, now); err != nil {
+			logger.Error("Failed to mark webhook delivery sent", "id", del.ID, "err", err)
+		}
+		if endpoint.Failures > 0 {
+			if err := d.store.ResetFailures(ctx, del.SubscriptionID); err != nil {
+				logger.Error("Failed to reset webhook failures", "subscription_id", del.SubscriptionID, "err", err)
+			}
+		}
+		d.updateStatus(ctx, del, domain.DeliverySent, "")
+		d.metrics.RecordDelivery(ResultSent)
+		return
+	}
+
+	attempts := del.Attempts + 1
+	if attempts >= d.cfg.MaxAttempts {
+		logger.Error("Webhook delivery exhausted retries", "id", del.ID, "subscription_id", del.SubscriptionID, "attempts", attempts, "err", sendErr)
+		if err := d.store.MarkDead(ctx, del.ID, attempts, sendErr.Error()); err != nil {
+			logger.Error("Failed to mark webhook delivery dead", "id", del.ID, "err", err)
+		}
+		d.updateStatus(ctx, del, domain.DeliveryFailed, sendErr.Error())
+		d.metrics.RecordDelivery(ResultDead)
+
+		disabled, err := d.store.RecordFailure(ctx, del.SubscriptionID, d.cfg.DisableAfter, d.now(), sendErr.Error())
+		if err != nil {
+			logger.Error("Failed to record webhook failure", "subscription_id", del.SubscriptionID, "err", err)
+		}
+		if disabled {
+			logger.Warn("Webhook endpoint disabled after repeated failures", "subscription_id", del.SubscriptionID)
+			d.metrics.RecordDisabled()
+		}
+		return
+	}
+
+	next := d.now().Add(d.backoff(attempts))
+	logger.Warn("Webhook delivery failed, will retry", "id", del.ID, "attempts", attempts, "next_attempt_at", next, "err", sendErr)
+	if err := d.store.MarkRetry(ctx, del.ID, attempts, next, sendErr.Error()); err != nil {
+		logger.Error("Failed to schedule webhook retry", "id", del.ID, "err", err)
+	}
+	d.metrics.RecordDelivery(ResultRetry)
+}
+
+// drop gives up a delivery that cannot be posted without trying it.
+func (d *Dispatcher) drop(ctx context.Context, del Delivery, reason string) {
+	if err := d.store.MarkDead(ctx, del.ID, del.Attempts, reason); err != nil {
+		loggerPkg.From(ctx).Error("Failed to mark webhook delivery dead", "id", del.ID, "err", err)
+	}
+	d.updateStatus(ctx, del, domain.DeliveryFailed, reason)
+	d.metrics.RecordDelivery(ResultDropped)
+}
+
+func (d *Dispatcher) updateStatus(ctx context.Context, del Delivery, status domain.DeliveryStatus, errMsg string) {
+	if _, err := d.deliveries.UpdateStatus(ctx, del.IdempotencyKey, status, d.now(), errMsg); err != nil {
+		loggerPkg.From(ctx).Error("Failed to update delivery status", "id_key", del.IdempotencyKey, "err", err)
+	}
+}
+
+// backoff doubles the delay for every failed attempt, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,327 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	endpoints     map[string]*domain.WebhookEndpoint
+	verifications []domain.WebhookEndpoint
+	pending       []Delivery
+	sent          []int64
+	retries       map[int64]time.Time
+	dead          map[int64]string
+	resets        []string
+	failures      []string
+}
+
+func newFakeStore(endpoints ...domain.WebhookEndpoint) *fakeStore {
+	s := &fakeStore{
+		endpoints: map[string]*domain.WebhookEndpoint{},
+		retries:   map[int64]time.Time{},
+		dead:      map[int64]string{},
+	}
+	for _, e := range endpoints {
+		s.endpoints[e.SubscriptionID] = &e
+	}
+	return s
+}
+
+func (s *fakeStore) GetEndpoint(_ context.Context, subscriptionID string) (*domain.WebhookEndpoint, error) {
+	e, ok := s.endpoints[subscriptionID]
+	if !ok {
+		return nil, ErrEndpointNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (s *fakeStore) ClaimVerifications(_ context.Context, _ int, _, _ time.Time) ([]domain.WebhookEndpoint, error) {
+	var due []domain.WebhookEndpoint
+	for _, e := range s.endpoints {
+		if e.VerifiedAt == nil && e.NextVerifyAt != nil {
+			due = append(due, *e)
+		}
+	}
+	return due, nil
+}
+
+func (s *fakeStore) SaveVerification(_ context.Context, e domain.WebhookEndpoint) error {
+	s.verifications = append(s.verifications, e)
+	return nil
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _, _ time.Time) ([]Delivery, error) {
+	if limit > len(s.pending) {
+		limit = len(s.pending)
+	}
+	claimed := s.pending[:limit]
+	s.pending = s.pending[limit:]
+	return claimed, nil
+}
+
+func (s *fakeStore) MarkSent(_ context.Context, id int64, _ time.Time) error {
+	s.sent = append(s.sent, id)
+	return nil
+}
+
+func (s *fakeStore) MarkRetry(_ context.Context, id int64, _ int, next time.Time, _ string) error {
+	s.retries[id] = next
+	return nil
+}
+
+func (s *fakeStore) MarkDead(_ context.Context, id int64, _ int, lastErr string) error {
+	s.dead[id] = lastErr
+	return nil
+}
+
+func (s *fakeStore) ResetFailures(_ context.Context, subscriptionID string) error {
+	s.resets = append(s.resets, subscriptionID)
+	return nil
+}
+
+func (s *fakeStore) RecordFailure(_ context.Context, subscriptionID string, disableAfter int, _ time.Time, _ string) (bool, error) {
+	s.failures = append(s.failures, subscriptionID)
+	e := s.endpoints[subscriptionID]
+	e.Failures++
+	return e.Failures >= disableAfter, nil
+}
+
+type fakeSender struct {
+	err    error
+	answer func(body []byte) []byte
+	ids    []string
+}
+
+func (s *fakeSender) Send(_ context.Context, _, _, id string, body []byte) ([]byte, error) {
+	s.ids = append(s.ids, id)
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.answer != nil {
+		return s.answer(body), nil
+	}
+	return nil, nil
+}
+
+type fakeStatuses struct {
+	updates map[string]domain.DeliveryStatus
+}
+
+func (f *fakeStatuses) UpdateStatus(_ context.Context, idKey string, status domain.DeliveryStatus, _ time.Time, _ string) (bool, error) {
+	if f.updates == nil {
+		f.updates = map[string]domain.DeliveryStatus{}
+	}
+	f.updates[idKey] = status
+	return true, nil
+}
+
+type countingMetrics struct {
+	deliveries    map[string]int
+	verifications map[string]int
+	disabled      int
+}
+
+func (m *countingMetrics) RecordDelivery(result string) {
+	if m.deliveries == nil {
+		m.deliveries = map[string]int{}
+	}
+	m.deliveries[result]++
+}
+
+func (m *countingMetrics) RecordVerification(result string) {
+	if m.verifications == nil {
+		m.verifications = map[string]int{}
+	}
+	m.verifications[result]++
+}
+
+func (m *countingMetrics) RecordDisabled() {
+	m.disabled++
+}
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestDispatcher(store *fakeStore, snd *fakeSender) (*Dispatcher, *fakeStatuses, *countingMetrics) {
+	statuses := &fakeStatuses{}
+	metrics := &countingMetrics{}
+	d := NewDispatcher(store, snd, statuses, metrics, DefaultConfig())
+	d.now = func() time.Time { return testNow }
+	d.challenge = func() (string, error) { return "challenge-1", nil }
+	return d, statuses, metrics
+}
+
+func verifiedEndpoint(subscriptionID string) domain.WebhookEndpoint {
+	verifiedAt := testNow.Add(-time.Hour)
+	return domain.WebhookEndpoint{
+		SubscriptionID: subscriptionID,
+		URL:            "https://hooks.example.com",
+		Secret:         "whsec_1",
+		VerifiedAt:     &verifiedAt,
+	}
+}
+
+func TestDeliverBatch_MarksSent(t *testing.T) {
+	endpoint := verifiedEndpoint("sub-1")
+	endpoint.Failures = 2
+	store := newFakeStore(endpoint)
+	store.pending = []Delivery{{ID: 1, SubscriptionID: "sub-1", IdempotencyKey: "key-1", Payload: []byte(`{}`)}}
+	snd := &fakeSender{}
+	d, statuses, metrics := newTestDispatcher(store, snd)
+
+	n, err := d.DeliverBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []int64{1}, store.sent)
+	assert.Equal(t, []string{"key-1"}, snd.ids)
+	assert.Equal(t, []string{"sub-1"}, store.resets)
+	assert.Equal(t, domain.DeliverySent, statuses.updates["key-1"])
+	assert.Equal(t, 1, metrics.deliveries[ResultSent])
+}
+
+func TestDeliverBatch_RetriesWithBackoff(t *testing.T) {
+	store := newFakeStore(verifiedEndpoint("sub-1"))
+	store.pending = []Delivery{{ID: 1, SubscriptionID: "sub-1", IdempotencyKey: "key-1", Attempts: 2}}
+	d, statuses, metrics := newTestDispatcher(store, &fakeSender{err: ErrUnexpectedStatus})
+
+	_, err := d.DeliverBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, testNow.Add(2*time.Minute), store.retries[1])
+	assert.Empty(t, statuses.updates)
+	assert.Equal(t, 1, metrics.deliveries[ResultRetry])
+}
+
+func TestDeliverBatch_DisablesEndpointAfterRepeatedFailures(t *testing.T) {
+	endpoint := verifiedEndpoint("sub-1")
+	endpoint.Failures = 2
+	store := newFakeStore(endpoint)
+	store.pending = []Delivery{{ID: 1, SubscriptionID: "sub-1", IdempotencyKey: "key-1", Attempts: 7}}
+	d, statuses, metrics := newTestDispatcher(store, &fakeSender{err: ErrUnexpectedStatus})
+
+	_, err := d.DeliverBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Contains(t, store.dead, int64(1))
+	assert.Equal(t, []string{"sub-1"}, store.failures)
+	assert.Equal(t, domain.DeliveryFailed, statuses.updates["key-1"])
+	assert.Equal(t, 1, metrics.deliveries[ResultDead])
+	assert.Equal(t, 1, metrics.disabled)
+}
+
+func TestDeliverBatch_DropsWhenEndpointNotVerified(t *testing.T) {
+	store := newFakeStore(domain.WebhookEndpoint{SubscriptionID: "sub-1", URL: "https://hooks.example.com"})
+	store.pending = []Delivery{
+		{ID: 1, SubscriptionID: "sub-1", IdempotencyKey: "key-1"},
+		{ID: 2, SubscriptionID: "sub-gone", IdempotencyKey: "key-2"},
+	}
+	snd := &fakeSender{}
+	d, statuses, metrics := newTestDispatcher(store, snd)
+
+	_, err := d.DeliverBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, snd.ids)
+	assert.Equal(t, map[int64]string{1: "webhook endpoint not verified", 2: "webhook endpoint removed"}, store.dead)
+	assert.Equal(t, domain.DeliveryFailed, statuses.updates["key-1"])
+	assert.Equal(t, 2, metrics.deliveries[ResultDropped])
+}
+
+func TestDeliverBatch_DropsWhenEndpointDisabled(t *testing.T) {
+	endpoint := verifiedEndpoint("sub-1")
+	endpoint.DisabledAt = &testNow
+	store := newFakeStore(endpoint)
+	store.pending = []Delivery{{ID: 1, SubscriptionID: "sub-1", IdempotencyKey: "key-1"}}
+	snd := &fakeSender{}
+	d, _, _ := newTestDispatcher(store, snd)
+
+	_, err := d.DeliverBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, snd.ids)
+	assert.Equal(t, "webhook endpoint disabled", store.dead[1])
+}
+
+func pendingEndpoint(attempts int) domain.WebhookEndpoint {
+	return domain.WebhookEndpoint{
+		SubscriptionID: "sub-1",
+		URL:            "https://hooks.example.com",
+		Secret:         "whsec_1",
+		VerifyAttempts: attempts,
+		NextVerifyAt:   &testNow,
+	}
+}
+
+func TestVerifyBatch_AcceptsEchoedChallenge(t *testing.T) {
+	for name, answer := range map[string]string{
+		"json":  `{"challenge":"challenge-1"}`,
+		"plain": "challenge-1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newFakeStore(pendingEndpoint(2))
+			snd := &fakeSender{answer: func([]byte) []byte { return []byte(answer) }}
+			d, _, metrics := newTestDispatcher(store, snd)
+
+			n, err := d.VerifyBatch(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Equal(t, []string{"verify:sub-1:challenge-1"}, snd.ids)
+			require.Len(t, store.verifications, 1)
+			saved := store.verifications[0]
+			assert.Equal(t, &testNow, saved.VerifiedAt)
+			assert.Zero(t, saved.VerifyAttempts)
+			assert.Nil(t, saved.NextVerifyAt)
+			assert.Equal(t, 1, metrics.verifications[ResultVerified])
+		})
+	}
+}
+
+func TestVerifyBatch_RetriesOnWrongAnswer(t *testing.T) {
+	store := newFakeStore(pendingEndpoint(0))
+	snd := &fakeSender{answer: func([]byte) []byte { return []byte("ok") }}
+	d, _, metrics := newTestDispatcher(store, snd)
+
+	_, err := d.VerifyBatch(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, store.verifications, 1)
+	saved := store.verifications[0]
+	assert.Nil(t, saved.VerifiedAt)
+	assert.Equal(t, 1, saved.VerifyAttempts)
+	require.NotNil(t, saved.NextVerifyAt)
+	assert.Equal(t, testNow.Add(30*time.Second), *saved.NextVerifyAt)
+	assert.Equal(t, errChallengeMismatch.Error(), saved.LastError)
+	assert.Equal(t, 1, metrics.verifications[ResultRetry])
+}
+
+func TestVerifyBatch_GivesUpAfterMaxAttempts(t *testing.T) {
+	store := newFakeStore(pendingEndpoint(4))
+	d, _, metrics := newTestDispatcher(store, &fakeSender{err: errors.New("connection refused")})
+
+	_, err := d.VerifyBatch(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, store.verifications, 1)
+	saved := store.verifications[0]
+	assert.Equal(t, 5, saved.VerifyAttempts)
+	assert.Nil(t, saved.NextVerifyAt)
+	assert.Equal(t, 1, metrics.verifications[ResultFailed])
+}
+
+func TestBackoff_IsCapped(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, nil, DefaultConfig())
+
+	assert.Equal(t, 30*time.Second, d.backoff(1))
+	assert.Equal(t, 4*time.Minute, d.backoff(4))
+	assert.Equal(t, time.Hour, d.backoff(20))
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"subscription/internal/domain"
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid webhook url")
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+)
+
+const maxURLLength = 2048
+
+type endpointStore interface {
+	GetEndpoint(ctx context.Context, subscriptionID string) (*domain.WebhookEndpoint, error)
+	// SaveEndpoint inserts the endpoint or replaces the one of the same subscription.
+	SaveEndpoint(ctx context.Context, e domain.WebhookEndpoint) error
+}
+
+// Endpoints registers the URLs of webhook subscriptions and schedules their handshake, which
+// the Dispatcher then performs.
+type Endpoints struct {
+	store     endpointStore
+	allowHTTP bool
+	now       func() time.Time
+}
+
+// NewEndpoints accepts only https URLs unless allowHTTP is set, e.g. for local development.
+func NewEndpoints(store endpointStore, allowHTTP bool) *Endpoints {
+	return &Endpoints{store: store, allowHTTP: allowHTTP, now: time.Now}
+}
+
+// ValidateURL checks that raw is an absolute URL the service may post to and returns it
+// without its fragment.
+func (e *Endpoints) ValidateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxURLLength {
+		return "", ErrInvalidURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", ErrInvalidURL
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && e.allowHTTP:
+	default:
+		return "", fmt.Errorf("%w: scheme must be https", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// Register stores url for the subscription with a new signing secret and schedules the
+// handshake right away. Registering again replaces the URL and the secret.
+func (e *Endpoints) Register(ctx context.Context, subscriptionID, url string) (string, error) {
+	secret, err := NewSecret()
+	if err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+
+	now := e.now()
+	err = e.store.SaveEndpoint(ctx, domain.WebhookEndpoint{
+		SubscriptionID: subscriptionID,
+		URL:            url,
+		Secret:         secret,
+		NextVerifyAt:   &now,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("save webhook endpoint: %w", err)
+	}
+	return secret, nil
+}
+
+// Reverify re-enables a disabled endpoint, or one whose handshake failed, by scheduling a new
+// handshake. The secret stays the same.
+func (e *Endpoints) Reverify(ctx context.Context, subscriptionID string) error {
+	endpoint, err := e.store.GetEndpoint(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	now := e.now()
+	endpoint.VerifiedAt = nil
+	endpoint.VerifyAttempts = 0
+	endpoint.NextVerifyAt = &now
+	endpoint.Failures = 0
+	endpoint.DisabledAt = nil
+	endpoint.LastError = ""
+	if err := e.store.SaveEndpoint(ctx, *endpoint); err != nil {
+		return fmt.Errorf("save webhook endpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Metrics struct {
+	deliveries    *prometheus.CounterVec
+	verifications *prometheus.CounterVec
+	disabled      prometheus.Counter
+	once          sync.Once
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		deliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "subscription_webhook_deliveries_total",
+			Help: "Webhook report delivery attempts by result (sent, retry, dead, dropped)",
+		}, []string{"result"}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "subscription_webhook_verifications_total",
+			Help: "Webhook handshakes by result (verified, retry, failed)",
+		}, []string{"result"}),
+		disabled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "subscription_webhook_endpoints_disabled_total",
+			Help: "Webhook endpoints disabled after repeated failed deliveries",
+		}),
+	}
+}
+
+func (m *Metrics) Register() {
+	m.once.Do(func() {
+		prometheus.MustRegister(m.deliveries, m.verifications, m.disabled)
+	})
+}
+
+func (m *Metrics) RecordDelivery(result string) {
+	m.deliveries.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) RecordVerification(result string) {
+	m.verifications.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) RecordDisabled() {
+	m.disabled.Inc()
+}
@@ -0,0 +1,40 @@
+package webhook
+
+import "time"
+
+// Values of the "type" field of webhook bodies.
+const (
+	EventWeatherReport = "weather.report"
+	EventVerification  = "webhook.verification"
+)
+
+// ReportPayload is the body of a weather report delivery. ID is also sent as the X-Webhook-Id
+// header and stays the same across retries, so receivers can drop duplicates.
+type ReportPayload struct {
+	ID             string         `json:"id"`
+	Type           string         `json:"type"`
+	SubscriptionID string         `json:"subscription_id"` //nolint:tagliatelle
+	City           string         `json:"city"`
+	Locale         string         `json:"locale,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"` //nolint:tagliatelle
+	Weather        WeatherPayload `json:"weather"`
+}
+
+type WeatherPayload struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	Description string  `json:"description"`
+}
+
+// VerificationPayload is the body of the handshake. The endpoint proves it expects our reports by
+// answering with a 2xx status and the challenge, either as {"challenge": "..."} or as plain text.
+type VerificationPayload struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	SubscriptionID string `json:"subscription_id"` //nolint:tagliatelle
+	Challenge      string `json:"challenge"`
+}
+
+type verificationAnswer struct {
+	Challenge string `json:"challenge"`
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign_MatchesKnownVector(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac whsec_test
+	assert.Equal(t,
+		"sha256=38877139021993b830af32feea6e18a8da83eb2f6e49ee50bd9e4cf4ca4d3789",
+		Sign("whsec_test", 1700000000, []byte(`{"a":1}`)),
+	)
+}
+
+func TestSender_PostsSignedBody(t *testing.T) {
+	body := []byte(`{"id":"key-1"}`)
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("thanks"))
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	sender.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	answer, err := sender.Send(context.Background(), server.URL, "whsec_test", "key-1", body)
+
+	require.NoError(t, err)
+	assert.Equal(t, "thanks", string(answer))
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, body, gotBody)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, "key-1", got.Header.Get(HeaderID))
+	assert.Equal(t, strconv.Itoa(1700000000), got.Header.Get(HeaderTimestamp))
+	assert.Equal(t, Sign("whsec_test", 1700000000, body), got.Header.Get(HeaderSignature))
+}
+
+func TestSender_RejectsNon2xx(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"error": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+		"redirect": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			_, err := NewSender(time.Second, true).Send(context.Background(), server.URL, "s", "id", nil)
+
+			assert.ErrorIs(t, err, ErrUnexpectedStatus)
+		})
+	}
+}
+
+func TestSender_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewSender(time.Second, false).Send(context.Background(), server.URL, "s", "id", nil)
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
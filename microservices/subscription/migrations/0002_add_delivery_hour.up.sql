@@ -0,0 +1,2 @@
+ALTER TABLE subscriptions
+    ADD COLUMN delivery_hour INTEGER NOT NULL DEFAULT 12;
@@ -0,0 +1,9 @@
+CREATE TABLE revoked_tokens (
+                                jti TEXT PRIMARY KEY,
+                                subscription_id TEXT NOT NULL,
+                                purpose TEXT NOT NULL,
+                                expires_at TIMESTAMPTZ,
+                                revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_subscription_id ON revoked_tokens (subscription_id);
@@ -0,0 +1,14 @@
+CREATE TABLE outbox (
+                        id BIGSERIAL PRIMARY KEY,
+                        message_id TEXT NOT NULL,
+                        routing_key TEXT NOT NULL,
+                        payload JSONB NOT NULL,
+                        status TEXT NOT NULL DEFAULT 'pending',
+                        attempts INTEGER NOT NULL DEFAULT 0,
+                        last_error TEXT,
+                        next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                        sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (next_attempt_at, id) WHERE status = 'pending';
@@ -0,0 +1,14 @@
+CREATE TABLE jobs (
+                      id BIGSERIAL PRIMARY KEY,
+                      email TEXT NOT NULL,
+                      city TEXT NOT NULL,
+                      token TEXT NOT NULL,
+                      status TEXT NOT NULL DEFAULT 'queued',
+                      attempts INTEGER NOT NULL DEFAULT 0,
+                      last_error TEXT,
+                      visible_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                      updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_queued ON jobs (visible_at, id) WHERE status = 'queued';
@@ -0,0 +1,9 @@
+ALTER TABLE jobs
+    ADD COLUMN recipients JSONB NOT NULL DEFAULT '[]';
+
+UPDATE jobs
+SET recipients = jsonb_build_array(jsonb_build_object('email', email, 'token', token));
+
+ALTER TABLE jobs
+    DROP COLUMN email,
+    DROP COLUMN token;
@@ -0,0 +1,17 @@
+CREATE TABLE dispatch_runs (
+                               id BIGSERIAL PRIMARY KEY,
+                               frequency TEXT NOT NULL,
+                               scheduled_at TIMESTAMPTZ NOT NULL,
+                               started_at TIMESTAMPTZ NOT NULL,
+                               finished_at TIMESTAMPTZ,
+                               status TEXT NOT NULL,
+                               catch_up BOOLEAN NOT NULL DEFAULT FALSE,
+                               tasks_total INTEGER NOT NULL DEFAULT 0,
+                               tasks_enqueued INTEGER NOT NULL DEFAULT 0,
+                               tasks_failed INTEGER NOT NULL DEFAULT 0,
+                               recipients INTEGER NOT NULL DEFAULT 0,
+                               error TEXT,
+                               UNIQUE (frequency, scheduled_at)
+);
+
+CREATE INDEX idx_dispatch_runs_started_at ON dispatch_runs (started_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE jobs
+    ADD COLUMN run_id BIGINT REFERENCES dispatch_runs (id) ON DELETE SET NULL;
@@ -0,0 +1,20 @@
+CREATE TABLE deliveries (
+                            id BIGSERIAL PRIMARY KEY,
+                            subscription_id TEXT NOT NULL,
+                            email TEXT NOT NULL,
+                            city TEXT NOT NULL,
+                            run_id BIGINT REFERENCES dispatch_runs (id) ON DELETE SET NULL,
+                            job_id TEXT,
+                            idempotency_key TEXT NOT NULL UNIQUE,
+                            weather JSONB,
+                            status TEXT NOT NULL,
+                            error TEXT,
+                            queued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                            sent_at TIMESTAMPTZ,
+                            failed_at TIMESTAMPTZ,
+                            bounced_at TIMESTAMPTZ,
+                            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_deliveries_subscription ON deliveries (subscription_id, queued_at DESC);
+CREATE INDEX idx_deliveries_run ON deliveries (run_id) WHERE run_id IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    ADD COLUMN reminder_sent_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_pending_created_at ON subscriptions (created_at)
+    WHERE is_confirmed = FALSE AND is_unsubscribed = FALSE;
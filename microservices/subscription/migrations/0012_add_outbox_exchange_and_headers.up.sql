@@ -0,0 +1,4 @@
+-- Domain events go to their own exchange; an empty value means the default email exchange.
+ALTER TABLE outbox
+    ADD COLUMN exchange TEXT NOT NULL DEFAULT '',
+    ADD COLUMN headers JSONB;
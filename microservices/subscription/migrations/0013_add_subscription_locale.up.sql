@@ -0,0 +1,3 @@
+-- Existing subscribers have always received Ukrainian emails.
+ALTER TABLE subscriptions
+    ADD COLUMN locale TEXT NOT NULL DEFAULT 'uk';
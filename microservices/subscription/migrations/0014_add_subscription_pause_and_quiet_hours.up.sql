@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    ADD COLUMN paused_until TIMESTAMPTZ,
+    ADD COLUMN quiet_hours_start INT,
+    ADD COLUMN quiet_hours_end INT,
+    ADD COLUMN quiet_hours_timezone TEXT;
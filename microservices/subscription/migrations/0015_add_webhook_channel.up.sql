@@ -0,0 +1,34 @@
+ALTER TABLE subscriptions
+    ADD COLUMN channel TEXT NOT NULL DEFAULT 'email';
+
+CREATE TABLE webhook_endpoints (
+                                   subscription_id TEXT PRIMARY KEY REFERENCES subscriptions (id) ON DELETE CASCADE,
+                                   url TEXT NOT NULL,
+                                   secret TEXT NOT NULL,
+                                   verified_at TIMESTAMPTZ,
+                                   verify_attempts INTEGER NOT NULL DEFAULT 0,
+                                   next_verify_at TIMESTAMPTZ,
+                                   failures INTEGER NOT NULL DEFAULT 0,
+                                   disabled_at TIMESTAMPTZ,
+                                   last_error TEXT,
+                                   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhook_endpoints_verify ON webhook_endpoints (next_verify_at)
+    WHERE verified_at IS NULL AND next_verify_at IS NOT NULL;
+
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    subscription_id TEXT NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+                                    idempotency_key TEXT NOT NULL UNIQUE,
+                                    payload JSONB NOT NULL,
+                                    status TEXT NOT NULL DEFAULT 'pending',
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    last_error TEXT,
+                                    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at, id) WHERE status = 'pending';
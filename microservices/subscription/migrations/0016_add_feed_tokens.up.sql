@@ -0,0 +1,6 @@
+ALTER TABLE subscriptions
+    ADD COLUMN feed_token TEXT NOT NULL DEFAULT '';
+
+-- Feeds read the latest report snapshots of a city from the delivery history.
+CREATE INDEX idx_deliveries_feed ON deliveries (lower(city), run_id)
+    WHERE weather IS NOT NULL AND run_id IS NOT NULL;
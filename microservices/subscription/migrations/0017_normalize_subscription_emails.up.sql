@@ -0,0 +1,10 @@
+-- Addresses are lowercased on input now; bring the stored ones in line so lookups keep finding them.
+-- Rows that would fold into an address another row has are left alone: the service merges them
+-- once at startup and announces the subscriptions it removes.
+UPDATE subscriptions s
+    SET email = rtrim(lower(trim(s.email)), '.')
+    WHERE s.email <> rtrim(lower(trim(s.email)), '.')
+      AND NOT EXISTS (
+          SELECT 1 FROM subscriptions o
+          WHERE o.id <> s.id AND rtrim(lower(trim(o.email)), '.') = rtrim(lower(trim(s.email)), '.')
+      );
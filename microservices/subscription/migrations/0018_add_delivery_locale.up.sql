@@ -0,0 +1,8 @@
+-- Reports are fetched per recipient locale; feeds pick the snapshot in the subscriber's language.
+-- Older deliveries have no known locale and are left out of feeds.
+ALTER TABLE deliveries
+    ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_deliveries_feed;
+CREATE INDEX idx_deliveries_feed ON deliveries (lower(city), locale, run_id)
+    WHERE weather IS NOT NULL AND run_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- An address can follow several cities, one subscription per city.
+ALTER TABLE subscriptions
+    DROP CONSTRAINT subscriptions_email_key;
+
+CREATE UNIQUE INDEX idx_subscriptions_email_city ON subscriptions (email, lower(city));
@@ -0,0 +1,14 @@
+-- Subscribers can have the reports of several cities combined into one digest email.
+ALTER TABLE subscriptions
+    ADD COLUMN digest BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A digest task carries one address's subscriptions in several cities instead of one city's subscribers.
+ALTER TABLE jobs
+    ADD COLUMN digest BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The deliveries of a digest share the key of its one email, so status reports update all of them.
+ALTER TABLE deliveries
+    DROP CONSTRAINT deliveries_idempotency_key_key;
+
+ALTER TABLE deliveries
+    ADD CONSTRAINT deliveries_idempotency_key_subscription_key UNIQUE (idempotency_key, subscription_id);
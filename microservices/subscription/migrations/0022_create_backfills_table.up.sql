@@ -0,0 +1,5 @@
+-- One-off data fixes run by the service; a row records that the named one ran.
+CREATE TABLE backfills (
+    name        TEXT PRIMARY KEY,
+    finished_at TIMESTAMPTZ NOT NULL
+);
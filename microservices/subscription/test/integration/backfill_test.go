@@ -0,0 +1,96 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/domain"
+	"subscription/internal/subscription"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRepository_MergeAddresses(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	deliveries := gorm.NewDeliveryRepo(pg.DB.Gorm)
+	backfills := gorm.NewBackfillRepo(pg.DB.Gorm)
+	now := time.Now()
+	create := func(email, city string, confirmed bool, createdAt time.Time) *domain.Subscription {
+		sub := &domain.Subscription{
+			ID:          uuid.NewString(),
+			Email:       email,
+			City:        city,
+			Frequency:   domain.FreqDaily,
+			IsConfirmed: confirmed,
+			Token:       "token-" + email,
+			CreatedAt:   createdAt,
+		}
+		require.NoError(t, repo.Create(ctx, sub))
+		return sub
+	}
+
+	create("Solo+news@example.com", "Kyiv", true, now)
+	active := create("dup+a@example.com", "Kyiv", true, now.Add(-time.Hour))
+	pending := create("Dup@example.com", "Kyiv", false, now)
+	create("+leading@example.com", "Kyiv", false, now)
+	other := create("dup+b@example.com", "Lviv", false, now)
+
+	require.NoError(t, deliveries.Record(ctx, domain.Delivery{
+		SubscriptionID: pending.ID, Email: pending.Email, City: "Kyiv",
+		IdempotencyKey: "report:h1", Status: domain.DeliveryQueued, QueuedAt: now, UpdatedAt: now,
+	}))
+
+	claimed, err := backfills.Claim(ctx, "merge_addresses_strip_plus_tags")
+	require.NoError(t, err)
+	require.True(t, claimed)
+	claimed, err = backfills.Claim(ctx, "merge_addresses_strip_plus_tags")
+	require.NoError(t, err)
+	require.False(t, claimed, "a backfill runs once")
+
+	res, err := backfills.MergeAddresses(ctx, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, res.Rewritten)
+	require.Len(t, res.Removed, 1)
+	require.Equal(t, pending.ID, res.Removed[0].Sub.ID)
+	require.Equal(t, active.ID, res.Removed[0].KeptID)
+
+	solo, err := repo.GetByEmailAndCity(ctx, "solo@example.com", "Kyiv")
+	require.NoError(t, err)
+	require.True(t, solo.IsConfirmed)
+
+	dup, err := repo.GetByEmailAndCity(ctx, "dup@example.com", "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, active.ID, dup.ID, "the confirmed subscription is kept")
+	require.Equal(t, active.Token, dup.Token, "with its tokens")
+
+	history, err := deliveries.ListBySubscription(ctx, active.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1, "the delivery history of the removed subscription moves to the kept one")
+
+	_, err = repo.GetByID(ctx, pending.ID)
+	require.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+
+	_, err = repo.GetByEmailAndCity(ctx, "+leading@example.com", "Kyiv")
+	require.NoError(t, err)
+
+	lviv, err := repo.GetByEmailAndCity(ctx, "dup@example.com", "Lviv")
+	require.NoError(t, err, "subscriptions to other cities are not duplicates")
+	require.Equal(t, other.ID, lviv.ID)
+}
@@ -0,0 +1,189 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/domain"
+	"subscription/internal/job"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryRepository_History(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	runs := gorm.NewDispatchRunRepo(pg.DB.Gorm)
+	run, err := runs.Start(ctx, job.Run{Frequency: "hourly", ScheduledAt: time.Now().UTC().Truncate(time.Hour), StartedAt: time.Now(), Status: job.RunRunning})
+	require.NoError(t, err)
+
+	repo := gorm.NewDeliveryRepo(pg.DB.Gorm)
+	now := time.Now()
+	report := domain.Report{Temperature: 18.5, Humidity: 60, Description: "Cloudy"}
+
+	// A weather outage is recorded as failed, then the retried task queues the same delivery again.
+	failed := domain.Delivery{
+		SubscriptionID: "sub-1", Email: "a@example.com", City: "Kyiv", RunID: run.ID, JobID: "1",
+		IdempotencyKey: "report:a@example.com:h1", Status: domain.DeliveryFailed, Error: "weather down",
+		FailedAt: &now, QueuedAt: now, UpdatedAt: now,
+	}
+	require.NoError(t, repo.Record(ctx, failed))
+
+	queued := failed
+	queued.Status, queued.Error, queued.FailedAt, queued.Weather = domain.DeliveryQueued, "", nil, &report
+	require.NoError(t, repo.Record(ctx, queued))
+
+	history, err := repo.ListBySubscription(ctx, "sub-1", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, domain.DeliveryQueued, history[0].Status)
+	require.Equal(t, &report, history[0].Weather)
+	require.NotNil(t, history[0].FailedAt, "earlier failure is kept")
+
+	// Status events: sent wins over a late failed, bounced wins over sent.
+	ok, err := repo.UpdateStatus(ctx, "report:a@example.com:h1", domain.DeliverySent, now, "")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.UpdateStatus(ctx, "report:a@example.com:h1", domain.DeliveryFailed, now, "late retry")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// A duplicate task must not reset a sent delivery.
+	require.NoError(t, repo.Record(ctx, queued))
+
+	byRun, err := repo.ListByRun(ctx, run.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, byRun, 1)
+	require.Equal(t, domain.DeliverySent, byRun[0].Status)
+	require.NotNil(t, byRun[0].SentAt)
+	require.Empty(t, byRun[0].Error)
+
+	ok, err = repo.UpdateStatus(ctx, "report:a@example.com:h1", domain.DeliveryBounced, now, "mailbox unavailable")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.UpdateStatus(ctx, "confirm:unknown", domain.DeliverySent, now, "")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	history, err = repo.ListBySubscription(ctx, "sub-1", 10)
+	require.NoError(t, err)
+	require.Equal(t, domain.DeliveryBounced, history[0].Status)
+	require.Equal(t, "mailbox unavailable", history[0].Error)
+}
+
+func TestDeliveryRepository_DigestSharesKey(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewDeliveryRepo(pg.DB.Gorm)
+	now := time.Now()
+	for _, city := range []string{"Kyiv", "Lviv"} {
+		require.NoError(t, repo.Record(ctx, domain.Delivery{
+			SubscriptionID: "sub-" + city, Email: "a@example.com", City: city,
+			IdempotencyKey: "digest:a@example.com:h1", Status: domain.DeliveryQueued, QueuedAt: now, UpdatedAt: now,
+		}))
+	}
+
+	ok, err := repo.UpdateStatus(ctx, "digest:a@example.com:h1", domain.DeliverySent, now, "")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	for _, id := range []string{"sub-Kyiv", "sub-Lviv"} {
+		history, err := repo.ListBySubscription(ctx, id, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, domain.DeliverySent, history[0].Status)
+	}
+}
+
+func TestDeliveryRepository_LatestReports(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	runs := gorm.NewDispatchRunRepo(pg.DB.Gorm)
+	repo := gorm.NewDeliveryRepo(pg.DB.Gorm)
+	slot := time.Now().UTC().Truncate(time.Hour)
+	now := time.Now()
+
+	record := func(frequency string, scheduledAt time.Time, city string, temps ...float64) {
+		run, err := runs.Start(ctx, job.Run{Frequency: frequency, ScheduledAt: scheduledAt, StartedAt: now, Status: job.RunRunning})
+		require.NoError(t, err)
+		for i, temp := range temps {
+			// The Ukrainian copy of the run is recorded first, so a locale-blind pick would find it.
+			uk := domain.Report{Temperature: temp, Humidity: 50, Description: "Ясно"}
+			require.NoError(t, repo.Record(ctx, domain.Delivery{
+				SubscriptionID: "sub-uk", Email: "b@example.com", City: city, Locale: "uk", RunID: run.ID,
+				IdempotencyKey: fmt.Sprintf("report:%s:%d:%d:uk", frequency, run.ID, i), Weather: &uk,
+				Status: domain.DeliveryQueued, QueuedAt: now, UpdatedAt: now,
+			}))
+			report := domain.Report{Temperature: temp, Humidity: 50, Description: "Clear"}
+			require.NoError(t, repo.Record(ctx, domain.Delivery{
+				SubscriptionID: "sub", Email: "a@example.com", City: city, Locale: "en", RunID: run.ID,
+				IdempotencyKey: fmt.Sprintf("report:%s:%d:%d", frequency, run.ID, i), Weather: &report,
+				Status: domain.DeliveryQueued, QueuedAt: now, UpdatedAt: now,
+			}))
+		}
+	}
+	// Two recipients in the newest run stand for one report.
+	record("hourly", slot.Add(-2*time.Hour), "Kyiv", 10)
+	record("hourly", slot.Add(-time.Hour), "kyiv", 11)
+	record("hourly", slot, "Kyiv", 12, 12)
+	record("daily", slot.Add(-3*time.Hour), "Kyiv", 20)
+
+	latest, err := repo.LatestReports(ctx, "KYIV", "en", domain.FreqHourly, 2)
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	require.Equal(t, 12.0, latest[0].Report.Temperature)
+	require.Equal(t, "Clear", latest[0].Report.Description)
+	require.True(t, slot.Equal(latest[0].At))
+	require.Equal(t, 11.0, latest[1].Report.Temperature)
+	require.NotZero(t, latest[0].RunID)
+
+	ukrainian, err := repo.LatestReports(ctx, "Kyiv", "uk", domain.FreqHourly, 1)
+	require.NoError(t, err)
+	require.Len(t, ukrainian, 1)
+	require.Equal(t, "Ясно", ukrainian[0].Report.Description)
+
+	daily, err := repo.LatestReports(ctx, "Kyiv", "en", domain.FreqDaily, 10)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+
+	none, err := repo.LatestReports(ctx, "Lviv", "en", domain.FreqHourly, 10)
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	unknown, err := repo.LatestReports(ctx, "Kyiv", "de", domain.FreqHourly, 10)
+	require.NoError(t, err)
+	require.Empty(t, unknown)
+}
@@ -0,0 +1,59 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/job"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatchRunRepository_SlotRecordedOnce(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewDispatchRunRepo(pg.DB.Gorm)
+	slot := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok, err := repo.LatestScheduled(ctx, "daily")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	run, err := repo.Start(ctx, job.Run{Frequency: "daily", ScheduledAt: slot, StartedAt: time.Now(), Status: job.RunRunning})
+	require.NoError(t, err)
+	require.NotZero(t, run.ID)
+
+	_, err = repo.Start(ctx, job.Run{Frequency: "daily", ScheduledAt: slot, StartedAt: time.Now(), Status: job.RunRunning})
+	require.ErrorIs(t, err, job.ErrRunExists)
+
+	finished := time.Now()
+	run.Status = job.RunSucceeded
+	run.FinishedAt = &finished
+	run.TasksTotal = 2
+	require.NoError(t, repo.Finish(ctx, run))
+
+	latest, ok, err := repo.LatestScheduled(ctx, "daily")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, latest.Equal(slot))
+
+	runs, err := repo.ListRecent(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, job.RunSucceeded, runs[0].Status)
+	require.Equal(t, 2, runs[0].TasksTotal)
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/job"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository_ClaimRespectsVisibilityTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewJobRepo(pg.DB.Gorm)
+	slot := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Insert(ctx, job.Task{ScheduledAt: slot, City: "Kyiv", Recipients: []job.Recipient{{Email: "jobs@example.com", Token: "t"}}}))
+
+	now := time.Now()
+	task, ok, err := repo.Claim(ctx, now, now.Add(time.Minute), 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, task.Attempts)
+	require.Equal(t, "jobs@example.com", task.Recipients[0].Email)
+	require.Equal(t, slot, task.ScheduledAt)
+
+	_, ok, err = repo.Claim(ctx, now, now.Add(time.Minute), 3)
+	require.NoError(t, err)
+	require.False(t, ok, "leased job must stay invisible")
+
+	later := now.Add(2 * time.Minute)
+	again, ok, err := repo.Claim(ctx, later, later.Add(time.Minute), 3)
+	require.NoError(t, err)
+	require.True(t, ok, "job must reappear after the visibility timeout")
+	require.Equal(t, task.ID, again.ID)
+	require.Equal(t, 2, again.Attempts)
+
+	require.NoError(t, repo.Complete(ctx, again.ID))
+
+	_, ok, err = repo.Claim(ctx, later.Add(time.Hour), later.Add(2*time.Hour), 3)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestJobRepository_KeepsDigestTasks(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewJobRepo(pg.DB.Gorm)
+	require.NoError(t, repo.Insert(ctx, job.Task{Digest: true, Recipients: []job.Recipient{
+		{SubscriptionID: "sub-kyiv", Email: "digest@example.com", Token: "t1", City: "Kyiv"},
+		{SubscriptionID: "sub-lviv", Email: "digest@example.com", Token: "t2", City: "Lviv"},
+	}}))
+
+	now := time.Now()
+	task, ok, err := repo.Claim(ctx, now, now.Add(time.Minute), 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, task.Digest)
+	require.Equal(t, "Lviv", task.Recipients[1].City)
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvisoryLock_OnlyOneHolderAndFailover(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	first := gorm.NewAdvisoryLock(pg.DB.Gorm, 42)
+	second := gorm.NewAdvisoryLock(pg.DB.Gorm, 42)
+
+	ok, err := first.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	held, err := first.Held(ctx)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	ok, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, first.Release(ctx))
+
+	ok, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, second.Release(ctx))
+}
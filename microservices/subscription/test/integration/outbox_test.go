@@ -0,0 +1,115 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/domain"
+	"subscription/internal/outbox"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_RolledBackWithSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	outboxRepo := gorm.NewOutboxRepo(pg.DB.Gorm)
+	tx := gorm.NewTransactor(pg.DB.Gorm)
+
+	errAbort := errors.New("abort")
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, repo.Create(ctx, &domain.Subscription{
+			ID:        uuid.NewString(),
+			Email:     "rollback@example.com",
+			City:      "Kyiv",
+			Frequency: domain.FreqDaily,
+			Token:     "token",
+			CreatedAt: time.Now(),
+		}))
+		require.NoError(t, outboxRepo.Add(ctx, outbox.Message{MessageID: "m-1", RoutingKey: "email.confirmation", Payload: []byte(`{}`)}))
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	stats, err := outboxRepo.Stats(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.Pending)
+}
+
+func TestOutbox_ClaimAndMarkSent(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	outboxRepo := gorm.NewOutboxRepo(pg.DB.Gorm)
+	require.NoError(t, outboxRepo.Add(ctx, outbox.Message{MessageID: "m-1", RoutingKey: "email.confirmation", Payload: []byte(`{"to":"a@b.c"}`)}))
+
+	now := time.Now()
+	claimed, err := outboxRepo.Claim(ctx, 10, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "m-1", claimed[0].MessageID)
+
+	again, err := outboxRepo.Claim(ctx, 10, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, again, "leased rows must not be claimed twice")
+
+	require.NoError(t, outboxRepo.MarkSent(ctx, claimed[0].ID, time.Now()))
+
+	stats, err := outboxRepo.Stats(ctx)
+	require.NoError(t, err)
+	require.Zero(t, stats.Pending)
+}
+
+func TestOutbox_DeleteSentBefore(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	outboxRepo := gorm.NewOutboxRepo(pg.DB.Gorm)
+	require.NoError(t, outboxRepo.Add(ctx, outbox.Message{MessageID: "m-1", RoutingKey: "email.confirmation", Payload: []byte(`{}`)}))
+	require.NoError(t, outboxRepo.Add(ctx, outbox.Message{MessageID: "m-2", RoutingKey: "email.confirmation", Payload: []byte(`{}`)}))
+
+	now := time.Now()
+	claimed, err := outboxRepo.Claim(ctx, 1, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NoError(t, outboxRepo.MarkSent(ctx, claimed[0].ID, now.Add(-48*time.Hour)))
+
+	deleted, err := outboxRepo.DeleteSentBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	stats, err := outboxRepo.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Pending, "unsent messages are never purged")
+}
@@ -0,0 +1,106 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/domain"
+	"subscription/internal/job"
+	"subscription/internal/outbox"
+	"subscription/internal/webhook"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacyRepository_CollectAndErase(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	subs := gorm.NewRepo(pg.DB.Gorm)
+	deliveries := gorm.NewDeliveryRepo(pg.DB.Gorm)
+	outboxRepo := gorm.NewOutboxRepo(pg.DB.Gorm)
+	jobs := gorm.NewJobRepo(pg.DB.Gorm)
+	webhooks := gorm.NewWebhookRepo(pg.DB.Gorm)
+	repo := gorm.NewPrivacyRepo(pg.DB.Gorm)
+	now := time.Now()
+
+	for _, email := range []string{"gone@example.com", "stay@example.com"} {
+		id := uuid.NewString()
+		require.NoError(t, subs.Create(ctx, &domain.Subscription{
+			ID: id, Email: email, City: "Kyiv", Frequency: domain.FreqDaily, Channel: domain.ChannelWebhook, Token: "t", CreatedAt: now,
+		}))
+		require.NoError(t, webhooks.SaveEndpoint(ctx, domain.WebhookEndpoint{
+			SubscriptionID: id, URL: "https://hooks.example.com/" + id, Secret: "secret-" + id, CreatedAt: now,
+		}))
+		require.NoError(t, webhooks.Enqueue(ctx, webhook.Delivery{
+			SubscriptionID: id, IdempotencyKey: "report:" + email, Payload: []byte(`{}`),
+		}))
+		require.NoError(t, deliveries.Record(ctx, domain.Delivery{
+			Email: email, City: "Kyiv", IdempotencyKey: "report:" + email, Status: domain.DeliveryQueued, QueuedAt: now, UpdatedAt: now,
+		}))
+		require.NoError(t, outboxRepo.Add(ctx, outbox.Message{
+			MessageID: uuid.NewString(), RoutingKey: "email.confirmation", Payload: []byte(`{"to":"` + email + `"}`),
+		}))
+	}
+	require.NoError(t, jobs.Insert(ctx, job.Task{City: "Kyiv", Recipients: []job.Recipient{
+		{Email: "gone@example.com", Token: "t"},
+		{Email: "stay@example.com", Token: "t"},
+	}}))
+
+	data, err := repo.Collect(ctx, "gone@example.com")
+	require.NoError(t, err)
+	require.Len(t, data.Subscriptions, 1)
+	require.Len(t, data.Deliveries, 1)
+	require.Len(t, data.Messages, 1)
+	require.Equal(t, "email.confirmation", data.Messages[0].RoutingKey)
+	require.Len(t, data.Webhooks, 1)
+	require.Equal(t, data.Subscriptions[0].ID, data.Webhooks[0].SubscriptionID)
+	require.Len(t, data.WebhookDeliveries, 1)
+
+	res, err := repo.Erase(ctx, "gone@example.com")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.Subscriptions)
+	require.Len(t, res.Removed, 1)
+	require.Equal(t, "gone@example.com", res.Removed[0].Email)
+	require.Equal(t, int64(1), res.Deliveries)
+	require.Equal(t, int64(1), res.Messages)
+	require.Equal(t, int64(1), res.Jobs)
+	require.Equal(t, int64(1), res.Webhooks)
+	require.Equal(t, int64(1), res.WebhookDeliveries)
+
+	data, err = repo.Collect(ctx, "gone@example.com")
+	require.NoError(t, err)
+	require.Empty(t, data.Subscriptions)
+	require.Empty(t, data.Deliveries)
+	require.Empty(t, data.Messages)
+	require.Empty(t, data.Webhooks)
+	require.Empty(t, data.WebhookDeliveries)
+
+	// The queued job keeps delivering to everyone else.
+	task, ok, err := jobs.Claim(ctx, now, now.Add(time.Minute), 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []job.Recipient{{Email: "stay@example.com", Token: "t"}}, task.Recipients)
+
+	kept, err := repo.Collect(ctx, "stay@example.com")
+	require.NoError(t, err)
+	require.Len(t, kept.Subscriptions, 1)
+	require.Len(t, kept.Deliveries, 1)
+	require.Len(t, kept.Messages, 1)
+	require.Len(t, kept.Webhooks, 1)
+	require.Len(t, kept.WebhookDeliveries, 1)
+}
@@ -44,7 +44,7 @@ func TestSubscriptionRepository_CRUD(t *testing.T) {
 	err = repo.Create(ctx, sub)
 	require.NoError(t, err)
 
-	got, err := repo.GetByEmail(ctx, "test@example.com")
+	got, err := repo.GetByEmailAndCity(ctx, "test@example.com", "Kyiv")
 	require.NoError(t, err)
 
 	require.Equal(t, sub.Email, got.Email)
@@ -57,11 +57,49 @@ func TestSubscriptionRepository_CRUD(t *testing.T) {
 	err = repo.Update(ctx, got)
 	require.NoError(t, err)
 
-	updated, err := repo.GetByEmail(ctx, "test@example.com")
+	updated, err := repo.GetByEmailAndCity(ctx, "test@example.com", "Kyiv")
 	require.NoError(t, err)
 	require.True(t, updated.IsConfirmed)
 }
 
+func TestSubscriptionRepository_SeveralCitiesPerAddress(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	now := time.Now()
+	create := func(city string, createdAt time.Time) error {
+		return repo.Create(ctx, &domain.Subscription{
+			ID: uuid.NewString(), Email: "multi@example.com", City: city, Frequency: domain.FreqDaily,
+			Token: "t", CreatedAt: createdAt, Digest: true,
+		})
+	}
+	require.NoError(t, create("Lviv", now))
+	require.NoError(t, create("Kyiv", now.Add(-time.Hour)))
+	require.Error(t, create("KYIV", now), "an address follows a city once")
+
+	subs, err := repo.ListByEmail(ctx, "multi@example.com")
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	require.Equal(t, "Kyiv", subs[0].City, "oldest first")
+	require.True(t, subs[0].Digest)
+
+	lviv, err := repo.GetByEmailAndCity(ctx, "multi@example.com", "lviv")
+	require.NoError(t, err)
+	require.Equal(t, "Lviv", lviv.City)
+
+	none, err := repo.ListByEmail(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	require.Empty(t, none)
+}
+
 func TestSubscriptionRepository_NotFound(t *testing.T) {
 	ctx := context.Background()
 
@@ -75,7 +113,132 @@ func TestSubscriptionRepository_NotFound(t *testing.T) {
 
 	repo := gorm.NewRepo(pg.DB.Gorm)
 
-	sub, err := repo.GetByEmail(ctx, "nonexistent@example.com")
+	sub, err := repo.GetByEmailAndCity(ctx, "nonexistent@example.com", "Kyiv")
 	require.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
 	require.Nil(t, sub)
 }
+
+func TestSubscriptionRepository_SearchAndStats(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []domain.Subscription{
+		{Email: "anna@example.com", City: "Kyiv", Frequency: domain.FreqDaily, IsConfirmed: true},
+		{Email: "bob@example.com", City: "kyiv", Frequency: domain.FreqHourly, IsConfirmed: true},
+		{Email: "carl@example.com", City: "Lviv", Frequency: domain.FreqDaily},
+		{Email: "dana@test.org", City: "Kyiv", Frequency: domain.FreqDaily, IsConfirmed: true, IsUnsubscribed: true},
+		{Email: "a_b@example.com", City: "Odesa", Frequency: domain.FreqDaily, IsConfirmed: true},
+	}
+	for i := range seed {
+		seed[i].ID = uuid.NewString()
+		seed[i].Token = "t"
+		seed[i].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, repo.Create(ctx, &seed[i]))
+	}
+
+	kyiv, err := repo.Search(ctx, subscription.SearchFilter{City: "KYIV", Status: domain.StatusActive}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, kyiv, 2)
+	require.Equal(t, "bob@example.com", kyiv[0].Email, "newest first")
+
+	// Underscore is matched literally, not as a LIKE wildcard.
+	literal, err := repo.Search(ctx, subscription.SearchFilter{Email: "A_B"}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, literal, 1)
+
+	var seen []string
+	var after *subscription.Cursor
+	for {
+		page, err := repo.Search(ctx, subscription.SearchFilter{Email: "example.com"}, after, 2)
+		require.NoError(t, err)
+		for _, s := range page {
+			seen = append(seen, s.Email)
+		}
+		if len(page) < 2 {
+			break
+		}
+		last := page[len(page)-1]
+		after = &subscription.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	require.Equal(t, []string{"a_b@example.com", "carl@example.com", "bob@example.com", "anna@example.com"}, seen)
+
+	stats, err := repo.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), stats.Total)
+	require.Equal(t, int64(1), stats.Pending)
+	require.Equal(t, int64(3), stats.Active)
+	require.Equal(t, int64(1), stats.Unsubscribed)
+	require.Equal(t, int64(4), stats.Confirmed)
+	require.Equal(t, int64(2), stats.ByFrequency[domain.FreqDaily])
+	require.Equal(t, int64(1), stats.ByFrequency[domain.FreqHourly])
+	require.Len(t, stats.ByCity, 3)
+}
+
+func TestSubscriptionRepository_ReminderAndExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	now := time.Now()
+
+	create := func(email string, age time.Duration, confirmed bool) string {
+		id := uuid.NewString()
+		require.NoError(t, repo.Create(ctx, &domain.Subscription{
+			ID: id, Email: email, City: "Kyiv", Frequency: domain.FreqDaily,
+			IsConfirmed: confirmed, Token: "t", CreatedAt: now.Add(-age),
+		}))
+		return id
+	}
+	stale := create("stale@example.com", 100*time.Hour, false)
+	due := create("due@example.com", 30*time.Hour, false)
+	create("fresh@example.com", time.Hour, false)
+	create("active@example.com", 100*time.Hour, true)
+
+	subs, err := repo.DueForReminder(ctx, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	require.Equal(t, stale, subs[0].ID, "oldest first")
+	require.Equal(t, due, subs[1].ID)
+
+	ok, err := repo.MarkReminded(ctx, due, now)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.MarkReminded(ctx, due, now)
+	require.NoError(t, err)
+	require.False(t, ok, "a subscription is reminded once")
+
+	subs, err = repo.DueForReminder(ctx, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+
+	got, err := repo.GetByID(ctx, due)
+	require.NoError(t, err)
+	require.NotNil(t, got.ReminderSentAt)
+
+	deleted, err := repo.DeleteUnconfirmedBefore(ctx, now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, stale, deleted[0].ID)
+
+	_, err = repo.GetByID(ctx, stale)
+	require.ErrorIs(t, err, subscription.ErrSubscriptionNotFound)
+	_, err = repo.GetByEmailAndCity(ctx, "active@example.com", "Kyiv")
+	require.NoError(t, err, "confirmed subscriptions never expire")
+}
@@ -0,0 +1,118 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"subscription/internal/adapter/weathergrpc"
+	weatherpb "subscription/internal/adapter/weathergrpc/pb"
+
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig/certtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type stubWeatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+}
+
+func (stubWeatherServer) GetWeather(_ context.Context, req *weatherpb.WeatherRequest) (*weatherpb.WeatherResponse, error) {
+	return &weatherpb.WeatherResponse{Temperature: 18, Humidity: 55, Description: "Cloudy in " + req.City}, nil
+}
+
+// startTLSWeatherServer serves the weather API over mutual TLS, accepting only clients whose
+// certificate carries one of allowedSANs.
+func startTLSWeatherServer(t *testing.T, ca *certtest.CA, allowedSANs ...string) string {
+	t.Helper()
+
+	files := ca.Issue(t, "weather", "localhost", "127.0.0.1")
+	source, err := tlsconfig.Load(tlsconfig.Options{
+		CertFile:    files.CertFile,
+		KeyFile:     files.KeyFile,
+		CAFile:      ca.CAFile,
+		AllowedSANs: allowedSANs,
+	})
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(source.ServerConfig())))
+	weatherpb.RegisterWeatherServiceServer(server, stubWeatherServer{})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func newTLSWeatherClient(t *testing.T, addr string, opts tlsconfig.Options) *weathergrpc.Client {
+	t.Helper()
+
+	source, err := tlsconfig.Load(opts)
+	require.NoError(t, err)
+	client, err := weathergrpc.NewClient(context.Background(), addr, source.ClientConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestWeatherGRPC_MutualTLS(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	addr := startTLSWeatherServer(t, ca, "subscription.svc.local")
+	files := ca.Issue(t, "subscription", "subscription.svc.local")
+
+	client := newTLSWeatherClient(t, addr, tlsconfig.Options{
+		CertFile:   files.CertFile,
+		KeyFile:    files.KeyFile,
+		CAFile:     ca.CAFile,
+		ServerName: "localhost",
+	})
+
+	report, err := client.GetWeather(context.Background(), "Kyiv", "uk")
+	require.NoError(t, err)
+	assert.Equal(t, "Cloudy in Kyiv", report.Description)
+}
+
+func TestWeatherGRPC_RejectsClientOutsideAllowlist(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	addr := startTLSWeatherServer(t, ca, "subscription.svc.local")
+	files := ca.Issue(t, "gateway", "gateway.svc.local")
+
+	client := newTLSWeatherClient(t, addr, tlsconfig.Options{
+		CertFile:   files.CertFile,
+		KeyFile:    files.KeyFile,
+		CAFile:     ca.CAFile,
+		ServerName: "localhost",
+	})
+
+	_, err := client.GetWeather(context.Background(), "Kyiv", "uk")
+	assert.Error(t, err)
+}
+
+func TestWeatherGRPC_RejectsClientWithoutCertificate(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	addr := startTLSWeatherServer(t, ca)
+
+	client := newTLSWeatherClient(t, addr, tlsconfig.Options{CAFile: ca.CAFile, ServerName: "localhost"})
+
+	_, err := client.GetWeather(context.Background(), "Kyiv", "uk")
+	assert.Error(t, err)
+}
+
+func TestWeatherGRPC_PlaintextClientCannotConnect(t *testing.T) {
+	ca := certtest.NewCA(t, "internal")
+	addr := startTLSWeatherServer(t, ca)
+
+	client, err := weathergrpc.NewClient(context.Background(), addr, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetWeather(context.Background(), "Kyiv", "uk")
+	assert.Error(t, err)
+}
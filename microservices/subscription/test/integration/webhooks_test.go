@@ -0,0 +1,155 @@
+//go:build integration
+
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"subscription/test/integration/testutils"
+
+	"subscription/internal/adapter/gorm"
+	"subscription/internal/domain"
+	"subscription/internal/webhook"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createWebhookSubscription(ctx context.Context, t *testing.T, repo *gorm.GormSubscriptionRepository) string {
+	t.Helper()
+	id := uuid.NewString()
+	require.NoError(t, repo.Create(ctx, &domain.Subscription{
+		ID:        id,
+		Email:     id + "@example.com",
+		City:      "Kyiv",
+		Frequency: domain.FreqDaily,
+		Channel:   domain.ChannelWebhook,
+		Token:     "token-" + id,
+		CreatedAt: time.Now(),
+	}))
+	return id
+}
+
+func TestWebhooks_EnqueueDedupesAndClaims(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	webhookRepo := gorm.NewWebhookRepo(pg.DB.Gorm)
+	subID := createWebhookSubscription(ctx, t, repo)
+
+	delivery := webhook.Delivery{SubscriptionID: subID, IdempotencyKey: "report-1", Payload: []byte(`{"id":"report-1"}`)}
+	require.NoError(t, webhookRepo.Enqueue(ctx, delivery))
+	require.NoError(t, webhookRepo.Enqueue(ctx, delivery))
+
+	now := time.Now().Add(time.Second)
+	claimed, err := webhookRepo.Claim(ctx, 10, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "report-1", claimed[0].IdempotencyKey)
+	require.JSONEq(t, `{"id":"report-1"}`, string(claimed[0].Payload))
+
+	again, err := webhookRepo.Claim(ctx, 10, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, again, "a leased delivery must not be claimed twice")
+
+	require.NoError(t, webhookRepo.MarkSent(ctx, claimed[0].ID, now))
+	later := now.Add(2 * time.Minute)
+	afterLease, err := webhookRepo.Claim(ctx, 10, later, later.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, afterLease)
+}
+
+func TestWebhooks_RecordFailureDisablesEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	webhookRepo := gorm.NewWebhookRepo(pg.DB.Gorm)
+	subID := createWebhookSubscription(ctx, t, repo)
+	endpoints := webhook.NewEndpoints(webhookRepo, false)
+	_, err = endpoints.Register(ctx, subID, "https://hooks.example.com")
+	require.NoError(t, err)
+
+	at := time.Now().UTC().Truncate(time.Microsecond)
+	disabled, err := webhookRepo.RecordFailure(ctx, subID, 2, at, "status 500")
+	require.NoError(t, err)
+	require.False(t, disabled)
+
+	disabled, err = webhookRepo.RecordFailure(ctx, subID, 2, at, "status 500")
+	require.NoError(t, err)
+	require.True(t, disabled)
+
+	disabled, err = webhookRepo.RecordFailure(ctx, subID, 2, at.Add(time.Minute), "status 500")
+	require.NoError(t, err)
+	require.False(t, disabled, "an already disabled endpoint is not disabled again")
+
+	endpoint, err := webhookRepo.GetEndpoint(ctx, subID)
+	require.NoError(t, err)
+	require.Equal(t, 3, endpoint.Failures)
+	require.NotNil(t, endpoint.DisabledAt)
+	require.Equal(t, "status 500", endpoint.LastError)
+
+	require.NoError(t, endpoints.Reverify(ctx, subID))
+	endpoint, err = webhookRepo.GetEndpoint(ctx, subID)
+	require.NoError(t, err)
+	require.Zero(t, endpoint.Failures)
+	require.Nil(t, endpoint.DisabledAt)
+	require.NotNil(t, endpoint.NextVerifyAt)
+}
+
+func TestWebhooks_VerificationIgnoredAfterSecretChanged(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := testutils.StartPostgres(ctx)
+	require.NoError(t, err)
+	defer func() {
+		if err := pg.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres: %v", err)
+		}
+	}()
+
+	repo := gorm.NewRepo(pg.DB.Gorm)
+	webhookRepo := gorm.NewWebhookRepo(pg.DB.Gorm)
+	subID := createWebhookSubscription(ctx, t, repo)
+	endpoints := webhook.NewEndpoints(webhookRepo, false)
+	_, err = endpoints.Register(ctx, subID, "https://old.example.com")
+	require.NoError(t, err)
+
+	now := time.Now().Add(time.Second)
+	claimed, err := webhookRepo.ClaimVerifications(ctx, 10, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	_, err = endpoints.Register(ctx, subID, "https://new.example.com")
+	require.NoError(t, err)
+
+	verified := claimed[0]
+	verified.VerifiedAt = &now
+	require.NoError(t, webhookRepo.SaveVerification(ctx, verified))
+
+	endpoint, err := webhookRepo.GetEndpoint(ctx, subID)
+	require.NoError(t, err)
+	require.Equal(t, "https://new.example.com", endpoint.URL)
+	require.Nil(t, endpoint.VerifiedAt)
+
+	require.NoError(t, repo.Delete(ctx, subID))
+	_, err = webhookRepo.GetEndpoint(ctx, subID)
+	require.ErrorIs(t, err, webhook.ErrEndpointNotFound)
+}
@@ -14,7 +14,7 @@ func NewProvider() *Provider {
 	return &Provider{}
 }
 
-func (m *Provider) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (m *Provider) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	delay := time.Duration(rand.Intn(800)+200) * time.Millisecond
 	time.Sleep(delay)
 
@@ -11,7 +11,7 @@ import (
 )
 
 type reader interface {
-	Get(ctx context.Context, city, provider string) (domain.Report, error)
+	Get(ctx context.Context, city, lang, provider string) (domain.Report, error)
 }
 
 type metrics interface {
@@ -37,9 +37,9 @@ func NewReader(provider weather.Provider, cache reader, metrics metrics, provide
 // Cache reads are handled at the Reader level (not inside providers) to enable
 // accurate metrics collection. In particular, total cache misses can only be
 // detected reliably here, after all sources have been checked.
-func (c Reader) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (c Reader) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	for _, name := range c.ProviderNames {
-		report, err := c.Cache.Get(ctx, city, name)
+		report, err := c.Cache.Get(ctx, city, lang, name)
 		if err == nil {
 			c.Metrics.RecordProviderHit(name)
 			c.Metrics.RecordTotalHit()
@@ -54,7 +54,7 @@ func (c Reader) GetWeather(ctx context.Context, city string) (domain.Report, err
 	}
 
 	c.Metrics.RecordTotalMiss()
-	return c.Provider.GetWeather(ctx, city)
+	return c.Provider.GetWeather(ctx, city, lang)
 }
 
 func (c Reader) CityIsValid(ctx context.Context, city string) (bool, error) {
@@ -35,16 +35,21 @@ func makeKey(prefix, keyType, city, provider string) string {
 	return fmt.Sprintf("%s:%s:%s:%s", prefix, keyType, normalizeCity(city), provider)
 }
 
-func (r RedisCache) key(city, provider string) string {
-	return makeKey(cachePrefix, "report", city, provider)
+// key keeps the pre-localisation key for English reports, so those stay cached across the upgrade.
+func (r RedisCache) key(city, lang, provider string) string {
+	key := makeKey(cachePrefix, "report", city, provider)
+	if lang != "" {
+		key += ":" + lang
+	}
+	return key
 }
 
 func (r RedisCache) notFoundKey(city, provider string) string {
 	return makeKey(cachePrefix, "notfound", city, provider)
 }
 
-func (r RedisCache) Set(ctx context.Context, city, provider string, report domain.Report, ttl time.Duration) error {
-	key := r.key(city, provider)
+func (r RedisCache) Set(ctx context.Context, city, lang, provider string, report domain.Report, ttl time.Duration) error {
+	key := r.key(city, lang, provider)
 
 	data, err := json.Marshal(report)
 	if err != nil {
@@ -61,8 +66,8 @@ func (r RedisCache) Set(ctx context.Context, city, provider string, report domai
 	return nil
 }
 
-func (r RedisCache) Get(ctx context.Context, city, provider string) (domain.Report, error) {
-	key := r.key(city, provider)
+func (r RedisCache) Get(ctx context.Context, city, lang, provider string) (domain.Report, error) {
+	key := r.key(city, lang, provider)
 
 	data, err := r.client.Get(ctx, key).Result()
 
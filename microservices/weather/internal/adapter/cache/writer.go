@@ -12,7 +12,7 @@ import (
 )
 
 type writer interface {
-	Set(ctx context.Context, city string, provider string, report domain.Report, ttl time.Duration) error
+	Set(ctx context.Context, city, lang, provider string, report domain.Report, ttl time.Duration) error
 	SetCityNotFound(ctx context.Context, city, provider string, ttl time.Duration) error
 	GetCityNotFound(ctx context.Context, city, provider string) (bool, error)
 }
@@ -49,22 +49,22 @@ func NewWriter(
 // This is important because some weather providers do not support
 // small or less-known cities. Caching negative results avoids repeated
 // unnecessary calls to the provider and improves performance.
-func (c Writer) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (c Writer) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	if notFound, err := c.Cache.GetCityNotFound(ctx, city, c.ProviderName); err == nil && notFound {
 		return domain.Report{}, domain.ErrCityNotFound
 	} else if err != nil {
 		log.Printf("Error checking CityNotFound cache for %q/%s: %v", city, c.ProviderName, err)
 	}
-	return c.getReportAndCache(ctx, city)
+	return c.getReportAndCache(ctx, city, lang)
 }
 
-func (c Writer) getReportAndCache(ctx context.Context, city string) (domain.Report, error) {
-	report, err := c.Provider.GetWeather(ctx, city)
+func (c Writer) getReportAndCache(ctx context.Context, city, lang string) (domain.Report, error) {
+	report, err := c.Provider.GetWeather(ctx, city, lang)
 	if err != nil {
 		c.cacheCityNotFound(ctx, city, err)
 		return report, err
 	}
-	if cacheErr := c.Cache.Set(ctx, city, c.ProviderName, report, c.TTL); cacheErr != nil {
+	if cacheErr := c.Cache.Set(ctx, city, lang, c.ProviderName, report, c.TTL); cacheErr != nil {
 		log.Printf("Caching weather data for %q/%s: %v", city, c.ProviderName, cacheErr)
 	}
 	return report, nil
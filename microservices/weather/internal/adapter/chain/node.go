@@ -9,7 +9,7 @@ import (
 )
 
 type provider interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValid(ctx context.Context, city string) (bool, error)
 }
 
@@ -32,13 +32,13 @@ func (c *Node) SetNext(next ChainableProvider) ChainableProvider {
 	return next
 }
 
-func (c *Node) GetWeather(ctx context.Context, city string) (domain.Report, error) {
-	report, err := c.provider.GetWeather(ctx, city)
+func (c *Node) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	report, err := c.provider.GetWeather(ctx, city, lang)
 	if err == nil {
 		return report, nil
 	}
 	if c.next != nil {
-		return c.next.GetWeather(ctx, city)
+		return c.next.GetWeather(ctx, city, lang)
 	}
 	return domain.Report{}, fmt.Errorf("all providers failed: %w", err)
 }
@@ -11,12 +11,12 @@ import (
 )
 
 type MockProvider struct {
-	GetWeatherFunc  func(ctx context.Context, city string) (domain.Report, error)
+	GetWeatherFunc  func(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValidFunc func(ctx context.Context, city string) (bool, error)
 }
 
-func (m *MockProvider) GetWeather(ctx context.Context, city string) (domain.Report, error) {
-	return m.GetWeatherFunc(ctx, city)
+func (m *MockProvider) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	return m.GetWeatherFunc(ctx, city, lang)
 }
 
 func (m *MockProvider) CityIsValid(ctx context.Context, city string) (bool, error) {
@@ -44,7 +44,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 
 	t.Run("fallback to second", func(t *testing.T) {
 		first := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, errors.New("network error")
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -53,7 +53,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 		})
 
 		second := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{Temperature: 25, Description: "Sunny"}, nil
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -63,7 +63,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 
 		first.SetNext(second)
 
-		res, err := first.GetWeather(ctx, "Kyiv")
+		res, err := first.GetWeather(ctx, "Kyiv", "")
 		require.NoError(t, err)
 		require.Equal(t, "Sunny", res.Description)
 
@@ -74,7 +74,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 
 	t.Run("all not found", func(t *testing.T) {
 		first := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, domain.ErrCityNotFound
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -82,7 +82,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 			},
 		})
 		second := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, domain.ErrCityNotFound
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -91,7 +91,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 		})
 		first.SetNext(second)
 
-		_, err := first.GetWeather(ctx, "Atlantis")
+		_, err := first.GetWeather(ctx, "Atlantis", "")
 		require.Error(t, err)
 		require.True(t, errors.Is(err, domain.ErrCityNotFound))
 
@@ -103,7 +103,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 
 	t.Run("mixed errors with not found", func(t *testing.T) {
 		first := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, errors.New("timeout")
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -111,7 +111,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 			},
 		})
 		second := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, domain.ErrCityNotFound
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -120,7 +120,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 		})
 		first.SetNext(second)
 
-		_, err := first.GetWeather(ctx, "Unknown")
+		_, err := first.GetWeather(ctx, "Unknown", "")
 		require.Error(t, err)
 		require.True(t, errors.Is(err, domain.ErrCityNotFound))
 
@@ -132,7 +132,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 
 	t.Run("all fail with non-notfound", func(t *testing.T) {
 		first := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, errors.New("bad gateway")
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -140,7 +140,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 			},
 		})
 		second := NewNode(&MockProvider{
-			GetWeatherFunc: func(ctx context.Context, city string) (domain.Report, error) {
+			GetWeatherFunc: func(ctx context.Context, city, lang string) (domain.Report, error) {
 				return domain.Report{}, errors.New("rate limit")
 			},
 			CityIsValidFunc: func(ctx context.Context, city string) (bool, error) {
@@ -149,7 +149,7 @@ func TestBaseProvider_ChainLogic(t *testing.T) {
 		})
 		first.SetNext(second)
 
-		_, err := first.GetWeather(ctx, "Kyiv")
+		_, err := first.GetWeather(ctx, "Kyiv", "")
 		require.Error(t, err)
 		require.False(t, errors.Is(err, domain.ErrCityNotFound))
 
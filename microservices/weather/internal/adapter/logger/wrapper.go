@@ -23,9 +23,9 @@ func NewWrapper(next weather.Provider, providerName string) LogWrapper {
 	}
 }
 
-func (p LogWrapper) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (p LogWrapper) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	start := time.Now()
-	res, err := p.next.GetWeather(ctx, city)
+	res, err := p.next.GetWeather(ctx, city, lang)
 	dur := time.Since(start)
 	status := "OK"
 	if err != nil {
@@ -37,6 +37,7 @@ func (p LogWrapper) GetWeather(ctx context.Context, city string) (domain.Report,
 		"provider", p.provider,
 		"method", "GetWeather",
 		"city", city,
+		"lang", lang,
 		"duration_ms", dur.Milliseconds(),
 		"status", status,
 	)
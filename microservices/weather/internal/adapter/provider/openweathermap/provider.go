@@ -45,8 +45,11 @@ type apiResponse struct {
 	Cod int `json:"cod"`
 }
 
-func (p Provider) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (p Provider) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	url := fmt.Sprintf("%s?q=%s&appid=%s&units=metric", p.baseURL, city, p.apiKey)
+	if lang != "" {
+		url += "&lang=" + lang
+	}
 	body, err := p.makeRequest(ctx, url)
 	if err != nil {
 		if isCityNotFound(body) {
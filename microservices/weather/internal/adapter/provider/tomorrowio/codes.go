@@ -26,3 +26,32 @@ var weatherCodeDescriptions = map[int]string{
 	7102: "Light Ice Pellets",
 	8000: "Thunderstorm",
 }
+
+var localizedDescriptions = map[string]map[int]string{
+	"uk": {
+		0:    "Невідомо",
+		1000: "Ясно, сонячно",
+		1001: "Хмарно",
+		1100: "Переважно ясно",
+		1101: "Мінлива хмарність",
+		1102: "Переважно хмарно",
+		2000: "Туман",
+		2100: "Легкий туман",
+		4000: "Мряка",
+		4001: "Дощ",
+		4200: "Невеликий дощ",
+		4201: "Сильний дощ",
+		5000: "Сніг",
+		5001: "Сніжинки",
+		5100: "Невеликий сніг",
+		5101: "Сильний сніг",
+		6000: "Крижана мряка",
+		6001: "Крижаний дощ",
+		6200: "Невеликий крижаний дощ",
+		6201: "Сильний крижаний дощ",
+		7000: "Крижана крупа",
+		7101: "Сильна крижана крупа",
+		7102: "Легка крижана крупа",
+		8000: "Гроза",
+	},
+}
@@ -44,7 +44,7 @@ type apiResponse struct {
 	} `json:"data"`
 }
 
-func (p Provider) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+func (p Provider) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	url := fmt.Sprintf("%s?location=%s&apikey=%s", p.baseURL, city, p.apiKey)
 	body, err := p.makeRequest(ctx, url)
 	if err != nil {
@@ -63,7 +63,7 @@ func (p Provider) GetWeather(ctx context.Context, city string) (domain.Report, e
 	return domain.Report{
 		Temperature: values.Temperature,
 		Humidity:    values.Humidity,
-		Description: getDescription(values.WeatherCode),
+		Description: getDescription(values.WeatherCode, lang),
 	}, nil
 }
 
@@ -113,7 +113,12 @@ func (p Provider) makeRequest(ctx context.Context, url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-func getDescription(code int) string {
+// getDescription translates the weather code itself, as tomorrow.io has no language parameter.
+// Languages without a translation get English.
+func getDescription(code int, lang string) string {
+	if desc, ok := localizedDescriptions[lang][code]; ok {
+		return desc
+	}
 	if desc, ok := weatherCodeDescriptions[code]; ok {
 		return desc
 	}
@@ -51,8 +51,8 @@ type errorAPIResponse struct {
 	} `json:"error"`
 }
 
-func (p Provider) GetWeather(ctx context.Context, city string) (domain.Report, error) {
-	body, err := p.makeRequest(ctx, city)
+func (p Provider) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
+	body, err := p.makeRequest(ctx, city, lang)
 	if err != nil {
 		return domain.Report{}, err
 	}
@@ -74,7 +74,7 @@ func (p Provider) GetWeather(ctx context.Context, city string) (domain.Report, e
 }
 
 func (p Provider) CityIsValid(ctx context.Context, city string) (bool, error) {
-	body, err := p.makeRequest(ctx, city)
+	body, err := p.makeRequest(ctx, city, "")
 	if err != nil {
 		return false, err
 	}
@@ -86,11 +86,14 @@ func (p Provider) CityIsValid(ctx context.Context, city string) (bool, error) {
 	return true, nil
 }
 
-func (p Provider) makeRequest(ctx context.Context, city string) ([]byte, error) {
+func (p Provider) makeRequest(ctx context.Context, city, lang string) ([]byte, error) {
 	if p.apiKey == "" {
 		return nil, errors.New("missing API key")
 	}
 	url := fmt.Sprintf("%s/current.json?key=%s&q=%s", p.baseURL, p.apiKey, city)
+	if lang != "" {
+		url += "&lang=" + lang
+	}
 	return p.doRequestBody(ctx, url)
 }
 
@@ -36,7 +36,7 @@ func TestGetCurrentWeather_Success(t *testing.T) {
 	client := mockServer.Client()
 	provider := New("fake-api-key", client, mockServer.URL)
 
-	result, err := provider.GetWeather(context.Background(), "Kyiv")
+	result, err := provider.GetWeather(context.Background(), "Kyiv", "")
 
 	require.NoError(t, err)
 	require.Equal(t, 21.5, result.Temperature)
@@ -44,6 +44,24 @@ func TestGetCurrentWeather_Success(t *testing.T) {
 	require.Equal(t, "Clear", result.Description)
 }
 
+func TestGetCurrentWeather_RequestsLanguage(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "uk", r.URL.Query().Get("lang"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"current": {"temp_c": 3, "humidity": 80, "condition": {"text": "Хмарно"}}}`)
+		require.NoError(t, err)
+	}))
+	defer mockServer.Close()
+
+	provider := New("fake-api-key", mockServer.Client(), mockServer.URL)
+
+	result, err := provider.GetWeather(context.Background(), "Kyiv", "uk")
+
+	require.NoError(t, err)
+	require.Equal(t, "Хмарно", result.Description)
+}
+
 func TestCityExists_True(t *testing.T) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -105,7 +123,7 @@ func TestGetCurrentWeather_Timeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	_, err := provider.GetWeather(ctx, "Kyiv")
+	_, err := provider.GetWeather(ctx, "Kyiv", "")
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "timed out")
@@ -20,6 +20,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"weather/internal/app/di"
 	"weather/internal/config"
@@ -30,6 +31,7 @@ import (
 
 	loggerPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/logger"
 	metricsPkg "github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/metrics"
+	"github.com/GenesisEducationKyiv/software-engineering-school-5-0-mykyyta/microservices/pkg/tlsconfig"
 )
 
 type App struct {
@@ -38,6 +40,7 @@ type App struct {
 	Redis      *redis.Client
 	GrpcServer *grpc.Server
 	GrpcLis    net.Listener
+	GrpcTLS    *tlsconfig.Source
 }
 
 func Run(lg *loggerPkg.Logger) error {
@@ -132,9 +135,28 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("listen gRPC: %w", err)
 	}
 
-	grpcServer := grpc.NewServer(
+	grpcOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpcapi.LoggingUnaryServerInterceptor(logger)),
-	)
+	}
+	var grpcTLS *tlsconfig.Source
+	if cfg.GRPCTLS.CertFile != "" {
+		grpcTLS, err = tlsconfig.Load(tlsconfig.Options{
+			CertFile:       cfg.GRPCTLS.CertFile,
+			KeyFile:        cfg.GRPCTLS.KeyFile,
+			CAFile:         cfg.GRPCTLS.ClientCAFile,
+			AllowedSANs:    cfg.GRPCTLS.AllowedSANs,
+			ReloadInterval: cfg.GRPCTLS.ReloadInterval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gRPC TLS: %w", err)
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcTLS.ServerConfig())))
+		logger.Info("gRPC TLS enabled", "mutual", cfg.GRPCTLS.ClientCAFile != "", "allowed_sans", cfg.GRPCTLS.AllowedSANs)
+	} else {
+		logger.Warn("gRPC TLS disabled, serving plaintext")
+	}
+
+	grpcServer := grpc.NewServer(grpcOpts...)
 	grpcHandler := grpcapi.NewHandler(weatherService)
 	weatherpb.RegisterWeatherServiceServer(grpcServer, grpcHandler)
 
@@ -144,6 +166,7 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		HttpLis:    httpLis,
 		GrpcServer: grpcServer,
 		GrpcLis:    grpcLis,
+		GrpcTLS:    grpcTLS,
 		Redis:      redisClient,
 	}, nil
 }
@@ -170,6 +193,16 @@ func (a *App) Start(ctx context.Context) error {
 		return nil
 	})
 
+	if a.GrpcTLS != nil {
+		go a.GrpcTLS.Watch(ctx, func(err error) {
+			if err != nil {
+				logger.Error("Failed to reload gRPC TLS certificates, keeping the previous ones", "error", err)
+				return
+			}
+			logger.Info("gRPC TLS certificates reloaded")
+		})
+	}
+
 	time.Sleep(100 * time.Millisecond)
 
 	go func() {
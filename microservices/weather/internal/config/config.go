@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,7 @@ type Config struct {
 	WeatherAPIKey    string
 	TomorrowioAPIKey string
 	Cache            CacheConfig
+	GRPCTLS          TLSConfig
 	BenchmarkMode    bool
 }
 
@@ -25,6 +27,16 @@ type CacheConfig struct {
 	NotFoundTTL   time.Duration
 }
 
+// TLSConfig secures the gRPC server. TLS is on when CertFile is set; ClientCAFile also
+// requires client certificates, optionally limited to AllowedSANs.
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	AllowedSANs    []string
+	ReloadInterval time.Duration
+}
+
 func LoadConfig() *Config {
 	_ = godotenv.Load()
 
@@ -34,6 +46,7 @@ func LoadConfig() *Config {
 		WeatherAPIKey:    mustGet("WEATHER_API_KEY"),
 		TomorrowioAPIKey: mustGet("TOMORROWIO_API_KEY"),
 		Cache:            loadCacheConfig(),
+		GRPCTLS:          loadTLSConfig(),
 		BenchmarkMode:    getBoolEnv("BENCHMARK_MODE", false),
 	}
 }
@@ -48,6 +61,16 @@ func loadCacheConfig() CacheConfig {
 	}
 }
 
+func loadTLSConfig() TLSConfig {
+	return TLSConfig{
+		CertFile:       getEnv("GRPC_TLS_CERT_FILE", ""),
+		KeyFile:        getEnv("GRPC_TLS_KEY_FILE", ""),
+		ClientCAFile:   getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+		AllowedSANs:    getListEnv("GRPC_TLS_ALLOWED_SANS"),
+		ReloadInterval: getDurationEnv("GRPC_TLS_RELOAD_INTERVAL", 30*time.Second),
+	}
+}
+
 func mustGet(key string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -64,6 +87,16 @@ func getEnv(key, fallback string) string {
 	return val
 }
 
+func getListEnv(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getBoolEnv(key string, fallback bool) bool {
 	val := os.Getenv(key)
 	if val == "" {
@@ -11,7 +11,7 @@ import (
 )
 
 type weatherService interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValid(ctx context.Context, city string) (bool, error)
 }
 
@@ -25,7 +25,7 @@ func NewHandler(s weatherService) *Handler {
 }
 
 func (s *Handler) GetWeather(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.WeatherResponse, error) {
-	report, err := s.ws.GetWeather(ctx, req.City)
+	report, err := s.ws.GetWeather(ctx, req.City, req.Lang)
 	if err != nil {
 		logger := loggerPkg.From(ctx)
 		if errors.Is(err, domain.ErrCityNotFound) {
@@ -13,7 +13,7 @@ import (
 )
 
 type weatherService interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValid(ctx context.Context, city string) (bool, error)
 }
 
@@ -34,7 +34,7 @@ func (h *Handler) GetWeather(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	report, err := h.ws.GetWeather(r.Context(), city)
+	report, err := h.ws.GetWeather(r.Context(), city, r.URL.Query().Get("lang"))
 	if err != nil {
 		logger := loggerPkg.From(r.Context())
 		if errors.Is(err, domain.ErrCityNotFound) {
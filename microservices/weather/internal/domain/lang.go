@@ -0,0 +1,20 @@
+package domain
+
+import "strings"
+
+// NormalizeLang reduces a language tag such as "uk-UA" to its lower-case ISO 639 code.
+// Anything that is not a language code becomes "", which providers serve in English.
+func NormalizeLang(tag string) string {
+	lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang = strings.ToLower(lang)
+	if len(lang) < 2 || len(lang) > 3 {
+		return ""
+	}
+	for _, r := range lang {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return lang
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type Provider interface {
-	GetWeather(ctx context.Context, city string) (domain.Report, error)
+	GetWeather(ctx context.Context, city, lang string) (domain.Report, error)
 	CityIsValid(ctx context.Context, city string) (bool, error)
 }
 
@@ -23,11 +23,14 @@ func NewService(p Provider) Service {
 	return Service{provider: p}
 }
 
-func (s Service) GetWeather(ctx context.Context, city string) (domain.Report, error) {
+// GetWeather returns the current weather with the description in lang where the provider
+// supports it; see domain.NormalizeLang.
+func (s Service) GetWeather(ctx context.Context, city, lang string) (domain.Report, error) {
 	logger := loggerPkg.From(ctx)
-	logger.Info("getting weather data from provider", "city", city)
+	lang = domain.NormalizeLang(lang)
+	logger.Info("getting weather data from provider", "city", city, "lang", lang)
 
-	report, err := s.provider.GetWeather(ctx, city)
+	report, err := s.provider.GetWeather(ctx, city, lang)
 	if err != nil {
 		if errors.Is(err, domain.ErrCityNotFound) {
 			logger.Warn("city not found in provider", "city", city)